@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -10,15 +11,21 @@ import (
 	"syscall"
 
 	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal"
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/apply"
+	"github.com/dash0hq/dash0-cli/internal/authtokens"
 	"github.com/dash0hq/dash0-cli/internal/client"
 	"github.com/dash0hq/dash0-cli/internal/checkrules"
 	"github.com/dash0hq/dash0-cli/internal/failedchecks"
 	dashcolor "github.com/dash0hq/dash0-cli/internal/color"
 	"github.com/dash0hq/dash0-cli/internal/config"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
 	"github.com/dash0hq/dash0-cli/internal/dashboards"
+	"github.com/dash0hq/dash0-cli/internal/doctor"
+	"github.com/dash0hq/dash0-cli/internal/events"
 	"github.com/dash0hq/dash0-cli/internal/help"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
 	"github.com/dash0hq/dash0-cli/internal/logging"
 	"github.com/dash0hq/dash0-cli/internal/login"
 	"github.com/dash0hq/dash0-cli/internal/members"
@@ -70,13 +77,20 @@ func init() {
 	// Propagate build version to shared package
 	versionpkg.Version = version
 
+	// Let `config profiles create --create-auth-token` create auth tokens,
+	// which the config package cannot do without an import cycle
+	config.CreateAuthToken = authtokens.CreateForProfile
+
 	// Register subcommands
 	rootCmd.AddCommand(apply.NewApplyCmd())
 	rootCmd.AddCommand(rawapi.NewAPICmd())
+	rootCmd.AddCommand(authtokens.NewAuthTokensCmd())
 	rootCmd.AddCommand(checkrules.NewCheckRulesCmd())
 	rootCmd.AddCommand(failedchecks.NewFailedChecksCmd())
 	rootCmd.AddCommand(config.NewConfigCmd())
 	rootCmd.AddCommand(dashboards.NewDashboardsCmd())
+	rootCmd.AddCommand(doctor.NewDoctorCmd())
+	rootCmd.AddCommand(events.NewEventsCmd())
 	rootCmd.AddCommand(logging.NewLogsCmd())
 	rootCmd.AddCommand(login.NewLoginCmd())
 	rootCmd.AddCommand(login.NewLogoutCmd())
@@ -102,6 +116,9 @@ func init() {
 	rootCmd.PersistentFlags().Bool("agent-mode", false, "Enable agent mode for AI coding agents (env: DASH0_AGENT_MODE)")
 	rootCmd.PersistentFlags().String("profile", "", "Profile to use for this invocation; overrides the active profile on disk (env: DASH0_PROFILE)")
 	rootCmd.PersistentFlags().String("max-retries", "", "Maximum number of retries for failed API requests (0-5; default: 3; env: DASH0_MAX_RETRIES)")
+	rootCmd.PersistentFlags().String("otlp-protocol", "", `Protocol for sending telemetry: "http/json" (default), "http/protobuf", or "grpc" (env: OTEL_EXPORTER_OTLP_PROTOCOL)`)
+	rootCmd.PersistentFlags().String("otlp-compression", "", `Compression for sending telemetry: "none" (default), "gzip", or "zstd" (env: OTEL_EXPORTER_OTLP_COMPRESSION)`)
+	rootCmd.PersistentFlags().Bool("insecure-skip-tls-verify", false, "Skip the verification of server certificates (insecure; prefer --ca-file on the profile or DASH0_CA_FILE)")
 	rootCmd.PersistentFlags().Bool("no-skill-hint", false, "Suppress the agent-mode error hint pointing at dash0 skill install / dash0 skill show (env: DASH0_NO_SKILL_HINT)")
 }
 
@@ -230,11 +247,26 @@ func skillHintSuppressed() bool {
 // This approach avoids the need to predict which command will run — something
 // that cobra's Traverse cannot reliably do when persistent flags like -X
 // precede the subcommand name.
+//
+// Secrets of the active profile that live in a credential store are opened
+// here. Failing to open them is reported, since the commands would otherwise
+// fail with a misleading authentication error. Expired access tokens are
+// left alone: the clients refresh them, so that local commands stay offline.
 func loadConfig() *profiles.Configuration {
+	profileName := ""
+	if store, err := profiles.NewStore(); err == nil {
+		if active, err := store.GetActiveProfile(); err == nil && active != nil {
+			profileName = active.Name
+		}
+	}
 	cfg, err := profiles.ResolveConfiguration("", "")
 	if err != nil {
 		return nil
 	}
+	if err := credentials.OpenConfiguration(profileName, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
 	return cfg
 }
 
@@ -329,23 +361,46 @@ func main() {
 	// the severity range type), so main bridges the value here.
 	otlp.SetTailColorEnabled(!dashcolor.NoColor)
 
-	// Resolve the per-invocation profile selector (--profile flag or
-	// DASH0_PROFILE env var) before loading config so the selection flows
-	// into both the loaded configuration and the context consumed by
-	// `config show`.
-	selector := config.ResolveProfileSelector(flagValue(os.Args[1:], "profile"))
+	// Discover the project configuration file (.dash0.yaml) in the working
+	// directory or one of its parents. It may pin the profile, so it is read
+	// before the profile selector is resolved.
+	project, err := config.LoadProjectConfig()
+	if err != nil {
+		printError(err)
+		os.Exit(1)
+	}
+	ctx = config.WithProjectConfig(ctx, project)
+
+	// Resolve the per-invocation profile selector (--profile flag,
+	// DASH0_PROFILE env var, or the project configuration file) before
+	// loading config so the selection flows into both the loaded
+	// configuration and the context consumed by `config show`.
+	selector := config.ResolveProfileSelector(flagValue(os.Args[1:], "profile"), project)
 	// login/logout resolve the target profile themselves (login may create a
 	// missing profile; logout produces its own profile-not-found message).
 	// Skip the pre-resolve so `dash0 login --profile <new>` can reach runLogin.
 	skipProfileResolve := targetCmd != nil && (targetCmd.Name() == "login" || targetCmd.Name() == "logout")
 	if selector.IsSet() && !skipProfileResolve {
 		cfg, err := config.ResolveConfigurationForProfile(ctx, selector.Name)
-		if err != nil {
+		switch {
+		case err == nil:
+			ctx = profiles.WithConfiguration(ctx, cfg)
+		case selector.Source == config.ProfileSourceProject && errors.Is(err, profiles.ErrProfileNotFound):
+			// A profile pinned by a checked-out repository may not exist on
+			// this machine yet. Failing here would block the very commands
+			// that create or import it, so fall back to the active profile.
+			fmt.Fprintf(os.Stderr, "Warning: profile %q selected by %s does not exist; using the active profile. Create it with 'dash0 config profiles create %s' or 'dash0 config profiles import'.\n",
+				selector.Name, selector.File, selector.Name)
+			selector = config.ProfileSelector{}
+		default:
+			if selector.Source == config.ProfileSourceProject {
+				err = fmt.Errorf("%w (selected by %s)", err, selector.File)
+			}
 			printError(err)
 			os.Exit(1)
 		}
-		ctx = profiles.WithConfiguration(ctx, cfg)
-	} else if !selector.IsSet() {
+	}
+	if !selector.IsSet() {
 		if cfg := loadConfig(); cfg != nil {
 			// Always attempt to load configuration. Commands that don't need it
 			// (help, version, config) simply ignore it. Commands that do need it
@@ -365,7 +420,25 @@ func main() {
 		ctx = client.WithMaxRetries(ctx, &v)
 	}
 
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	// Resolve --otlp-protocol and --otlp-compression the same way; the OTLP
+	// client validates them.
+	ctx = client.WithOtlpTransport(ctx, flagValue(os.Args[1:], "otlp-protocol"), flagValue(os.Args[1:], "otlp-compression"))
+
+	// Resolve --insecure-skip-tls-verify the same way; every outbound
+	// client takes its transport from the context.
+	if hasFlag(os.Args[1:], "--insecure-skip-tls-verify") {
+		ctx = httptransport.WithInsecureSkipVerify(ctx)
+	}
+
+	err = rootCmd.ExecuteContext(ctx)
+	// The API client writes refreshed OAuth tokens back in plaintext; move
+	// them into the credential store of their profile.
+	credentials.Reseal()
+	if err != nil {
+		var exitErr *internal.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		printError(err)
 		// Show usage only for flag/argument errors, not for runtime errors.
 		// Commands set SilenceUsage = true once past flag validation.
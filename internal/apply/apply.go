@@ -15,6 +15,7 @@ import (
 	"github.com/dash0hq/dash0-cli/internal"
 	"github.com/dash0hq/dash0-cli/internal/asset"
 	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/config"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 	sigsyaml "sigs.k8s.io/yaml"
@@ -77,7 +78,10 @@ If an asset exists, it will be updated. If it doesn't exist, it will be created.
 				return fmt.Errorf("unexpected arguments: %s\nTo apply multiple files, pass a directory with -f instead of a glob pattern", strings.Join(args, " "))
 			}
 			if flags.File == "" {
-				return fmt.Errorf("file is required; use -f to specify the file (use '-' for stdin)")
+				flags.File = config.ProjectConfigFromContext(cmd.Context()).ApplyRootPath()
+			}
+			if flags.File == "" {
+				return fmt.Errorf("file is required; use -f to specify the file (use '-' for stdin), or set apply-root in %s", config.ProjectFileName)
 			}
 			cmd.SilenceUsage = true
 			return runApply(cmd.Context(), &flags)
@@ -0,0 +1,18 @@
+package authtokens
+
+import "github.com/spf13/cobra"
+
+// NewAuthTokensCmd creates a new auth-tokens command.
+func NewAuthTokensCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth-tokens",
+		Short: "Manage auth tokens",
+		Long:  `List, create, and revoke the auth tokens of your Dash0 organization.`,
+	}
+
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newCreateCmd())
+	cmd.AddCommand(newRevokeCmd())
+
+	return cmd
+}
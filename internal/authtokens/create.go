@@ -0,0 +1,214 @@
+package authtokens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/agentmode"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+type createFlags struct {
+	ApiUrl    string
+	AuthToken string
+	Scope     []string
+	Dataset   []string
+	ExpiresIn string
+	Output    string
+}
+
+// validScopes maps the scopes an auth token can be granted to what they
+// allow.
+var validScopes = map[string]string{
+	"ingest": "send telemetry via OTLP",
+	"read":   "query telemetry and read assets",
+	"write":  "create, update, and delete assets",
+}
+
+func newCreateCmd() *cobra.Command {
+	flags := &createFlags{}
+
+	cmd := &cobra.Command{
+		Use:     "create <name>",
+		Aliases: []string{"add"},
+		Short:   "Create an auth token",
+		Long: `Create an auth token for your Dash0 organization.
+
+Grant the token one or more scopes with --scope: ` + scopesHelp() + `.
+Restrict it to datasets with --dataset; without it, the token can access every dataset.
+Limit its lifetime with --expires-in, e.g. "90d" or "12h"; without it, the token does not expire.
+
+The secret of the token is printed once and cannot be retrieved again.
+To create a profile that uses a new token, run 'dash0 config profiles create <name> --create-auth-token'.` + internal.CONFIG_HINT,
+		Example: `  # Token for a CI pipeline that sends telemetry
+  dash0 auth-tokens create ci --scope ingest --expires-in 90d
+
+  # Read-only token restricted to one dataset
+  dash0 auth-tokens create grafana --scope read --dataset production
+
+  # Capture the secret in a script
+  TOKEN=$(dash0 auth-tokens create ci --scope ingest -o json | jq -r .token)`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreate(cmd, args[0], flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.ApiUrl, "api-url", "", "API endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringArrayVar(&flags.Scope, "scope", nil, "Scope to grant: ingest, read, write (repeatable; at least one required)")
+	cmd.Flags().StringArrayVar(&flags.Dataset, "dataset", nil, "Dataset the token is restricted to (repeatable; default: all datasets)")
+	cmd.Flags().StringVar(&flags.ExpiresIn, "expires-in", "", `Lifetime of the token, e.g. "90d" or "12h" (default: no expiry)`)
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Output format: table, json (default: table; json in agent mode)")
+
+	return cmd
+}
+
+func runCreate(cmd *cobra.Command, name string, flags *createFlags) error {
+	ctx := cmd.Context()
+
+	useJSON := strings.ToLower(flags.Output) == "json" ||
+		(flags.Output == "" && agentmode.Enabled)
+	if !useJSON && flags.Output != "" && strings.ToLower(flags.Output) != "table" {
+		return fmt.Errorf("unknown output format: %s (valid formats: table, json)", flags.Output)
+	}
+
+	request, err := newCreateRequest(name, flags.Scope, flags.Dataset, flags.ExpiresIn, time.Now())
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewClientFromContext(ctx, flags.ApiUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	created, err := apiClient.CreateAuthToken(ctx, request)
+	if err != nil {
+		return client.HandleAPIError(err, client.ErrorContext{
+			AssetType: "auth token",
+			AssetName: name,
+		})
+	}
+
+	if useJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(created)
+	}
+
+	if created.ExpiresAt != nil {
+		fmt.Printf("Auth token %q created (id: %s), expires %s.\n", name, created.Id, created.ExpiresAt.UTC().Format(time.RFC3339))
+	} else {
+		fmt.Printf("Auth token %q created (id: %s).\n", name, created.Id)
+	}
+	fmt.Printf("Token: %s\n", created.Token)
+	fmt.Fprintln(os.Stderr, "Store the token now; it cannot be displayed again.")
+	return nil
+}
+
+// newCreateRequest validates the scopes, datasets, and lifetime of a token to
+// create and builds the request for it.
+func newCreateRequest(name string, scopes, datasets []string, expiresIn string, now time.Time) (*dash0api.CreateAuthTokenRequest, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("the auth token name must not be empty")
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("at least one --scope is required (valid scopes: %s)", strings.Join(scopeNames(), ", "))
+	}
+	for _, scope := range scopes {
+		if _, ok := validScopes[scope]; !ok {
+			return nil, fmt.Errorf("unknown scope %q (valid scopes: %s)", scope, strings.Join(scopeNames(), ", "))
+		}
+	}
+
+	request := &dash0api.CreateAuthTokenRequest{
+		Name:   name,
+		Scopes: scopes,
+	}
+	if len(datasets) > 0 {
+		request.Datasets = &datasets
+	}
+	if expiresIn != "" {
+		lifetime, err := parseExpiresIn(expiresIn)
+		if err != nil {
+			return nil, err
+		}
+		expiresAt := now.Add(lifetime).UTC()
+		request.ExpiresAt = &expiresAt
+	}
+	return request, nil
+}
+
+// parseExpiresIn parses the --expires-in value: a Go duration such as "12h",
+// or a number of days such as "90d".
+func parseExpiresIn(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --expires-in value %q (examples: \"90d\", \"12h\")", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --expires-in value %q (examples: \"90d\", \"12h\")", s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("--expires-in must be positive, got %q", s)
+	}
+	return d, nil
+}
+
+func scopeNames() []string {
+	names := make([]string, 0, len(validScopes))
+	for name := range validScopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func scopesHelp() string {
+	var parts []string
+	for _, name := range scopeNames() {
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, validScopes[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CreateForProfile creates the auth token of a new profile with the
+// credentials of the selected profile; it implements config.CreateAuthToken.
+func CreateForProfile(ctx context.Context, req config.AuthTokenRequest) (*config.CreatedAuthToken, error) {
+	request, err := newCreateRequest(req.Name, req.Scopes, req.Datasets, req.ExpiresIn, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient, err := client.NewClientFromContext(ctx, req.ApiUrl, "")
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := apiClient.CreateAuthToken(ctx, request)
+	if err != nil {
+		return nil, client.HandleAPIError(err, client.ErrorContext{
+			AssetType: "auth token",
+			AssetName: req.Name,
+		})
+	}
+	return &config.CreatedAuthToken{ID: created.Id, Token: created.Token}, nil
+}
@@ -0,0 +1,67 @@
+package authtokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpiresIn(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"90d", 90 * 24 * time.Hour},
+		{"1d", 24 * time.Hour},
+		{"12h", 12 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseExpiresIn(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseExpiresInInvalid(t *testing.T) {
+	for _, in := range []string{"d", "ninety days", "1w", "0d", "-5h"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := parseExpiresIn(in)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewCreateRequest(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	req, err := newCreateRequest("ci", []string{"ingest", "read"}, []string{"production"}, "90d", now)
+	require.NoError(t, err)
+	assert.Equal(t, "ci", req.Name)
+	assert.Equal(t, []string{"ingest", "read"}, req.Scopes)
+	require.NotNil(t, req.Datasets)
+	assert.Equal(t, []string{"production"}, *req.Datasets)
+	require.NotNil(t, req.ExpiresAt)
+	assert.Equal(t, time.Date(2027, 1, 16, 12, 0, 0, 0, time.UTC), *req.ExpiresAt)
+}
+
+func TestNewCreateRequestDefaults(t *testing.T) {
+	req, err := newCreateRequest("ci", []string{"ingest"}, nil, "", time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, req.Datasets, "no dataset restriction without --dataset")
+	assert.Nil(t, req.ExpiresAt, "no expiry without --expires-in")
+}
+
+func TestNewCreateRequestRejectsInvalidScopes(t *testing.T) {
+	_, err := newCreateRequest("ci", nil, nil, "", time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least one --scope is required")
+
+	_, err = newCreateRequest("ci", []string{"admin"}, nil, "", time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown scope "admin" (valid scopes: ingest, read, write)`)
+}
@@ -0,0 +1,225 @@
+//go:build integration
+
+package authtokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/dash0hq/dash0-cli/internal/testutil"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	apiPathAuthTokens = "/api/auth-tokens"
+	testAuthToken     = "auth_test_token"
+)
+
+var authTokenIDPattern = regexp.MustCompile(`^/api/auth-tokens/[^/]+$`)
+
+// newAuthTokensCmd creates a root command with the auth-tokens subcommand
+// attached, mirroring the real command tree.
+func newAuthTokensCmd() *cobra.Command {
+	root := &cobra.Command{Use: "dash0", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewAuthTokensCmd())
+	return root
+}
+
+func TestListAuthTokens_Success(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodGet, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensListSuccess,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "list", "--api-url", server.URL, "--auth-token", testAuthToken})
+
+	var err error
+	output := testutil.CaptureStdout(t, func() {
+		err = cmd.Execute()
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "SCOPES")
+	assert.Contains(t, output, "ci")
+	assert.Contains(t, output, "ingest")
+	assert.Contains(t, output, "2027-01-16T12:00:00Z")
+	assert.Contains(t, output, "production")
+	assert.Contains(t, output, "never")
+}
+
+func TestListAuthTokens_Empty(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodGet, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensListEmpty,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "list", "--api-url", server.URL, "--auth-token", testAuthToken})
+
+	var err error
+	output := testutil.CaptureStdout(t, func() {
+		err = cmd.Execute()
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "No auth tokens found.")
+}
+
+func TestListAuthTokens_JSON(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodGet, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensListSuccess,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "list", "--api-url", server.URL, "--auth-token", testAuthToken, "-o", "json"})
+
+	var err error
+	output := testutil.CaptureStdout(t, func() {
+		err = cmd.Execute()
+	})
+
+	require.NoError(t, err)
+	var parsed []interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &parsed))
+	assert.Len(t, parsed, 3)
+}
+
+func TestCreateAuthToken_PrintsSecretOnce(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodPost, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensCreateSuccess,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "create", "ci", "--scope", "ingest", "--dataset", "production", "--expires-in", "90d",
+		"--api-url", server.URL, "--auth-token", testAuthToken})
+
+	var err error
+	output := testutil.CaptureStdout(t, func() {
+		err = cmd.Execute()
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, output, `Auth token "ci" created (id: at_01k5vpx97efdnrkqan15b41k87)`)
+	assert.Contains(t, output, "Token: auth_newly_created_secret")
+
+	bodies := server.RequestBodies(t, http.MethodPost, apiPathAuthTokens)
+	require.Len(t, bodies, 1)
+	assert.Equal(t, "ci", bodies[0]["name"])
+	assert.Equal(t, []any{"ingest"}, bodies[0]["scopes"])
+	assert.Equal(t, []any{"production"}, bodies[0]["datasets"])
+	assert.NotEmpty(t, bodies[0]["expiresAt"])
+}
+
+func TestCreateAuthToken_RequiresScope(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "create", "ci", "--api-url", server.URL, "--auth-token", testAuthToken})
+
+	err := cmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least one --scope is required")
+	assert.Empty(t, server.Requests(), "no request is sent for an invalid token")
+}
+
+func TestRevokeAuthToken_ByName(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodGet, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensListSuccess,
+		Validator:  testutil.RequireHeaders,
+	})
+	server.OnPattern(http.MethodDelete, authTokenIDPattern, testutil.MockResponse{
+		StatusCode: http.StatusNoContent,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "revoke", "ci", "--force", "--api-url", server.URL, "--auth-token", testAuthToken})
+
+	var err error
+	output := testutil.CaptureStdout(t, func() {
+		err = cmd.Execute()
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, output, `Auth token "ci" (at_01k5vpx97efdnrkqan15b41k84) revoked`)
+	last := server.LastRequest()
+	require.NotNil(t, last)
+	assert.Equal(t, http.MethodDelete, last.Method)
+	assert.Equal(t, apiPathAuthTokens+"/at_01k5vpx97efdnrkqan15b41k84", last.Path)
+}
+
+func TestRevokeAuthToken_AmbiguousName(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodGet, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensListSuccess,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "revoke", "grafana", "--force", "--api-url", server.URL, "--auth-token", testAuthToken})
+
+	err := cmd.Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `2 auth tokens are named "grafana"`)
+}
+
+func TestRevokeAuthToken_AlreadyRevokedWithForce(t *testing.T) {
+	testutil.SetupTestEnv(t)
+
+	server := testutil.NewMockServer(t, testutil.FixturesDir())
+	server.On(http.MethodGet, apiPathAuthTokens, testutil.MockResponse{
+		StatusCode: http.StatusOK,
+		BodyFile:   testutil.FixtureAuthTokensListEmpty,
+		Validator:  testutil.RequireHeaders,
+	})
+	server.OnPattern(http.MethodDelete, authTokenIDPattern, testutil.MockResponse{
+		StatusCode: http.StatusNotFound,
+		BodyFile:   testutil.FixtureAuthTokensNotFound,
+		Validator:  testutil.RequireHeaders,
+	})
+
+	cmd := newAuthTokensCmd()
+	cmd.SetArgs([]string{"auth-tokens", "revoke", "at_gone", "--force", "--api-url", server.URL, "--auth-token", testAuthToken})
+
+	var err error
+	stderr := testutil.CaptureStderr(t, func() {
+		err = cmd.Execute()
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, stderr, "was already deleted")
+}
@@ -0,0 +1,216 @@
+package authtokens
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/agentmode"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/output"
+	"github.com/dash0hq/dash0-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+type listFlags struct {
+	ApiUrl     string
+	AuthToken  string
+	Output     string
+	SkipHeader bool
+	Column     []string
+}
+
+// listFormat represents the output format for auth token list.
+type listFormat string
+
+const (
+	listFormatTable listFormat = "table"
+	listFormatJSON  listFormat = "json"
+	listFormatCSV   listFormat = "csv"
+)
+
+var authTokenListDefaultColumns = []query.ColumnDef{
+	{Key: "name", Aliases: []string{"token name"}, Header: internal.HEADER_NAME, Width: 30},
+	{Key: "id", Aliases: []string{"token id"}, Header: internal.HEADER_ID, Width: 36},
+	{Key: "scopes", Header: internal.HEADER_SCOPES, Width: 20},
+	{Key: "datasets", Header: internal.HEADER_DATASETS, Width: 20},
+	{Key: "expires", Aliases: []string{"expires at"}, Header: internal.HEADER_EXPIRES, Width: 20},
+	{Key: "last used", Aliases: []string{"last used at"}, Header: internal.HEADER_LAST_USED, Width: 20},
+}
+
+func parseListFormat(s string) (listFormat, error) {
+	switch strings.ToLower(s) {
+	case "":
+		if agentmode.Enabled {
+			return listFormatJSON, nil
+		}
+		return listFormatTable, nil
+	case "table":
+		return listFormatTable, nil
+	case "json":
+		return listFormatJSON, nil
+	case "csv":
+		return listFormatCSV, nil
+	default:
+		return "", fmt.Errorf("unknown output format: %s (valid formats: table, json, csv)", s)
+	}
+}
+
+func newListCmd() *cobra.Command {
+	flags := &listFlags{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List auth tokens",
+		Long: `List the auth tokens of your Dash0 organization with their scopes, dataset
+restrictions, and expiry. The secrets of the tokens are never shown.` + internal.CONFIG_HINT,
+		Example: `  # List all auth tokens
+  dash0 auth-tokens list
+
+  # Output as JSON
+  dash0 auth-tokens list -o json
+
+  # Show only specific columns
+  dash0 auth-tokens list --column name --column expires`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runList(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.ApiUrl, "api-url", "", "API endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Output format: table, json, csv (default: table)")
+	cmd.Flags().BoolVar(&flags.SkipHeader, "skip-header", false, "Omit the header row from table and CSV output")
+	cmd.Flags().StringArrayVar(&flags.Column, "column", nil, "Column to display (alias or attribute key; repeatable; table and CSV only)")
+
+	return cmd
+}
+
+func runList(cmd *cobra.Command, flags *listFlags) error {
+	ctx := cmd.Context()
+
+	if err := output.ValidateSkipHeader(flags.SkipHeader, flags.Output); err != nil {
+		return err
+	}
+
+	if err := query.ValidateColumnFormat(flags.Column, flags.Output); err != nil {
+		return err
+	}
+
+	format, err := parseListFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+
+	cols, err := resolveListColumns(flags.Column)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewClientFromContext(ctx, flags.ApiUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	iter := apiClient.ListAuthTokensIter(ctx)
+
+	var items []*dash0api.AuthTokensListItem
+	for iter.Next() {
+		items = append(items, iter.Current())
+	}
+	if err := iter.Err(); err != nil {
+		return client.HandleAPIError(err, client.ErrorContext{AssetType: "auth token"})
+	}
+
+	switch format {
+	case listFormatJSON:
+		return renderAuthTokensJSON(items)
+	case listFormatTable:
+		return renderAuthTokensTable(items, cols, flags.SkipHeader)
+	case listFormatCSV:
+		return renderAuthTokensCSV(items, cols, flags.SkipHeader)
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+func resolveListColumns(columns []string) ([]query.ColumnDef, error) {
+	if len(columns) == 0 {
+		return authTokenListDefaultColumns, nil
+	}
+	specs, err := query.ParseColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+	return query.ResolveColumns(specs, authTokenListDefaultColumns), nil
+}
+
+func authTokenValues(item *dash0api.AuthTokensListItem) map[string]string {
+	datasets := "all"
+	if item.Datasets != nil && len(*item.Datasets) > 0 {
+		datasets = strings.Join(*item.Datasets, ",")
+	}
+	return map[string]string{
+		"name":      item.Name,
+		"id":        item.Id,
+		"scopes":    strings.Join(item.Scopes, ","),
+		"datasets":  datasets,
+		"expires":   formatTimestamp(item.ExpiresAt),
+		"last used": formatTimestamp(item.LastUsedAt),
+	}
+}
+
+// formatTimestamp formats an optional timestamp of an auth token; a token
+// without one never expires, or was never used.
+func formatTimestamp(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "never"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func renderAuthTokensJSON(items []*dash0api.AuthTokensListItem) error {
+	if items == nil {
+		items = []*dash0api.AuthTokensListItem{}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+func renderAuthTokensTable(items []*dash0api.AuthTokensListItem, cols []query.ColumnDef, skipHeader bool) error {
+	if len(items) == 0 {
+		fmt.Println("No auth tokens found.")
+		return nil
+	}
+	var rows []map[string]string
+	for _, item := range items {
+		rows = append(rows, authTokenValues(item))
+	}
+	query.RenderTable(os.Stdout, cols, rows, skipHeader)
+	return nil
+}
+
+func renderAuthTokensCSV(items []*dash0api.AuthTokensListItem, cols []query.ColumnDef, skipHeader bool) error {
+	w := csv.NewWriter(os.Stdout)
+	if !skipHeader {
+		if err := query.WriteCSVHeader(w, cols); err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		values := authTokenValues(item)
+		if err := query.WriteCSVRow(w, cols, values); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
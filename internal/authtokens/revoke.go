@@ -0,0 +1,145 @@
+package authtokens
+
+import (
+	"context"
+	"fmt"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/confirmation"
+	"github.com/spf13/cobra"
+)
+
+type revokeFlags struct {
+	ApiUrl    string
+	AuthToken string
+	Force     bool
+}
+
+func newRevokeCmd() *cobra.Command {
+	flags := &revokeFlags{}
+
+	cmd := &cobra.Command{
+		Use:     "revoke <id-or-name> [<id-or-name>...]",
+		Aliases: []string{"delete", "remove"},
+		Short:   "Revoke auth tokens",
+		Long: `Revoke one or more auth tokens by ID or name. Requests authenticated with a
+revoked token are rejected immediately. A name must identify exactly one token.
+Use --force to skip the confirmation prompt.` + internal.CONFIG_HINT,
+		Example: `  # Revoke with confirmation prompt
+  dash0 auth-tokens revoke ci
+
+  # Revoke several tokens without confirmation (for scripts and automation)
+  dash0 auth-tokens revoke <id> <id> --force`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRevoke(cmd, args, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.ApiUrl, "api-url", "", "API endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().BoolVar(&flags.Force, "force", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runRevoke(cmd *cobra.Command, args []string, flags *revokeFlags) error {
+	ctx := cmd.Context()
+
+	noun := "auth token"
+	if len(args) > 1 {
+		noun = "auth tokens"
+	}
+	confirmed, err := confirmation.ConfirmDestructiveOperation(
+		ctx,
+		fmt.Sprintf("Are you sure you want to revoke %d %s? [y/N]: ", len(args), noun),
+		flags.Force,
+	)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Revocation cancelled")
+		return nil
+	}
+
+	apiClient, err := client.NewClientFromContext(ctx, flags.ApiUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := resolveAuthTokens(ctx, apiClient, args)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range resolved {
+		err = apiClient.DeleteAuthToken(ctx, token.ID)
+		if err != nil {
+			ectx := client.ErrorContext{
+				AssetType: "auth token",
+				AssetID:   token.ID,
+				AssetName: token.Name,
+			}
+			if client.IsAlreadyDeleted(err, flags.Force, ectx) {
+				continue
+			}
+			return client.HandleAPIError(err, ectx)
+		}
+		fmt.Printf("Auth token %s revoked\n", token.displayString())
+	}
+	return nil
+}
+
+// resolvedAuthToken holds the ID and, when known, the name of an auth token
+// to revoke.
+type resolvedAuthToken struct {
+	ID   string
+	Name string
+}
+
+// displayString returns `"name" (id)` when the name is known, or just the ID
+// otherwise.
+func (r resolvedAuthToken) displayString() string {
+	if r.Name != "" {
+		return fmt.Sprintf("%q (%s)", r.Name, r.ID)
+	}
+	return r.ID
+}
+
+// resolveAuthTokens resolves a mix of auth token IDs and names to IDs via the
+// auth token list API. An argument that matches neither an ID nor a name is
+// passed on as an ID, so revoking a token that is already gone reports it
+// like any other delete.
+func resolveAuthTokens(ctx context.Context, apiClient dash0api.Client, args []string) ([]resolvedAuthToken, error) {
+	byID := make(map[string]string)
+	byName := make(map[string][]string)
+	iter := apiClient.ListAuthTokensIter(ctx)
+	for iter.Next() {
+		item := iter.Current()
+		byID[item.Id] = item.Name
+		byName[item.Name] = append(byName[item.Name], item.Id)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list auth tokens: %w", err)
+	}
+
+	resolved := make([]resolvedAuthToken, 0, len(args))
+	for _, arg := range args {
+		if name, ok := byID[arg]; ok {
+			resolved = append(resolved, resolvedAuthToken{ID: arg, Name: name})
+			continue
+		}
+		switch ids := byName[arg]; len(ids) {
+		case 0:
+			resolved = append(resolved, resolvedAuthToken{ID: arg})
+		case 1:
+			resolved = append(resolved, resolvedAuthToken{ID: ids[0], Name: arg})
+		default:
+			return nil, fmt.Errorf("%d auth tokens are named %q; revoke them by ID instead (see 'dash0 auth-tokens list')", len(ids), arg)
+		}
+	}
+	return resolved, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	"github.com/dash0hq/dash0-api-client-go/profiles"
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/config"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
 	"github.com/dash0hq/dash0-cli/internal/version"
 )
 
@@ -35,6 +38,9 @@ func NewClientFromContext(ctx context.Context, apiUrl, authToken string) (dash0a
 		apiUrl = resolved.ApiUrl
 		authToken = resolved.AuthToken
 	} else {
+		if err := prepareCredentials(ctx, cfg, authToken); err != nil {
+			return nil, err
+		}
 		// Apply flag overrides on top of context configuration
 		if apiUrl == "" {
 			apiUrl = cfg.ApiUrl
@@ -53,12 +59,19 @@ func NewClientFromContext(ctx context.Context, apiUrl, authToken string) (dash0a
 		return nil, err
 	}
 
-	client, err := dash0api.NewClient(
+	base, err := httptransport.RoundTripper(ctx)
+	if err != nil {
+		return nil, err
+	}
+	authOpts, err := authOptions(ctx, cfg, authToken, base)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dash0api.NewClient(append([]dash0api.ClientOption{
 		dash0api.WithApiUrl(apiUrl),
-		authTokenOption(cfg, authToken),
 		dash0api.WithUserAgent(version.UserAgent()),
 		dash0api.WithMaxRetries(maxRetries),
-	)
+	}, authOpts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
@@ -70,6 +83,9 @@ func NewClientFromContext(ctx context.Context, apiUrl, authToken string) (dash0a
 // Flag overrides (otlpUrl, authToken) are applied on top of the context configuration.
 func NewOtlpClientFromContext(ctx context.Context, otlpUrl, authToken string) (dash0api.Client, error) {
 	cfg := profiles.FromContext(ctx)
+	if err := prepareCredentials(ctx, cfg, authToken); err != nil {
+		return nil, err
+	}
 
 	var finalOtlpUrl, finalAuthToken string
 	if cfg != nil {
@@ -88,14 +104,11 @@ func NewOtlpClientFromContext(ctx context.Context, otlpUrl, authToken string) (d
 	if err := checkOAuthEmpty(ctx, cfg, finalAuthToken); err != nil {
 		return nil, err
 	}
-	if err := checkOAuthOnOtlp(ctx, cfg, finalAuthToken); err != nil {
-		return nil, err
-	}
 
 	if finalOtlpUrl == "" {
 		return nil, fmt.Errorf("otlp-url is required; provide it as a flag, environment variable, or configure a profile")
 	}
-	if finalAuthToken == "" {
+	if finalAuthToken == "" && authTokenCommand(cfg, finalAuthToken) == nil {
 		return nil, fmt.Errorf("auth-token is required; provide it as a flag, environment variable, or configure a profile")
 	}
 
@@ -103,17 +116,41 @@ func NewOtlpClientFromContext(ctx context.Context, otlpUrl, authToken string) (d
 	if err != nil {
 		return nil, err
 	}
+	transport, err := resolveOtlpTransport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := httptransport.FromContext(ctx)
+	base, err := settings.RoundTripper()
+	if err != nil {
+		return nil, err
+	}
 
-	client, err := dash0api.NewClient(
-		dash0api.WithOtlpEndpoint(dash0api.OtlpEncodingJson, finalOtlpUrl),
-		authTokenOption(cfg, finalAuthToken),
+	authOpts, err := otlpAuthOptions(ctx, cfg, finalAuthToken, finalOtlpUrl, transport.roundTripper(base))
+	if err != nil {
+		return nil, err
+	}
+	client, err := dash0api.NewClient(append([]dash0api.ClientOption{
+		dash0api.WithOtlpEndpoint(transport.encoding(), finalOtlpUrl),
 		dash0api.WithUserAgent(version.UserAgent()),
 		dash0api.WithMaxRetries(maxRetries),
-	)
+	}, authOpts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OTLP client: %w", err)
 	}
 
+	if transport.protocol == OtlpProtocolGRPC {
+		tokens, err := otlpTokenSource(ctx, cfg, finalAuthToken, finalOtlpUrl)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig, err := settings.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		return newGrpcOtlpClient(client, tokens, finalOtlpUrl, transport.compression, maxRetries, tlsConfig)
+	}
 	return client, nil
 }
 
@@ -211,10 +248,10 @@ func profileNameForUpdate(_ context.Context, displayName string) string {
 // OAuth state for this invocation, via DASH0_AUTH_TOKEN or --auth-token. When
 // one does, the static token is used as-is and no refresh is attempted.
 //
-// checkOAuthEmpty and checkOAuthOnOtlp test the same two conditions inline.
+// checkOAuthEmpty tests the same two conditions inline.
 //
 // TODO(dash0-api-client-go #39): once Configuration records each field's source,
-// this and both inline copies collapse into one check.
+// this and the inline copy collapse into one check.
 func oauthShadowed(cfg *profiles.Configuration, resolvedAuthToken string) bool {
 	if cfg == nil || cfg.OAuth == nil {
 		return false
@@ -234,13 +271,66 @@ func oauthShadowed(cfg *profiles.Configuration, resolvedAuthToken string) bool {
 // so a token frozen at construction breaks any command that outlives it.
 // Anything else authenticates with the resolved token directly, because a
 // static auth_* token does not expire.
-func authTokenOption(cfg *profiles.Configuration, resolvedAuthToken string) dash0api.ClientOption {
+func authTokenOption(ctx context.Context, cfg *profiles.Configuration, resolvedAuthToken string) dash0api.ClientOption {
 	if cfg != nil && cfg.OAuth != nil && !oauthShadowed(cfg, resolvedAuthToken) {
-		return dash0api.WithAuthTokenProvider(cfg.AuthTokenProvider())
+		return dash0api.WithAuthTokenProvider(oauthTokenProvider(ctx, cfg))
 	}
 	return dash0api.WithAuthToken(resolvedAuthToken)
 }
 
+// authOptions returns the client options that authenticate requests. A
+// profile with an auth token command (see `config profiles create
+// --auth-token-command`) gets a provider that runs the command, and an HTTP
+// client that runs it again when the API rejects the token it printed, unless
+// a token from --auth-token or DASH0_AUTH_TOKEN takes precedence. Anything
+// else is left to authTokenOption. Requests go through base.
+func authOptions(ctx context.Context, cfg *profiles.Configuration, resolvedAuthToken string, base http.RoundTripper) ([]dash0api.ClientOption, error) {
+	if provider := commandProviderFor(cfg, resolvedAuthToken); provider != nil {
+		return []dash0api.ClientOption{
+			dash0api.WithAuthTokenProvider(provider),
+			dash0api.WithHTTPClient(newReauthHTTPClient(base, provider)),
+		}, nil
+	}
+	opts := []dash0api.ClientOption{authTokenOption(ctx, cfg, resolvedAuthToken)}
+	if base != http.DefaultTransport {
+		opts = append(opts, dash0api.WithHTTPClient(&http.Client{Transport: base}))
+	}
+	return opts, nil
+}
+
+// otlpAuthOptions returns the client options that authenticate OTLP exports.
+// The OTLP ingress does not accept OAuth access tokens, so an OAuth profile
+// authenticates with ingestion tokens exchanged for its access token, and an
+// HTTP client that exchanges a new one when the ingress rejects it. Anything
+// else authenticates like an API client.
+func otlpAuthOptions(ctx context.Context, cfg *profiles.Configuration, resolvedAuthToken, otlpUrl string, base http.RoundTripper) ([]dash0api.ClientOption, error) {
+	if cfg != nil && cfg.OAuth != nil && !oauthShadowed(cfg, resolvedAuthToken) {
+		provider := newIngestionTokenProvider(ctx, cfg, otlpUrl)
+		return []dash0api.ClientOption{
+			dash0api.WithAuthTokenProvider(provider),
+			dash0api.WithHTTPClient(newReauthHTTPClient(base, provider)),
+		}, nil
+	}
+	return authOptions(ctx, cfg, resolvedAuthToken, base)
+}
+
+// prepareCredentials refreshes the access token of the OAuth profile that cfg
+// was resolved from, when its refresh token is kept in a credential store
+// (see [credentials.Prepare]). flagAuthToken is the --auth-token value; a
+// static token that shadows the OAuth state needs no refresh.
+func prepareCredentials(ctx context.Context, cfg *profiles.Configuration, flagAuthToken string) error {
+	if cfg == nil || cfg.OAuth == nil || oauthShadowed(cfg, flagAuthToken) {
+		return nil
+	}
+	return credentials.Prepare(ctx, config.ProfileSelectorFromContext(ctx).Name, cfg)
+}
+
+// oauthTokenProvider returns the access token provider of the OAuth profile
+// that cfg was resolved from; see [credentials.TokenProvider].
+func oauthTokenProvider(ctx context.Context, cfg *profiles.Configuration) *credentials.TokenProvider {
+	return credentials.TokenProviderFor(config.ProfileSelectorFromContext(ctx).Name, cfg)
+}
+
 // checkOAuthEmpty surfaces a friendly "not authenticated" error when the
 // resolved profile is OAuth-typed but has no refresh token (i.e. nobody
 // has run `dash0 login` against it yet, or the user has just logged out).
@@ -290,59 +380,6 @@ func checkOAuthEmpty(ctx context.Context, cfg *profiles.Configuration, resolvedA
 	)
 }
 
-// checkOAuthOnOtlp refuses to construct an OTLP client whose auth token
-// would be an OAuth-issued access token. The Dash0 OTLP ingress does not
-// (yet) accept OAuth access tokens — it only honors static `auth_*`
-// tokens — so a `dash0 logs send` or `dash0 spans send` driven from an
-// OAuth profile would otherwise emit a noisy 401 from the server with no
-// hint at the actual root cause. Fail upfront with a copy-pasteable
-// recovery path instead.
-//
-// Mirrors checkOAuthEmpty's escape-hatch rules: an explicit
-// DASH0_AUTH_TOKEN env var or a per-command --auth-token flag override
-// shadows the OAuth state and is trusted as-is. That keeps the
-// "set DASH0_AUTH_TOKEN to a static auth_* token" workaround working
-// without forcing the user to convert the whole profile.
-func checkOAuthOnOtlp(ctx context.Context, cfg *profiles.Configuration, resolvedAuthToken string) error {
-	if cfg == nil || cfg.OAuth == nil {
-		return nil // not an OAuth profile
-	}
-	// Env-var override: trust the static token shadow.
-	if os.Getenv(profiles.EnvAuthToken) != "" {
-		return nil
-	}
-	// Flag override: the resolved token differs from what the profile
-	// would have supplied, so the user passed --auth-token explicitly.
-	if resolvedAuthToken != "" && cfg.AuthToken != resolvedAuthToken {
-		return nil
-	}
-
-	selector := config.ProfileSelectorFromContext(ctx)
-	displayName := selector.Name
-
-	// OTLP-bound commands are routinely run from CI / agent contexts, so
-	// surface the static-token fallback (env var or `--auth-token`)
-	// front-and-center in both modes; the agent-mode branch additionally
-	// names the per-profile conversion command since `dash0 login` is
-	// off-limits there.
-	if agentmode.Enabled {
-		return fmt.Errorf(
-			"OTLP ingestion does not accept OAuth access tokens; %s is OAuth-typed.\n"+
-				"Hint: Set DASH0_AUTH_TOKEN to a static `auth_*` token for this invocation, "+
-				"or convert the profile with `dash0 config profiles update %s --oauth=false --auth-token auth_<...> --force`.",
-			config.ProfileDisplayName(displayName),
-			profileNameForUpdate(ctx, displayName),
-		)
-	}
-	return fmt.Errorf(
-		"OTLP ingestion does not accept OAuth access tokens; %s is OAuth-typed.\n"+
-			"Hint: Pass `--auth-token auth_<...>` for this invocation, set DASH0_AUTH_TOKEN, "+
-			"or convert the profile with `dash0 config profiles update %s --oauth=false --auth-token auth_<...> --force`.",
-		config.ProfileDisplayName(displayName),
-		profileNameForUpdate(ctx, displayName),
-	)
-}
-
 type maxRetriesCmdKey struct{}
 
 // WithMaxRetries stores the --max-retries flag value in the context.
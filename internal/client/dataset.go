@@ -6,17 +6,21 @@ import (
 
 	dash0api "github.com/dash0hq/dash0-api-client-go"
 	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/config"
 )
 
 // ResolveDataset returns the dataset to use for API calls, checking (in order):
 // 1. The CLI flag value (flagDataset)
-// 2. The configuration from context (profile + DASH0_DATASET env var)
-// 3. The DASH0_DATASET env var, then a freshly resolved configuration, when
+// 2. The dataset pinned by the project configuration file (.dash0.yaml),
+// unless DASH0_DATASET is set or the profile was chosen with --profile or
+// DASH0_PROFILE
+// 3. The configuration from context (profile + DASH0_DATASET env var)
+// 4. The DASH0_DATASET env var, then a freshly resolved configuration, when
 // the context carries no configuration at all
 // Returns nil when no dataset is configured or when the dataset is "default",
 // since the API uses "default" implicitly when no dataset parameter is sent.
 //
-// Step 3 exists because [NewClientFromContext] falls back to
+// Step 4 exists because [NewClientFromContext] falls back to
 // [profiles.ResolveConfiguration] when the context configuration is missing.
 // Without the same fallback here, a command whose config failed to load would
 // still reach the API with credentials recovered from flags or env vars, but
@@ -26,6 +30,9 @@ func ResolveDataset(ctx context.Context, flagDataset string) *string {
 	if flagDataset != "" {
 		return dash0api.DatasetPtr(flagDataset)
 	}
+	if projectDataset := config.ProjectDataset(ctx); projectDataset != "" {
+		return dash0api.DatasetPtr(projectDataset)
+	}
 	if cfg := profiles.FromContext(ctx); cfg != nil {
 		if cfg.Dataset != "" {
 			return dash0api.DatasetPtr(cfg.Dataset)
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/oauth"
+)
+
+// OAuth 2.0 Token Exchange (RFC 8693) parameters. The OTLP ingress does not
+// accept the OAuth access tokens `dash0 login` obtains, so OTLP commands run
+// from an OAuth profile trade the access token for a short-lived ingestion
+// token scoped to the OTLP endpoint.
+const (
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	accessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// ingestionTokenRefreshMargin is how long before its expiry an ingestion
+// token is exchanged again, so a request never goes out with a token that
+// expires in flight.
+const ingestionTokenRefreshMargin = time.Minute
+
+// authTokenSource supplies the OAuth access token of a profile, refreshing
+// it as needed. It is satisfied by credentials.TokenProvider, which keeps the
+// rotated refresh token of a profile sealed.
+type authTokenSource interface {
+	AuthToken(ctx context.Context) (string, error)
+}
+
+// ingestionTokenProvider supplies ingestion tokens for an OAuth profile,
+// exchanging the access token of the profile again shortly before the
+// current ingestion token expires.
+type ingestionTokenProvider struct {
+	apiURL   string
+	otlpURL  string
+	clientID string
+	source   authTokenSource
+	// profile selects the TLS settings of the exchange, which runs with the
+	// context of whichever request needs a token.
+	profile *profiles.Configuration
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// newIngestionTokenProvider returns the provider for an OAuth profile that
+// sends telemetry to otlpURL.
+func newIngestionTokenProvider(ctx context.Context, cfg *profiles.Configuration, otlpURL string) *ingestionTokenProvider {
+	return &ingestionTokenProvider{
+		apiURL:   cfg.ApiUrl,
+		otlpURL:  otlpURL,
+		clientID: cfg.OAuth.ClientID,
+		source:   oauthTokenProvider(ctx, cfg),
+		profile:  cfg,
+	}
+}
+
+// AuthToken returns the cached ingestion token, exchanging a fresh access
+// token for a new one when there is none or it is about to expire.
+func (p *ingestionTokenProvider) AuthToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Until(p.expiresAt) > ingestionTokenRefreshMargin {
+		return p.token, nil
+	}
+	accessToken, err := p.source.AuthToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	token, expiresIn, err := exchangeIngestionToken(profiles.WithConfiguration(ctx, p.profile), p.apiURL, p.otlpURL, p.clientID, accessToken)
+	if err != nil {
+		return "", err
+	}
+	p.token, p.expiresAt = token, time.Now().Add(expiresIn)
+	return token, nil
+}
+
+// invalidate drops the cached token if it is still the rejected one, so the
+// next AuthToken call exchanges a new one.
+func (p *ingestionTokenProvider) invalidate(rejected string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == rejected {
+		p.token = ""
+	}
+}
+
+// tokenExchangeResponse is the response of the token endpoint to a token
+// exchange request (RFC 8693 §2.2), or an OAuth error (RFC 6749 §5.2).
+type tokenExchangeResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchangeIngestionToken trades an OAuth access token for an ingestion token
+// for otlpURL at the token endpoint of the authorization server of apiURL.
+func exchangeIngestionToken(ctx context.Context, apiURL, otlpURL, clientID, accessToken string) (string, time.Duration, error) {
+	tokenEndpoint, err := discoverTokenEndpoint(ctx, apiURL)
+	if err != nil {
+		return "", 0, err
+	}
+
+	form := url.Values{
+		"grant_type":         {tokenExchangeGrantType},
+		"subject_token":      {accessToken},
+		"subject_token_type": {accessTokenType},
+		"resource":           {otlpURL},
+	}
+	if clientID != "" {
+		form.Set("client_id", clientID)
+	}
+	var resp tokenExchangeResponse
+	status, err := oauth.Request(ctx, http.MethodPost, tokenEndpoint, form, &resp)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to obtain an OTLP ingestion token: %w", err)
+	}
+	if resp.Error == "unsupported_grant_type" {
+		return "", 0, fmt.Errorf("the authorization server at %s does not issue OTLP ingestion tokens.\nHint: Set DASH0_AUTH_TOKEN or pass --auth-token with a static `auth_*` token for this invocation.", apiURL)
+	}
+	if resp.Error != "" {
+		code := oauth.SanitizeASText(resp.Error)
+		if desc := oauth.SanitizeASText(resp.ErrorDescription); desc != "" {
+			return "", 0, fmt.Errorf("failed to obtain an OTLP ingestion token: %s: %s", code, desc)
+		}
+		return "", 0, fmt.Errorf("failed to obtain an OTLP ingestion token: %s", code)
+	}
+	if status != http.StatusOK {
+		return "", 0, fmt.Errorf("failed to obtain an OTLP ingestion token: unexpected status %d", status)
+	}
+	if resp.AccessToken == "" {
+		return "", 0, errors.New("failed to obtain an OTLP ingestion token: the authorization server returned an empty token")
+	}
+	if resp.ExpiresIn <= 0 {
+		// Without a lifetime, reuse the token for as long as an access
+		// token lives and rely on the 401 retry beyond that.
+		resp.ExpiresIn = int64((15 * time.Minute).Seconds())
+	}
+	return resp.AccessToken, time.Duration(resp.ExpiresIn) * time.Second, nil
+}
+
+// discoverTokenEndpoint reads the token endpoint from the authorization
+// server metadata (RFC 8414) of apiURL.
+func discoverTokenEndpoint(ctx context.Context, apiURL string) (string, error) {
+	var metadata struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	status, err := oauth.Request(ctx, http.MethodGet, oauth.MetadataURL(apiURL), nil, &metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover OAuth metadata from %s: %w", apiURL, err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("failed to discover OAuth metadata from %s: unexpected status %d", apiURL, status)
+	}
+	if metadata.TokenEndpoint == "" {
+		return "", fmt.Errorf("the authorization server at %s does not advertise a token endpoint", apiURL)
+	}
+	return metadata.TokenEndpoint, nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticAccessToken is an authTokenSource that returns a fixed OAuth access
+// token.
+type staticAccessToken string
+
+func (s staticAccessToken) AuthToken(context.Context) (string, error) { return string(s), nil }
+
+// newTokenExchangeServer serves the authorization server metadata and a token
+// endpoint that issues "ingest-<n>" on its n-th token exchange.
+func newTokenExchangeServer(t *testing.T, exchanges *atomic.Int32) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": server.URL + "/oauth/token"})
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, tokenExchangeGrantType, r.PostForm.Get("grant_type"))
+		assert.Equal(t, "at_access", r.PostForm.Get("subject_token"))
+		assert.Equal(t, accessTokenType, r.PostForm.Get("subject_token_type"))
+		assert.Equal(t, "https://ingress.example.com", r.PostForm.Get("resource"))
+		assert.Equal(t, "client-abc", r.PostForm.Get("client_id"))
+		n := exchanges.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":      fmt.Sprintf("ingest-%d", n),
+			"issued_token_type": accessTokenType,
+			"token_type":        "Bearer",
+			"expires_in":        900,
+		})
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIngestionTokenProvider_ExchangesAndCaches(t *testing.T) {
+	var exchanges atomic.Int32
+	server := newTokenExchangeServer(t, &exchanges)
+	provider := &ingestionTokenProvider{
+		apiURL:   server.URL,
+		otlpURL:  "https://ingress.example.com",
+		clientID: "client-abc",
+		source:   staticAccessToken("at_access"),
+	}
+
+	first, err := provider.AuthToken(context.Background())
+	require.NoError(t, err)
+	second, err := provider.AuthToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ingest-1", first)
+	assert.Equal(t, "ingest-1", second, "the ingestion token should be reused until shortly before it expires")
+	assert.Equal(t, int32(1), exchanges.Load())
+
+	provider.invalidate("ingest-1")
+	third, err := provider.AuthToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ingest-2", third)
+}
+
+func TestIngestionTokenProvider_UnsupportedGrant(t *testing.T) {
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token_endpoint": server.URL + "/oauth/token"})
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "unsupported_grant_type"})
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := &ingestionTokenProvider{
+		apiURL:  server.URL,
+		otlpURL: "https://ingress.example.com",
+		source:  staticAccessToken("at_access"),
+	}
+	_, err := provider.AuthToken(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not issue OTLP ingestion tokens")
+	assert.Contains(t, err.Error(), "DASH0_AUTH_TOKEN")
+}
+
+// TestNewIngestionTokenProvider_SharesProfileTokenProvider verifies that the
+// access tokens exchanged for ingestion tokens come from the provider that
+// keeps the rotated refresh token of the profile sealed, not from the bare
+// provider of the configuration.
+func TestNewIngestionTokenProvider_SharesProfileTokenProvider(t *testing.T) {
+	cfg := &profiles.Configuration{
+		ApiUrl:    "https://api.example.com",
+		AuthToken: "at_access",
+		OAuth:     &profiles.OAuthState{ClientID: "client-abc"},
+	}
+	provider := newIngestionTokenProvider(context.Background(), cfg, "https://ingress.example.com")
+
+	assert.Same(t, credentials.TokenProviderFor("", cfg), provider.source)
+}
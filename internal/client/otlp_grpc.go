@@ -0,0 +1,306 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/version"
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpccredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultOtlpGrpcPort is the port of the OTLP/gRPC endpoint when the OTLP URL
+// does not name one. The Dash0 ingress serves OTLP/gRPC on the host of its
+// OTLP/HTTP endpoint, on port 4317.
+const defaultOtlpGrpcPort = "4317"
+
+func init() {
+	// The collector registers a zstd compressor when its gRPC config package
+	// is linked in; register one otherwise.
+	if encoding.GetCompressor(OtlpCompressionZstd) == nil {
+		encoding.RegisterCompressor(zstdCompressor{})
+	}
+}
+
+// grpcOtlpClient exports telemetry over OTLP/gRPC. It embeds the OTLP/HTTP
+// client for the rest of the dash0api.Client interface.
+type grpcOtlpClient struct {
+	dash0api.Client
+	conn     *grpc.ClientConn
+	tokens   tokenSource
+	callOpts []grpc.CallOption
+	logs     plogotlp.GRPCClient
+	traces   ptraceotlp.GRPCClient
+	metrics  pmetricotlp.GRPCClient
+}
+
+// tokenSource supplies the bearer token of each export.
+type tokenSource interface {
+	AuthToken(ctx context.Context) (string, error)
+}
+
+// staticToken is a token that never changes, such as an auth_* token.
+type staticToken string
+
+func (t staticToken) AuthToken(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// otlpTokenSource picks the token source of OTLP/gRPC exports the way
+// otlpAuthOptions picks the authentication of OTLP/HTTP exports.
+func otlpTokenSource(ctx context.Context, cfg *profiles.Configuration, resolvedAuthToken, otlpUrl string) (tokenSource, error) {
+	if cfg != nil && cfg.OAuth != nil && !oauthShadowed(cfg, resolvedAuthToken) {
+		return newIngestionTokenProvider(ctx, cfg, otlpUrl), nil
+	}
+	if provider := commandProviderFor(cfg, resolvedAuthToken); provider != nil {
+		return provider, nil
+	}
+	return staticToken(resolvedAuthToken), nil
+}
+
+// newGrpcOtlpClient connects to the OTLP/gRPC endpoint derived from otlpUrl,
+// with tlsConfig for https URLs; nil means the system CA certificates. The
+// connection is established lazily, on the first export. Like the OTLP/HTTP
+// client, it connects through the proxy that HTTPS_PROXY names.
+func newGrpcOtlpClient(httpClient dash0api.Client, tokens tokenSource, otlpUrl, compression string, maxRetries int, tlsConfig *tls.Config) (*grpcOtlpClient, error) {
+	target, useTLS, err := grpcTarget(otlpUrl)
+	if err != nil {
+		return nil, err
+	}
+	creds := insecure.NewCredentials()
+	if useTLS {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = grpccredentials.NewTLS(tlsConfig)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent(version.UserAgent()),
+	}
+	if maxRetries > 0 {
+		opts = append(opts, grpc.WithDefaultServiceConfig(grpcRetryServiceConfig(maxRetries)))
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP/gRPC client for %s: %w", target, err)
+	}
+
+	var callOpts []grpc.CallOption
+	if compression != OtlpCompressionNone {
+		callOpts = append(callOpts, grpc.UseCompressor(compression))
+	}
+	return &grpcOtlpClient{
+		Client:   httpClient,
+		conn:     conn,
+		tokens:   tokens,
+		callOpts: callOpts,
+		logs:     plogotlp.NewGRPCClient(conn),
+		traces:   ptraceotlp.NewGRPCClient(conn),
+		metrics:  pmetricotlp.NewGRPCClient(conn),
+	}, nil
+}
+
+// grpcTarget derives the gRPC target from an OTLP URL: its host, and its
+// port or 4317. https URLs connect with TLS, http URLs in plaintext.
+func grpcTarget(otlpUrl string) (string, bool, error) {
+	u, err := url.Parse(otlpUrl)
+	if err != nil || u.Hostname() == "" {
+		return "", false, fmt.Errorf("invalid otlp-url %q for OTLP/gRPC: must be an http:// or https:// URL", otlpUrl)
+	}
+	var useTLS bool
+	switch u.Scheme {
+	case "https":
+		useTLS = true
+	case "http":
+	default:
+		return "", false, fmt.Errorf("invalid otlp-url %q for OTLP/gRPC: must be an http:// or https:// URL", otlpUrl)
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultOtlpGrpcPort
+	}
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
+
+// grpcRetryServiceConfig retries exports that failed with UNAVAILABLE or
+// RESOURCE_EXHAUSTED up to maxRetries times, like the OTLP/HTTP client
+// retries 503 and 429 responses.
+func grpcRetryServiceConfig(maxRetries int) string {
+	return fmt.Sprintf(`{"methodConfig": [{
+  "name": [
+    {"service": "opentelemetry.proto.collector.logs.v1.LogsService"},
+    {"service": "opentelemetry.proto.collector.trace.v1.TraceService"},
+    {"service": "opentelemetry.proto.collector.metrics.v1.MetricsService"}
+  ],
+  "retryPolicy": {
+    "maxAttempts": %d,
+    "initialBackoff": "%gs",
+    "maxBackoff": "%gs",
+    "backoffMultiplier": 2,
+    "retryableStatusCodes": ["UNAVAILABLE", "RESOURCE_EXHAUSTED"]
+  }
+}]}`, maxRetries+1, retryInitialBackoff.Seconds(), retryMaxBackoff.Seconds())
+}
+
+func (c *grpcOtlpClient) SendLogs(ctx context.Context, logs plog.Logs, dataset *string) error {
+	req := plogotlp.NewExportRequestFromLogs(logs)
+	return c.export(ctx, dataset, func(ctx context.Context) error {
+		_, err := c.logs.Export(ctx, req, c.callOpts...)
+		return err
+	})
+}
+
+func (c *grpcOtlpClient) SendTraces(ctx context.Context, traces ptrace.Traces, dataset *string) error {
+	req := ptraceotlp.NewExportRequestFromTraces(traces)
+	return c.export(ctx, dataset, func(ctx context.Context) error {
+		_, err := c.traces.Export(ctx, req, c.callOpts...)
+		return err
+	})
+}
+
+func (c *grpcOtlpClient) SendMetrics(ctx context.Context, metrics pmetric.Metrics, dataset *string) error {
+	req := pmetricotlp.NewExportRequestFromMetrics(metrics)
+	return c.export(ctx, dataset, func(ctx context.Context) error {
+		_, err := c.metrics.Export(ctx, req, c.callOpts...)
+		return err
+	})
+}
+
+// Close closes the gRPC connection and the embedded OTLP/HTTP client.
+func (c *grpcOtlpClient) Close(ctx context.Context) error {
+	connErr := c.conn.Close()
+	if err := c.Client.Close(ctx); err != nil {
+		return err
+	}
+	return connErr
+}
+
+// export runs one export call with the token and dataset as request
+// metadata. A call rejected with UNAUTHENTICATED is retried once with a
+// fresh token, like reauthTransport does for OTLP/HTTP.
+func (c *grpcOtlpClient) export(ctx context.Context, dataset *string, call func(context.Context) error) error {
+	token, err := c.tokens.AuthToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = call(outgoingContext(ctx, token, dataset))
+	if status.Code(err) == codes.Unauthenticated {
+		if provider, ok := c.tokens.(reauthProvider); ok {
+			provider.invalidate(token)
+			if fresh, tokenErr := provider.AuthToken(ctx); tokenErr == nil && fresh != token {
+				err = call(outgoingContext(ctx, fresh, dataset))
+			}
+		}
+	}
+	return grpcExportError(err)
+}
+
+func outgoingContext(ctx context.Context, token string, dataset *string) context.Context {
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	if dataset != nil {
+		md.Set("dash0-dataset", *dataset)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// grpcHTTPStatus maps the gRPC status codes of failed exports to the HTTP
+// status codes an OTLP/HTTP endpoint answers with in the same situation.
+var grpcHTTPStatus = map[codes.Code]int{
+	codes.InvalidArgument:   http.StatusBadRequest,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+	codes.PermissionDenied:  http.StatusForbidden,
+	codes.NotFound:          http.StatusNotFound,
+	codes.ResourceExhausted: http.StatusTooManyRequests,
+	codes.Internal:          http.StatusInternalServerError,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+}
+
+// grpcExportError converts the status of a failed export to an
+// *dash0api.APIError, so HandleAPIError and the proxy classify it like a
+// failed OTLP/HTTP export.
+func grpcExportError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	statusCode, ok := grpcHTTPStatus[st.Code()]
+	if !ok {
+		return err
+	}
+	return &dash0api.APIError{
+		StatusCode: statusCode,
+		Status:     st.Code().String(),
+		Message:    st.Message(),
+	}
+}
+
+// zstdCompressor implements the gRPC "zstd" compressor.
+type zstdCompressor struct{}
+
+func (zstdCompressor) Name() string {
+	return OtlpCompressionZstd
+}
+
+// zstdEncoders pools the encoders of gRPC messages. An encoder allocates
+// its window buffers up front, which costs more than compressing a typical
+// export, so encoders are reset and reused rather than created per message.
+var zstdEncoders sync.Pool
+
+func (zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	enc, ok := zstdEncoders.Get().(*zstd.Encoder)
+	if !ok {
+		var err error
+		if enc, err = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1)); err != nil {
+			return nil, err
+		}
+	}
+	enc.Reset(w)
+	return &pooledZstdWriter{Encoder: enc}, nil
+}
+
+// pooledZstdWriter returns its encoder to zstdEncoders when it is closed.
+type pooledZstdWriter struct {
+	*zstd.Encoder
+}
+
+func (w *pooledZstdWriter) Close() error {
+	err := w.Encoder.Close()
+	// Drop the destination, which gRPC reuses for other messages.
+	w.Encoder.Reset(nil)
+	zstdEncoders.Put(w.Encoder)
+	return err
+}
+
+func (zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
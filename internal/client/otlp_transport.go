@@ -0,0 +1,205 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/klauspost/compress/zstd"
+)
+
+// OTLP protocols, named like the values of OTEL_EXPORTER_OTLP_PROTOCOL.
+const (
+	OtlpProtocolHTTPJSON     = "http/json"
+	OtlpProtocolHTTPProtobuf = "http/protobuf"
+	OtlpProtocolGRPC         = "grpc"
+)
+
+// OTLP compression algorithms, named like the values of
+// OTEL_EXPORTER_OTLP_COMPRESSION.
+const (
+	OtlpCompressionNone = "none"
+	OtlpCompressionGzip = "gzip"
+	OtlpCompressionZstd = "zstd"
+)
+
+const (
+	envOtelProtocol    = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	envOtelCompression = "OTEL_EXPORTER_OTLP_COMPRESSION"
+)
+
+// otlpTransport is how telemetry is exported to the OTLP endpoint.
+type otlpTransport struct {
+	protocol    string
+	compression string
+}
+
+type otlpTransportFlagsKey struct{}
+
+type otlpTransportFlags struct {
+	protocol    string
+	compression string
+}
+
+// WithOtlpTransport stores the --otlp-protocol and --otlp-compression flag
+// values in the context. Empty values leave the choice to the environment.
+func WithOtlpTransport(ctx context.Context, protocol, compression string) context.Context {
+	return context.WithValue(ctx, otlpTransportFlagsKey{}, otlpTransportFlags{protocol: protocol, compression: compression})
+}
+
+type ignoreOtelExporterEnvKey struct{}
+
+// WithoutOtelExporterEnv makes OTLP clients created with the context ignore
+// OTEL_EXPORTER_OTLP_PROTOCOL and OTEL_EXPORTER_OTLP_COMPRESSION. The proxy
+// reads those variables as the way applications export to it, not as the
+// way it exports to Dash0.
+func WithoutOtelExporterEnv(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ignoreOtelExporterEnvKey{}, true)
+}
+
+// resolveOtlpTransport returns the OTLP protocol and compression. It checks
+// (in order): the --otlp-protocol and --otlp-compression flags from context,
+// the OTEL_EXPORTER_OTLP_PROTOCOL and OTEL_EXPORTER_OTLP_COMPRESSION
+// environment variables, then falls back to uncompressed http/json.
+func resolveOtlpTransport(ctx context.Context) (otlpTransport, error) {
+	flags, _ := ctx.Value(otlpTransportFlagsKey{}).(otlpTransportFlags)
+	useEnv := ctx.Value(ignoreOtelExporterEnvKey{}) == nil
+
+	transport := otlpTransport{protocol: OtlpProtocolHTTPJSON, compression: OtlpCompressionNone}
+	switch {
+	case flags.protocol != "":
+		p, err := validateOtlpProtocol(flags.protocol, "--otlp-protocol flag")
+		if err != nil {
+			return otlpTransport{}, err
+		}
+		transport.protocol = p
+	case useEnv && os.Getenv(envOtelProtocol) != "":
+		p, err := validateOtlpProtocol(os.Getenv(envOtelProtocol), envOtelProtocol)
+		if err != nil {
+			return otlpTransport{}, err
+		}
+		transport.protocol = p
+	}
+	switch {
+	case flags.compression != "":
+		c, err := validateOtlpCompression(flags.compression, "--otlp-compression flag")
+		if err != nil {
+			return otlpTransport{}, err
+		}
+		transport.compression = c
+	case useEnv && os.Getenv(envOtelCompression) != "":
+		c, err := validateOtlpCompression(os.Getenv(envOtelCompression), envOtelCompression)
+		if err != nil {
+			return otlpTransport{}, err
+		}
+		transport.compression = c
+	}
+	return transport, nil
+}
+
+func validateOtlpProtocol(raw, source string) (string, error) {
+	switch p := strings.ToLower(strings.TrimSpace(raw)); p {
+	case OtlpProtocolHTTPJSON, OtlpProtocolHTTPProtobuf, OtlpProtocolGRPC:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid %s value %q: must be %s, %s, or %s", source, raw, OtlpProtocolHTTPJSON, OtlpProtocolHTTPProtobuf, OtlpProtocolGRPC)
+	}
+}
+
+func validateOtlpCompression(raw, source string) (string, error) {
+	switch c := strings.ToLower(strings.TrimSpace(raw)); c {
+	case OtlpCompressionNone, OtlpCompressionGzip, OtlpCompressionZstd:
+		return c, nil
+	default:
+		return "", fmt.Errorf("invalid %s value %q: must be %s, %s, or %s", source, raw, OtlpCompressionNone, OtlpCompressionGzip, OtlpCompressionZstd)
+	}
+}
+
+// encoding returns the encoding of OTLP/HTTP request bodies.
+func (t otlpTransport) encoding() dash0api.OtlpEncoding {
+	if t.protocol == OtlpProtocolHTTPProtobuf {
+		return dash0api.OtlpEncodingProtobuf
+	}
+	return dash0api.OtlpEncodingJson
+}
+
+// roundTripper returns the transport for OTLP/HTTP requests, which
+// compresses request bodies unless compression is off.
+func (t otlpTransport) roundTripper(base http.RoundTripper) http.RoundTripper {
+	if t.compression == OtlpCompressionNone {
+		return base
+	}
+	return &compressingTransport{base: base, algorithm: t.compression}
+}
+
+// compressingTransport compresses the body of every request with gzip or
+// zstd and sets Content-Encoding accordingly. It sits below reauthTransport,
+// so a request replayed with a fresh token is compressed again.
+type compressingTransport struct {
+	base      http.RoundTripper
+	algorithm string
+}
+
+func (t *compressingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return t.base.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := compressBody(t.algorithm, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress the request body with %s: %w", t.algorithm, err)
+	}
+
+	// RoundTrip must not modify the request it was given.
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(compressed))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	out.ContentLength = int64(len(compressed))
+	out.Header.Set("Content-Encoding", t.algorithm)
+	return t.base.RoundTrip(out)
+}
+
+// zstdBodyEncoder compresses the bodies of OTLP/HTTP requests. EncodeAll is
+// safe for concurrent use, so a single encoder serves every request instead
+// of allocating the window buffers of a new one each time.
+var zstdBodyEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+	return zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+})
+
+func compressBody(algorithm string, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch algorithm {
+	case OtlpCompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case OtlpCompressionZstd:
+		enc, err := zstdBodyEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(body, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", algorithm)
+	}
+	if _, err := w.Write(body); err != nil {
+		_ = w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
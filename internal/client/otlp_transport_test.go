@@ -0,0 +1,258 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestResolveOtlpTransport_Default(t *testing.T) {
+	t.Setenv(envOtelProtocol, "")
+	t.Setenv(envOtelCompression, "")
+
+	transport, err := resolveOtlpTransport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, otlpTransport{protocol: OtlpProtocolHTTPJSON, compression: OtlpCompressionNone}, transport)
+	assert.Equal(t, dash0api.OtlpEncodingJson, transport.encoding())
+}
+
+func TestResolveOtlpTransport_FromEnv(t *testing.T) {
+	t.Setenv(envOtelProtocol, "http/protobuf")
+	t.Setenv(envOtelCompression, "gzip")
+
+	transport, err := resolveOtlpTransport(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, otlpTransport{protocol: OtlpProtocolHTTPProtobuf, compression: OtlpCompressionGzip}, transport)
+	assert.Equal(t, dash0api.OtlpEncodingProtobuf, transport.encoding())
+}
+
+func TestResolveOtlpTransport_FlagTakesPrecedence(t *testing.T) {
+	t.Setenv(envOtelProtocol, "http/protobuf")
+	t.Setenv(envOtelCompression, "gzip")
+
+	ctx := WithOtlpTransport(context.Background(), "grpc", "zstd")
+	transport, err := resolveOtlpTransport(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, otlpTransport{protocol: OtlpProtocolGRPC, compression: OtlpCompressionZstd}, transport)
+}
+
+func TestResolveOtlpTransport_WithoutOtelExporterEnv(t *testing.T) {
+	t.Setenv(envOtelProtocol, "grpc")
+	t.Setenv(envOtelCompression, "gzip")
+
+	ctx := WithoutOtelExporterEnv(context.Background())
+	transport, err := resolveOtlpTransport(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, otlpTransport{protocol: OtlpProtocolHTTPJSON, compression: OtlpCompressionNone}, transport)
+
+	transport, err = resolveOtlpTransport(WithOtlpTransport(ctx, "http/protobuf", ""))
+	require.NoError(t, err)
+	assert.Equal(t, OtlpProtocolHTTPProtobuf, transport.protocol, "flags apply even when the environment is ignored")
+}
+
+func TestResolveOtlpTransport_Invalid(t *testing.T) {
+	t.Setenv(envOtelProtocol, "")
+	t.Setenv(envOtelCompression, "")
+
+	_, err := resolveOtlpTransport(WithOtlpTransport(context.Background(), "http", ""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid --otlp-protocol flag value "http"`)
+
+	t.Setenv(envOtelCompression, "brotli")
+	_, err = resolveOtlpTransport(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid OTEL_EXPORTER_OTLP_COMPRESSION value "brotli"`)
+}
+
+func TestCompressingTransport(t *testing.T) {
+	payload := strings.Repeat(`{"resourceLogs":[]}`, 100)
+
+	for _, algorithm := range []string{OtlpCompressionGzip, OtlpCompressionZstd} {
+		t.Run(algorithm, func(t *testing.T) {
+			var gotEncoding string
+			var gotBody []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotEncoding = r.Header.Get("Content-Encoding")
+				var reader io.Reader
+				switch gotEncoding {
+				case OtlpCompressionGzip:
+					gz, err := gzip.NewReader(r.Body)
+					require.NoError(t, err)
+					reader = gz
+				case OtlpCompressionZstd:
+					dec, err := zstd.NewReader(r.Body)
+					require.NoError(t, err)
+					defer dec.Close()
+					reader = dec
+				default:
+					reader = r.Body
+				}
+				body, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				gotBody = body
+			}))
+			defer server.Close()
+
+			transport := otlpTransport{protocol: OtlpProtocolHTTPJSON, compression: algorithm}
+			httpClient := &http.Client{Transport: transport.roundTripper(http.DefaultTransport)}
+			resp, err := httpClient.Post(server.URL+"/v1/logs", "application/json", strings.NewReader(payload))
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, algorithm, gotEncoding)
+			assert.Equal(t, payload, string(gotBody))
+		})
+	}
+}
+
+func TestZstdCompressor_ReusesEncoders(t *testing.T) {
+	var compressor zstdCompressor
+	for _, message := range []string{"first message", "second message, from a reused encoder"} {
+		var buf bytes.Buffer
+		w, err := compressor.Compress(&buf)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(message))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := compressor.Decompress(&buf)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, message, string(got))
+	}
+}
+
+func TestCompressingTransport_NoneIsPassThrough(t *testing.T) {
+	transport := otlpTransport{protocol: OtlpProtocolHTTPJSON, compression: OtlpCompressionNone}
+	assert.Same(t, http.DefaultTransport, transport.roundTripper(http.DefaultTransport))
+}
+
+func TestGrpcTarget(t *testing.T) {
+	tests := []struct {
+		url    string
+		target string
+		useTLS bool
+	}{
+		{"https://ingress.eu-west-1.aws.dash0.com", "ingress.eu-west-1.aws.dash0.com:4317", true},
+		{"https://ingress.eu-west-1.aws.dash0.com:4317/", "ingress.eu-west-1.aws.dash0.com:4317", true},
+		{"http://localhost:14317", "localhost:14317", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			target, useTLS, err := grpcTarget(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.target, target)
+			assert.Equal(t, tt.useTLS, useTLS)
+		})
+	}
+
+	for _, invalid := range []string{"ingress.dash0.com:4317", "ftp://ingress.dash0.com", "https://"} {
+		_, _, err := grpcTarget(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGrpcExportError(t *testing.T) {
+	assert.NoError(t, grpcExportError(nil))
+
+	err := grpcExportError(status.Error(codes.Unauthenticated, "invalid token"))
+	assert.True(t, dash0api.IsUnauthorized(err))
+	var apiErr *dash0api.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "invalid token", apiErr.Message)
+
+	err = grpcExportError(status.Error(codes.Canceled, "canceled"))
+	assert.Equal(t, codes.Canceled, status.Code(err), "unmapped codes are returned as is")
+}
+
+// recordingLogsServer records the logs and metadata of the exports it
+// receives, and rejects tokens other than acceptToken.
+type recordingLogsServer struct {
+	plogotlp.UnimplementedGRPCServer
+	acceptToken string
+	records     int
+	md          metadata.MD
+}
+
+func (s *recordingLogsServer) Export(ctx context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	if got := s.md.Get("authorization"); len(got) != 1 || got[0] != "Bearer "+s.acceptToken {
+		return plogotlp.NewExportResponse(), status.Error(codes.Unauthenticated, "invalid token")
+	}
+	s.records += req.Logs().LogRecordCount()
+	return plogotlp.NewExportResponse(), nil
+}
+
+// rotatingTokens hands out "stale" until it is invalidated, then "fresh".
+type rotatingTokens struct {
+	invalidated bool
+}
+
+func (r *rotatingTokens) AuthToken(context.Context) (string, error) {
+	if r.invalidated {
+		return "fresh", nil
+	}
+	return "stale", nil
+}
+
+func (r *rotatingTokens) invalidate(string) {
+	r.invalidated = true
+}
+
+func TestGrpcOtlpClient_SendLogs(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	logsServer := &recordingLogsServer{acceptToken: "fresh"}
+	plogotlp.RegisterGRPCServer(server, logsServer)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	tokens := &rotatingTokens{}
+	c, err := newGrpcOtlpClient(nil, tokens, "http://"+lis.Addr().String(), OtlpCompressionGzip, 0, nil)
+	require.NoError(t, err)
+	defer func() { _ = c.conn.Close() }()
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	dataset := "checkout"
+
+	require.NoError(t, c.SendLogs(context.Background(), logs, &dataset))
+	assert.True(t, tokens.invalidated, "a rejected token is replaced")
+	assert.Equal(t, 1, logsServer.records)
+	assert.Equal(t, []string{"checkout"}, logsServer.md.Get("dash0-dataset"))
+}
+
+func TestGrpcOtlpClient_SendLogsRejected(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	plogotlp.RegisterGRPCServer(server, &recordingLogsServer{acceptToken: "other"})
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	c, err := newGrpcOtlpClient(nil, staticToken("auth_wrong"), "http://"+lis.Addr().String(), OtlpCompressionNone, 0, nil)
+	require.NoError(t, err)
+	defer func() { _ = c.conn.Close() }()
+
+	err = c.SendLogs(context.Background(), plog.NewLogs(), nil)
+	assert.True(t, dash0api.IsUnauthorized(err))
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/config"
 	"github.com/dash0hq/dash0-cli/internal/version"
 )
 
@@ -40,6 +41,9 @@ func NewRawHTTPConfig(ctx context.Context, apiUrl, authToken string) (*RawHTTPCo
 	var resolvedDataset string
 
 	if cfg := profiles.FromContext(ctx); cfg != nil {
+		if err := prepareCredentials(ctx, cfg, authToken); err != nil {
+			return nil, err
+		}
 		if resolvedApiUrl == "" {
 			resolvedApiUrl = cfg.ApiUrl
 		}
@@ -57,6 +61,10 @@ func NewRawHTTPConfig(ctx context.Context, apiUrl, authToken string) (*RawHTTPCo
 		resolvedDataset = resolved.Dataset
 	}
 
+	if projectDataset := config.ProjectDataset(ctx); projectDataset != "" {
+		resolvedDataset = projectDataset
+	}
+
 	if resolvedApiUrl == "" {
 		return nil, fmt.Errorf("api-url is required; provide it as a flag, environment variable, or configure a profile")
 	}
@@ -69,20 +77,25 @@ func NewRawHTTPConfig(ctx context.Context, apiUrl, authToken string) (*RawHTTPCo
 	// One resolution up front is enough here. Raw commands issue a single short
 	// request, unlike the typed client, which consults its provider per request
 	// because it drives multi-page operations.
-	if cfg := profiles.FromContext(ctx); cfg != nil && !oauthShadowed(cfg, resolvedAuthToken) {
-		refreshed, err := cfg.AuthTokenProvider().AuthToken(ctx)
+	if cfg := profiles.FromContext(ctx); cfg != nil && cfg.OAuth != nil && !oauthShadowed(cfg, resolvedAuthToken) {
+		refreshed, err := oauthTokenProvider(ctx, cfg).AuthToken(ctx)
 		if err != nil {
 			return nil, translateConfigError(ctx, err)
 		}
 		resolvedAuthToken = refreshed
 	}
 
+	httpClient, resolvedAuthToken, err := resolveCommandToken(ctx, profiles.FromContext(ctx), resolvedAuthToken)
+	if err != nil {
+		return nil, err
+	}
+
 	if resolvedAuthToken == "" {
 		return nil, fmt.Errorf("auth-token is required; provide it as a flag, environment variable, or configure a profile")
 	}
 
 	return &RawHTTPConfig{
-		HTTPClient: &http.Client{},
+		HTTPClient: httpClient,
 		ApiUrl:     resolvedApiUrl,
 		AuthToken:  resolvedAuthToken,
 		Dataset:    resolvedDataset,
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+	"github.com/dash0hq/dash0-cli/internal/version"
+)
+
+// RawOtlpConfig holds the resolved configuration for raw OTLP/HTTP exports
+// to Dash0.
+type RawOtlpConfig struct {
+	// HTTPClient is a plain net/http client ready to perform requests.
+	HTTPClient *http.Client
+	// OtlpUrl is the resolved OTLP base URL, without a trailing slash.
+	OtlpUrl string
+	// AuthToken is the resolved bearer token.
+	AuthToken string
+	// Dataset is the resolved dataset, or nil for the default dataset.
+	Dataset *string
+	// UserAgent is the User-Agent string to send with requests.
+	UserAgent string
+}
+
+// NewRawOtlpConfig resolves the OTLP URL, auth token and dataset the same way
+// as NewOtlpClientFromContext, for commands that need the OTLP/HTTP response
+// itself. The typed client reports an export as a plain success or error and
+// drops the partial-success details of the response. Raw exports always use
+// OTLP/HTTP with protobuf; only the compression of OTLP clients applies. Like
+// the typed client, the HTTP client retries 429 and 503 responses up to
+// --max-retries times; unlike it, an export also times out.
+func NewRawOtlpConfig(ctx context.Context, otlpUrl, authToken, dataset string) (*RawOtlpConfig, error) {
+	cfg := profiles.FromContext(ctx)
+	if err := prepareCredentials(ctx, cfg, authToken); err != nil {
+		return nil, err
+	}
+
+	var finalOtlpUrl, finalAuthToken string
+	if cfg != nil {
+		finalOtlpUrl = cfg.OtlpUrl
+		finalAuthToken = cfg.AuthToken
+	}
+
+	// Apply flag overrides
+	if otlpUrl != "" {
+		finalOtlpUrl = otlpUrl
+	}
+	if authToken != "" {
+		finalAuthToken = authToken
+	}
+
+	if err := checkOAuthEmpty(ctx, cfg, finalAuthToken); err != nil {
+		return nil, err
+	}
+
+	if finalOtlpUrl == "" {
+		return nil, fmt.Errorf("otlp-url is required; provide it as a flag, environment variable, or configure a profile")
+	}
+	transport, err := resolveOtlpTransport(ctx)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, finalAuthToken, err := resolveOtlpToken(ctx, cfg, finalAuthToken, finalOtlpUrl)
+	if err != nil {
+		return nil, err
+	}
+	if httpClient.Transport == nil {
+		httpClient.Transport = http.DefaultTransport
+	}
+	maxRetries, err := resolveMaxRetries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = newRetryTransport(transport.roundTripper(httpClient.Transport), maxRetries)
+	httpClient.Timeout = rawOtlpRequestTimeout
+	if finalAuthToken == "" {
+		return nil, fmt.Errorf("auth-token is required; provide it as a flag, environment variable, or configure a profile")
+	}
+
+	return &RawOtlpConfig{
+		HTTPClient: httpClient,
+		OtlpUrl:    strings.TrimRight(finalOtlpUrl, "/"),
+		AuthToken:  finalAuthToken,
+		Dataset:    ResolveDataset(ctx, dataset),
+		UserAgent:  version.UserAgent(),
+	}, nil
+}
+
+// resolveOtlpToken returns the token for a raw OTLP export with an HTTP client
+// that obtains a new one on 401 responses: an ingestion token for an OAuth
+// profile, or the token resolveCommandToken returns otherwise.
+func resolveOtlpToken(ctx context.Context, cfg *profiles.Configuration, authToken, otlpUrl string) (*http.Client, string, error) {
+	if cfg == nil || cfg.OAuth == nil || oauthShadowed(cfg, authToken) {
+		return resolveCommandToken(ctx, cfg, authToken)
+	}
+	base, err := httptransport.RoundTripper(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	provider := newIngestionTokenProvider(ctx, cfg, otlpUrl)
+	token, err := provider.AuthToken(ctx)
+	if err != nil {
+		if dash0api.IsOAuthTokenError(err) || errors.Is(err, profiles.ErrReauthenticationRequired) {
+			return nil, "", translateConfigError(ctx, err)
+		}
+		return nil, "", err
+	}
+	return newReauthHTTPClient(base, provider), token, nil
+}
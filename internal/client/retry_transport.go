@@ -0,0 +1,76 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// Backoff between the retries of an OTLP export, shared by the OTLP/HTTP
+// retry transport and the gRPC retry policy.
+const (
+	retryInitialBackoff = 500 * time.Millisecond
+	retryMaxBackoff     = 5 * time.Second
+)
+
+// rawOtlpRequestTimeout bounds a raw OTLP export, retries included, so that a
+// stalled ingress fails the command instead of hanging it.
+const rawOtlpRequestTimeout = time.Minute
+
+// retryTransport retries requests that were answered with 429 Too Many
+// Requests or 503 Service Unavailable up to maxRetries times, with
+// exponential backoff, like the typed OTLP client. A request whose body
+// cannot be replayed is not retried.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+// newRetryTransport returns base with retries, or base itself when
+// maxRetries is zero.
+func newRetryTransport(base http.RoundTripper, maxRetries int) http.RoundTripper {
+	if maxRetries <= 0 {
+		return base
+	}
+	return &retryTransport{base: base, maxRetries: maxRetries, backoff: retryInitialBackoff}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	backoff := t.backoff
+	for attempt := 0; ; attempt++ {
+		out := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			out = req.Clone(req.Context())
+			out.Body = body
+		}
+		resp, err := t.base.RoundTrip(out)
+		if err != nil || !retryableStatus(resp.StatusCode) || attempt == t.maxRetries || !replayable {
+			return resp, err
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, retryMaxBackoff)
+	}
+}
+
+// maxDrainBytes is how much of the body of a retried response is read, so
+// that its connection can be reused.
+const maxDrainBytes = 4 << 10
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer answers the first failures requests with status, then 200, and
+// records the bodies it receives.
+func flakyServer(t *testing.T, failures, status int) (*httptest.Server, *[]string) {
+	t.Helper()
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestRetryTransport_RetriesRetryableStatus(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		server, bodies := flakyServer(t, 2, status)
+		rt := &retryTransport{base: http.DefaultTransport, maxRetries: 3, backoff: time.Millisecond}
+
+		resp, err := (&http.Client{Transport: rt}).Post(server.URL, "application/x-protobuf", strings.NewReader("payload"))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"payload", "payload", "payload"}, *bodies, "the body is replayed on every attempt")
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	server, bodies := flakyServer(t, 10, http.StatusServiceUnavailable)
+	rt := &retryTransport{base: http.DefaultTransport, maxRetries: 2, backoff: time.Millisecond}
+
+	resp, err := (&http.Client{Transport: rt}).Post(server.URL, "application/x-protobuf", strings.NewReader("payload"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, *bodies, 3)
+}
+
+func TestRetryTransport_OtherStatusIsNotRetried(t *testing.T) {
+	server, bodies := flakyServer(t, 1, http.StatusBadRequest)
+	rt := &retryTransport{base: http.DefaultTransport, maxRetries: 3, backoff: time.Millisecond}
+
+	resp, err := (&http.Client{Transport: rt}).Post(server.URL, "application/x-protobuf", strings.NewReader("payload"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestNewRetryTransport_NoRetries(t *testing.T) {
+	assert.Same(t, http.DefaultTransport, newRetryTransport(http.DefaultTransport, 0))
+}
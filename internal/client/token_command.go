@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+)
+
+// authTokenCommandTTL is how long the token printed by an auth token command
+// is reused before the command runs again. Tokens issued by secret managers
+// typically live for hours; re-running well before that keeps long-running
+// commands such as `otlp proxy` authenticated without running the helper for
+// every request.
+const authTokenCommandTTL = 5 * time.Minute
+
+var (
+	commandProvidersMu sync.Mutex
+	// commandProviders shares one provider per command within the process, so
+	// commands that build several clients run the helper once.
+	commandProviders = map[string]*commandTokenProvider{}
+)
+
+// commandTokenProvider supplies the auth token printed by an auth token
+// command, caching it for authTokenCommandTTL.
+type commandTokenProvider struct {
+	args []string
+
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+}
+
+// authTokenCommand returns the auth token command of the profile cfg was
+// resolved from when it supplies the token, that is, when no token was
+// resolved from --auth-token, DASH0_AUTH_TOKEN, or the profile. A resolved
+// token is always used as is, whatever it looks like.
+func authTokenCommand(cfg *profiles.Configuration, resolvedAuthToken string) []string {
+	if cfg == nil || resolvedAuthToken != "" {
+		return nil
+	}
+	return cfg.AuthTokenCommand
+}
+
+// commandProviderFor returns the provider for the auth token command of cfg,
+// or nil when the token does not come from one (see authTokenCommand).
+func commandProviderFor(cfg *profiles.Configuration, resolvedAuthToken string) *commandTokenProvider {
+	args := authTokenCommand(cfg, resolvedAuthToken)
+	if len(args) == 0 {
+		return nil
+	}
+	key := strings.Join(args, "\x00")
+	commandProvidersMu.Lock()
+	defer commandProvidersMu.Unlock()
+	p, ok := commandProviders[key]
+	if !ok {
+		p = &commandTokenProvider{args: args}
+		commandProviders[key] = p
+	}
+	return p
+}
+
+// AuthToken returns the cached token, running the command when there is none
+// or it is older than authTokenCommandTTL.
+func (p *commandTokenProvider) AuthToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Since(p.fetchedAt) < authTokenCommandTTL {
+		return p.token, nil
+	}
+	token, err := credentials.RunCommand(ctx, p.args)
+	if err != nil {
+		return "", err
+	}
+	p.token, p.fetchedAt = token, time.Now()
+	return token, nil
+}
+
+// invalidate drops the cached token if it is still the rejected one, so the
+// next AuthToken call runs the command again. Concurrent requests that were
+// rejected with the same token then share a single re-run.
+func (p *commandTokenProvider) invalidate(rejected string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == rejected {
+		p.token = ""
+	}
+}
+
+// reauthProvider is a token provider that caches its token and can be told
+// that the API rejected it: an auth token command, or the exchange of an
+// OAuth access token for an OTLP ingestion token.
+type reauthProvider interface {
+	AuthToken(ctx context.Context) (string, error)
+	invalidate(rejected string)
+}
+
+// reauthTransport retries a request that was rejected with 401 Unauthorized
+// once, with a token freshly obtained from the provider. An auth token
+// command may have handed out a token that was revoked or rotated before the
+// TTL ran out.
+type reauthTransport struct {
+	base     http.RoundTripper
+	provider reauthProvider
+}
+
+// newReauthHTTPClient returns an HTTP client that asks provider for a new
+// token on 401 responses. Requests go through base.
+func newReauthHTTPClient(base http.RoundTripper, provider reauthProvider) *http.Client {
+	return &http.Client{Transport: &reauthTransport{base: base, provider: provider}}
+}
+
+func (t *reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// A request whose body cannot be replayed cannot be retried.
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	const bearer = "Bearer "
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), bearer)
+	t.provider.invalidate(rejected)
+	token, tokenErr := t.provider.AuthToken(req.Context())
+	if tokenErr != nil || token == rejected {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	retry.Header.Set("Authorization", bearer+token)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// resolveCommandToken runs the auth token command of cfg when it supplies the
+// token (see authTokenCommand), for raw HTTP requests that take the token up
+// front. It returns the token with an HTTP client that re-runs the command on
+// 401 responses. A resolved authToken is returned as is, with a plain HTTP
+// client. Both clients use the transport of the context.
+func resolveCommandToken(ctx context.Context, cfg *profiles.Configuration, authToken string) (*http.Client, string, error) {
+	base, err := httptransport.RoundTripper(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	provider := commandProviderFor(cfg, authToken)
+	if provider == nil {
+		return &http.Client{Transport: base}, authToken, nil
+	}
+	token, err := provider.AuthToken(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return newReauthHTTPClient(base, provider), token, nil
+}
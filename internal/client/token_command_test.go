@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingTokenCommand returns a profile whose auth token command prints
+// "token-<n>" on its n-th run, and the file in which the command counts its
+// runs.
+func countingTokenCommand(t *testing.T) (*profiles.Configuration, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake auth token command is a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "token.sh")
+	counter := filepath.Join(dir, "count")
+	body := "#!/bin/sh\nn=$(cat '" + counter + "' 2>/dev/null || echo 0)\nn=$((n+1))\necho $n > '" + counter + "'\necho token-$n\n"
+	require.NoError(t, os.WriteFile(script, []byte(body), 0o755))
+	return &profiles.Configuration{AuthTokenCommand: []string{script}}, counter
+}
+
+func TestCommandTokenProvider_CachesToken(t *testing.T) {
+	cfg, _ := countingTokenCommand(t)
+	provider := commandProviderFor(cfg, "")
+	require.NotNil(t, provider)
+
+	first, err := provider.AuthToken(context.Background())
+	require.NoError(t, err)
+	second, err := provider.AuthToken(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", first)
+	assert.Equal(t, "token-1", second, "the cached token should be reused within the TTL")
+}
+
+func TestCommandProviderFor_StaticToken(t *testing.T) {
+	assert.Nil(t, commandProviderFor(&profiles.Configuration{AuthToken: "auth_static"}, "auth_static"))
+}
+
+// TestResolveCommandToken_FlagTokenIsLiteral verifies that a token passed via
+// --auth-token or DASH0_AUTH_TOKEN is used as is, even one that looks like a
+// command, and that it takes precedence over the command of the profile.
+func TestResolveCommandToken_FlagTokenIsLiteral(t *testing.T) {
+	cfg, counter := countingTokenCommand(t)
+	flagToken := `dash0-credential:command:["` + cfg.AuthTokenCommand[0] + `"]`
+
+	for _, profile := range []*profiles.Configuration{nil, {}, cfg} {
+		assert.Nil(t, commandProviderFor(profile, flagToken))
+		_, token, err := resolveCommandToken(context.Background(), profile, flagToken)
+		require.NoError(t, err)
+		assert.Equal(t, flagToken, token)
+	}
+	assert.NoFileExists(t, counter, "the command must not run")
+}
+
+func TestResolveCommandToken_RetriesUnauthorizedWithFreshToken(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	cfg, _ := countingTokenCommand(t)
+	httpClient, token, err := resolveCommandToken(context.Background(), cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "payload", string(body), "the retry should replay the request body")
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestResolveCommandToken_CommandFails(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on the false command")
+	}
+	cfg := &profiles.Configuration{AuthTokenCommand: []string{"false"}}
+
+	_, _, err := resolveCommandToken(context.Background(), cfg, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `auth token command "false" failed`)
+}
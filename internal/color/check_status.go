@@ -0,0 +1,39 @@
+package color
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SprintCheckStatus returns the status of a `dash0 doctor` check color-coded
+// and padded to width visible characters for terminal output. When width is
+// 0, no padding is applied.
+func SprintCheckStatus(status string, width int) string {
+	if NoColor {
+		if width > 0 {
+			return fmt.Sprintf("%-*s", width, status)
+		}
+		return status
+	}
+	return sprintCheckStatusColored(status, width)
+}
+
+func sprintCheckStatusColored(status string, width int) string {
+	o := StdoutOutput()
+
+	padded := status
+	if width > 0 {
+		padded = fmt.Sprintf("%-*s", width, status)
+	}
+
+	switch strings.ToLower(status) {
+	case "fail":
+		return o.String(padded).Foreground(o.Color("1")).String() // red
+	case "warn":
+		return o.String(padded).Foreground(o.Color("3")).String() // yellow
+	case "pass":
+		return o.String(padded).Foreground(o.Color("2")).String() // green
+	default:
+		return padded
+	}
+}
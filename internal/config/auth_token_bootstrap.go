@@ -0,0 +1,27 @@
+package config
+
+import "context"
+
+// AuthTokenRequest describes the auth token that `config profiles create
+// --create-auth-token` creates for the new profile.
+type AuthTokenRequest struct {
+	// Name is the name of the token, shown by `auth-tokens list`.
+	Name string
+	// ApiUrl overrides the API URL of the selected profile, like --api-url.
+	ApiUrl    string
+	Scopes    []string
+	Datasets  []string
+	ExpiresIn string
+}
+
+// CreatedAuthToken is an auth token created for a new profile.
+type CreatedAuthToken struct {
+	ID    string
+	Token string
+}
+
+// CreateAuthToken creates an auth token with the credentials of the selected
+// profile. It is implemented by the authtokens package and set in
+// cmd/dash0, because config cannot import the API client without an import
+// cycle. A nil CreateAuthToken disables --create-auth-token.
+var CreateAuthToken func(ctx context.Context, req AuthTokenRequest) (*CreatedAuthToken, error)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,6 +13,8 @@ import (
 	"github.com/dash0hq/dash0-cli/internal"
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/confirmation"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
 	oauthpkg "github.com/dash0hq/dash0-cli/internal/oauth"
 	"github.com/spf13/cobra"
 )
@@ -38,6 +42,10 @@ type configShowJSON struct {
 	Dataset   *configShowField `json:"dataset"`
 	AuthToken *configShowField `json:"authToken"`
 	OAuth     *configShowOAuth `json:"oauth,omitempty"`
+	TLS       *configShowTLS   `json:"tls"`
+	// Proxy is the proxy that requests to the API URL go through, from
+	// HTTPS_PROXY, HTTP_PROXY, and NO_PROXY.
+	Proxy string `json:"proxy,omitempty"`
 }
 
 // configShowOAuth is the JSON-serializable representation of the OAuth state
@@ -46,15 +54,19 @@ type configShowJSON struct {
 // how to recover (the human-targeted `Run \`dash0 login\`` hint is
 // unrunnable in agent mode).
 type configShowOAuth struct {
-	ClientID      string `json:"clientId,omitempty"`
-	ExpiresAt     string `json:"expiresAt,omitempty"`
-	Authenticated bool   `json:"authenticated"`
-	Hint          string `json:"hint,omitempty"`
+	ClientID            string `json:"clientId,omitempty"`
+	ExpiresAt           string `json:"expiresAt,omitempty"`
+	Authenticated       bool   `json:"authenticated"`
+	RefreshTokenStorage string `json:"refreshTokenStorage,omitempty"`
+	Hint                string `json:"hint,omitempty"`
 }
 
 type configShowField struct {
 	Value  string `json:"value"`
 	Source string `json:"source,omitempty"`
+	// Storage names the credential store that holds the secret, when it is
+	// not kept in the profiles file.
+	Storage string `json:"storage,omitempty"`
 }
 
 // newShowCmd creates a new show command
@@ -73,6 +85,8 @@ Configuration is resolved in the following order (highest priority first):
   3. Active profile settings
 
 When an environment variable overrides a profile value, config show annotates the field with "(from <VAR> environment variable)".
+When a secret of the profile lives in a credential store (see DASH0_CREDENTIAL_STORE), config show names the store.
+It also shows the effective TLS settings (the CA file and client certificate of the profile, or DASH0_CA_FILE, DASH0_CLIENT_CERT, and DASH0_CLIENT_KEY, and --insecure-skip-tls-verify), and the proxy from HTTPS_PROXY, HTTP_PROXY, and NO_PROXY, if any.
 
 The DASH0_CONFIG_DIR environment variable changes the configuration directory (default: ~/.dash0).`,
 		Example: `  # Show the active profile and its settings
@@ -94,6 +108,7 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 
 			profileName := ""
 			var config *profiles.Configuration
+			var authStorage, refreshStorage string
 
 			if profileSelector.IsSet() {
 				profileName = profileSelector.Name
@@ -116,6 +131,11 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 					// a failing refresh attempt). Apply env-var overrides
 					// here to match the resolution order.
 					cfg := activeProfile.Configuration
+					if err := credentials.OpenConfiguration(profileName, &cfg); err != nil {
+						// Keep the reference, which maskToken renders as
+						// the name of the store.
+						fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					}
 					if v := os.Getenv(profiles.EnvApiUrl); v != "" {
 						cfg.ApiUrl = v
 					}
@@ -140,22 +160,51 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 				}
 			}
 
+			if profileName != "" {
+				authStorage, refreshStorage = secretStorage(profileName)
+			}
+			if envAuthToken != "" {
+				authStorage, refreshStorage = "", ""
+			}
+
 			apiUrl := ""
 			authToken := ""
+			maskedAuthToken := ""
 			otlpUrl := ""
 			dataset := ""
 			if config != nil {
 				apiUrl = config.ApiUrl
 				authToken = config.AuthToken
+				maskedAuthToken = maskAuthToken(*config)
 				otlpUrl = config.OtlpUrl
 				dataset = config.Dataset
 			}
 
+			// A dataset pinned by the project configuration file takes
+			// precedence over the one of the profile, but not over
+			// DASH0_DATASET or a profile chosen with --profile or
+			// DASH0_PROFILE.
+			datasetSource := ""
+			if envDataset != "" {
+				datasetSource = profiles.EnvDataset
+			} else if projectDataset := ProjectDataset(cmd.Context()); projectDataset != "" {
+				dataset = projectDataset
+				datasetSource = ProjectConfigFromContext(cmd.Context()).Path
+			}
+
 			datasetDisplay := dataset
 			if datasetDisplay == "" {
 				datasetDisplay = "default"
 			}
 
+			tlsSettings := httptransport.SettingsFor(cmd.Context(), config)
+			proxy := ""
+			if target := cmp.Or(apiUrl, otlpUrl); target != "" {
+				if u := httptransport.ProxyFor(target); u != nil {
+					proxy = u.Redacted()
+				}
+			}
+
 			useJSON := strings.ToLower(outputFmt) == "json" ||
 				(outputFmt == "" && agentmode.Enabled)
 
@@ -164,12 +213,16 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 					Profile:   profileField(profileName, profileSelector),
 					ApiUrl:    showField(apiUrl, envApiUrl, profiles.EnvApiUrl),
 					OtlpUrl:   showField(otlpUrl, envOtlpUrl, profiles.EnvOtlpUrl),
-					Dataset:   showField(datasetDisplay, envDataset, profiles.EnvDataset),
-					AuthToken: showField(maskToken(authToken), envAuthToken, profiles.EnvAuthToken),
+					Dataset:   &configShowField{Value: datasetDisplay, Source: datasetSource},
+					AuthToken: showField(maskedAuthToken, envAuthToken, profiles.EnvAuthToken),
+					TLS:       showTLS(tlsSettings),
+					Proxy:     proxy,
 				}
+				result.AuthToken.Storage = authStorage
 				if config != nil && config.OAuth != nil && envAuthToken == "" {
 					o := &configShowOAuth{
-						Authenticated: config.OAuth.RefreshToken != "",
+						Authenticated:       config.OAuth.RefreshToken != "",
+						RefreshTokenStorage: refreshStorage,
 					}
 					if o.Authenticated {
 						o.ClientID = config.OAuth.ClientID
@@ -198,7 +251,7 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 			} else {
 				fmt.Printf("%s", profileName)
 			}
-			if desc := profileSelector.Source.Description(); desc != "" {
+			if desc := profileSelector.Description(); desc != "" {
 				fmt.Printf("    (%s)", desc)
 			}
 			fmt.Println()
@@ -226,16 +279,24 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 			fmt.Printf("Dataset:    %s", datasetDisplay)
 			if envDataset != "" {
 				fmt.Printf("    (from %s environment variable)", profiles.EnvDataset)
+			} else if datasetSource != "" {
+				fmt.Printf("    (from %s)", datasetSource)
 			}
 			fmt.Println()
 
 			switch {
-			case authToken != "":
-				fmt.Printf("Auth Token: %s", maskToken(authToken))
+			case maskedAuthToken != "":
+				fmt.Printf("Auth Token: %s", maskedAuthToken)
 				if envAuthToken != "" {
 					fmt.Printf("    (from %s environment variable)", profiles.EnvAuthToken)
 				} else if config != nil && config.OAuth != nil {
-					fmt.Printf("    (OAuth, expires in %s)", friendlyExpiry(time.Until(config.OAuth.ExpiresAt)))
+					fmt.Printf("    (OAuth, expires in %s", friendlyExpiry(time.Until(config.OAuth.ExpiresAt)))
+					if refreshStorage != "" {
+						fmt.Printf("; refresh token in %s", refreshStorage)
+					}
+					fmt.Printf(")")
+				} else if authStorage != "" && !credentials.IsReference(authToken) {
+					fmt.Printf("    (stored in %s)", authStorage)
 				}
 				fmt.Println()
 			case config != nil && config.OAuth != nil && envAuthToken == "":
@@ -247,6 +308,17 @@ The DASH0_CONFIG_DIR environment variable changes the configuration directory (d
 				fmt.Println("Auth Token: (not set)")
 			}
 
+			for i, line := range tlsLines(tlsSettings) {
+				if i == 0 {
+					fmt.Printf("TLS:        %s\n", line)
+				} else {
+					fmt.Printf("            %s\n", line)
+				}
+			}
+			if proxy != "" {
+				fmt.Printf("Proxy:      %s\n", proxy)
+			}
+
 			return nil
 		},
 	}
@@ -271,17 +343,24 @@ func profileField(name string, selector ProfileSelector) *configShowField {
 		f.Source = "flag:--profile"
 	case ProfileSourceEnv:
 		f.Source = EnvProfile
+	case ProfileSourceProject:
+		f.Source = selector.File
 	}
 	return f
 }
 
 // maskToken masks all but the last 7 characters of a token. An empty input
 // returns "" so callers that pass through to JSON output do not falsely
-// imply a token exists for OAuth-empty or token-less profiles.
+// imply a token exists for OAuth-empty or token-less profiles. A reference
+// to a credential store renders as the name of the store, since the token
+// itself is not at hand.
 func maskToken(token string) string {
 	if token == "" {
 		return ""
 	}
+	if credentials.IsReference(token) {
+		return fmt.Sprintf("(stored in %s)", credentials.Location(token))
+	}
 	if len(token) <= 12 {
 		return "********"
 	}
@@ -289,6 +368,34 @@ func maskToken(token string) string {
 	return "..." + token[len(token)-7:]
 }
 
+// maskAuthToken is maskToken for the auth token of cfg, which the auth token
+// command of the profile prints when the profile holds no token.
+func maskAuthToken(cfg profiles.Configuration) string {
+	if cfg.AuthToken == "" && len(cfg.AuthTokenCommand) > 0 {
+		return fmt.Sprintf("(from command `%s`)", strings.Join(cfg.AuthTokenCommand, " "))
+	}
+	return maskToken(cfg.AuthToken)
+}
+
+// secretStorage names the credential stores holding the auth token and the
+// OAuth refresh token of the named profile, as stored in the profiles file.
+// Each is "" when the secret is kept in the profiles file itself.
+func secretStorage(name string) (authToken, refreshToken string) {
+	store, err := profiles.NewStore()
+	if err != nil {
+		return "", ""
+	}
+	cfg, err := loadProfileConfig(store, name)
+	if err != nil {
+		return "", ""
+	}
+	authToken = credentials.Location(cfg.AuthToken)
+	if cfg.OAuth != nil {
+		refreshToken = credentials.Location(cfg.OAuth.RefreshToken)
+	}
+	return authToken, refreshToken
+}
+
 // friendlyExpiry renders a time.Duration as a short human-readable string for
 // the `config show` line that annotates OAuth tokens.
 // Negative durations render as "expired".
@@ -314,6 +421,8 @@ func newProfileCmd() *cobra.Command {
 	cmd.AddCommand(newListProfileCmd())
 	cmd.AddCommand(newDeleteProfileCmd())
 	cmd.AddCommand(newSelectProfileCmd())
+	cmd.AddCommand(newExportProfileCmd())
+	cmd.AddCommand(newImportProfileCmd())
 
 	return cmd
 }
@@ -321,8 +430,11 @@ func newProfileCmd() *cobra.Command {
 // newCreateProfileCmd creates a new create profile command
 func newCreateProfileCmd() *cobra.Command {
 	var (
-		apiUrl, authToken, otlpUrl, dataset string
-		oauth                               bool
+		apiUrl, authToken, authTokenCommand, otlpUrl, dataset string
+		oauth, createAuthToken                                bool
+		scopes                                                []string
+		expiresIn                                             string
+		tlsFiles                                              tlsFlags
 	)
 
 	cmd := &cobra.Command{
@@ -332,9 +444,27 @@ func newCreateProfileCmd() *cobra.Command {
 		Long: `Create a new named configuration profile.
 
 By default the profile holds a static auth token, supplied via --auth-token.
+The token is written to the credential store selected by DASH0_CREDENTIAL_STORE
+(default: plaintext in the profiles file).
+Pass --auth-token-command instead to obtain the token from an external
+command, such as a secret manager's CLI, whenever it is needed. The command
+must print the token on stdout. It is run again every 5 minutes and when the
+API rejects the token. Give it as a JSON array of arguments, or as a command
+line that is split at whitespace. A token given with --auth-token or
+DASH0_AUTH_TOKEN when running a command takes precedence over it.
 Pass --oauth to create a profile that authenticates via OAuth 2.0; the
 profile is created empty and must be populated by running 'dash0 login'.
---oauth and --auth-token are mutually exclusive.`,
+Pass --create-auth-token to create a new auth token for the profile with
+the credentials of the selected profile, e.g. a logged-in OAuth profile;
+the new profile takes the API URL, OTLP URL, and dataset of the selected
+profile unless given, and the token is restricted to --dataset, if given.
+Grant the token scopes with --scope and limit its lifetime with
+--expires-in, as with 'dash0 auth-tokens create'.
+--oauth, --auth-token, --auth-token-command, and --create-auth-token are
+mutually exclusive.
+Behind a TLS-intercepting proxy, pass --ca-file with the CA certificate of
+the proxy; for endpoints that require mutual TLS, pass --client-cert and
+--client-key. The files are read whenever the profile is used.`,
 		Example: `  # Static-token profile
   dash0 config profiles create dev \
       --api-url https://api.us-west-2.aws.dash0.com \
@@ -345,6 +475,21 @@ profile is created empty and must be populated by running 'dash0 login'.
   dash0 config profiles create dev --oauth \
       --api-url https://api.us-west-2.aws.dash0.com
 
+  # Profile whose auth token is read from Vault
+  dash0 config profiles create prod \
+      --api-url https://api.eu-west-1.aws.dash0.com \
+      --auth-token-command '["vault", "read", "-field=token", "secret/dash0"]'
+
+  # Profile behind a TLS-intercepting proxy with a private CA
+  dash0 config profiles create corp \
+      --api-url https://api.eu-west-1.aws.dash0.com \
+      --auth-token auth_xxx \
+      --ca-file /etc/ssl/certs/corp-root-ca.pem
+
+  # CI profile with a new ingest-only token, created with your login session
+  dash0 config profiles create ci --create-auth-token \
+      --scope ingest --expires-in 90d
+
   # Minimal static profile (fill the rest in later with 'profiles update')
   dash0 config profiles create staging --api-url https://api.example.com`,
 		Args: cobra.ExactArgs(1),
@@ -354,20 +499,68 @@ profile is created empty and must be populated by running 'dash0 login'.
 			if oauth && authToken != "" {
 				return fmt.Errorf("--oauth and --auth-token are mutually exclusive")
 			}
+			var command []string
+			if authTokenCommand != "" {
+				if oauth || authToken != "" {
+					return fmt.Errorf("--auth-token-command cannot be combined with --oauth or --auth-token")
+				}
+				parsed, err := credentials.ParseCommand(authTokenCommand)
+				if err != nil {
+					return err
+				}
+				command = parsed
+			}
 			if oauth && apiUrl == "" {
 				return fmt.Errorf("--oauth requires --api-url so that 'dash0 login' knows where to authenticate")
 			}
+			if createAuthToken {
+				if oauth || authToken != "" || command != nil {
+					return fmt.Errorf("--create-auth-token cannot be combined with --oauth, --auth-token, or --auth-token-command")
+				}
+			} else if len(scopes) > 0 || expiresIn != "" {
+				return fmt.Errorf("--scope and --expires-in require --create-auth-token")
+			}
+
+			var withTLS profiles.Configuration
+			if err := tlsFiles.apply(cmd, &withTLS); err != nil {
+				return err
+			}
+			if err := validateProfileTLS(withTLS); err != nil {
+				return err
+			}
 
 			store, err := profiles.NewStore()
 			if err != nil {
 				return err
 			}
 
+			// Sealing an auth token for an existing profile would overwrite
+			// that profile's secret before AddProfile rejects the name.
+			if _, err := loadProfileConfig(store, name); err == nil {
+				return fmt.Errorf("failed to add profile: profile %q already exists", name)
+			}
+			var created *CreatedAuthToken
+			if createAuthToken {
+				created, err = createProfileAuthToken(cmd.Context(), name, &apiUrl, &otlpUrl, &dataset, scopes, expiresIn)
+				if err != nil {
+					return err
+				}
+				authToken = created.Token
+			}
+			sealedAuthToken, err := credentials.Seal(name, credentials.KindAuthToken, authToken, "")
+			if err != nil {
+				return err
+			}
+
 			config := profiles.Configuration{
-				ApiUrl:    apiUrl,
-				AuthToken: authToken,
-				OtlpUrl:   otlpUrl,
-				Dataset:   dataset,
+				ApiUrl:           apiUrl,
+				AuthToken:        sealedAuthToken,
+				AuthTokenCommand: command,
+				OtlpUrl:          otlpUrl,
+				Dataset:          dataset,
+				CaFile:           withTLS.CaFile,
+				ClientCert:       withTLS.ClientCert,
+				ClientKey:        withTLS.ClientKey,
 			}
 			if oauth {
 				config.OAuth = &profiles.OAuthState{}
@@ -379,12 +572,18 @@ profile is created empty and must be populated by running 'dash0 login'.
 			}
 
 			if err := store.AddProfile(profile); err != nil {
+				credentials.Remove(sealedAuthToken)
+				if created != nil {
+					return fmt.Errorf("failed to add profile: %w\nHint: Run `dash0 auth-tokens revoke %s --force` to revoke the auth token created for it.", err, created.ID)
+				}
 				return fmt.Errorf("failed to add profile: %w", err)
 			}
 
 			if oauth {
 				fmt.Printf("Profile %q created (OAuth).\n", name)
 				fmt.Println(OAuthAuthenticateHint(name))
+			} else if created != nil {
+				fmt.Printf("Profile %q added with new auth token %s\n", name, created.ID)
 			} else {
 				fmt.Printf("Profile %q added\n", name)
 			}
@@ -397,16 +596,61 @@ profile is created empty and must be populated by running 'dash0 login'.
 	cmd.Flags().StringVar(&authToken, "auth-token", "", "Authentication token for the Dash0 API")
 	cmd.Flags().StringVar(&otlpUrl, "otlp-url", "", "OTLP endpoint URL for sending telemetry data")
 	cmd.Flags().StringVar(&dataset, "dataset", "", "Dataset to operate on")
+	cmd.Flags().StringVar(&authTokenCommand, "auth-token-command", "", "Command that prints the auth token, as a JSON array or a command line")
 	cmd.Flags().BoolVar(&oauth, "oauth", false, "Create the profile in OAuth mode (run 'dash0 login' to authenticate)")
+	cmd.Flags().BoolVar(&createAuthToken, "create-auth-token", false, "Create a new auth token for the profile with the credentials of the selected profile")
+	cmd.Flags().StringArrayVar(&scopes, "scope", nil, "Scope to grant the new auth token: ingest, read, write (repeatable; with --create-auth-token)")
+	cmd.Flags().StringVar(&expiresIn, "expires-in", "", `Lifetime of the new auth token, e.g. "90d" (with --create-auth-token; default: no expiry)`)
+	tlsFiles.register(cmd)
 
 	return cmd
 }
 
+// createProfileAuthToken creates the auth token of the new profile name with
+// the credentials of the selected profile, and fills in the API URL, OTLP
+// URL, and dataset of the new profile from the selected one where they are
+// not given.
+func createProfileAuthToken(ctx context.Context, name string, apiUrl, otlpUrl, dataset *string, scopes []string, expiresIn string) (*CreatedAuthToken, error) {
+	if CreateAuthToken == nil {
+		return nil, fmt.Errorf("--create-auth-token is not supported by this build")
+	}
+	// Only an explicit --dataset restricts the token; the dataset taken over
+	// from the selected profile is merely the default of the new profile.
+	var datasets []string
+	if *dataset != "" {
+		datasets = []string{*dataset}
+	}
+	source := profiles.FromContext(ctx)
+	if source == nil && *apiUrl == "" {
+		return nil, fmt.Errorf("--create-auth-token requires a profile to create the auth token with\nHint: Run `dash0 login` first, or select a profile with --profile.")
+	}
+	if source != nil {
+		if *apiUrl == "" {
+			*apiUrl = source.ApiUrl
+		}
+		if *otlpUrl == "" {
+			*otlpUrl = source.OtlpUrl
+		}
+		if *dataset == "" {
+			*dataset = source.Dataset
+		}
+	}
+
+	return CreateAuthToken(ctx, AuthTokenRequest{
+		Name:      name,
+		ApiUrl:    *apiUrl,
+		Scopes:    scopes,
+		Datasets:  datasets,
+		ExpiresIn: expiresIn,
+	})
+}
+
 // newUpdateProfileCmd creates a new update profile command
 func newUpdateProfileCmd() *cobra.Command {
 	var (
-		apiUrl, authToken, otlpUrl, dataset string
-		oauth, force                        bool
+		apiUrl, authToken, authTokenCommand, otlpUrl, dataset string
+		oauth, force                                          bool
+		tlsFiles                                              tlsFlags
 	)
 
 	cmd := &cobra.Command{
@@ -420,7 +664,14 @@ Pass --oauth to convert a static-token profile to an OAuth profile, or
 --oauth=false to convert an OAuth profile back to a static-token profile.
 Both transitions are destructive (they discard the existing credentials) and
 will prompt for confirmation unless --force is set. --oauth and --auth-token
-are mutually exclusive.`,
+are mutually exclusive.
+
+--auth-token-command replaces the auth token with a command that prints it
+(see 'dash0 config profiles create --help'); --auth-token replaces the command
+with a token.
+
+--ca-file, --client-cert, and --client-key set the TLS settings of the
+profile (see 'dash0 config profiles create --help').`,
 		Example: `  # Update the API URL of a profile
   dash0 config profiles update prod --api-url https://api.us-east-1.aws.dash0.com
 
@@ -434,7 +685,13 @@ are mutually exclusive.`,
   dash0 config profiles update prod --oauth
 
   # Convert an OAuth profile back to a static-token profile in one step
-  dash0 config profiles update prod --oauth=false --auth-token auth_xxx --force`,
+  dash0 config profiles update prod --oauth=false --auth-token auth_xxx --force
+
+  # Trust the CA of a TLS-intercepting proxy
+  dash0 config profiles update prod --ca-file /etc/ssl/certs/corp-root-ca.pem
+
+  # Read the auth token from 1Password
+  dash0 config profiles update prod --auth-token-command 'op read op://dev/dash0/token'`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
@@ -445,11 +702,32 @@ are mutually exclusive.`,
 			datasetChanged := cmd.Flags().Changed("dataset")
 			oauthChanged := cmd.Flags().Changed("oauth")
 
-			if !apiUrlChanged && !authTokenChanged && !otlpUrlChanged && !datasetChanged && !oauthChanged {
-				return fmt.Errorf("at least one of --api-url, --auth-token, --otlp-url, --dataset, or --oauth must be specified")
+			tlsChanged := tlsFiles.changed(cmd)
+
+			if !apiUrlChanged && !authTokenChanged && !otlpUrlChanged && !datasetChanged && !oauthChanged && !tlsChanged && !cmd.Flags().Changed("auth-token-command") {
+				return fmt.Errorf("at least one of --api-url, --auth-token, --auth-token-command, --otlp-url, --dataset, --oauth, --ca-file, --client-cert, or --client-key must be specified")
+			}
+
+			// A profile has either an auth token or an auth token command,
+			// so setting one clears the other, and from here on both are an
+			// auth token change. command stays nil unless a command is set.
+			var command []string
+			if cmd.Flags().Changed("auth-token-command") {
+				if authTokenChanged {
+					return fmt.Errorf("--auth-token and --auth-token-command are mutually exclusive")
+				}
+				authTokenChanged = true
+				authToken = ""
+				if authTokenCommand != "" {
+					parsed, err := credentials.ParseCommand(authTokenCommand)
+					if err != nil {
+						return err
+					}
+					command = parsed
+				}
 			}
 
-			if oauthChanged && oauth && authTokenChanged && authToken != "" {
+			if oauthChanged && oauth && authTokenChanged && (authToken != "" || command != nil) {
 				return fmt.Errorf("--oauth and --auth-token are mutually exclusive")
 			}
 
@@ -465,6 +743,18 @@ are mutually exclusive.`,
 				return err
 			}
 
+			// Validate the TLS settings as they will be stored, so that a
+			// client certificate is never stored without its key.
+			withTLS := existing
+			if tlsChanged {
+				if err := tlsFiles.apply(cmd, &withTLS); err != nil {
+					return err
+				}
+				if err := validateProfileTLS(withTLS); err != nil {
+					return err
+				}
+			}
+
 			currentlyOAuth := existing.OAuth != nil
 			targetOAuth := currentlyOAuth
 			if oauthChanged {
@@ -527,12 +817,23 @@ are mutually exclusive.`,
 				// after the update so the user knows the AS still holds
 				// the token.
 				if existing.OAuth != nil {
-					if !oauthpkg.Revoke(existing.ApiUrl, existing.OAuth.RefreshToken) {
+					refreshToken, err := credentials.Open(existing.OAuth.RefreshToken)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					}
+					if err != nil || !oauthpkg.Revoke(cmd.Context(), existing.ApiUrl, refreshToken) {
 						defer fmt.Println("Note: server-side refresh-token revocation failed; the token will remain valid on the authorization server until natural expiry.")
 					}
 				}
 			}
 
+			var sealedAuthToken string
+			if authTokenChanged {
+				if sealedAuthToken, err = credentials.Seal(name, credentials.KindAuthToken, authToken, existing.AuthToken); err != nil {
+					return err
+				}
+			}
+
 			if err := store.UpdateProfile(name, func(cfg *profiles.Configuration) {
 				if apiUrlChanged {
 					cfg.ApiUrl = apiUrl
@@ -543,11 +844,17 @@ are mutually exclusive.`,
 				if datasetChanged {
 					cfg.Dataset = dataset
 				}
+				if tlsChanged {
+					cfg.CaFile = withTLS.CaFile
+					cfg.ClientCert = withTLS.ClientCert
+					cfg.ClientKey = withTLS.ClientKey
+				}
 				if oauthChanged {
 					if targetOAuth {
 						// Static -> OAuth-empty: clear the static token and
 						// mark the profile as OAuth-typed.
 						cfg.AuthToken = ""
+						cfg.AuthTokenCommand = nil
 						cfg.OAuth = &profiles.OAuthState{}
 					} else {
 						// OAuth -> Static / no-auth: clear the OAuth block and
@@ -557,17 +864,27 @@ are mutually exclusive.`,
 					}
 				}
 				if authTokenChanged {
-					cfg.AuthToken = authToken
+					cfg.AuthToken = sealedAuthToken
+					cfg.AuthTokenCommand = command
 				}
 			}); err != nil {
 				return fmt.Errorf("failed to update profile: %w", err)
 			}
 
+			// Drop the secrets the profile no longer refers to. A re-sealed
+			// auth token reuses its entry, so it is left alone.
+			if (oauthChanged || authTokenChanged) && existing.AuthToken != sealedAuthToken {
+				credentials.Remove(existing.AuthToken)
+			}
+			if oauthChanged && !targetOAuth && existing.OAuth != nil {
+				credentials.Remove(existing.OAuth.RefreshToken)
+			}
+
 			fmt.Printf("Profile %q updated\n", name)
 			if oauthChanged && targetOAuth && !currentlyOAuth {
 				fmt.Println(OAuthAuthenticateHint(name))
 			}
-			if oauthChanged && !targetOAuth && (!authTokenChanged || authToken == "") {
+			if oauthChanged && !targetOAuth && (!authTokenChanged || (authToken == "" && command == nil)) {
 				// OAuth → no-auth: the profile is now token-less.
 				fmt.Printf("Hint: This profile has no auth token configured. Set one with `dash0 config profiles update %s --auth-token auth_<...>`.\n", name)
 			}
@@ -580,8 +897,10 @@ are mutually exclusive.`,
 	cmd.Flags().StringVar(&authToken, "auth-token", "", "Authentication token for the Dash0 API")
 	cmd.Flags().StringVar(&otlpUrl, "otlp-url", "", "OTLP endpoint URL for sending telemetry data")
 	cmd.Flags().StringVar(&dataset, "dataset", "", "Dataset to operate on")
+	cmd.Flags().StringVar(&authTokenCommand, "auth-token-command", "", "Command that prints the auth token, as a JSON array or a command line")
 	cmd.Flags().BoolVar(&oauth, "oauth", false, "Mark the profile as OAuth (--oauth=false to disable). Discards existing credentials on transition.")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip confirmation prompts for destructive transitions")
+	tlsFiles.register(cmd)
 
 	return cmd
 }
@@ -646,7 +965,7 @@ func authKind(cfg profiles.Configuration) string {
 		return "oauth-active"
 	case cfg.OAuth != nil:
 		return "oauth-empty"
-	case cfg.AuthToken != "":
+	case cfg.AuthToken != "" || len(cfg.AuthTokenCommand) > 0:
 		return "static"
 	default:
 		return "none"
@@ -663,7 +982,7 @@ func formatAuthColumn(cfg profiles.Configuration) string {
 	case "oauth-empty":
 		return "oauth (not logged in)"
 	case "static":
-		return fmt.Sprintf("static %s", maskToken(cfg.AuthToken))
+		return fmt.Sprintf("static %s", maskAuthToken(cfg))
 	default:
 		return "(none)"
 	}
@@ -752,7 +1071,7 @@ func renderProfilesJSON(profiles []profiles.Profile, activeProfileName string) e
 			ApiUrl:    p.Configuration.ApiUrl,
 			OtlpUrl:   p.Configuration.OtlpUrl,
 			Dataset:   dataset,
-			AuthToken: maskToken(p.Configuration.AuthToken),
+			AuthToken: maskAuthToken(p.Configuration),
 			AuthKind:  authKind(p.Configuration),
 		}
 	}
@@ -860,9 +1179,14 @@ func newDeleteProfileCmd() *cobra.Command {
 				return err
 			}
 
+			existing, _ := loadProfileConfig(store, name)
 			if err := store.RemoveProfile(name); err != nil {
 				return fmt.Errorf("failed to remove profile: %w", err)
 			}
+			credentials.Remove(existing.AuthToken)
+			if existing.OAuth != nil {
+				credentials.Remove(existing.OAuth.RefreshToken)
+			}
 
 			fmt.Printf("Profile '%s' deleted\n", name)
 
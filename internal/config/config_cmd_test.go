@@ -10,6 +10,7 @@ import (
 
 	"github.com/dash0hq/dash0-api-client-go/profiles"
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
 	"github.com/spf13/cobra"
 )
 
@@ -678,6 +679,46 @@ func TestCreateProfileCmd(t *testing.T) {
 	}
 }
 
+// TestCreateProfileCmdEncryptedCredentialStore tests that the auth token goes
+// to the credential store selected via DASH0_CREDENTIAL_STORE and that
+// config show names the store.
+func TestCreateProfileCmdEncryptedCredentialStore(t *testing.T) {
+	_ = setupTestConfigDir(t)
+	t.Setenv(credentials.EnvCredentialStore, credentials.StoreEncryptedFile)
+	t.Setenv(credentials.EnvCredentialPassphrase, "test-passphrase")
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	if _, err := executeCommand(rootCmd, "config", "profiles", "create", "sealed", "--api-url", "https://sealed.example.com", "--auth-token", "auth_sealed_token"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	store, err := profiles.NewStore()
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	result, err := store.GetProfiles()
+	if err != nil || len(result) != 1 {
+		t.Fatalf("Expected 1 profile, got %d (%v)", len(result), err)
+	}
+	stored := result[0].Configuration.AuthToken
+	if !credentials.IsReference(stored) {
+		t.Fatalf("Expected a credential reference in the profiles file, got %q", stored)
+	}
+	if secret, err := credentials.Open(stored); err != nil || secret != "auth_sealed_token" {
+		t.Fatalf("Expected the sealed token, got %q (%v)", secret, err)
+	}
+
+	output, err := executeCommand(rootCmd, "config", "show")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Contains([]byte(output), []byte("...d_token    (stored in encrypted credentials file)")) {
+		t.Errorf("Expected the masked token and its store, got: %s", output)
+	}
+}
+
 // TestDeleteProfileCmd tests the delete profile command
 func TestDeleteProfileCmd(t *testing.T) {
 	// Setup test environment
@@ -1214,3 +1255,145 @@ func TestUpdateProfileCmdAuthTokenOnOAuthActiveErrors(t *testing.T) {
 		t.Errorf("expected error to suggest --oauth=false, got: %v", err)
 	}
 }
+
+// stubCreateAuthToken replaces CreateAuthToken for the duration of the test
+// and records the requests it receives.
+func stubCreateAuthToken(t *testing.T, created *CreatedAuthToken, err error) *[]AuthTokenRequest {
+	t.Helper()
+	var requests []AuthTokenRequest
+	prev := CreateAuthToken
+	CreateAuthToken = func(_ context.Context, req AuthTokenRequest) (*CreatedAuthToken, error) {
+		requests = append(requests, req)
+		return created, err
+	}
+	t.Cleanup(func() { CreateAuthToken = prev })
+	return &requests
+}
+
+// TestCreateProfileCmdCreateAuthToken verifies that --create-auth-token
+// creates a token with the selected profile and stores it in a new profile
+// that takes over the URLs and dataset of the selected one.
+func TestCreateProfileCmdCreateAuthToken(t *testing.T) {
+	_ = setupTestConfigDir(t)
+	requests := stubCreateAuthToken(t, &CreatedAuthToken{ID: "at_1", Token: "auth_new"}, nil)
+
+	ctx := profiles.WithConfiguration(context.Background(), &profiles.Configuration{
+		ApiUrl:  "https://api.example.com",
+		OtlpUrl: "https://ingress.example.com",
+		Dataset: "checkout",
+	})
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	out, err := executeCommandWithContext(ctx, rootCmd, "config", "profiles", "create", "ci",
+		"--create-auth-token", "--scope", "ingest", "--expires-in", "90d")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains([]byte(out), []byte("at_1")) {
+		t.Errorf("expected output to name the new auth token, got: %s", out)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 auth token request, got %d", len(*requests))
+	}
+	req := (*requests)[0]
+	if req.Name != "ci" || req.ApiUrl != "https://api.example.com" || req.ExpiresIn != "90d" {
+		t.Errorf("unexpected auth token request: %+v", req)
+	}
+	if len(req.Scopes) != 1 || req.Scopes[0] != "ingest" {
+		t.Errorf("expected scopes [ingest], got %v", req.Scopes)
+	}
+	if len(req.Datasets) != 0 {
+		t.Errorf("expected no dataset restriction without --dataset, got %v", req.Datasets)
+	}
+
+	store, _ := profiles.NewStore()
+	cfg, err := loadProfileConfig(store, "ci")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if cfg.AuthToken != "auth_new" {
+		t.Errorf("expected the new auth token, got %q", cfg.AuthToken)
+	}
+	if cfg.ApiUrl != "https://api.example.com" || cfg.OtlpUrl != "https://ingress.example.com" || cfg.Dataset != "checkout" {
+		t.Errorf("expected the URLs and dataset of the selected profile, got %+v", cfg)
+	}
+}
+
+// TestCreateProfileCmdCreateAuthTokenRestrictsDataset verifies that an
+// explicit --dataset restricts the new auth token to it.
+func TestCreateProfileCmdCreateAuthTokenRestrictsDataset(t *testing.T) {
+	_ = setupTestConfigDir(t)
+	requests := stubCreateAuthToken(t, &CreatedAuthToken{ID: "at_1", Token: "auth_new"}, nil)
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	_, err := executeCommand(rootCmd, "config", "profiles", "create", "ci",
+		"--create-auth-token", "--scope", "ingest",
+		"--api-url", "https://api.example.com", "--dataset", "production")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("expected 1 auth token request, got %d", len(*requests))
+	}
+	if got := (*requests)[0].Datasets; len(got) != 1 || got[0] != "production" {
+		t.Errorf("expected datasets [production], got %v", got)
+	}
+}
+
+// TestCreateProfileCmdCreateAuthTokenExistingProfile verifies that no token
+// is created for a profile name that is already taken.
+func TestCreateProfileCmdCreateAuthTokenExistingProfile(t *testing.T) {
+	configDir := setupTestConfigDir(t)
+	createTestProfilesFile(t, configDir, []profiles.Profile{
+		{Name: "ci", Configuration: profiles.Configuration{ApiUrl: "https://api.example.com", AuthToken: "auth_old"}},
+	})
+	requests := stubCreateAuthToken(t, &CreatedAuthToken{ID: "at_1", Token: "auth_new"}, nil)
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	_, err := executeCommand(rootCmd, "config", "profiles", "create", "ci",
+		"--create-auth-token", "--scope", "ingest", "--api-url", "https://api.example.com")
+	if err == nil {
+		t.Fatalf("expected an error for an existing profile")
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no auth token to be created, got %d requests", len(*requests))
+	}
+}
+
+// TestCreateProfileCmdCreateAuthTokenFlagCombinations verifies the flags that
+// cannot be combined with --create-auth-token, and the ones that need it.
+func TestCreateProfileCmdCreateAuthTokenFlagCombinations(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"with auth token", []string{"--create-auth-token", "--auth-token", "auth_x"}, "cannot be combined"},
+		{"with oauth", []string{"--create-auth-token", "--oauth", "--api-url", "https://api.example.com"}, "cannot be combined"},
+		{"scope without create", []string{"--scope", "ingest", "--auth-token", "auth_x"}, "require --create-auth-token"},
+		{"no profile to create with", []string{"--create-auth-token", "--scope", "ingest"}, "dash0 login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_ = setupTestConfigDir(t)
+			stubCreateAuthToken(t, &CreatedAuthToken{ID: "at_1", Token: "auth_new"}, nil)
+
+			rootCmd := &cobra.Command{Use: "dash0"}
+			rootCmd.AddCommand(NewConfigCmd())
+
+			_, err := executeCommand(rootCmd, append([]string{"config", "profiles", "create", "ci"}, tt.args...)...)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !bytes.Contains([]byte(err.Error()), []byte(tt.want)) {
+				t.Errorf("expected error to contain %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
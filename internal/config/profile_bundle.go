@@ -0,0 +1,291 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/agentmode"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// Auth kinds of a profile in a profile bundle, named like the `auth` option
+// of the Home Manager module.
+const (
+	bundleAuthOAuth  = "oauth"
+	bundleAuthStatic = "static"
+)
+
+// profileBundle is the portable format `config profiles export` writes and
+// `config profiles import` reads: the non-secret settings of profiles, keyed
+// by profile name. Auth tokens, auth token commands, and OAuth tokens are
+// never part of it, so a bundle can be shared with a team.
+type profileBundle struct {
+	Profiles map[string]bundledProfile `json:"profiles"`
+}
+
+type bundledProfile struct {
+	ApiUrl  string `json:"apiUrl,omitempty"`
+	OtlpUrl string `json:"otlpUrl,omitempty"`
+	Dataset string `json:"dataset,omitempty"`
+	// Auth is "oauth" for a profile that authenticates with `dash0 login`,
+	// and "static" for one that holds an auth token.
+	Auth string `json:"auth"`
+}
+
+// promptAuthToken asks for the auth token of an imported static profile. It
+// returns "" without asking when stdin is not a terminal or agent mode is
+// active. Tests override it.
+var promptAuthToken = func(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if agentmode.Enabled || !term.IsTerminal(fd) {
+		return "", nil
+	}
+	fmt.Printf("Auth token for profile %q (leave empty to add it later): ", name)
+	token, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the auth token: %w", err)
+	}
+	return string(bytes.TrimSpace(token)), nil
+}
+
+// newExportProfileCmd creates a new export profile command
+func newExportProfileCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "export [<name>...]",
+		Short: "Export the non-secret settings of profiles as YAML",
+		Long: `Print the API URL, OTLP URL, dataset, and kind of authentication of profiles
+as a YAML bundle that 'dash0 config profiles import' reads. Auth tokens, auth
+token commands, and OAuth tokens are never exported, so the bundle can be
+shared, e.g. to onboard new team members.
+Without arguments, the active profile is exported; pass --all to export every
+profile.`,
+		Example: `  # Export the active profile
+  dash0 config profiles export > dash0-profiles.yaml
+
+  # Export two profiles
+  dash0 config profiles export prod staging > dash0-profiles.yaml
+
+  # Export every profile
+  dash0 config profiles export --all > dash0-profiles.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return fmt.Errorf("cannot combine --all with profile names")
+			}
+
+			store, err := profiles.NewStore()
+			if err != nil {
+				return err
+			}
+			existing, err := store.GetProfiles()
+			if err != nil {
+				return fmt.Errorf("failed to read profiles: %w", err)
+			}
+
+			names := args
+			switch {
+			case all:
+				names = nil
+				for _, p := range existing {
+					names = append(names, p.Name)
+				}
+				if len(names) == 0 {
+					return fmt.Errorf("no profiles to export")
+				}
+			case len(names) == 0:
+				active, err := store.GetActiveProfile()
+				if err != nil || active == nil {
+					return fmt.Errorf("no active profile to export\nHint: Pass the names of the profiles to export, or --all.")
+				}
+				names = []string{active.Name}
+			}
+
+			bundle, err := newProfileBundle(existing, names)
+			if err != nil {
+				return err
+			}
+			out, err := sigsyaml.Marshal(bundle)
+			if err != nil {
+				return fmt.Errorf("failed to encode profiles: %w", err)
+			}
+			_, err = os.Stdout.Write(out)
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Export every profile")
+
+	return cmd
+}
+
+// newProfileBundle builds the bundle of the named profiles.
+func newProfileBundle(existing []profiles.Profile, names []string) (*profileBundle, error) {
+	byName := make(map[string]profiles.Configuration, len(existing))
+	for _, p := range existing {
+		byName[p.Name] = p.Configuration
+	}
+	bundle := &profileBundle{Profiles: make(map[string]bundledProfile, len(names))}
+	for _, name := range names {
+		cfg, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("profile %q does not exist", name)
+		}
+		auth := bundleAuthStatic
+		if cfg.OAuth != nil {
+			auth = bundleAuthOAuth
+		}
+		bundle.Profiles[name] = bundledProfile{
+			ApiUrl:  cfg.ApiUrl,
+			OtlpUrl: cfg.OtlpUrl,
+			Dataset: cfg.Dataset,
+			Auth:    auth,
+		}
+	}
+	return bundle, nil
+}
+
+// parseProfileBundle parses a bundle. Unknown keys are rejected so a typo
+// does not silently drop a setting.
+func parseProfileBundle(data []byte) (*profileBundle, error) {
+	bundle := &profileBundle{}
+	if err := sigsyaml.UnmarshalStrict(data, bundle); err != nil {
+		return nil, err
+	}
+	if len(bundle.Profiles) == 0 {
+		return nil, fmt.Errorf("the bundle contains no profiles")
+	}
+	for name, p := range bundle.Profiles {
+		if name == "" {
+			return nil, fmt.Errorf("the bundle contains a profile without a name")
+		}
+		switch p.Auth {
+		case "":
+			p.Auth = bundleAuthStatic
+			bundle.Profiles[name] = p
+		case bundleAuthStatic:
+		case bundleAuthOAuth:
+			if p.ApiUrl == "" {
+				return nil, fmt.Errorf("profile %q: OAuth profiles require apiUrl so that 'dash0 login' knows where to authenticate", name)
+			}
+		default:
+			return nil, fmt.Errorf("profile %q: unknown auth %q: must be oauth or static", name, p.Auth)
+		}
+	}
+	return bundle, nil
+}
+
+// newImportProfileCmd creates a new import profile command
+func newImportProfileCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create profiles from a YAML bundle",
+		Long: `Create the profiles of a YAML bundle written by 'dash0 config profiles export'.
+Profiles that already exist are skipped.
+For each static profile, the command asks for its auth token when stdin is a
+terminal; leave the answer empty to add the token later with
+'dash0 config profiles update'. OAuth profiles are created empty and must be
+populated by running 'dash0 login'.`,
+		Example: `  # Import profiles from a file
+  dash0 config profiles import -f dash0-profiles.yaml
+
+  # Import profiles from stdin
+  curl -s https://example.com/dash0-profiles.yaml | dash0 config profiles import -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if file == "" {
+				return fmt.Errorf("-f/--file is required")
+			}
+			var (
+				data []byte
+				err  error
+			)
+			if file == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read profiles: %w", err)
+			}
+			bundle, err := parseProfileBundle(data)
+			if err != nil {
+				return fmt.Errorf("invalid profile bundle: %w", err)
+			}
+			return importProfiles(bundle)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Path to the YAML bundle (use '-' for stdin)")
+
+	return cmd
+}
+
+// importProfiles creates the profiles of the bundle in name order, skipping
+// the ones that exist.
+func importProfiles(bundle *profileBundle) error {
+	store, err := profiles.NewStore()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(bundle.Profiles))
+	for name := range bundle.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := bundle.Profiles[name]
+		if _, err := loadProfileConfig(store, name); err == nil {
+			fmt.Fprintf(os.Stderr, "Warning: profile %q already exists; skipped\n", name)
+			continue
+		}
+
+		config := profiles.Configuration{
+			ApiUrl:  p.ApiUrl,
+			OtlpUrl: p.OtlpUrl,
+			Dataset: p.Dataset,
+		}
+		if p.Auth == bundleAuthOAuth {
+			config.OAuth = &profiles.OAuthState{}
+		} else {
+			token, err := promptAuthToken(name)
+			if err != nil {
+				return err
+			}
+			sealed, err := credentials.Seal(name, credentials.KindAuthToken, token, "")
+			if err != nil {
+				return err
+			}
+			config.AuthToken = sealed
+		}
+
+		if err := store.AddProfile(profiles.Profile{Name: name, Configuration: config}); err != nil {
+			credentials.Remove(config.AuthToken)
+			return fmt.Errorf("failed to add profile %q: %w", name, err)
+		}
+
+		switch {
+		case p.Auth == bundleAuthOAuth:
+			fmt.Printf("Profile %q imported (OAuth).\n", name)
+			fmt.Println(OAuthAuthenticateHint(name))
+		case config.AuthToken == "":
+			fmt.Printf("Profile %q imported.\n", name)
+			fmt.Printf("Hint: Run `dash0 config profiles update %s --auth-token auth_<...>` to add its auth token.\n", name)
+		default:
+			fmt.Printf("Profile %q imported.\n", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/spf13/cobra"
+)
+
+// stubPromptAuthToken replaces promptAuthToken for the duration of the test
+// and answers every prompt with token.
+func stubPromptAuthToken(t *testing.T, token string) *[]string {
+	t.Helper()
+	var prompted []string
+	prev := promptAuthToken
+	promptAuthToken = func(name string) (string, error) {
+		prompted = append(prompted, name)
+		return token, nil
+	}
+	t.Cleanup(func() { promptAuthToken = prev })
+	return &prompted
+}
+
+// TestExportProfileCmdOmitsSecrets verifies that exported profiles carry
+// their URLs, dataset, and kind of authentication, but no secrets.
+func TestExportProfileCmdOmitsSecrets(t *testing.T) {
+	configDir := setupTestConfigDir(t)
+	createTestProfilesFile(t, configDir, []profiles.Profile{
+		{
+			Name: "prod",
+			Configuration: profiles.Configuration{
+				ApiUrl:    "https://api.example.com",
+				OtlpUrl:   "https://ingress.example.com",
+				Dataset:   "checkout",
+				AuthToken: "auth_secret",
+			},
+		},
+		{
+			Name: "sso",
+			Configuration: profiles.Configuration{
+				ApiUrl:    "https://api.example.com",
+				AuthToken: "oauth_access",
+				OAuth:     &profiles.OAuthState{RefreshToken: "refresh_secret"},
+			},
+		},
+		{
+			Name: "other",
+			Configuration: profiles.Configuration{
+				ApiUrl: "https://other.example.com",
+			},
+		},
+	})
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	out, err := executeCommand(rootCmd, "config", "profiles", "export", "prod", "sso")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, secret := range []string{"auth_secret", "oauth_access", "refresh_secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("expected the export to omit %q, got:\n%s", secret, out)
+		}
+	}
+	if strings.Contains(out, "other") {
+		t.Errorf("expected only the named profiles, got:\n%s", out)
+	}
+
+	bundle, err := parseProfileBundle([]byte(out))
+	if err != nil {
+		t.Fatalf("failed to parse the export: %v\n%s", err, out)
+	}
+	want := map[string]bundledProfile{
+		"prod": {ApiUrl: "https://api.example.com", OtlpUrl: "https://ingress.example.com", Dataset: "checkout", Auth: bundleAuthStatic},
+		"sso":  {ApiUrl: "https://api.example.com", Auth: bundleAuthOAuth},
+	}
+	if len(bundle.Profiles) != len(want) {
+		t.Fatalf("expected %d profiles, got %+v", len(want), bundle.Profiles)
+	}
+	for name, p := range want {
+		if bundle.Profiles[name] != p {
+			t.Errorf("profile %q: expected %+v, got %+v", name, p, bundle.Profiles[name])
+		}
+	}
+}
+
+// TestExportProfileCmdActiveProfile verifies that export without arguments
+// exports the active profile, and that unknown names are rejected.
+func TestExportProfileCmdActiveProfile(t *testing.T) {
+	configDir := setupTestConfigDir(t)
+	createTestProfilesFile(t, configDir, []profiles.Profile{
+		{Name: "a", Configuration: profiles.Configuration{ApiUrl: "https://a.example.com"}},
+		{Name: "b", Configuration: profiles.Configuration{ApiUrl: "https://b.example.com"}},
+	})
+	setActiveProfile(t, configDir, "b")
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	out, err := executeCommand(rootCmd, "config", "profiles", "export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "https://b.example.com") || strings.Contains(out, "https://a.example.com") {
+		t.Errorf("expected only the active profile, got:\n%s", out)
+	}
+
+	rootCmd = &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+	if _, err := executeCommand(rootCmd, "config", "profiles", "export", "missing"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+// TestParseProfileBundleInvalid verifies that malformed bundles are rejected.
+func TestParseProfileBundleInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":         "profiles:\n  prod:\n    apiUrl: https://api.example.com\n    authToken: auth_x\n",
+		"unknown auth":        "profiles:\n  prod:\n    auth: saml\n",
+		"oauth without url":   "profiles:\n  prod:\n    auth: oauth\n",
+		"no profiles":         "profiles: {}\n",
+		"not a bundle at all": "- prod\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseProfileBundle([]byte(data)); err == nil {
+				t.Errorf("expected an error for:\n%s", data)
+			}
+		})
+	}
+}
+
+// TestImportProfileCmd verifies that import creates the profiles of a bundle,
+// asks for the auth tokens of static profiles, and skips existing profiles.
+func TestImportProfileCmd(t *testing.T) {
+	configDir := setupTestConfigDir(t)
+	createTestProfilesFile(t, configDir, []profiles.Profile{
+		{Name: "existing", Configuration: profiles.Configuration{ApiUrl: "https://keep.example.com", AuthToken: "auth_keep"}},
+	})
+	prompted := stubPromptAuthToken(t, "auth_entered")
+
+	file := filepath.Join(t.TempDir(), "bundle.yaml")
+	bundle := `profiles:
+  prod:
+    apiUrl: https://api.example.com
+    otlpUrl: https://ingress.example.com
+    dataset: checkout
+    auth: static
+  sso:
+    apiUrl: https://api.example.com
+    auth: oauth
+  existing:
+    apiUrl: https://replaced.example.com
+`
+	if err := os.WriteFile(file, []byte(bundle), 0600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	out, err := executeCommand(rootCmd, "config", "profiles", "import", "-f", file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "dash0 login") {
+		t.Errorf("expected a hint to log in to the OAuth profile, got:\n%s", out)
+	}
+	if len(*prompted) != 1 || (*prompted)[0] != "prod" {
+		t.Errorf("expected a prompt for the static profile only, got %v", *prompted)
+	}
+
+	store, _ := profiles.NewStore()
+	prod, err := loadProfileConfig(store, "prod")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if prod.ApiUrl != "https://api.example.com" || prod.OtlpUrl != "https://ingress.example.com" ||
+		prod.Dataset != "checkout" || prod.AuthToken != "auth_entered" {
+		t.Errorf("unexpected imported profile: %+v", prod)
+	}
+	sso, err := loadProfileConfig(store, "sso")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if sso.OAuth == nil || sso.AuthToken != "" {
+		t.Errorf("expected an empty OAuth profile, got %+v", sso)
+	}
+	existing, err := loadProfileConfig(store, "existing")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if existing.ApiUrl != "https://keep.example.com" || existing.AuthToken != "auth_keep" {
+		t.Errorf("expected the existing profile to be kept, got %+v", existing)
+	}
+}
+
+// TestImportProfileCmdWithoutToken verifies that a static profile imported
+// without an auth token points at how to add one.
+func TestImportProfileCmdWithoutToken(t *testing.T) {
+	_ = setupTestConfigDir(t)
+	_ = stubPromptAuthToken(t, "")
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+	rootCmd.SetIn(strings.NewReader("profiles:\n  prod:\n    apiUrl: https://api.example.com\n"))
+
+	out, err := executeCommand(rootCmd, "config", "profiles", "import", "-f", "-")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "dash0 config profiles update prod --auth-token") {
+		t.Errorf("expected a hint to add the auth token, got:\n%s", out)
+	}
+
+	store, _ := profiles.NewStore()
+	prod, err := loadProfileConfig(store, "prod")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if prod.AuthToken != "" {
+		t.Errorf("expected no auth token, got %q", prod.AuthToken)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
 )
 
 // EnvProfile is the environment variable that selects a named profile for the
@@ -25,6 +26,9 @@ const (
 	ProfileSourceFlag
 	// ProfileSourceEnv indicates the selection came from the DASH0_PROFILE env var.
 	ProfileSourceEnv
+	// ProfileSourceProject indicates the selection came from the project
+	// configuration file (.dash0.yaml).
+	ProfileSourceProject
 )
 
 // Description returns a human-readable source description suitable for
@@ -35,6 +39,8 @@ func (s ProfileSource) Description() string {
 		return "from --profile flag"
 	case ProfileSourceEnv:
 		return "from " + EnvProfile + " environment variable"
+	case ProfileSourceProject:
+		return "from " + ProjectFileName
 	default:
 		return ""
 	}
@@ -47,6 +53,18 @@ type ProfileSelector struct {
 	Name string
 	// Source identifies where the Name came from.
 	Source ProfileSource
+	// File is the path of the project configuration file, when Source is
+	// ProfileSourceProject.
+	File string
+}
+
+// Description returns a human-readable source description suitable for
+// displaying in `config show`, naming the file for a project selection.
+func (s ProfileSelector) Description() string {
+	if s.Source == ProfileSourceProject && s.File != "" {
+		return "from " + s.File
+	}
+	return s.Source.Description()
 }
 
 // IsSet reports whether the selector has a non-empty explicit name.
@@ -55,16 +73,20 @@ func (s ProfileSelector) IsSet() bool {
 }
 
 // ResolveProfileSelector returns the explicit profile selector derived from
-// the --profile flag value and the DASH0_PROFILE environment variable.
+// the --profile flag value, the DASH0_PROFILE environment variable, and the
+// profile pinned by the project configuration file, if any.
 // Empty strings are treated as "not set" and fall through to the next
 // precedence step, which means the caller should use the active profile.
-func ResolveProfileSelector(flagValue string) ProfileSelector {
+func ResolveProfileSelector(flagValue string, project *ProjectConfig) ProfileSelector {
 	if flagValue != "" {
 		return ProfileSelector{Name: flagValue, Source: ProfileSourceFlag}
 	}
 	if envValue := os.Getenv(EnvProfile); envValue != "" {
 		return ProfileSelector{Name: envValue, Source: ProfileSourceEnv}
 	}
+	if project != nil && project.Profile != "" {
+		return ProfileSelector{Name: project.Profile, Source: ProfileSourceProject, File: project.Path}
+	}
 	return ProfileSelector{}
 }
 
@@ -90,7 +112,8 @@ func ProfileSelectorFromContext(ctx context.Context) ProfileSelector {
 // resolution chain; this function only handles explicit selections.
 //
 // The returned configuration records the profile it came from, which is what
-// lets the client refresh its OAuth access token per request.
+// lets the client refresh its OAuth access token per request. Secrets kept in
+// a credential store are opened in the returned copy.
 func ResolveConfigurationForProfile(ctx context.Context, profileName string) (*profiles.Configuration, error) {
 	if profileName == "" {
 		return nil, fmt.Errorf("profile name must not be empty")
@@ -103,6 +126,9 @@ func ResolveConfigurationForProfile(ctx context.Context, profileName string) (*p
 
 	cfg, err := store.GetConfigurationForProfile(ctx, profileName)
 	if err == nil {
+		if err := credentials.OpenConfiguration(profileName, cfg); err != nil {
+			return nil, err
+		}
 		return cfg, nil
 	}
 	if !errors.Is(err, profiles.ErrProfileNotFound) {
@@ -115,10 +141,10 @@ func ResolveConfigurationForProfile(ctx context.Context, profileName string) (*p
 	}
 
 	if len(all) == 0 {
-		return nil, fmt.Errorf(
+		return nil, profileNotFoundError(fmt.Sprintf(
 			"profile %q does not exist; no profiles are configured.\nHint: create one with 'dash0 config profiles create'",
 			profileName,
-		)
+		))
 	}
 
 	names := make([]string, 0, len(all))
@@ -127,9 +153,17 @@ func ResolveConfigurationForProfile(ctx context.Context, profileName string) (*p
 	}
 
 	sort.Strings(names)
-	return nil, fmt.Errorf(
+	return nil, profileNotFoundError(fmt.Sprintf(
 		"profile %q does not exist. Available profiles: %s",
 		profileName,
 		strings.Join(names, ", "),
-	)
+	))
 }
+
+// profileNotFoundError is the error of ResolveConfigurationForProfile for a
+// profile that does not exist. It matches [profiles.ErrProfileNotFound].
+type profileNotFoundError string
+
+func (e profileNotFoundError) Error() string { return string(e) }
+
+func (e profileNotFoundError) Is(target error) bool { return target == profiles.ErrProfileNotFound }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -24,7 +25,7 @@ func TestResolveProfileSelector(t *testing.T) {
 		os.Setenv(EnvProfile, "from-env")
 		defer os.Unsetenv(EnvProfile)
 
-		sel := ResolveProfileSelector("from-flag")
+		sel := ResolveProfileSelector("from-flag", nil)
 		if !sel.IsSet() {
 			t.Fatal("selector should be set")
 		}
@@ -40,7 +41,7 @@ func TestResolveProfileSelector(t *testing.T) {
 		os.Setenv(EnvProfile, "from-env")
 		defer os.Unsetenv(EnvProfile)
 
-		sel := ResolveProfileSelector("")
+		sel := ResolveProfileSelector("", nil)
 		if !sel.IsSet() {
 			t.Fatal("selector should be set")
 		}
@@ -55,7 +56,7 @@ func TestResolveProfileSelector(t *testing.T) {
 	t.Run("empty flag and empty env var fall through", func(t *testing.T) {
 		os.Unsetenv(EnvProfile)
 
-		sel := ResolveProfileSelector("")
+		sel := ResolveProfileSelector("", nil)
 		if sel.IsSet() {
 			t.Errorf("selector should not be set, got %+v", sel)
 		}
@@ -68,7 +69,7 @@ func TestResolveProfileSelector(t *testing.T) {
 		os.Setenv(EnvProfile, "")
 		defer os.Unsetenv(EnvProfile)
 
-		sel := ResolveProfileSelector("")
+		sel := ResolveProfileSelector("", nil)
 		if sel.IsSet() {
 			t.Errorf("selector should not be set, got %+v", sel)
 		}
@@ -173,6 +174,9 @@ func TestResolveConfigurationForProfile(t *testing.T) {
 		if !strings.Contains(msg, "dev") || !strings.Contains(msg, "prod") {
 			t.Errorf("expected available profiles in error, got %q", msg)
 		}
+		if !errors.Is(err, profiles.ErrProfileNotFound) {
+			t.Errorf("expected the error to match profiles.ErrProfileNotFound, got %v", err)
+		}
 	})
 
 	t.Run("empty name is rejected", func(t *testing.T) {
@@ -197,4 +201,7 @@ func TestResolveConfigurationForProfile_NoProfilesAtAll(t *testing.T) {
 	if !strings.Contains(msg, "dash0 config profiles create") {
 		t.Errorf("expected hint in error, got %q", msg)
 	}
+	if !errors.Is(err, profiles.ErrProfileNotFound) {
+		t.Errorf("expected the error to match profiles.ErrProfileNotFound, got %v", err)
+	}
 }
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+	"github.com/spf13/cobra"
+)
+
+// tlsFlags holds the flags of the TLS settings of a profile.
+type tlsFlags struct {
+	caFile, clientCert, clientKey string
+}
+
+func (f *tlsFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.caFile, "ca-file", "", "PEM file of CA certificates to trust in addition to the system ones, e.g. the CA of a TLS-intercepting proxy")
+	cmd.Flags().StringVar(&f.clientCert, "client-cert", "", "PEM file of the client certificate for mutual TLS (requires --client-key)")
+	cmd.Flags().StringVar(&f.clientKey, "client-key", "", "PEM file of the private key of --client-cert")
+}
+
+// changed reports whether any of the flags was given.
+func (f *tlsFlags) changed(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("ca-file") || cmd.Flags().Changed("client-cert") || cmd.Flags().Changed("client-key")
+}
+
+// apply sets the TLS settings of cfg that were given on the command line.
+// The paths are stored as absolute paths, so that the profile works from any
+// directory.
+func (f *tlsFlags) apply(cmd *cobra.Command, cfg *profiles.Configuration) error {
+	for _, setting := range []struct {
+		flag  string
+		value string
+		dst   *string
+	}{
+		{"ca-file", f.caFile, &cfg.CaFile},
+		{"client-cert", f.clientCert, &cfg.ClientCert},
+		{"client-key", f.clientKey, &cfg.ClientKey},
+	} {
+		if !cmd.Flags().Changed(setting.flag) {
+			continue
+		}
+		path := setting.value
+		if path != "" {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return fmt.Errorf("invalid --%s %q: %w", setting.flag, path, err)
+			}
+			path = abs
+		}
+		*setting.dst = path
+	}
+	return nil
+}
+
+// validateProfileTLS checks that the CA file and client certificate of cfg
+// can be loaded, before they are stored.
+func validateProfileTLS(cfg profiles.Configuration) error {
+	return httptransport.Settings{CAFile: cfg.CaFile, ClientCert: cfg.ClientCert, ClientKey: cfg.ClientKey}.Validate()
+}
+
+// configShowTLS is the JSON-serializable representation of the effective TLS
+// settings in config show output.
+type configShowTLS struct {
+	CAFile             *configShowField `json:"caFile,omitempty"`
+	ClientCert         *configShowField `json:"clientCert,omitempty"`
+	ClientKey          *configShowField `json:"clientKey,omitempty"`
+	InsecureSkipVerify bool             `json:"insecureSkipVerify"`
+}
+
+func showTLS(s httptransport.Settings) *configShowTLS {
+	result := &configShowTLS{InsecureSkipVerify: s.InsecureSkipVerify}
+	if s.CAFile != "" {
+		result.CAFile = showField(s.CAFile, os.Getenv(httptransport.EnvCAFile), httptransport.EnvCAFile)
+	}
+	certEnv := os.Getenv(httptransport.EnvClientCert) + os.Getenv(httptransport.EnvClientKey)
+	if s.ClientCert != "" {
+		result.ClientCert = showField(s.ClientCert, certEnv, httptransport.EnvClientCert)
+	}
+	if s.ClientKey != "" {
+		result.ClientKey = showField(s.ClientKey, certEnv, httptransport.EnvClientKey)
+	}
+	return result
+}
+
+// tlsLines describes the effective TLS settings for the table output of
+// config show, one line per aspect.
+func tlsLines(s httptransport.Settings) []string {
+	var lines []string
+	switch {
+	case s.InsecureSkipVerify:
+		lines = append(lines, "certificate verification DISABLED (--insecure-skip-tls-verify); connections can be intercepted")
+	case s.CAFile != "":
+		line := "system CA certificates and " + s.CAFile
+		if os.Getenv(httptransport.EnvCAFile) != "" {
+			line += fmt.Sprintf("    (from %s environment variable)", httptransport.EnvCAFile)
+		}
+		lines = append(lines, line)
+	default:
+		lines = append(lines, "system CA certificates")
+	}
+	if s.ClientCert != "" || s.ClientKey != "" {
+		line := fmt.Sprintf("client certificate %s, key %s", orNotSet(s.ClientCert), orNotSet(s.ClientKey))
+		if os.Getenv(httptransport.EnvClientCert) != "" || os.Getenv(httptransport.EnvClientKey) != "" {
+			line += fmt.Sprintf("    (from %s and %s environment variables)", httptransport.EnvClientCert, httptransport.EnvClientKey)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func orNotSet(s string) string {
+	if s == "" {
+		return "(not set)"
+	}
+	return s
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+	"github.com/spf13/cobra"
+)
+
+// writeCAFile writes the certificate of a test TLS server as a PEM file to
+// dir and returns its path.
+func writeCAFile(t *testing.T, dir string) string {
+	t.Helper()
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	server.Close()
+	path := filepath.Join(dir, "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write CA file: %v", err)
+	}
+	return path
+}
+
+// TestCreateProfileCmdCAFile verifies that the CA file of a new profile is
+// stored as an absolute path.
+func TestCreateProfileCmdCAFile(t *testing.T) {
+	_ = setupTestConfigDir(t)
+	dir := t.TempDir()
+	_ = writeCAFile(t, dir)
+	t.Chdir(dir)
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+
+	if _, err := executeCommand(rootCmd, "config", "profiles", "create", "corp",
+		"--api-url", "https://api.example.com", "--auth-token", "auth_x", "--ca-file", "ca.pem"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store, _ := profiles.NewStore()
+	corp, err := loadProfileConfig(store, "corp")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+	if want := filepath.Join(dir, "ca.pem"); corp.CaFile != want {
+		t.Errorf("expected CA file %q, got %q", want, corp.CaFile)
+	}
+}
+
+// TestCreateProfileCmdInvalidTLSFiles verifies that a profile is not created
+// with TLS files that cannot be loaded.
+func TestCreateProfileCmdInvalidTLSFiles(t *testing.T) {
+	_ = setupTestConfigDir(t)
+	caFile := writeCAFile(t, t.TempDir())
+
+	tests := map[string][]string{
+		"missing CA file":        {"--ca-file", filepath.Join(t.TempDir(), "missing.pem")},
+		"certificate alone":      {"--client-cert", caFile},
+		"certificate as the key": {"--client-cert", caFile, "--client-key", caFile},
+	}
+	for name, flags := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "dash0"}
+			rootCmd.AddCommand(NewConfigCmd())
+
+			args := append([]string{"config", "profiles", "create", "corp", "--api-url", "https://api.example.com"}, flags...)
+			if _, err := executeCommand(rootCmd, args...); err == nil {
+				t.Fatal("expected an error")
+			}
+			store, _ := profiles.NewStore()
+			if _, err := loadProfileConfig(store, "corp"); err == nil {
+				t.Error("expected the profile not to be created")
+			}
+		})
+	}
+}
+
+// TestUpdateProfileCmdCAFile verifies that update sets and removes the CA
+// file of a profile, and leaves the other settings alone.
+func TestUpdateProfileCmdCAFile(t *testing.T) {
+	configDir := setupTestConfigDir(t)
+	createTestProfilesFile(t, configDir, []profiles.Profile{
+		{Name: "prod", Configuration: profiles.Configuration{ApiUrl: "https://api.example.com", AuthToken: "auth_x"}},
+	})
+	caFile := writeCAFile(t, t.TempDir())
+
+	for _, value := range []string{caFile, ""} {
+		rootCmd := &cobra.Command{Use: "dash0"}
+		rootCmd.AddCommand(NewConfigCmd())
+		if _, err := executeCommand(rootCmd, "config", "profiles", "update", "prod", "--ca-file", value); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		store, _ := profiles.NewStore()
+		prod, err := loadProfileConfig(store, "prod")
+		if err != nil {
+			t.Fatalf("failed to load profile: %v", err)
+		}
+		if prod.CaFile != value || prod.ApiUrl != "https://api.example.com" || prod.AuthToken != "auth_x" {
+			t.Errorf("expected CA file %q and the other settings unchanged, got %+v", value, prod)
+		}
+	}
+}
+
+// TestShowCmdTLS verifies that config show displays the effective TLS
+// settings and where they come from.
+func TestShowCmdTLS(t *testing.T) {
+	configDir := setupTestConfigDir(t)
+	createTestProfilesFile(t, configDir, []profiles.Profile{
+		{Name: "corp", Configuration: profiles.Configuration{
+			ApiUrl:     "https://api.example.com",
+			AuthToken:  "auth_x",
+			CaFile:     "/profile/ca.pem",
+			ClientCert: "/profile/client.pem",
+			ClientKey:  "/profile/client.key",
+		}},
+	})
+	setActiveProfile(t, configDir, "corp")
+	t.Setenv(httptransport.EnvCAFile, "/env/ca.pem")
+	t.Setenv(httptransport.EnvClientCert, "")
+	t.Setenv(httptransport.EnvClientKey, "")
+
+	rootCmd := &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+	output, err := executeCommand(rootCmd, "config", "show")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"TLS:        system CA certificates and /env/ca.pem    (from DASH0_CA_FILE environment variable)",
+		"            client certificate /profile/client.pem, key /profile/client.key\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	rootCmd = &cobra.Command{Use: "dash0"}
+	rootCmd.AddCommand(NewConfigCmd())
+	ctx := httptransport.WithInsecureSkipVerify(context.Background())
+	output, err = executeCommandWithContext(ctx, rootCmd, "config", "show", "-o", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result configShowJSON
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("failed to parse output: %v\n%s", err, output)
+	}
+	if result.TLS == nil || !result.TLS.InsecureSkipVerify {
+		t.Fatalf("expected insecureSkipVerify, got %+v", result.TLS)
+	}
+	if result.TLS.CAFile == nil || result.TLS.CAFile.Value != "/env/ca.pem" || result.TLS.CAFile.Source != httptransport.EnvCAFile {
+		t.Errorf("expected the CA file from %s, got %+v", httptransport.EnvCAFile, result.TLS.CAFile)
+	}
+	if result.TLS.ClientCert == nil || result.TLS.ClientCert.Value != "/profile/client.pem" || result.TLS.ClientCert.Source != "" {
+		t.Errorf("expected the client certificate of the profile, got %+v", result.TLS.ClientCert)
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// ProjectFileName is the name of the project configuration file. The CLI
+// looks for it in the working directory and each of its parents, and uses
+// the first one it finds.
+const ProjectFileName = ".dash0.yaml"
+
+// ProjectConfig is the schema of a project configuration file. It pins
+// settings for everyone working in a repository, between the environment
+// variables and the selected profile in precedence. Keys mirror the names of
+// the flags they default.
+//
+// Credentials are deliberately absent, so the file can be committed.
+type ProjectConfig struct {
+	// Profile selects the profile to use when neither --profile nor
+	// DASH0_PROFILE is set.
+	Profile string `json:"profile,omitempty"`
+	// Dataset is used when neither --dataset nor DASH0_DATASET is set,
+	// instead of the dataset of the profile.
+	Dataset string `json:"dataset,omitempty"`
+	// ApplyRoot is the file or directory `dash0 apply` reads when -f is not
+	// given, relative to the directory of the file.
+	ApplyRoot string `json:"apply-root,omitempty"`
+	// Filters are added to the --filter flags of the query commands, keyed
+	// by signal: "logs", "spans", and "failed-checks".
+	Filters map[string][]string `json:"filters,omitempty"`
+
+	// Path is the absolute path of the file the configuration was read from.
+	Path string `json:"-"`
+}
+
+// projectFilterKeys are the keys accepted under `filters`.
+var projectFilterKeys = []string{"logs", "spans", "failed-checks"}
+
+// LoadProjectConfig finds and parses the project configuration file for the
+// working directory. It returns nil when there is none.
+func LoadProjectConfig() (*ProjectConfig, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, nil
+	}
+	return FindProjectConfig(wd)
+}
+
+// FindProjectConfig looks for a project configuration file in dir and each
+// of its parents, and parses the first one it finds. It returns nil when
+// there is none.
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			project, err := parseProjectConfig(data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid project configuration file %s: %w", path, err)
+			}
+			project.Path = path
+			return project, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read project configuration file: %w", err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// parseProjectConfig parses the file contents. Unknown keys are rejected so a
+// typo does not silently leave a setting at its default.
+func parseProjectConfig(data []byte) (*ProjectConfig, error) {
+	project := &ProjectConfig{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return project, nil
+	}
+	if err := sigsyaml.UnmarshalStrict(data, project); err != nil {
+		return nil, err
+	}
+	for key := range project.Filters {
+		if !slices.Contains(projectFilterKeys, key) {
+			return nil, fmt.Errorf("unknown filters key %q: must be one of logs, spans, failed-checks", key)
+		}
+	}
+	return project, nil
+}
+
+// ApplyRootPath returns the apply root resolved against the directory of the
+// file, or "" when the file sets none.
+func (p *ProjectConfig) ApplyRootPath() string {
+	if p == nil || p.ApplyRoot == "" {
+		return ""
+	}
+	if filepath.IsAbs(p.ApplyRoot) {
+		return p.ApplyRoot
+	}
+	return filepath.Join(filepath.Dir(p.Path), p.ApplyRoot)
+}
+
+// FiltersFor returns the filters the file adds to the query command of the
+// given signal.
+func (p *ProjectConfig) FiltersFor(signal string) []string {
+	if p == nil {
+		return nil
+	}
+	return p.Filters[signal]
+}
+
+type projectConfigContextKey struct{}
+
+// WithProjectConfig returns a new context carrying the project configuration,
+// which may be nil.
+func WithProjectConfig(ctx context.Context, project *ProjectConfig) context.Context {
+	return context.WithValue(ctx, projectConfigContextKey{}, project)
+}
+
+// ProjectConfigFromContext returns the project configuration stored in ctx,
+// or nil if there is none.
+func ProjectConfigFromContext(ctx context.Context) *ProjectConfig {
+	project, _ := ctx.Value(projectConfigContextKey{}).(*ProjectConfig)
+	return project
+}
+
+// ProjectDataset returns the dataset pinned by the project configuration in
+// ctx, or "" when there is none or DASH0_DATASET takes precedence over it.
+// It applies to the profile of the project configuration and to the active
+// profile only: a profile chosen with --profile or DASH0_PROFILE brings its
+// own dataset.
+func ProjectDataset(ctx context.Context) string {
+	if os.Getenv(profiles.EnvDataset) != "" {
+		return ""
+	}
+	if sel := ProfileSelectorFromContext(ctx); sel.IsSet() && sel.Source != ProfileSourceProject {
+		return ""
+	}
+	if project := ProjectConfigFromContext(ctx); project != nil {
+		return project.Dataset
+	}
+	return ""
+}
+
+// QueryFilters returns the filters for the query command of the given
+// signal: those of the project configuration in ctx, followed by the ones
+// passed with --filter. Both apply.
+func QueryFilters(ctx context.Context, signal string, flagFilters []string) []string {
+	projectFilters := ProjectConfigFromContext(ctx).FiltersFor(signal)
+	if len(projectFilters) == 0 {
+		return flagFilters
+	}
+	return append(append([]string{}, projectFilters...), flagFilters...)
+}
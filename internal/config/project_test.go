@@ -0,0 +1,134 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+)
+
+func writeProjectFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, ProjectFileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write project file: %v", err)
+	}
+	return path
+}
+
+func TestFindProjectConfig_WalksUpward(t *testing.T) {
+	root := t.TempDir()
+	path := writeProjectFile(t, root, "profile: prod\ndataset: checkout\napply-root: dash0\nfilters:\n  logs:\n    - service.name is checkout\n")
+	nested := filepath.Join(root, "services", "checkout")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	project, err := FindProjectConfig(nested)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if project == nil {
+		t.Fatal("expected the project file in a parent directory to be found")
+	}
+	if project.Path != path {
+		t.Errorf("expected path %q, got %q", path, project.Path)
+	}
+	if project.Profile != "prod" || project.Dataset != "checkout" {
+		t.Errorf("unexpected project configuration: %+v", project)
+	}
+	if got, want := project.ApplyRootPath(), filepath.Join(root, "dash0"); got != want {
+		t.Errorf("expected apply root %q, got %q", want, got)
+	}
+	if got := project.FiltersFor("logs"); len(got) != 1 || got[0] != "service.name is checkout" {
+		t.Errorf("unexpected log filters: %q", got)
+	}
+}
+
+func TestFindProjectConfig_NoneFound(t *testing.T) {
+	project, err := FindProjectConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The temporary directory may live below a directory with a project
+	// file of its own; only a file inside it would be a bug.
+	if project != nil && strings.HasPrefix(project.Path, os.TempDir()) {
+		t.Errorf("expected no project file, got %q", project.Path)
+	}
+}
+
+func TestFindProjectConfig_RejectsUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeProjectFile(t, dir, "datset: checkout\n")
+
+	_, err := FindProjectConfig(dir)
+	if err == nil || !strings.Contains(err.Error(), "invalid project configuration file") {
+		t.Fatalf("expected an invalid-file error, got %v", err)
+	}
+
+	writeProjectFile(t, dir, "filters:\n  metrics:\n    - service.name is checkout\n")
+	_, err = FindProjectConfig(dir)
+	if err == nil || !strings.Contains(err.Error(), `unknown filters key "metrics"`) {
+		t.Fatalf("expected an unknown-filters-key error, got %v", err)
+	}
+}
+
+func TestResolveProfileSelector_Project(t *testing.T) {
+	t.Setenv(EnvProfile, "")
+	project := &ProjectConfig{Profile: "prod", Path: "/repo/.dash0.yaml"}
+
+	sel := ResolveProfileSelector("", project)
+	if sel.Name != "prod" || sel.Source != ProfileSourceProject {
+		t.Fatalf("expected the project profile, got %+v", sel)
+	}
+	if got, want := sel.Description(), "from /repo/.dash0.yaml"; got != want {
+		t.Errorf("expected description %q, got %q", want, got)
+	}
+
+	t.Setenv(EnvProfile, "from-env")
+	if sel := ResolveProfileSelector("", project); sel.Source != ProfileSourceEnv {
+		t.Errorf("expected DASH0_PROFILE to take precedence, got %+v", sel)
+	}
+	if sel := ResolveProfileSelector("from-flag", project); sel.Source != ProfileSourceFlag {
+		t.Errorf("expected --profile to take precedence, got %+v", sel)
+	}
+}
+
+func TestProjectDatasetAndFilters(t *testing.T) {
+	t.Setenv(profiles.EnvDataset, "")
+	project := &ProjectConfig{
+		Dataset: "checkout",
+		Filters: map[string][]string{"spans": {"service.name is checkout"}},
+	}
+	ctx := WithProjectConfig(context.Background(), project)
+
+	if got := ProjectDataset(ctx); got != "checkout" {
+		t.Errorf("expected dataset %q, got %q", "checkout", got)
+	}
+	got := QueryFilters(ctx, "spans", []string{"otel.span.status.code is ERROR"})
+	if strings.Join(got, "|") != "service.name is checkout|otel.span.status.code is ERROR" {
+		t.Errorf("unexpected filters %q", got)
+	}
+	if got := QueryFilters(ctx, "logs", nil); len(got) != 0 {
+		t.Errorf("expected no log filters, got %q", got)
+	}
+
+	projectCtx := WithProfileSelector(ctx, ProfileSelector{Name: "prod", Source: ProfileSourceProject, File: "/repo/.dash0.yaml"})
+	if got := ProjectDataset(projectCtx); got != "checkout" {
+		t.Errorf("expected the dataset for the profile of the project file, got %q", got)
+	}
+	for _, source := range []ProfileSource{ProfileSourceFlag, ProfileSourceEnv} {
+		explicitCtx := WithProfileSelector(ctx, ProfileSelector{Name: "other", Source: source})
+		if got := ProjectDataset(explicitCtx); got != "" {
+			t.Errorf("expected no dataset for a profile %s, got %q", source.Description(), got)
+		}
+	}
+
+	t.Setenv(profiles.EnvDataset, "from-env")
+	if got := ProjectDataset(ctx); got != "" {
+		t.Errorf("expected DASH0_DATASET to take precedence, got %q", got)
+	}
+}
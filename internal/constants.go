@@ -4,11 +4,15 @@ const HEADER_ID = "ID"
 const HEADER_API_VERSION = "APIVERSION"
 const HEADER_CONTEXT = "CONTEXT"
 const HEADER_DATASET = "DATASET"
+const HEADER_DATASETS = "DATASETS"
 const HEADER_EMAIL = "EMAIL"
+const HEADER_EXPIRES = "EXPIRES"
 const HEADER_FILTERS = "FILTERS"
+const HEADER_LAST_USED = "LAST USED"
 const HEADER_MEMBERS = "MEMBERS"
 const HEADER_NAME = "NAME"
 const HEADER_ORIGIN = "ORIGIN"
+const HEADER_SCOPES = "SCOPES"
 const HEADER_TYPE = "TYPE"
 const HEADER_URL = "URL"
 
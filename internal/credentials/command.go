@@ -0,0 +1,63 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds a single run of an auth token command. Helpers that
+// need a login prompt should be run once by hand beforehand.
+const commandTimeout = time.Minute
+
+// ParseCommand parses the auth token command of a profile, as given to
+// --auth-token-command: either a JSON array of arguments, e.g.
+// ["vault", "read", "-field=token", "secret/dash0"], or a plain command line,
+// which is split at whitespace.
+func ParseCommand(command string) ([]string, error) {
+	command = strings.TrimSpace(command)
+	var args []string
+	if strings.HasPrefix(command, "[") {
+		if err := json.Unmarshal([]byte(command), &args); err != nil {
+			return nil, fmt.Errorf("invalid auth token command %s: %w", command, err)
+		}
+	} else {
+		args = strings.Fields(command)
+	}
+	if len(args) == 0 || args[0] == "" {
+		return nil, errors.New("the auth token command must not be empty")
+	}
+	return args, nil
+}
+
+// RunCommand runs an auth token command and returns the token it prints on
+// stdout, without surrounding whitespace. What the command prints on stderr
+// is quoted in the error when it fails.
+func RunCommand(ctx context.Context, args []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("auth token command %q failed: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("auth token command %q failed: %w", args[0], err)
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("auth token command %q printed no token", args[0])
+	}
+	if strings.ContainsAny(token, "\r\n") {
+		return "", fmt.Errorf("auth token command %q printed more than one line; it must print the token only", args[0])
+	}
+	return token, nil
+}
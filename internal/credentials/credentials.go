@@ -0,0 +1,216 @@
+// Package credentials keeps the secrets of a profile — static auth tokens and
+// OAuth refresh tokens — out of the plaintext profiles file. When a credential
+// store is selected via DASH0_CREDENTIAL_STORE, the profile holds a reference
+// of the form "dash0-credential:<store>:<account>" in place of the secret, and
+// the secret itself lives in the OS keychain (macOS Keychain, the Secret
+// Service on Linux, Windows Credential Manager) or in a passphrase-encrypted
+// file under the configuration directory.
+//
+// References are opened in memory when a command loads its configuration;
+// the API client never sees them.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	// EnvCredentialStore selects where new secrets are written: "plaintext"
+	// (the default: inside the profiles file), "system" (the credential store
+	// of the operating system), or "encrypted-file".
+	EnvCredentialStore = "DASH0_CREDENTIAL_STORE"
+	// EnvCredentialPassphrase is the passphrase of the encrypted-file store.
+	EnvCredentialPassphrase = "DASH0_CREDENTIAL_PASSPHRASE"
+)
+
+// Store names accepted by DASH0_CREDENTIAL_STORE.
+const (
+	StorePlaintext     = "plaintext"
+	StoreSystem        = "system"
+	StoreEncryptedFile = "encrypted-file"
+)
+
+// Kinds of secrets a profile holds. The kind is part of the account name
+// under which the secret is stored.
+const (
+	KindAuthToken    = "auth-token"
+	KindRefreshToken = "refresh-token"
+)
+
+// referencePrefix marks a profile field whose secret lives in a credential
+// store. No Dash0 token starts with it.
+const referencePrefix = "dash0-credential:"
+
+// serviceName is the service (or target prefix) under which the secrets are
+// stored in the OS credential stores.
+const serviceName = "dash0-cli"
+
+// ErrNotFound is returned when a credential store has no secret for a
+// reference, e.g. because the keychain entry was deleted by hand.
+var ErrNotFound = errors.New("secret not found in the credential store")
+
+// backend is a place to keep secrets, addressed by account name.
+type backend interface {
+	// description names the backend for humans, e.g. "macOS Keychain".
+	description() string
+	get(account string) (string, error)
+	set(account, secret string) error
+	delete(account string) error
+}
+
+// backendFor returns the backend with the given ID, as recorded in
+// references.
+func backendFor(id string) (backend, error) {
+	switch {
+	case id == encryptedFileBackendID:
+		return newEncryptedFileBackend(), nil
+	case id != "" && id == systemBackendID:
+		return newSystemBackend(), nil
+	}
+	return nil, fmt.Errorf("unknown credential store %q", id)
+}
+
+// selectedBackendID returns the ID of the backend selected via
+// DASH0_CREDENTIAL_STORE, or "" for plaintext storage.
+func selectedBackendID() (string, error) {
+	switch v := strings.TrimSpace(strings.ToLower(os.Getenv(EnvCredentialStore))); v {
+	case "", StorePlaintext:
+		return "", nil
+	case StoreSystem:
+		if systemBackendID == "" {
+			return "", fmt.Errorf("%s=%s is not supported on this operating system; use %q instead", EnvCredentialStore, v, StoreEncryptedFile)
+		}
+		return systemBackendID, nil
+	case StoreEncryptedFile:
+		return encryptedFileBackendID, nil
+	default:
+		return "", fmt.Errorf("invalid %s value %q: must be one of %s, %s, %s", EnvCredentialStore, v, StorePlaintext, StoreSystem, StoreEncryptedFile)
+	}
+}
+
+// IsReference reports whether a profile field holds a reference to a secret
+// in a credential store rather than the secret itself.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, referencePrefix)
+}
+
+// parseReference splits a reference into its backend ID and account.
+func parseReference(ref string) (id, account string, err error) {
+	id, account, ok := strings.Cut(strings.TrimPrefix(ref, referencePrefix), ":")
+	if !ok || id == "" || account == "" {
+		return "", "", fmt.Errorf("malformed credential reference %q", ref)
+	}
+	return id, account, nil
+}
+
+// accountFor returns the account under which a secret of a profile is
+// stored. The profile name is escaped so it cannot contain the separators
+// of the reference or of the OS store's command line.
+func accountFor(profileName, kind string) string {
+	return url.PathEscape(profileName) + "/" + kind
+}
+
+// Seal stores secret for the given profile and kind and returns the value to
+// write into the profile: a reference when a credential store is in use, or
+// secret itself for plaintext storage. A profile whose previous value of the
+// same field is a reference keeps using that store, so switching
+// DASH0_CREDENTIAL_STORE affects new secrets only. An empty secret is
+// returned as is. A secret that looks like a reference is rejected, since it
+// would be taken for one when the profile is loaded.
+func Seal(profileName, kind, secret, previous string) (string, error) {
+	if secret == "" {
+		return secret, nil
+	}
+	if IsReference(secret) {
+		return "", fmt.Errorf("the %s must not start with %q", strings.ReplaceAll(kind, "-", " "), referencePrefix)
+	}
+	id, err := selectedBackendID()
+	if err != nil {
+		return "", err
+	}
+	if IsReference(previous) {
+		if prevID, _, err := parseReference(previous); err == nil {
+			id = prevID
+		}
+	}
+	if id == "" {
+		return secret, nil
+	}
+	return sealWith(id, profileName, kind, secret)
+}
+
+// sealWith stores secret in the backend with the given ID and returns the
+// reference to it.
+func sealWith(id, profileName, kind, secret string) (string, error) {
+	b, err := backendFor(id)
+	if err != nil {
+		return "", err
+	}
+	account := accountFor(profileName, kind)
+	if err := b.set(account, secret); err != nil {
+		return "", fmt.Errorf("failed to store the %s of profile %q in the %s: %w", kind, profileName, b.description(), err)
+	}
+	return referencePrefix + id + ":" + account, nil
+}
+
+// Open returns the secret a profile field refers to. Values that are not
+// references are returned as is.
+func Open(value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	id, account, err := parseReference(value)
+	if err != nil {
+		return "", err
+	}
+	b, err := backendFor(id)
+	if err != nil {
+		return "", err
+	}
+	secret, err := b.get(account)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from the %s: %w", account, b.description(), err)
+	}
+	return secret, nil
+}
+
+// Remove deletes the secret a profile field refers to. It is best-effort:
+// the profile no longer points at the secret, so a failure only leaves an
+// orphaned entry behind, which is reported as a warning.
+func Remove(value string) {
+	if !IsReference(value) {
+		return
+	}
+	id, account, err := parseReference(value)
+	if err != nil {
+		return
+	}
+	b, err := backendFor(id)
+	if err != nil {
+		return
+	}
+	if err := b.delete(account); err != nil && !errors.Is(err, ErrNotFound) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to delete %s from the %s: %v\n", account, b.description(), err)
+	}
+}
+
+// Location describes where the secret of a profile field lives, e.g.
+// "macOS Keychain", or returns "" for a plaintext value.
+func Location(value string) string {
+	if !IsReference(value) {
+		return ""
+	}
+	id, _, err := parseReference(value)
+	if err != nil {
+		return "unknown credential store"
+	}
+	b, err := backendFor(id)
+	if err != nil {
+		return fmt.Sprintf("unknown credential store %q", id)
+	}
+	return b.description()
+}
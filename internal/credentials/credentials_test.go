@@ -0,0 +1,173 @@
+package credentials
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+)
+
+// setupEncryptedFileStore selects the encrypted-file store in a temporary
+// configuration directory, with a key derivation cheap enough for tests.
+func setupEncryptedFileStore(t *testing.T) {
+	t.Helper()
+	t.Setenv(profiles.EnvConfigDir, t.TempDir())
+	t.Setenv(EnvCredentialStore, StoreEncryptedFile)
+	t.Setenv(EnvCredentialPassphrase, "correct horse battery staple")
+	prev := pbkdf2Iterations
+	pbkdf2Iterations = 1000
+	t.Cleanup(func() { pbkdf2Iterations = prev })
+}
+
+func TestSeal_PlaintextByDefault(t *testing.T) {
+	t.Setenv(EnvCredentialStore, "")
+	sealed, err := Seal("prod", KindAuthToken, "auth_secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sealed != "auth_secret" {
+		t.Fatalf("expected the secret as is, got %q", sealed)
+	}
+	if Location(sealed) != "" {
+		t.Fatalf("expected no location for a plaintext secret, got %q", Location(sealed))
+	}
+}
+
+func TestSeal_InvalidStore(t *testing.T) {
+	t.Setenv(EnvCredentialStore, "vault")
+	_, err := Seal("prod", KindAuthToken, "auth_secret", "")
+	if err == nil || !strings.Contains(err.Error(), "invalid DASH0_CREDENTIAL_STORE value") {
+		t.Fatalf("expected an invalid-store error, got %v", err)
+	}
+}
+
+func TestEncryptedFile_RoundTrip(t *testing.T) {
+	setupEncryptedFileStore(t)
+
+	sealed, err := Seal("my prod", KindAuthToken, "auth_secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "dash0-credential:encrypted-file:my%20prod/auth-token"; sealed != want {
+		t.Fatalf("expected reference %q, got %q", want, sealed)
+	}
+	if got := Location(sealed); got != "encrypted credentials file" {
+		t.Fatalf("unexpected location %q", got)
+	}
+	secret, err := Open(sealed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret != "auth_secret" {
+		t.Fatalf("expected %q, got %q", "auth_secret", secret)
+	}
+
+	Remove(sealed)
+	if _, err := Open(sealed); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after Remove, got %v", err)
+	}
+}
+
+func TestEncryptedFile_WrongPassphrase(t *testing.T) {
+	setupEncryptedFileStore(t)
+
+	sealed, err := Seal("prod", KindRefreshToken, "refresh_secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv(EnvCredentialPassphrase, "wrong")
+	_, err = Open(sealed)
+	if err == nil || !strings.Contains(err.Error(), "wrong DASH0_CREDENTIAL_PASSPHRASE") {
+		t.Fatalf("expected a decryption error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "refresh_secret") {
+		t.Fatalf("error leaks the secret: %v", err)
+	}
+}
+
+func TestSeal_KeepsStoreOfPreviousReference(t *testing.T) {
+	setupEncryptedFileStore(t)
+
+	first, err := Seal("prod", KindAuthToken, "auth_one", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Switching to plaintext affects new secrets only.
+	t.Setenv(EnvCredentialStore, StorePlaintext)
+	second, err := Seal("prod", KindAuthToken, "auth_two", first)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second != first {
+		t.Fatalf("expected the reference %q to be reused, got %q", first, second)
+	}
+	if secret, _ := Open(second); secret != "auth_two" {
+		t.Fatalf("expected the updated secret, got %q", secret)
+	}
+}
+
+func TestOpenConfiguration(t *testing.T) {
+	setupEncryptedFileStore(t)
+
+	authToken, err := Seal("prod", KindAuthToken, "auth_secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	refreshToken, err := Seal("prod", KindRefreshToken, "refresh_secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := &profiles.Configuration{
+		AuthToken: authToken,
+		OAuth:     &profiles.OAuthState{RefreshToken: refreshToken},
+	}
+	if err := OpenConfiguration("prod", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AuthToken != "auth_secret" || cfg.OAuth.RefreshToken != "refresh_secret" {
+		t.Fatalf("expected opened secrets, got %q and %q", cfg.AuthToken, cfg.OAuth.RefreshToken)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	args, err := ParseCommand(`["vault", "read", "-field=token", "secret/dash0 prod"]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(args, "|") != "vault|read|-field=token|secret/dash0 prod" {
+		t.Fatalf("unexpected arguments %q", args)
+	}
+
+	args, err = ParseCommand("  op read op://dev/dash0/token ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(args) != 3 || args[2] != "op://dev/dash0/token" {
+		t.Fatalf("unexpected arguments %q", args)
+	}
+
+	if _, err := ParseCommand("[]"); err == nil {
+		t.Fatal("expected an error for an empty command")
+	}
+}
+
+func TestSeal_RejectsReferenceLookalike(t *testing.T) {
+	t.Setenv(EnvCredentialStore, "")
+	_, err := Seal("prod", KindAuthToken, `dash0-credential:command:["vault", "read"]`, "")
+	if err == nil || !strings.Contains(err.Error(), `the auth token must not start with "dash0-credential:"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOpenConfiguration_EnvAuthTokenIsLiteral(t *testing.T) {
+	const token = `dash0-credential:command:["vault", "read"]`
+	t.Setenv(profiles.EnvAuthToken, token)
+	cfg := &profiles.Configuration{AuthToken: token}
+	if err := OpenConfiguration("prod", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AuthToken != token {
+		t.Fatalf("expected the token of %s as is, got %q", profiles.EnvAuthToken, cfg.AuthToken)
+	}
+}
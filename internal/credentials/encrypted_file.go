@@ -0,0 +1,199 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dash0hq/dash0-cli/internal"
+)
+
+// encryptedFileBackendID identifies the encrypted-file store in references.
+const encryptedFileBackendID = "encrypted-file"
+
+// encryptedFileName is the file, under the configuration directory, that
+// holds the secrets of the encrypted-file store.
+const encryptedFileName = "credentials.enc"
+
+// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+// Tests lower it to keep the suite fast.
+var pbkdf2Iterations = 600_000
+
+// encryptedFileBackend keeps all secrets in one AES-256-GCM encrypted JSON
+// object. The key is derived from DASH0_CREDENTIAL_PASSPHRASE, so the store
+// works wherever there is no OS keychain, e.g. in a container or over SSH.
+type encryptedFileBackend struct{}
+
+func newEncryptedFileBackend() backend { return encryptedFileBackend{} }
+
+func (encryptedFileBackend) description() string { return "encrypted credentials file" }
+
+// encryptedFile is the on-disk format of the store.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (b encryptedFileBackend) get(account string) (string, error) {
+	secrets, _, err := b.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (b encryptedFileBackend) set(account, secret string) error {
+	secrets, file, err := b.load()
+	if err != nil {
+		return err
+	}
+	secrets[account] = secret
+	return b.save(secrets, file)
+}
+
+func (b encryptedFileBackend) delete(account string) error {
+	secrets, file, err := b.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[account]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets, account)
+	return b.save(secrets, file)
+}
+
+func encryptedFilePath() (string, error) {
+	return internal.StateDir(encryptedFileName)
+}
+
+func passphrase() (string, error) {
+	p := os.Getenv(EnvCredentialPassphrase)
+	if p == "" {
+		return "", fmt.Errorf("%s must be set to use the encrypted credentials file", EnvCredentialPassphrase)
+	}
+	return p, nil
+}
+
+// load decrypts the store. A missing file is an empty store; the returned
+// encryptedFile then carries a fresh salt for the first save.
+func (encryptedFileBackend) load() (map[string]string, *encryptedFile, error) {
+	pass, err := passphrase()
+	if err != nil {
+		return nil, nil, err
+	}
+	path, err := encryptedFilePath()
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, nil, err
+		}
+		return map[string]string{}, &encryptedFile{Version: 1, Iterations: pbkdf2Iterations, Salt: salt}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if file.Version != 1 {
+		return nil, nil, fmt.Errorf("unsupported version %d of %s", file.Version, path)
+	}
+	aead, err := newAEAD(pass, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt %s: wrong %s or corrupted file", path, EnvCredentialPassphrase)
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the decrypted %s: %w", path, err)
+	}
+	return secrets, &file, nil
+}
+
+// save encrypts secrets with a fresh nonce and replaces the file atomically.
+func (encryptedFileBackend) save(secrets map[string]string, file *encryptedFile) error {
+	pass, err := passphrase()
+	if err != nil {
+		return err
+	}
+	path, err := encryptedFilePath()
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(pass, file.Salt, file.Iterations)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, nil)
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), encryptedFileName+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func newAEAD(pass string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations <= 0 {
+		return nil, fmt.Errorf("invalid key derivation iteration count %d", iterations)
+	}
+	key, err := pbkdf2.Key(sha256.New, pass, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
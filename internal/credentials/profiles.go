@@ -0,0 +1,282 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+	"github.com/dash0hq/dash0-cli/internal/version"
+)
+
+// refreshMargin is how long before expiry Prepare refreshes the access token
+// of a profile whose refresh token is sealed. It exceeds the five minutes
+// before expiry at which the API client refreshes, so that the API client
+// never gets to refresh from the sealed reference.
+const refreshMargin = 6 * time.Minute
+
+var (
+	openedMu sync.Mutex
+	// opened maps the profiles whose secrets this process opened to the ID
+	// of their credential store, so Reseal knows where to put secrets that
+	// were written back in plaintext.
+	opened = map[string]string{}
+)
+
+// OpenConfiguration replaces the references in cfg, the configuration of the
+// named profile, with the secrets they refer to. Only the in-memory copy
+// changes; the profile keeps its references. An auth token set via
+// DASH0_AUTH_TOKEN is not the profile's and is left as is.
+func OpenConfiguration(profileName string, cfg *profiles.Configuration) error {
+	if cfg == nil {
+		return nil
+	}
+	open := func(field *string) error {
+		if !IsReference(*field) {
+			return nil
+		}
+		id, _, err := parseReference(*field)
+		if err != nil {
+			return err
+		}
+		secret, err := Open(*field)
+		if err != nil {
+			return err
+		}
+		*field = secret
+		if profileName != "" {
+			openedMu.Lock()
+			opened[profileName] = id
+			openedMu.Unlock()
+		}
+		return nil
+	}
+	if os.Getenv(profiles.EnvAuthToken) == "" {
+		if err := open(&cfg.AuthToken); err != nil {
+			return err
+		}
+	}
+	if cfg.OAuth != nil {
+		if err := open(&cfg.OAuth.RefreshToken); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prepare refreshes the access token of the named profile (the active
+// profile if name is empty) when its refresh token is sealed and the access
+// token is about to expire. The API client refreshes from the profiles file,
+// where it would find the reference instead of the refresh token; refreshing
+// here first means it never has to.
+//
+// cfg, the configuration resolved from the profile, takes the new tokens, so
+// that clients built from it use them. It is called when a client is built,
+// never for commands that work offline.
+func Prepare(ctx context.Context, name string, cfg *profiles.Configuration) error {
+	store, err := profiles.NewStore()
+	if err != nil {
+		return nil
+	}
+	var profile *profiles.Profile
+	if name == "" {
+		if profile, err = store.GetActiveProfile(); err != nil || profile == nil {
+			return nil
+		}
+	} else {
+		all, err := store.GetProfiles()
+		if err != nil {
+			return nil
+		}
+		for i := range all {
+			if all[i].Name == name {
+				profile = &all[i]
+			}
+		}
+	}
+	if profile == nil {
+		return nil
+	}
+	oauth := profile.Configuration.OAuth
+	if oauth == nil || !IsReference(oauth.RefreshToken) || time.Until(oauth.ExpiresAt) > refreshMargin {
+		return nil
+	}
+	if err := refresh(ctx, profile, cfg); err != nil {
+		return fmt.Errorf("failed to refresh the access token of profile %q: %w", profile.Name, err)
+	}
+	return nil
+}
+
+var (
+	tokenProvidersMu sync.Mutex
+	// tokenProviders shares one provider per configuration, so that the
+	// clients built from it never refresh the same token concurrently.
+	tokenProviders = map[*profiles.Configuration]*TokenProvider{}
+)
+
+// TokenProvider supplies the OAuth access token of a profile. Unlike the
+// provider of the configuration alone, it keeps working past the expiry of
+// the access token when the refresh token is sealed: each refresh opens the
+// sealed refresh token and seals the rotated one, through Prepare.
+type TokenProvider struct {
+	name string
+	cfg  *profiles.Configuration
+
+	mu sync.Mutex
+}
+
+// TokenProviderFor returns the provider of cfg, the OAuth configuration
+// resolved from the named profile (the active profile if name is empty).
+func TokenProviderFor(name string, cfg *profiles.Configuration) *TokenProvider {
+	tokenProvidersMu.Lock()
+	defer tokenProvidersMu.Unlock()
+	p, ok := tokenProviders[cfg]
+	if !ok {
+		p = &TokenProvider{name: name, cfg: cfg}
+		tokenProviders[cfg] = p
+	}
+	return p
+}
+
+// AuthToken returns the access token of the profile, refreshing it first
+// when it is about to expire.
+func (p *TokenProvider) AuthToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg.OAuth != nil && time.Until(p.cfg.OAuth.ExpiresAt) <= refreshMargin {
+		if err := Prepare(ctx, p.name, p.cfg); err != nil {
+			return "", err
+		}
+	}
+	return p.cfg.AuthTokenProvider().AuthToken(ctx)
+}
+
+// refresh redeems the sealed refresh token of profile for a new access
+// token and stores the result, sealing the rotated refresh token. The
+// tokens of cfg, if it is an OAuth configuration, are replaced in memory;
+// an access token that overrides the one of the profile is kept.
+func refresh(ctx context.Context, profile *profiles.Profile, cfg *profiles.Configuration) error {
+	stored := profile.Configuration
+	refreshToken, err := Open(stored.OAuth.RefreshToken)
+	if err != nil {
+		return err
+	}
+	transport, err := httptransport.SettingsFor(ctx, &stored).RoundTripper()
+	if err != nil {
+		return err
+	}
+	client, err := dash0api.NewOAuthClient(
+		dash0api.WithApiUrl(stored.ApiUrl),
+		dash0api.WithUserAgent(version.UserAgent()),
+		dash0api.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close(context.Background()) }()
+
+	resp, err := client.ExchangeToken(ctx, &dash0api.OAuthTokenRequest{
+		GrantType:    dash0api.OAuthGrantTypeRefreshToken,
+		RefreshToken: dash0api.Ptr(refreshToken),
+		ClientId:     dash0api.Ptr(stored.OAuth.ClientID),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.AccessToken == "" || resp.ExpiresIn <= 0 {
+		return fmt.Errorf("the authorization server returned an invalid token response")
+	}
+	if resp.RefreshToken != nil && *resp.RefreshToken != "" {
+		refreshToken = *resp.RefreshToken
+	}
+	sealed, err := Seal(profile.Name, KindRefreshToken, refreshToken, stored.OAuth.RefreshToken)
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	store, err := profiles.NewStore()
+	if err != nil {
+		return err
+	}
+	err = store.UpdateProfile(profile.Name, func(c *profiles.Configuration) {
+		c.AuthToken = resp.AccessToken
+		c.OAuth = &profiles.OAuthState{
+			ClientID:     stored.OAuth.ClientID,
+			RefreshToken: sealed,
+			ExpiresAt:    expiresAt,
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if cfg != nil && cfg.OAuth != nil {
+		if cfg.AuthToken == stored.AuthToken {
+			cfg.AuthToken = resp.AccessToken
+		}
+		cfg.OAuth = &profiles.OAuthState{
+			ClientID:     stored.OAuth.ClientID,
+			RefreshToken: refreshToken,
+			ExpiresAt:    expiresAt,
+		}
+	}
+	return nil
+}
+
+// Reseal moves secrets that were written back to the profiles file in
+// plaintext during this process — the API client persists a rotated refresh
+// token as is — into the credential store of their profile. Call it once
+// the command has finished.
+func Reseal() {
+	openedMu.Lock()
+	defer openedMu.Unlock()
+	if len(opened) == 0 {
+		return
+	}
+	store, err := profiles.NewStore()
+	if err != nil {
+		return
+	}
+	all, err := store.GetProfiles()
+	if err != nil {
+		return
+	}
+	for _, p := range all {
+		id, ok := opened[p.Name]
+		if !ok {
+			continue
+		}
+		cfg := p.Configuration
+		var seal func(c *profiles.Configuration, ref string)
+		var kind, secret string
+		switch {
+		case cfg.OAuth != nil && cfg.OAuth.RefreshToken != "" && !IsReference(cfg.OAuth.RefreshToken):
+			kind, secret = KindRefreshToken, cfg.OAuth.RefreshToken
+			seal = func(c *profiles.Configuration, ref string) {
+				if c.OAuth != nil && c.OAuth.RefreshToken == secret {
+					c.OAuth.RefreshToken = ref
+				}
+			}
+		case cfg.OAuth == nil && cfg.AuthToken != "" && !IsReference(cfg.AuthToken):
+			kind, secret = KindAuthToken, cfg.AuthToken
+			seal = func(c *profiles.Configuration, ref string) {
+				if c.AuthToken == secret {
+					c.AuthToken = ref
+				}
+			}
+		default:
+			continue
+		}
+		ref, err := sealWith(id, p.Name, kind, secret)
+		if err == nil {
+			err = store.UpdateProfile(p.Name, func(c *profiles.Configuration) { seal(c, ref) })
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: the %s of profile %q is stored in plaintext: %v\n", kind, p.Name, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+)
+
+// refreshServer is an OAuth token endpoint that rotates the refresh token on
+// every refresh grant and records the refresh tokens it was given.
+type refreshServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	redeemed []string
+}
+
+func newRefreshServer(t *testing.T) *refreshServer {
+	t.Helper()
+	s := &refreshServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" || r.ParseForm() != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.mu.Lock()
+		s.redeemed = append(s.redeemed, r.PostForm.Get("refresh_token"))
+		n := len(s.redeemed)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  fmt.Sprintf("dash0_at_%d", n),
+			"refresh_token": fmt.Sprintf("rt-%d", n),
+			"token_type":    "Bearer",
+			"expires_in":    int64((15 * time.Minute).Seconds()),
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// writeOAuthProfile stores the active OAuth profile "prod", whose refresh
+// token is sealed in the encrypted-file store.
+func writeOAuthProfile(t *testing.T, apiURL string, expiresAt time.Time) {
+	t.Helper()
+	refreshToken, err := Seal("prod", KindRefreshToken, "rt-0", "")
+	if err != nil {
+		t.Fatalf("failed to seal the refresh token: %v", err)
+	}
+	data, err := json.Marshal(profiles.ProfilesFile{Profiles: []profiles.Profile{{
+		Name: "prod",
+		Configuration: profiles.Configuration{
+			ApiUrl:    apiURL,
+			AuthToken: "dash0_at_0",
+			OAuth:     &profiles.OAuthState{ClientID: "dash0-cli", RefreshToken: refreshToken, ExpiresAt: expiresAt},
+		},
+	}}})
+	if err != nil {
+		t.Fatalf("failed to encode the profiles: %v", err)
+	}
+	configDir := os.Getenv(profiles.EnvConfigDir)
+	if err := os.WriteFile(filepath.Join(configDir, profiles.ProfilesFileName), data, 0600); err != nil {
+		t.Fatalf("failed to write the profiles: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, profiles.ActiveProfileFileName), []byte("prod"), 0600); err != nil {
+		t.Fatalf("failed to write the active profile: %v", err)
+	}
+}
+
+// expire moves the expiry of the access token of cfg, and of the stored
+// profile, to the near future, as if the token had been in use for a while.
+func expire(t *testing.T, cfg *profiles.Configuration) {
+	t.Helper()
+	expiresAt := time.Now().Add(30 * time.Second)
+	cfg.OAuth.ExpiresAt = expiresAt
+	store, err := profiles.NewStore()
+	if err != nil {
+		t.Fatalf("failed to open the profiles: %v", err)
+	}
+	if err := store.UpdateProfile("prod", func(c *profiles.Configuration) { c.OAuth.ExpiresAt = expiresAt }); err != nil {
+		t.Fatalf("failed to update the profile: %v", err)
+	}
+}
+
+// TestTokenProvider_SealedRefreshTokenAcrossExpiries verifies that a single
+// provider keeps supplying access tokens past the expiry of each one, when
+// the refresh token is sealed and rotated on every refresh.
+func TestTokenProvider_SealedRefreshTokenAcrossExpiries(t *testing.T) {
+	setupEncryptedFileStore(t)
+	server := newRefreshServer(t)
+	writeOAuthProfile(t, server.URL, time.Now().Add(time.Hour))
+
+	store, err := profiles.NewStore()
+	if err != nil {
+		t.Fatalf("failed to open the profiles: %v", err)
+	}
+	cfg, err := store.GetConfigurationForProfile(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("failed to load the profile: %v", err)
+	}
+	if err := OpenConfiguration("prod", cfg); err != nil {
+		t.Fatalf("failed to open the profile: %v", err)
+	}
+	provider := TokenProviderFor("", cfg)
+
+	if token, err := provider.AuthToken(context.Background()); err != nil || token != "dash0_at_0" {
+		t.Fatalf("expected the stored access token, got %q, %v", token, err)
+	}
+	for i, want := range []string{"dash0_at_1", "dash0_at_2"} {
+		expire(t, cfg)
+		token, err := provider.AuthToken(context.Background())
+		if err != nil {
+			t.Fatalf("refresh %d: unexpected error: %v", i+1, err)
+		}
+		if token != want {
+			t.Errorf("refresh %d: expected %q, got %q", i+1, want, token)
+		}
+	}
+
+	if want := []string{"rt-0", "rt-1"}; fmt.Sprint(server.redeemed) != fmt.Sprint(want) {
+		t.Errorf("expected the rotated refresh tokens %v to be redeemed, got %v", want, server.redeemed)
+	}
+	stored, err := store.GetConfigurationForProfile(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("failed to load the profile: %v", err)
+	}
+	if !IsReference(stored.OAuth.RefreshToken) {
+		t.Fatalf("expected the refresh token to stay sealed, got %q", stored.OAuth.RefreshToken)
+	}
+	if refreshToken, err := Open(stored.OAuth.RefreshToken); err != nil || refreshToken != "rt-2" {
+		t.Errorf("expected the sealed refresh token %q, got %q, %v", "rt-2", refreshToken, err)
+	}
+}
@@ -0,0 +1,68 @@
+package credentials
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemBackendID identifies the macOS Keychain in references.
+const systemBackendID = "keychain"
+
+// securityBinary is the macOS command-line interface to the Keychain.
+const securityBinary = "/usr/bin/security"
+
+// errSecItemNotFound is the exit status of `security` when no item matches.
+const errSecItemNotFound = 44
+
+// keychainBackend stores secrets as generic passwords in the login keychain
+// through the `security` tool, so the CLI needs no cgo.
+type keychainBackend struct{}
+
+func newSystemBackend() backend { return keychainBackend{} }
+
+func (keychainBackend) description() string { return "macOS Keychain" }
+
+func (keychainBackend) get(account string) (string, error) {
+	out, err := exec.Command(securityBinary, "find-generic-password", "-s", serviceName, "-a", account, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func (keychainBackend) set(account, secret string) error {
+	// The secret goes through stdin in interactive mode, hex-encoded, so it
+	// never shows up in the process list.
+	cmd := exec.Command(securityBinary, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		securityQuote(serviceName), securityQuote(account), hex.EncodeToString([]byte(secret))))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (keychainBackend) delete(account string) error {
+	if err := exec.Command(securityBinary, "delete-generic-password", "-s", serviceName, "-a", account).Run(); err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+// securityQuote quotes an argument of a `security -i` command line, which
+// splits on whitespace outside double quotes and takes a backslash to escape
+// the next character.
+func securityQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+func securityError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return ErrNotFound
+	}
+	return err
+}
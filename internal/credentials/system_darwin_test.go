@@ -0,0 +1,18 @@
+package credentials
+
+import "testing"
+
+func TestSecurityQuote(t *testing.T) {
+	tests := map[string]string{
+		"dash0-cli":           `"dash0-cli"`,
+		"it's prod":           `"it's prod"`,
+		`say "hi"`:            `"say \"hi\""`,
+		`back\slash`:          `"back\\slash"`,
+		"refresh-token/a b%2": `"refresh-token/a b%2"`,
+	}
+	for arg, want := range tests {
+		if got := securityQuote(arg); got != want {
+			t.Errorf("securityQuote(%q) = %s, want %s", arg, got, want)
+		}
+	}
+}
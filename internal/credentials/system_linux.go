@@ -0,0 +1,64 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// systemBackendID identifies the Secret Service (GNOME Keyring, KWallet) in
+// references.
+const systemBackendID = "secret-service"
+
+// secretToolBinary is the libsecret command-line client, which talks to the
+// Secret Service over D-Bus.
+var secretToolBinary = "secret-tool"
+
+// secretServiceBackend stores secrets in the Secret Service through
+// `secret-tool`, so the CLI needs neither cgo nor a D-Bus library.
+type secretServiceBackend struct{}
+
+func newSystemBackend() backend { return secretServiceBackend{} }
+
+func (secretServiceBackend) description() string { return "Secret Service" }
+
+func (secretServiceBackend) get(account string) (string, error) {
+	out, err := secretTool(nil, "lookup", "service", serviceName, "account", account)
+	if err != nil {
+		var exitErr *exec.ExitError
+		// secret-tool exits with 1 and prints nothing when nothing matches.
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) == 0 {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (secretServiceBackend) set(account, secret string) error {
+	// The secret goes through stdin so it never shows up in the process list.
+	_, err := secretTool(strings.NewReader(secret), "store", "--label", "Dash0 CLI "+account, "service", serviceName, "account", account)
+	return err
+}
+
+func (secretServiceBackend) delete(account string) error {
+	_, err := secretTool(nil, "clear", "service", serviceName, "account", account)
+	return err
+}
+
+func secretTool(stdin *strings.Reader, args ...string) ([]byte, error) {
+	cmd := exec.Command(secretToolBinary, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	out, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("the Secret Service store needs `secret-tool` (libsecret-tools) on the PATH: %w", err)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
@@ -0,0 +1,9 @@
+//go:build !darwin && !linux && !windows
+
+package credentials
+
+// systemBackendID is empty where the CLI supports no OS credential store;
+// DASH0_CREDENTIAL_STORE=system is rejected there.
+const systemBackendID = ""
+
+func newSystemBackend() backend { return nil }
@@ -0,0 +1,496 @@
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/config"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+	"github.com/dash0hq/dash0-cli/internal/query"
+	"github.com/dash0hq/dash0-cli/internal/version"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// checkStatus is the outcome of a single check.
+type checkStatus string
+
+const (
+	statusPass checkStatus = "pass"
+	statusWarn checkStatus = "warn"
+	statusFail checkStatus = "fail"
+	statusSkip checkStatus = "skip"
+)
+
+// Names of the checks, in the order they run.
+const (
+	checkConfiguration = "configuration"
+	checkOAuth         = "oauth"
+	checkApiEndpoint   = "api-endpoint"
+	checkOtlpEndpoint  = "otlp-endpoint"
+	checkApiAuth       = "api-auth"
+	checkDataset       = "dataset"
+	checkOtlpSend      = "otlp-send"
+	checkLogRoundTrip  = "log-roundtrip"
+)
+
+const (
+	// endpointTimeout bounds the DNS lookup and TLS handshake of an
+	// endpoint check.
+	endpointTimeout = 10 * time.Second
+	// certificateExpiryWarning is how close to its expiry a server
+	// certificate must be for the endpoint check to warn about it.
+	certificateExpiryWarning = 14 * 24 * time.Hour
+	// roundTripPollInterval is how often the test log record is looked up.
+	roundTripPollInterval = 3 * time.Second
+	// testRunAttribute is the log record attribute that identifies the test
+	// log record of a run, so the lookup finds exactly that record.
+	testRunAttribute = "dash0.cli.doctor.run_id"
+)
+
+// checkResult is the outcome of a check, as reported.
+type checkResult struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+	Hint    string      `json:"hint,omitempty"`
+	// inapplicable marks a skip of a check that does not apply to the
+	// configuration, which does not block the checks that need it.
+	inapplicable bool
+}
+
+type reportSummary struct {
+	Passed   int `json:"passed"`
+	Warnings int `json:"warnings"`
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+}
+
+type report struct {
+	Checks  []checkResult `json:"checks"`
+	Summary reportSummary `json:"summary"`
+}
+
+// check is a named diagnostic that only runs when the checks it needs did
+// not fail and were not skipped because of a failure.
+type check struct {
+	name  string
+	needs []string
+	run   func(ctx context.Context) checkResult
+}
+
+// doctor holds the configuration the checks resolve and the clients they
+// share.
+type doctor struct {
+	flags *doctorFlags
+	// progress enables progress notes on stderr for the human-readable
+	// output.
+	progress bool
+	// tlsConfig verifies the certificates of the endpoints and presents the
+	// client certificate, if any; nil uses the system roots.
+	tlsConfig *tls.Config
+
+	cfg        *profiles.Configuration
+	apiUrl     string
+	otlpUrl    string
+	authToken  string
+	dataset    *string
+	runID      string
+	apiClient  dash0api.Client
+	otlpClient dash0api.Client
+}
+
+func newDoctor(flags *doctorFlags, progress bool) *doctor {
+	return &doctor{flags: flags, progress: progress, runID: uuid.NewString()}
+}
+
+func (d *doctor) checks() []check {
+	return []check{
+		{name: checkConfiguration, run: d.checkConfiguration},
+		{name: checkOAuth, needs: []string{checkConfiguration}, run: d.checkOAuth},
+		{name: checkApiEndpoint, needs: []string{checkConfiguration}, run: func(ctx context.Context) checkResult {
+			return d.checkEndpoint(ctx, "api-url", d.apiUrl)
+		}},
+		{name: checkOtlpEndpoint, needs: []string{checkConfiguration}, run: func(ctx context.Context) checkResult {
+			return d.checkEndpoint(ctx, "otlp-url", d.otlpUrl)
+		}},
+		{name: checkApiAuth, needs: []string{checkOAuth, checkApiEndpoint}, run: d.checkApiAuth},
+		{name: checkDataset, needs: []string{checkApiAuth}, run: d.checkDataset},
+		{name: checkOtlpSend, needs: []string{checkOAuth, checkOtlpEndpoint}, run: d.checkOtlpSend},
+		{name: checkLogRoundTrip, needs: []string{checkOtlpSend, checkDataset}, run: d.checkLogRoundTrip},
+	}
+}
+
+func (d *doctor) run(ctx context.Context) *report {
+	return runChecks(ctx, d.checks())
+}
+
+// runChecks runs the checks in order. A check whose prerequisites failed, or
+// were skipped, is skipped in turn.
+func runChecks(ctx context.Context, checks []check) *report {
+	rep := &report{}
+	blocked := map[string]bool{}
+	for _, c := range checks {
+		var res checkResult
+		if dep := firstBlocked(c.needs, blocked); dep != "" {
+			res = checkResult{Status: statusSkip, Message: fmt.Sprintf("skipped because %s did not pass", dep)}
+		} else {
+			res = c.run(ctx)
+		}
+		res.Name = c.name
+		if res.Status == statusFail || (res.Status == statusSkip && !res.inapplicable) {
+			blocked[c.name] = true
+		}
+		rep.Checks = append(rep.Checks, res)
+		switch res.Status {
+		case statusPass:
+			rep.Summary.Passed++
+		case statusWarn:
+			rep.Summary.Warnings++
+		case statusFail:
+			rep.Summary.Failed++
+		case statusSkip:
+			rep.Summary.Skipped++
+		}
+	}
+	return rep
+}
+
+func firstBlocked(needs []string, blocked map[string]bool) string {
+	for _, n := range needs {
+		if blocked[n] {
+			return n
+		}
+	}
+	return ""
+}
+
+func (d *doctor) close(ctx context.Context) {
+	if d.apiClient != nil {
+		_ = d.apiClient.Close(ctx)
+	}
+	if d.otlpClient != nil {
+		_ = d.otlpClient.Close(ctx)
+	}
+}
+
+// checkConfiguration resolves the effective configuration from the context,
+// flags, and environment variables, like `config show`.
+func (d *doctor) checkConfiguration(ctx context.Context) checkResult {
+	d.cfg = profiles.FromContext(ctx)
+	d.apiUrl = client.ResolveApiUrl(ctx, d.flags.ApiUrl)
+	d.otlpUrl = d.flags.OtlpUrl
+	d.authToken = d.flags.AuthToken
+	if d.cfg != nil {
+		if d.otlpUrl == "" {
+			d.otlpUrl = d.cfg.OtlpUrl
+		}
+		if d.authToken == "" {
+			d.authToken = d.cfg.AuthToken
+		}
+	}
+	d.dataset = client.ResolveDataset(ctx, d.flags.Dataset)
+
+	const setupHint = "Run `dash0 login` or `dash0 config profiles create`, or set DASH0_API_URL, DASH0_OTLP_URL, and DASH0_AUTH_TOKEN."
+	switch {
+	case d.cfg == nil && d.apiUrl == "" && d.otlpUrl == "":
+		return checkResult{Status: statusFail, Message: "no configuration found", Hint: setupHint}
+	case d.apiUrl == "":
+		return checkResult{Status: statusFail, Message: "api-url is not set", Hint: setupHint}
+	case d.authToken == "" && !d.usesOAuth() && !d.usesCommand():
+		return checkResult{Status: statusFail, Message: "auth-token is not set", Hint: setupHint}
+	}
+	tlsConfig, err := httptransport.FromContext(ctx).TLSConfig()
+	if err != nil {
+		return checkResult{Status: statusFail, Message: err.Error(), Hint: fmt.Sprintf("Check the CA file and client certificate of the profile, or %s, %s, and %s.", httptransport.EnvCAFile, httptransport.EnvClientCert, httptransport.EnvClientKey)}
+	}
+	d.tlsConfig = tlsConfig
+
+	datasetName := "default"
+	if d.dataset != nil {
+		datasetName = *d.dataset
+	}
+	return checkResult{
+		Status:  statusPass,
+		Message: fmt.Sprintf("%s, %s, dataset %q", profileDescription(ctx), d.authDescription(), datasetName),
+	}
+}
+
+// usesOAuth reports whether requests authenticate with the OAuth session of
+// the profile, rather than a static token that shadows it.
+func (d *doctor) usesOAuth() bool {
+	if d.cfg == nil || d.cfg.OAuth == nil {
+		return false
+	}
+	return d.flags.AuthToken == "" && os.Getenv(profiles.EnvAuthToken) == ""
+}
+
+// usesCommand reports whether the auth token is printed by the auth token
+// command of the profile, because no other source supplies one.
+func (d *doctor) usesCommand() bool {
+	return d.authToken == "" && d.cfg != nil && len(d.cfg.AuthTokenCommand) > 0
+}
+
+func (d *doctor) authDescription() string {
+	switch {
+	case d.flags.AuthToken != "":
+		return "auth token from --auth-token"
+	case os.Getenv(profiles.EnvAuthToken) != "":
+		return "auth token from " + profiles.EnvAuthToken
+	case d.usesOAuth():
+		return "OAuth"
+	case d.usesCommand():
+		return "auth token from a command"
+	default:
+		return "static auth token"
+	}
+}
+
+func profileDescription(ctx context.Context) string {
+	if sel := config.ProfileSelectorFromContext(ctx); sel.IsSet() {
+		return fmt.Sprintf("profile %q (%s)", sel.Name, sel.Description())
+	}
+	if store, err := profiles.NewStore(); err == nil {
+		if active, err := store.GetActiveProfile(); err == nil && active != nil {
+			return fmt.Sprintf("profile %q (active profile)", active.Name)
+		}
+	}
+	return "no profile"
+}
+
+// checkOAuth checks that the OAuth session of the profile can supply an
+// access token, refreshing it if it expired.
+func (d *doctor) checkOAuth(ctx context.Context) checkResult {
+	if !d.usesOAuth() {
+		return checkResult{Status: statusSkip, Message: "not an OAuth profile", inapplicable: true}
+	}
+	loginHint := fmt.Sprintf("Run `dash0 login%s` to log in again.", config.ProfileFlagFragment(config.ProfileSelectorFromContext(ctx).Name))
+	if d.cfg.OAuth.RefreshToken == "" {
+		return checkResult{Status: statusFail, Message: "the profile is OAuth-typed but not logged in", Hint: loginHint}
+	}
+	if _, err := credentials.TokenProviderFor(config.ProfileSelectorFromContext(ctx).Name, d.cfg).AuthToken(ctx); err != nil {
+		return checkResult{Status: statusFail, Message: fmt.Sprintf("failed to refresh the access token: %v", err), Hint: loginHint}
+	}
+	if until := time.Until(d.cfg.OAuth.ExpiresAt); until > 0 {
+		return checkResult{Status: statusPass, Message: fmt.Sprintf("access token valid, expires in %s; refreshed automatically", until.Round(time.Second))}
+	}
+	return checkResult{Status: statusPass, Message: "access token refreshed"}
+}
+
+// checkEndpoint resolves the host of rawURL and performs a TLS handshake
+// with it.
+func (d *doctor) checkEndpoint(ctx context.Context, setting, rawURL string) checkResult {
+	if rawURL == "" {
+		return checkResult{Status: statusFail, Message: setting + " is not set", Hint: fmt.Sprintf("Set the %s of the profile, or pass --%s.", setting, setting)}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return checkResult{Status: statusFail, Message: fmt.Sprintf("invalid %s %q", setting, rawURL)}
+	}
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	addr := net.JoinHostPort(host, port)
+
+	// Behind a proxy, the endpoint may be neither resolvable nor reachable
+	// directly; the checks that send requests through the proxy cover it.
+	if proxy := httptransport.ProxyFor(rawURL); proxy != nil {
+		return checkResult{Status: statusSkip, Message: fmt.Sprintf("%s is reached through proxy %s", addr, proxy.Redacted()), inapplicable: true}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, endpointTimeout)
+	defer cancel()
+
+	hint := fmt.Sprintf("Check the %s, your DNS settings, and your network.", setting)
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		return checkResult{Status: statusFail, Message: fmt.Sprintf("cannot resolve %s: %v", host, err), Hint: hint}
+	}
+
+	start := time.Now()
+	if u.Scheme == "http" {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return checkResult{Status: statusFail, Message: fmt.Sprintf("cannot connect to %s: %v", addr, err), Hint: hint}
+		}
+		_ = conn.Close()
+		return checkResult{Status: statusWarn, Message: fmt.Sprintf("%s is reachable but does not use TLS", addr)}
+	}
+
+	tlsConfig := &tls.Config{}
+	if d.tlsConfig != nil {
+		tlsConfig = d.tlsConfig.Clone()
+	}
+	tlsConfig.ServerName = host
+	dialer := &tls.Dialer{Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return checkResult{Status: statusFail, Message: fmt.Sprintf("TLS handshake with %s failed: %v", addr, err), Hint: hint}
+	}
+	defer conn.Close()
+	elapsed := time.Since(start)
+
+	state := conn.(*tls.Conn).ConnectionState()
+	msg := fmt.Sprintf("%s (%s, %dms)", addr, tls.VersionName(state.Version), elapsed.Milliseconds())
+	if len(state.PeerCertificates) > 0 {
+		if until := time.Until(state.PeerCertificates[0].NotAfter); until < certificateExpiryWarning {
+			return checkResult{Status: statusWarn, Message: fmt.Sprintf("%s; the server certificate expires in %s", msg, until.Round(time.Hour))}
+		}
+	}
+	return checkResult{Status: statusPass, Message: msg}
+}
+
+// checkApiAuth validates the auth token with a cheap authenticated call: the
+// first page of the dashboards of the default dataset.
+func (d *doctor) checkApiAuth(ctx context.Context) checkResult {
+	apiClient, err := client.NewClientFromContext(ctx, d.flags.ApiUrl, d.flags.AuthToken)
+	if err != nil {
+		return checkResult{Status: statusFail, Message: err.Error()}
+	}
+	d.apiClient = apiClient
+
+	iter := apiClient.ListDashboardsIter(ctx, nil)
+	iter.Next()
+	if err := iter.Err(); err != nil {
+		switch {
+		case dash0api.IsUnauthorized(err):
+			return checkResult{Status: statusFail, Message: "the API rejected the auth token (401 Unauthorized)", Hint: d.tokenHint(ctx)}
+		case dash0api.IsForbidden(err):
+			return checkResult{Status: statusFail, Message: "the auth token is not allowed to read dashboards (403 Forbidden)", Hint: d.tokenHint(ctx)}
+		default:
+			return checkResult{Status: statusFail, Message: client.HandleAPIError(err).Error()}
+		}
+	}
+	return checkResult{Status: statusPass, Message: fmt.Sprintf("%s accepted the auth token", d.apiUrl)}
+}
+
+func (d *doctor) tokenHint(ctx context.Context) string {
+	if d.usesOAuth() {
+		return fmt.Sprintf("Run `dash0 login%s` to log in again.", config.ProfileFlagFragment(config.ProfileSelectorFromContext(ctx).Name))
+	}
+	return "Check the auth token of the profile, DASH0_AUTH_TOKEN, or --auth-token, and its permissions."
+}
+
+// checkDataset confirms the dataset exists by listing its dashboards.
+func (d *doctor) checkDataset(ctx context.Context) checkResult {
+	if d.dataset == nil {
+		return checkResult{Status: statusPass, Message: "using the default dataset"}
+	}
+	iter := d.apiClient.ListDashboardsIter(ctx, d.dataset)
+	iter.Next()
+	if err := iter.Err(); err != nil {
+		hint := "Check --dataset, DASH0_DATASET, the dataset in .dash0.yaml, and the dataset of the profile."
+		switch {
+		case dash0api.IsNotFound(err), dash0api.IsBadRequest(err):
+			return checkResult{Status: statusFail, Message: fmt.Sprintf("dataset %q does not exist", *d.dataset), Hint: hint}
+		case dash0api.IsForbidden(err):
+			return checkResult{Status: statusFail, Message: fmt.Sprintf("the auth token is not allowed to access dataset %q", *d.dataset), Hint: hint}
+		default:
+			return checkResult{Status: statusFail, Message: client.HandleAPIError(err).Error()}
+		}
+	}
+	return checkResult{Status: statusPass, Message: fmt.Sprintf("dataset %q exists", *d.dataset)}
+}
+
+// checkOtlpSend sends a test log record via OTLP.
+func (d *doctor) checkOtlpSend(ctx context.Context) checkResult {
+	if d.flags.SkipSend {
+		return checkResult{Status: statusSkip, Message: "skipped with --skip-send"}
+	}
+	otlpClient, err := client.NewOtlpClientFromContext(ctx, d.flags.OtlpUrl, d.flags.AuthToken)
+	if err != nil {
+		return checkResult{Status: statusFail, Message: err.Error()}
+	}
+	d.otlpClient = otlpClient
+
+	if err := otlpClient.SendLogs(ctx, newTestLogs(d.runID), d.dataset); err != nil {
+		return checkResult{Status: statusFail, Message: fmt.Sprintf("failed to send a test log record: %v", err), Hint: d.tokenHint(ctx)}
+	}
+	return checkResult{Status: statusPass, Message: fmt.Sprintf("sent a test log record with %s=%s", testRunAttribute, d.runID)}
+}
+
+// newTestLogs returns the test log record of a run.
+func newTestLogs(runID string) plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "dash0-cli")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("dash0-cli")
+	sl.Scope().SetVersion(version.Version)
+	lr := sl.LogRecords().AppendEmpty()
+	now := pcommon.NewTimestampFromTime(time.Now())
+	lr.SetTimestamp(now)
+	lr.SetObservedTimestamp(now)
+	lr.SetSeverityNumber(plog.SeverityNumberInfo)
+	lr.SetSeverityText("INFO")
+	lr.Body().SetStr("dash0 doctor test log record")
+	lr.Attributes().PutStr(testRunAttribute, runID)
+	return logs
+}
+
+// checkLogRoundTrip looks the test log record up with a log query until it
+// shows up or --wait elapses.
+func (d *doctor) checkLogRoundTrip(ctx context.Context) checkResult {
+	filters, err := query.ParseFilters([]string{testRunAttribute + " is " + d.runID})
+	if err != nil {
+		return checkResult{Status: statusFail, Message: err.Error()}
+	}
+	if d.progress {
+		fmt.Fprintf(os.Stderr, "Waiting up to %s for the test log record to become queryable...\n", d.flags.Wait)
+	}
+
+	start := time.Now()
+	for {
+		found, err := d.findTestLog(ctx, filters)
+		if err != nil {
+			return checkResult{Status: statusFail, Message: client.HandleAPIError(err).Error()}
+		}
+		if found {
+			return checkResult{Status: statusPass, Message: fmt.Sprintf("found the test log record after %s", time.Since(start).Round(time.Second))}
+		}
+		if time.Since(start) >= d.flags.Wait {
+			return checkResult{
+				Status:  statusFail,
+				Message: fmt.Sprintf("the test log record was not found within %s", d.flags.Wait),
+				Hint:    "Ingestion can be delayed; re-run with a longer --wait. Spam filters that drop logs of the service dash0-cli also hide it.",
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return checkResult{Status: statusFail, Message: ctx.Err().Error()}
+		case <-time.After(roundTripPollInterval):
+		}
+	}
+}
+
+func (d *doctor) findTestLog(ctx context.Context, filters *dash0api.FilterCriteria) (bool, error) {
+	timeRange := dash0api.TimeReferenceRange{From: "now-15m", To: "now"}
+	sampling, err := query.ParsePrecision(string(dash0api.SamplingModeDisabled), timeRange)
+	if err != nil {
+		return false, err
+	}
+	iter := d.apiClient.GetLogRecordsIter(ctx, &dash0api.GetLogRecordsRequest{
+		TimeRange: timeRange,
+		Dataset:   d.dataset,
+		Filter:    filters,
+		Pagination: &dash0api.CursorPagination{
+			Limit: dash0api.Int64(1),
+		},
+		Sampling: sampling,
+	})
+	found := iter.Next()
+	return found, iter.Err()
+}
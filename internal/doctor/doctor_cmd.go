@@ -0,0 +1,110 @@
+// Package doctor implements `dash0 doctor`, which diagnoses the
+// configuration of the CLI and its connectivity to Dash0 end to end.
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/agentmode"
+	colorpkg "github.com/dash0hq/dash0-cli/internal/color"
+	"github.com/spf13/cobra"
+)
+
+type doctorFlags struct {
+	ApiUrl    string
+	OtlpUrl   string
+	AuthToken string
+	Dataset   string
+	Output    string
+	SkipSend  bool
+	Wait      time.Duration
+}
+
+// NewDoctorCmd creates the doctor command.
+func NewDoctorCmd() *cobra.Command {
+	flags := &doctorFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the configuration and connectivity to Dash0",
+		Long: `Resolve the effective configuration the way 'dash0 config show' does, and check it end to end:
+DNS resolution and TLS handshakes with the API and OTLP endpoints, the auth token with a cheap
+authenticated API call, the OAuth session of the profile, the dataset, and a test log record
+that is sent via OTLP and then looked up with a log query.
+
+Each check reports pass, warn, fail, or skip; checks that depend on a failed one are skipped.
+The command exits with status 1 when a check fails.` + internal.CONFIG_HINT,
+		Example: `  # Check the active profile
+  dash0 doctor
+
+  # Check another profile
+  dash0 doctor --profile prod
+
+  # Check without sending a test log record
+  dash0 doctor --skip-send
+
+  # Output the report as JSON
+  dash0 doctor -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctor(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.ApiUrl, "api-url", "", "API endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset name")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Output format: table, json (default: table; json in agent mode)")
+	cmd.Flags().BoolVar(&flags.SkipSend, "skip-send", false, "Do not send a test log record via OTLP")
+	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Minute, "How long to wait for the test log record to become queryable")
+
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, flags *doctorFlags) error {
+	useJSON := strings.ToLower(flags.Output) == "json" ||
+		(flags.Output == "" && agentmode.Enabled)
+	if !useJSON && flags.Output != "" && strings.ToLower(flags.Output) != "table" {
+		return fmt.Errorf("unknown output format: %s (valid formats: table, json)", flags.Output)
+	}
+	cmd.SilenceUsage = true
+
+	d := newDoctor(flags, !useJSON)
+	defer d.close(cmd.Context())
+	rep := d.run(cmd.Context())
+
+	if useJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			return err
+		}
+	} else {
+		printReport(rep)
+	}
+
+	if rep.Summary.Failed > 0 {
+		return &internal.ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+// checkNameWidth is the width of the check-name column of the table output.
+const checkNameWidth = 16
+
+func printReport(rep *report) {
+	for _, r := range rep.Checks {
+		fmt.Printf("%s  %-*s %s\n", colorpkg.SprintCheckStatus(strings.ToUpper(string(r.Status)), 4), checkNameWidth, r.Name, r.Message)
+		if r.Hint != "" {
+			fmt.Printf("%*s Hint: %s\n", 4+2+checkNameWidth, "", r.Hint)
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%d passed, %d warnings, %d failed, %d skipped\n", rep.Summary.Passed, rep.Summary.Warnings, rep.Summary.Failed, rep.Summary.Skipped)
+}
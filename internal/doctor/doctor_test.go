@@ -0,0 +1,100 @@
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedCheck(name string, status checkStatus, needs ...string) check {
+	return check{name: name, needs: needs, run: func(context.Context) checkResult {
+		return checkResult{Status: status, Message: name}
+	}}
+}
+
+func TestRunChecksSkipsDependentsOfFailedChecks(t *testing.T) {
+	rep := runChecks(context.Background(), []check{
+		fixedCheck("a", statusPass),
+		fixedCheck("b", statusFail, "a"),
+		fixedCheck("c", statusPass, "b"),
+		fixedCheck("d", statusPass, "c"),
+		fixedCheck("e", statusWarn, "a"),
+	})
+
+	require.Len(t, rep.Checks, 5)
+	assert.Equal(t, statusSkip, rep.Checks[2].Status)
+	assert.Equal(t, "skipped because b did not pass", rep.Checks[2].Message)
+	assert.Equal(t, statusSkip, rep.Checks[3].Status)
+	assert.Equal(t, "skipped because c did not pass", rep.Checks[3].Message)
+	assert.Equal(t, statusWarn, rep.Checks[4].Status)
+	assert.Equal(t, reportSummary{Passed: 1, Warnings: 1, Failed: 1, Skipped: 2}, rep.Summary)
+}
+
+func TestRunChecksInapplicableSkipDoesNotBlock(t *testing.T) {
+	rep := runChecks(context.Background(), []check{
+		{name: "oauth", run: func(context.Context) checkResult {
+			return checkResult{Status: statusSkip, Message: "not an OAuth profile", inapplicable: true}
+		}},
+		fixedCheck("explicit-skip", statusSkip),
+		fixedCheck("after-oauth", statusPass, "oauth"),
+		fixedCheck("after-explicit-skip", statusPass, "explicit-skip"),
+	})
+
+	assert.Equal(t, statusPass, rep.Checks[2].Status)
+	assert.Equal(t, statusSkip, rep.Checks[3].Status)
+}
+
+func TestCheckEndpointTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	d := &doctor{tlsConfig: &tls.Config{RootCAs: roots}}
+
+	res := d.checkEndpoint(context.Background(), "api-url", server.URL)
+	assert.Equal(t, statusPass, res.Status, res.Message)
+	assert.Contains(t, res.Message, "TLS 1.")
+}
+
+func TestCheckEndpointUntrustedCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	res := (&doctor{}).checkEndpoint(context.Background(), "api-url", server.URL)
+	assert.Equal(t, statusFail, res.Status)
+	assert.True(t, strings.HasPrefix(res.Message, "TLS handshake with "), res.Message)
+	assert.Contains(t, res.Message, "certificate")
+}
+
+func TestCheckEndpointPlainHTTPWarns(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	res := (&doctor{}).checkEndpoint(context.Background(), "otlp-url", server.URL)
+	assert.Equal(t, statusWarn, res.Status)
+	assert.Contains(t, res.Message, "does not use TLS")
+}
+
+func TestCheckEndpointNotSet(t *testing.T) {
+	res := (&doctor{}).checkEndpoint(context.Background(), "otlp-url", "")
+	assert.Equal(t, statusFail, res.Status)
+	assert.Equal(t, "otlp-url is not set", res.Message)
+}
+
+func TestNewTestLogsCarriesRunID(t *testing.T) {
+	logs := newTestLogs("run-1")
+
+	require.Equal(t, 1, logs.LogRecordCount())
+	lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	v, ok := lr.Attributes().Get(testRunAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "run-1", v.Str())
+}
@@ -0,0 +1,23 @@
+package events
+
+import "github.com/spf13/cobra"
+
+// NewEventsCmd creates a new events command.
+func NewEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Send deployment, change, and incident events",
+		Long: `Send deployment, change, and incident events to Dash0 via OTLP.
+
+Events are log records with an event name and attributes in the shape Dash0
+expects, so they show up as markers next to the telemetry of the affected
+service. A started (or opened) event followed by its finishing event is also
+sent as a span that covers the time in between.`,
+	}
+
+	cmd.AddCommand(newEventCmd(deploymentKind))
+	cmd.AddCommand(newEventCmd(changeKind))
+	cmd.AddCommand(newEventCmd(incidentKind))
+
+	return cmd
+}
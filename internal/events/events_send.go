@@ -0,0 +1,461 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// eventKind describes one `dash0 events` subcommand: the event name it
+// sends, the namespace of its attributes, and its statuses. The start
+// status opens an event that one of the finish statuses closes.
+type eventKind struct {
+	name           string
+	title          string
+	eventName      string
+	startStatus    string
+	finishStatuses []string
+	defaultStatus  string
+	// failedStatus marks the finish status that ends in an error.
+	failedStatus string
+	short        string
+	example      string
+}
+
+var deploymentKind = &eventKind{
+	name:           "deployment",
+	title:          "Deployment",
+	eventName:      "dash0.deployment",
+	startStatus:    "started",
+	finishStatuses: []string{"succeeded", "failed"},
+	defaultStatus:  "succeeded",
+	failedStatus:   "failed",
+	short:          "Send a deployment event to Dash0",
+	example: `  # Announce a finished deployment
+  dash0 events deployment --service checkout --version 1.4.0 --environment production
+
+  # Send the deployment as a span from start to finish
+  dash0 events deployment --service checkout --version 1.4.0 --environment production --status started
+  ./deploy.sh
+  dash0 events deployment --service checkout --version 1.4.0 --environment production --status succeeded
+
+  # Finish a deployment that was started on another machine
+  dash0 events deployment --service checkout --status failed --started-at "$DEPLOY_STARTED_AT"`,
+}
+
+var changeKind = &eventKind{
+	name:           "change",
+	title:          "Change",
+	eventName:      "dash0.change",
+	startStatus:    "started",
+	finishStatuses: []string{"succeeded", "failed"},
+	defaultStatus:  "succeeded",
+	failedStatus:   "failed",
+	short:          "Send a change event, such as a feature flag or configuration change, to Dash0",
+	example: `  # Record a feature flag change
+  dash0 events change --service checkout --environment production \
+      --type feature-flag --message "Enabled new-payment-flow for 10% of users"
+
+  # Record a database migration from start to finish
+  dash0 events change --service orders --type migration --id 2026-10-18-add-index --status started
+  ./migrate.sh
+  dash0 events change --service orders --type migration --id 2026-10-18-add-index --status succeeded`,
+}
+
+var incidentKind = &eventKind{
+	name:           "incident",
+	title:          "Incident",
+	eventName:      "dash0.incident",
+	startStatus:    "opened",
+	finishStatuses: []string{"resolved"},
+	defaultStatus:  "opened",
+	short:          "Send an incident event to Dash0",
+	example: `  # Open an incident
+  dash0 events incident --title "Checkout latency above SLO" --id INC-1042 --severity critical \
+      --service checkout --environment production
+
+  # Resolve it; the incident is also sent as a span from open to resolution
+  dash0 events incident --title "Checkout latency above SLO" --id INC-1042 --status resolved \
+      --service checkout --environment production`,
+}
+
+func (k *eventKind) statuses() []string {
+	return append([]string{k.startStatus}, k.finishStatuses...)
+}
+
+type eventFlags struct {
+	OtlpUrl            string
+	AuthToken          string
+	Dataset            string
+	Service            string
+	Version            string
+	Environment        string
+	Status             string
+	ID                 string
+	Message            string
+	StartedAt          string
+	Name               string
+	Type               string
+	Title              string
+	Severity           string
+	Attributes         []string
+	ResourceAttributes []string
+}
+
+func newEventCmd(kind *eventKind) *cobra.Command {
+	flags := &eventFlags{}
+
+	cmd := &cobra.Command{
+		Use:   kind.name,
+		Short: kind.short,
+		Long: fmt.Sprintf(`Send a %s event to Dash0 via OTLP, as a log record with the event name %s.
+
+Use --status %s when the %s begins and --status %s when it ends: the second event is then also sent as a span that covers the time in between.
+The start is remembered in the configuration directory, keyed by the event's service, environment, ID, and title; pass --started-at when the %s was started on another machine.
+In GitHub Actions, GitLab CI, and Jenkins, the pipeline run is added as cicd.* resource attributes; the repository, branch, and commit are added as vcs.* resource attributes, from the CI environment or the local git checkout.`,
+			kind.name, kind.eventName, kind.startStatus, kind.name, strings.Join(kind.finishStatuses, " or --status "), kind.name) + internal.CONFIG_HINT,
+		Example: kind.example,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runEvent(cmd, kind, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset name")
+	cmd.Flags().StringVar(&flags.Status, "status", kind.defaultStatus, "Status: "+strings.Join(kind.statuses(), ", "))
+	cmd.Flags().StringVar(&flags.ID, "id", "", fmt.Sprintf("Identifier of the %s, sent as %s.id", kind.name, kind.name))
+	cmd.Flags().StringVar(&flags.Environment, "environment", "", "Deployment environment, sent as deployment.environment.name")
+	cmd.Flags().StringVar(&flags.Version, "version", "", "Service version, sent as service.version")
+	cmd.Flags().StringVar(&flags.Message, "message", "", "Event body (default: a sentence built from the flags)")
+	cmd.Flags().StringVar(&flags.StartedAt, "started-at", "", fmt.Sprintf("When the %s started, in RFC3339 format, to send it as a span without a recorded start", kind.name))
+	cmd.Flags().StringArrayVar(&flags.Attributes, "attribute", nil, "Event attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil, "Resource attribute as 'key=value' (repeatable)")
+
+	switch kind {
+	case deploymentKind:
+		cmd.Flags().StringVar(&flags.Service, "service", "", "Name of the deployed service, sent as service.name (required)")
+		cmd.Flags().StringVar(&flags.Name, "name", "", "Name of the deployment, sent as deployment.name")
+	case changeKind:
+		cmd.Flags().StringVar(&flags.Service, "service", "", "Name of the changed service, sent as service.name (required)")
+		cmd.Flags().StringVar(&flags.Type, "type", "", "Kind of change, such as 'feature-flag', 'config', or 'migration', sent as change.type")
+	case incidentKind:
+		cmd.Flags().StringVar(&flags.Title, "title", "", "Title of the incident, sent as incident.title (required)")
+		cmd.Flags().StringVar(&flags.Severity, "severity", "", "Severity of the incident, such as 'critical', sent as incident.severity")
+		cmd.Flags().StringVar(&flags.Service, "service", "", "Name of the affected service, sent as service.name")
+	}
+
+	return cmd
+}
+
+func runEvent(cmd *cobra.Command, kind *eventKind, flags *eventFlags) error {
+	ctx := cmd.Context()
+
+	status := strings.ToLower(strings.TrimSpace(flags.Status))
+	if !slices.Contains(kind.statuses(), status) {
+		return fmt.Errorf("invalid status %q; expected one of: %s", flags.Status, strings.Join(kind.statuses(), ", "))
+	}
+	if kind == incidentKind && flags.Title == "" {
+		return fmt.Errorf(`required flag(s) "title" not set`)
+	}
+	if kind != incidentKind && flags.Service == "" {
+		return fmt.Errorf(`required flag(s) "service" not set`)
+	}
+	if flags.StartedAt != "" && status == kind.startStatus {
+		return fmt.Errorf("--started-at cannot be used with --status %s", kind.startStatus)
+	}
+
+	attrs, err := otlp.ParseKeyValuePairs(flags.Attributes)
+	if err != nil {
+		return fmt.Errorf("invalid attribute: %w", err)
+	}
+
+	flagResourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid resource attribute: %w", err)
+	}
+
+	now := time.Now()
+	key := kind.stateKey(flags)
+
+	// The start of the event becomes the start of its span. A start event
+	// already carries the IDs of that span, so both events belong to it.
+	var span *pendingEvent
+	if status == kind.startStatus {
+		span = newPendingEvent(now)
+	} else {
+		if pending, ok := loadPendingEvent(key); ok {
+			span = &pending
+		}
+		if flags.StartedAt != "" {
+			startedAt, err := time.Parse(time.RFC3339Nano, flags.StartedAt)
+			if err != nil {
+				return fmt.Errorf("invalid started-at format (expected RFC3339): %w", err)
+			}
+			if span == nil {
+				span = newPendingEvent(startedAt)
+			}
+			span.StartedAt = startedAt
+		}
+	}
+
+	resourceAttrs := eventResourceAttributes(ctx, flags, flagResourceAttrs)
+	ev := kind.newEvent(flags, status, attrs)
+	logs, err := ev.logs(resourceAttrs, span, now)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	defer apiClient.Close(ctx)
+	dataset := client.ResolveDataset(ctx, flags.Dataset)
+
+	if err := apiClient.SendLogs(ctx, logs, dataset); err != nil {
+		return fmt.Errorf("failed to send %s event: %w", kind.name, err)
+	}
+
+	if status == kind.startStatus {
+		if err := savePendingEvent(key, *span); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record the start of the %s; pass --started-at when it finishes: %v\n", kind.name, err)
+		}
+		fmt.Printf("%s event sent (status: %s, trace-id: %s)\n", kind.title, status, span.TraceID)
+		return nil
+	}
+	if span == nil {
+		fmt.Printf("%s event sent (status: %s)\n", kind.title, status)
+		return nil
+	}
+
+	traces, err := ev.span(resourceAttrs, *span, now)
+	if err != nil {
+		return err
+	}
+	if err := apiClient.SendTraces(ctx, traces, dataset); err != nil {
+		return fmt.Errorf("failed to send %s span: %w", kind.name, err)
+	}
+	removePendingEvent(key)
+	fmt.Printf("%s event sent (status: %s) with a span of %s (trace-id: %s)\n",
+		kind.title, status, now.Sub(span.StartedAt).Round(time.Millisecond), span.TraceID)
+	return nil
+}
+
+// event is the content of an event, shared by its log record and span.
+type event struct {
+	kind       *eventKind
+	status     string
+	subject    string
+	body       string
+	attributes map[string]string
+}
+
+// newEvent builds the event of the flags. Attributes given with --attribute
+// win over the ones built from the other flags.
+func (k *eventKind) newEvent(flags *eventFlags, status string, extra map[string]string) *event {
+	ev := &event{kind: k, status: status, attributes: map[string]string{}}
+	put := func(key, value string) {
+		if value != "" {
+			ev.attributes[key] = value
+		}
+	}
+
+	put(k.name+".status", status)
+	put(k.name+".id", flags.ID)
+	switch k {
+	case deploymentKind:
+		put("deployment.name", flags.Name)
+	case changeKind:
+		put("change.type", flags.Type)
+	case incidentKind:
+		put("incident.title", flags.Title)
+		put("incident.severity", flags.Severity)
+	}
+	for key, value := range extra {
+		ev.attributes[key] = value
+	}
+
+	ev.subject = flags.Service
+	if k == incidentKind {
+		ev.subject = flags.Title
+	}
+
+	ev.body = flags.Message
+	if ev.body == "" {
+		ev.body = k.defaultBody(flags, status)
+	}
+	return ev
+}
+
+// defaultBody describes the event in a sentence, such as "Deployment of
+// checkout 1.4.0 to production succeeded".
+func (k *eventKind) defaultBody(flags *eventFlags, status string) string {
+	if k == incidentKind {
+		return fmt.Sprintf("Incident %s: %s", status, flags.Title)
+	}
+	body := k.title + " of " + strings.TrimSpace(flags.Service+" "+flags.Version)
+	if flags.Environment != "" {
+		// A deployment goes to an environment; other changes happen in one.
+		preposition := " in "
+		if k == deploymentKind {
+			preposition = " to "
+		}
+		body += preposition + flags.Environment
+	}
+	return body + " " + status
+}
+
+func (ev *event) severity() plog.SeverityNumber {
+	switch {
+	case ev.kind.failedStatus != "" && ev.status == ev.kind.failedStatus:
+		return plog.SeverityNumberError
+	case ev.kind == incidentKind && ev.status == ev.kind.startStatus:
+		return plog.SeverityNumberWarn
+	default:
+		return plog.SeverityNumberInfo
+	}
+}
+
+// logs returns the payload of the event's log record. With a span, the
+// record carries its trace context; without one, the record belongs to the
+// span in TRACEPARENT, if any.
+func (ev *event) logs(resourceAttrs map[string]string, span *pendingEvent, now time.Time) (plog.Logs, error) {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	for k, v := range resourceAttrs {
+		rl.Resource().Attributes().PutStr(k, v)
+	}
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(otlp.DefaultScopeName)
+	sl.Scope().SetVersion(otlp.DefaultScopeVersion())
+
+	lr := sl.LogRecords().AppendEmpty()
+	lr.SetEventName(ev.kind.eventName)
+	lr.Body().SetStr(ev.body)
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+	lr.SetSeverityNumber(ev.severity())
+	lr.SetSeverityText(strings.ToUpper(ev.severity().String()))
+	for k, v := range ev.attributes {
+		lr.Attributes().PutStr(k, v)
+	}
+
+	if span != nil {
+		traceID, spanID, err := span.ids()
+		if err != nil {
+			return plog.Logs{}, err
+		}
+		lr.SetTraceID(traceID)
+		lr.SetSpanID(spanID)
+	} else if parent, ok := otlp.TraceparentFromEnv(); ok {
+		lr.SetTraceID(parent.TraceID)
+		lr.SetSpanID(parent.SpanID)
+	}
+	return logs, nil
+}
+
+// span returns the payload of the span from the start of the event to now.
+func (ev *event) span(resourceAttrs map[string]string, pending pendingEvent, now time.Time) (ptrace.Traces, error) {
+	traceID, spanID, err := pending.ids()
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	for k, v := range resourceAttrs {
+		rs.Resource().Attributes().PutStr(k, v)
+	}
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName(otlp.DefaultScopeName)
+	ss.Scope().SetVersion(otlp.DefaultScopeVersion())
+
+	s := ss.Spans().AppendEmpty()
+	s.SetName(ev.kind.name + " " + ev.subject)
+	s.SetKind(ptrace.SpanKindInternal)
+	s.SetTraceID(traceID)
+	s.SetSpanID(spanID)
+	if pending.ParentSpanID != "" {
+		parentSpanID, err := otlp.ParseSpanID(pending.ParentSpanID)
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		s.SetParentSpanID(parentSpanID)
+		s.TraceState().FromRaw(pending.TraceState)
+	}
+	s.SetStartTimestamp(pcommon.NewTimestampFromTime(pending.StartedAt))
+	s.SetEndTimestamp(pcommon.NewTimestampFromTime(now))
+	for k, v := range ev.attributes {
+		s.Attributes().PutStr(k, v)
+	}
+	if ev.kind.failedStatus != "" && ev.status == ev.kind.failedStatus {
+		s.Status().SetCode(ptrace.StatusCodeError)
+		s.Status().SetMessage(ev.body)
+	} else {
+		s.Status().SetCode(ptrace.StatusCodeOk)
+	}
+	return traces, nil
+}
+
+// eventResourceAttributes returns the resource of an event: the CI pipeline
+// run and the VCS revision, the service from the flags, and finally the
+// --resource-attribute values, each overriding the ones before.
+func eventResourceAttributes(ctx context.Context, flags *eventFlags, flagAttrs map[string]string) map[string]string {
+	attrs := otlp.CIResourceAttributes()
+	for k, v := range gitResourceAttributes(ctx) {
+		if _, exists := attrs[k]; !exists {
+			attrs[k] = v
+		}
+	}
+	if flags.Service != "" {
+		attrs["service.name"] = flags.Service
+	}
+	if flags.Version != "" {
+		attrs["service.version"] = flags.Version
+	}
+	if flags.Environment != "" {
+		attrs["deployment.environment.name"] = flags.Environment
+	}
+	for k, v := range flagAttrs {
+		attrs[k] = v
+	}
+	return attrs
+}
+
+func newTraceID() pcommon.TraceID {
+	var id pcommon.TraceID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() pcommon.SpanID {
+	var id pcommon.SpanID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func (p pendingEvent) ids() (pcommon.TraceID, pcommon.SpanID, error) {
+	traceID, err := otlp.ParseTraceID(p.TraceID)
+	if err != nil {
+		return pcommon.TraceID{}, pcommon.SpanID{}, err
+	}
+	spanID, err := otlp.ParseSpanID(p.SpanID)
+	if err != nil {
+		return pcommon.TraceID{}, pcommon.SpanID{}, err
+	}
+	return traceID, spanID, nil
+}
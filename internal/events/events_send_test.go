@@ -0,0 +1,135 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestEventValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		kind    *eventKind
+		args    []string
+		wantErr string
+	}{
+		{"deployment without service", deploymentKind, nil, `required flag(s) "service" not set`},
+		{"incident without title", incidentKind, []string{"--service", "checkout"}, `required flag(s) "title" not set`},
+		{"unknown status", deploymentKind, []string{"--service", "checkout", "--status", "resolved"}, `invalid status "resolved"; expected one of: started, succeeded, failed`},
+		{"started-at on start", changeKind, []string{"--service", "checkout", "--status", "started", "--started-at", "2026-10-18T12:00:00Z"}, "--started-at cannot be used with --status started"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newEventCmd(tc.kind)
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	deployment := deploymentKind.newEvent(&eventFlags{
+		Service: "checkout", Version: "1.4.0", Environment: "production", ID: "run-77", Name: "canary",
+	}, "failed", map[string]string{"team": "payments"})
+	assert.Equal(t, "Deployment of checkout 1.4.0 to production failed", deployment.body)
+	assert.Equal(t, map[string]string{
+		"deployment.status": "failed",
+		"deployment.id":     "run-77",
+		"deployment.name":   "canary",
+		"team":              "payments",
+	}, deployment.attributes)
+	assert.Equal(t, plog.SeverityNumberError, deployment.severity())
+
+	change := changeKind.newEvent(&eventFlags{Service: "checkout", Environment: "staging", Type: "feature-flag"}, "succeeded", nil)
+	assert.Equal(t, "Change of checkout in staging succeeded", change.body)
+	assert.Equal(t, "feature-flag", change.attributes["change.type"])
+
+	incident := incidentKind.newEvent(&eventFlags{Title: "Checkout is down", Severity: "critical", Message: "Paged on-call"}, "opened", nil)
+	assert.Equal(t, "Paged on-call", incident.body)
+	assert.Equal(t, "Checkout is down", incident.subject)
+	assert.Equal(t, plog.SeverityNumberWarn, incident.severity())
+}
+
+func TestEventPayloads_SharedSpan(t *testing.T) {
+	t.Setenv("TRACEPARENT", "")
+	ev := deploymentKind.newEvent(&eventFlags{Service: "checkout"}, "failed", nil)
+	resource := map[string]string{"service.name": "checkout"}
+	now := time.Date(2026, 10, 18, 12, 4, 0, 0, time.UTC)
+	pending := pendingEvent{
+		TraceID:   "0af7651916cd43dd8448eb211c80319c",
+		SpanID:    "b7ad6b7169203331",
+		StartedAt: now.Add(-4 * time.Minute),
+	}
+
+	logs, err := ev.logs(resource, &pending, now)
+	require.NoError(t, err)
+	lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "dash0.deployment", lr.EventName())
+	assert.Equal(t, "ERROR", lr.SeverityText())
+	assert.Equal(t, pending.TraceID, lr.TraceID().String())
+	assert.Equal(t, pending.SpanID, lr.SpanID().String())
+
+	traces, err := ev.span(resource, pending, now)
+	require.NoError(t, err)
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "deployment checkout", span.Name())
+	assert.Equal(t, lr.SpanID(), span.SpanID())
+	assert.Equal(t, 4*time.Minute, span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()))
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	status, _ := span.Attributes().Get("deployment.status")
+	assert.Equal(t, "failed", status.Str())
+
+	unpaired, err := ev.logs(resource, nil, now)
+	require.NoError(t, err)
+	assert.True(t, unpaired.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).TraceID().IsEmpty())
+}
+
+func TestNewPendingEvent_JoinsTraceparent(t *testing.T) {
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	t.Setenv("TRACESTATE", "vendor=abc")
+	now := time.Date(2026, 10, 18, 12, 4, 0, 0, time.UTC)
+	pending := newPendingEvent(now.Add(-time.Minute))
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", pending.TraceID)
+
+	ev := deploymentKind.newEvent(&eventFlags{Service: "checkout"}, "succeeded", nil)
+	traces, err := ev.span(nil, *pending, now)
+	require.NoError(t, err)
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "b7ad6b7169203331", span.ParentSpanID().String())
+	assert.Equal(t, "vendor=abc", span.TraceState().AsRaw())
+	assert.NotEqual(t, "b7ad6b7169203331", span.SpanID().String())
+
+	logs, err := ev.logs(nil, nil, now)
+	require.NoError(t, err)
+	lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "b7ad6b7169203331", lr.SpanID().String(), "an unpaired event belongs to the span in TRACEPARENT")
+}
+
+func TestPendingEvent(t *testing.T) {
+	t.Setenv(profiles.EnvConfigDir, t.TempDir())
+	started := &eventFlags{Service: "checkout", Environment: "production"}
+	other := &eventFlags{Service: "checkout", Environment: "staging"}
+	p := pendingEvent{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331", StartedAt: time.Now().UTC()}
+
+	require.NoError(t, savePendingEvent(deploymentKind.stateKey(started), p))
+	got, ok := loadPendingEvent(deploymentKind.stateKey(started))
+	require.True(t, ok)
+	assert.True(t, p.StartedAt.Equal(got.StartedAt))
+	assert.Equal(t, p.SpanID, got.SpanID)
+
+	_, ok = loadPendingEvent(deploymentKind.stateKey(other))
+	assert.False(t, ok, "another environment is another deployment")
+	_, ok = loadPendingEvent(changeKind.stateKey(started))
+	assert.False(t, ok, "a change is not a deployment")
+
+	removePendingEvent(deploymentKind.stateKey(started))
+	_, ok = loadPendingEvent(deploymentKind.stateKey(started))
+	assert.False(t, ok)
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+)
+
+// pendingEvent is an event that has started and not yet finished. It is
+// kept in the configuration directory so that the finishing event, usually
+// sent by a later command of the same CI job, can be sent as a span.
+type pendingEvent struct {
+	TraceID      string    `json:"trace-id"`
+	SpanID       string    `json:"span-id"`
+	ParentSpanID string    `json:"parent-span-id,omitempty"`
+	TraceState   string    `json:"trace-state,omitempty"`
+	StartedAt    time.Time `json:"started-at"`
+}
+
+// newPendingEvent returns the span of an event started at startedAt. Inside
+// a traced operation, such as an enclosing `dash0 spans exec`, the span
+// joins the trace in TRACEPARENT; otherwise it starts a new trace.
+func newPendingEvent(startedAt time.Time) *pendingEvent {
+	p := &pendingEvent{TraceID: newTraceID().String(), SpanID: newSpanID().String(), StartedAt: startedAt}
+	if parent, ok := otlp.TraceparentFromEnv(); ok {
+		p.TraceID = parent.TraceID.String()
+		p.ParentSpanID = parent.SpanID.String()
+		p.TraceState = otlp.TracestateFromEnv()
+	}
+	return p
+}
+
+// stateKey identifies an event across its start and finish: the same kind,
+// service, environment, ID, and title mean the same event.
+func (k *eventKind) stateKey(flags *eventFlags) string {
+	return strings.Join([]string{k.name, flags.Service, flags.Environment, flags.ID, flags.Title}, "\x00")
+}
+
+// pendingEventPath returns the file of an event's start, in the events
+// directory under the configuration directory (default: ~/.dash0).
+func pendingEventPath(key string) (string, error) {
+	dir, err := internal.StateDir("events")
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(key))
+	kind, _, _ := strings.Cut(key, "\x00")
+	return filepath.Join(dir, kind+"-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func savePendingEvent(key string, p pendingEvent) error {
+	path, err := pendingEventPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// loadPendingEvent returns the recorded start of an event, if any. An
+// unreadable record is treated as missing: the finishing event is still
+// sent, only without a span.
+func loadPendingEvent(key string) (pendingEvent, bool) {
+	path, err := pendingEventPath(key)
+	if err != nil {
+		return pendingEvent{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pendingEvent{}, false
+	}
+	var p pendingEvent
+	if err := json.Unmarshal(data, &p); err != nil || p.StartedAt.IsZero() {
+		return pendingEvent{}, false
+	}
+	return p, true
+}
+
+func removePendingEvent(key string) {
+	if path, err := pendingEventPath(key); err == nil {
+		_ = os.Remove(path)
+	}
+}
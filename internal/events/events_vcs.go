@@ -0,0 +1,25 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/vcs"
+)
+
+// gitTimeout bounds the git commands that describe the local checkout, so a
+// slow or hanging git never delays an event.
+const gitTimeout = 2 * time.Second
+
+// gitResourceAttributes describes the git checkout of the working directory
+// with `vcs.*` resource attributes, like `otlp proxy --detect git`. Outside of
+// a checkout, or without git, the result is empty.
+func gitResourceAttributes(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+	attrs, err := vcs.Detect(ctx, "")
+	if err != nil {
+		return map[string]string{}
+	}
+	return attrs
+}
@@ -0,0 +1,15 @@
+package internal
+
+import "fmt"
+
+// ExitCodeError makes the CLI exit with Code without printing an error
+// message. Commands that wrap a child process return it to pass the
+// child's exit code through after the child has already reported its own
+// failure.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
@@ -1,6 +1,7 @@
 package failedchecks
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/client"
 	colorpkg "github.com/dash0hq/dash0-cli/internal/color"
+	"github.com/dash0hq/dash0-cli/internal/config"
 	"github.com/dash0hq/dash0-cli/internal/output"
 	"github.com/dash0hq/dash0-cli/internal/query"
 	"github.com/spf13/cobra"
@@ -172,7 +174,7 @@ func runQuery(cmd *cobra.Command, flags *queryFlags) error {
 		return err
 	}
 
-	filters, err := buildFilters(flags)
+	filters, err := buildFilters(ctx, flags)
 	if err != nil {
 		return err
 	}
@@ -243,9 +245,10 @@ func resolveColumns(columns []string) ([]query.ColumnDef, error) {
 	return query.ResolveColumns(specs, failedCheckKnownColumns), nil
 }
 
-// buildFilters combines --filter, --status, and --active into a FilterCriteria.
-func buildFilters(flags *queryFlags) (*dash0api.FilterCriteria, error) {
-	filters, err := query.ParseFilters(flags.Filter)
+// buildFilters combines the project filters, --filter, --status, and --active
+// into a FilterCriteria.
+func buildFilters(ctx context.Context, flags *queryFlags) (*dash0api.FilterCriteria, error) {
+	filters, err := query.ParseFilters(config.QueryFilters(ctx, "failed-checks", flags.Filter))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,198 @@
+// Package httptransport builds the transport of every outbound connection of
+// the CLI: the API and OTLP clients, raw requests, OAuth requests, and the
+// proxy's forwarders. All of them honour HTTPS_PROXY and NO_PROXY, the CA file
+// and client certificate of the profile (or of DASH0_CA_FILE,
+// DASH0_CLIENT_CERT, and DASH0_CLIENT_KEY), and --insecure-skip-tls-verify.
+package httptransport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+)
+
+// Environment variables that override the TLS settings of the profile.
+const (
+	EnvCAFile     = "DASH0_CA_FILE"
+	EnvClientCert = "DASH0_CLIENT_CERT"
+	EnvClientKey  = "DASH0_CLIENT_KEY"
+)
+
+// Settings are the TLS settings of outbound connections.
+type Settings struct {
+	// CAFile is a PEM file of CA certificates trusted in addition to the
+	// system ones, e.g. the CA of an intercepting proxy.
+	CAFile string
+	// ClientCert and ClientKey are the PEM files of the client certificate
+	// presented to servers that require mutual TLS.
+	ClientCert string
+	ClientKey  string
+	// InsecureSkipVerify disables the verification of server certificates.
+	InsecureSkipVerify bool
+}
+
+type insecureSkipVerifyKey struct{}
+
+// WithInsecureSkipVerify stores the --insecure-skip-tls-verify flag in the
+// context.
+func WithInsecureSkipVerify(ctx context.Context) context.Context {
+	return context.WithValue(ctx, insecureSkipVerifyKey{}, true)
+}
+
+// FromContext returns the settings of the profile in the context, with the
+// environment and flag overrides applied.
+func FromContext(ctx context.Context) Settings {
+	return SettingsFor(ctx, profiles.FromContext(ctx))
+}
+
+// SettingsFor returns the settings of cfg, which may be nil. The
+// DASH0_CA_FILE, DASH0_CLIENT_CERT, and DASH0_CLIENT_KEY environment
+// variables take precedence over the profile; the client certificate and
+// key are overridden together so that a pair is never mixed.
+func SettingsFor(ctx context.Context, cfg *profiles.Configuration) Settings {
+	var s Settings
+	if cfg != nil {
+		s.CAFile = cfg.CaFile
+		s.ClientCert = cfg.ClientCert
+		s.ClientKey = cfg.ClientKey
+	}
+	if v := os.Getenv(EnvCAFile); v != "" {
+		s.CAFile = v
+	}
+	if cert, key := os.Getenv(EnvClientCert), os.Getenv(EnvClientKey); cert != "" || key != "" {
+		s.ClientCert = cert
+		s.ClientKey = key
+	}
+	s.InsecureSkipVerify = ctx.Value(insecureSkipVerifyKey{}) != nil
+	return s
+}
+
+// IsZero reports whether s leaves every setting at the default, i.e. the
+// system CA certificates, no client certificate, and verification on.
+func (s Settings) IsZero() bool {
+	return s == Settings{}
+}
+
+// Validate checks that the files of s can be loaded.
+func (s Settings) Validate() error {
+	_, err := s.tlsConfig()
+	return err
+}
+
+// TLSConfig returns the TLS configuration of s. The first configuration that
+// skips verification prints a warning to stderr.
+func (s Settings) TLSConfig() (*tls.Config, error) {
+	cfg, err := s.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if s.InsecureSkipVerify {
+		warnInsecure.Do(func() {
+			fmt.Fprintln(os.Stderr, "Warning: TLS certificate verification is disabled by --insecure-skip-tls-verify. "+
+				"Anyone on the network path can read and alter the traffic, including your credentials. "+
+				"Prefer trusting the CA of your proxy with --ca-file or DASH0_CA_FILE.")
+		})
+	}
+	return cfg, nil
+}
+
+var warnInsecure sync.Once
+
+func (s Settings) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: s.InsecureSkipVerify}
+	if s.CAFile != "" {
+		pem, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %q: %w", s.CAFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %q contains no PEM certificates", s.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	switch {
+	case s.ClientCert != "" && s.ClientKey != "":
+		cert, err := tls.LoadX509KeyPair(s.ClientCert, s.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %q with key %q: %w", s.ClientCert, s.ClientKey, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case s.ClientCert != "":
+		return nil, fmt.Errorf("client certificate %q has no client key\nHint: Set the client key with --client-key or %s.", s.ClientCert, EnvClientKey)
+	case s.ClientKey != "":
+		return nil, fmt.Errorf("client key %q has no client certificate\nHint: Set the client certificate with --client-cert or %s.", s.ClientKey, EnvClientCert)
+	}
+	return cfg, nil
+}
+
+var (
+	transportsMu sync.Mutex
+	// transports holds one transport per settings, so that the clients of
+	// an invocation share connections.
+	transports = map[Settings]*http.Transport{}
+)
+
+// RoundTripper returns the transport of outbound HTTP requests: the default
+// transport when the settings are zero, otherwise a copy of it with the TLS
+// configuration of the settings. Both take proxies from HTTPS_PROXY,
+// HTTP_PROXY, and NO_PROXY.
+func RoundTripper(ctx context.Context) (http.RoundTripper, error) {
+	return FromContext(ctx).RoundTripper()
+}
+
+// RoundTripper returns the transport of s; see the RoundTripper function.
+func (s Settings) RoundTripper() (http.RoundTripper, error) {
+	if s.IsZero() {
+		return http.DefaultTransport, nil
+	}
+	tlsConfig, err := s.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[s]; ok {
+		return t, nil
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	t.TLSClientConfig = tlsConfig
+	transports[s] = t
+	return t, nil
+}
+
+// Client returns an HTTP client with the transport of the context and the
+// given timeout; zero means no timeout.
+func Client(ctx context.Context, timeout time.Duration) (*http.Client, error) {
+	rt, err := RoundTripper(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rt, Timeout: timeout}, nil
+}
+
+// ProxyFor returns the proxy that requests to rawURL go through, or nil when
+// they connect directly.
+func ProxyFor(rawURL string) *url.URL {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil
+	}
+	proxy, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return nil
+	}
+	return proxy
+}
@@ -0,0 +1,181 @@
+package httptransport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed certificate for 127.0.0.1, usable as
+// a CA, a server certificate, and a client certificate, and its key as PEM
+// files to dir.
+func writeCertificate(t *testing.T, dir, name string) (certFile, keyFile string, cert tls.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0600))
+	cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return certFile, keyFile, cert
+}
+
+// newTLSServer starts a server with cert; clientCAs, if set, requires a
+// client certificate signed by one of them.
+func newTLSServer(t *testing.T, cert tls.Certificate, clientCAs *x509.CertPool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	if clientCAs != nil {
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+		server.TLS.ClientCAs = clientCAs
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, s Settings, url string) error {
+	t.Helper()
+	rt, err := s.RoundTripper()
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: rt}).Get(url)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+func TestSettingsFor(t *testing.T) {
+	t.Setenv(EnvCAFile, "")
+	t.Setenv(EnvClientCert, "")
+	t.Setenv(EnvClientKey, "")
+	cfg := &profiles.Configuration{CaFile: "/profile/ca.pem", ClientCert: "/profile/cert.pem", ClientKey: "/profile/key.pem"}
+
+	assert.True(t, SettingsFor(context.Background(), nil).IsZero())
+	assert.Equal(t, Settings{CAFile: "/profile/ca.pem", ClientCert: "/profile/cert.pem", ClientKey: "/profile/key.pem"},
+		SettingsFor(context.Background(), cfg))
+
+	t.Setenv(EnvCAFile, "/env/ca.pem")
+	t.Setenv(EnvClientCert, "/env/cert.pem")
+	s := SettingsFor(WithInsecureSkipVerify(context.Background()), cfg)
+	assert.Equal(t, Settings{CAFile: "/env/ca.pem", ClientCert: "/env/cert.pem", InsecureSkipVerify: true}, s,
+		"the environment overrides the certificate and key of the profile together")
+}
+
+func TestFromContext(t *testing.T) {
+	t.Setenv(EnvCAFile, "")
+	t.Setenv(EnvClientCert, "")
+	t.Setenv(EnvClientKey, "")
+	ctx := profiles.WithConfiguration(context.Background(), &profiles.Configuration{CaFile: "/profile/ca.pem"})
+	assert.Equal(t, Settings{CAFile: "/profile/ca.pem"}, FromContext(ctx))
+}
+
+func TestRoundTripper_Default(t *testing.T) {
+	rt, err := Settings{}.RoundTripper()
+	require.NoError(t, err)
+	assert.Same(t, http.DefaultTransport, rt)
+}
+
+func TestRoundTripper_CAFile(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _, cert := writeCertificate(t, dir, "server")
+	server := newTLSServer(t, cert, nil)
+
+	require.Error(t, get(t, Settings{}, server.URL), "the server certificate is not trusted by default")
+	require.NoError(t, get(t, Settings{CAFile: caFile}, server.URL))
+
+	first, err := Settings{CAFile: caFile}.RoundTripper()
+	require.NoError(t, err)
+	second, err := Settings{CAFile: caFile}.RoundTripper()
+	require.NoError(t, err)
+	assert.Same(t, first, second, "the transport of the same settings is shared")
+	assert.NotNil(t, first.(*http.Transport).Proxy, "proxies are taken from the environment")
+}
+
+func TestRoundTripper_ClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _, serverCert := writeCertificate(t, dir, "server")
+	clientCertFile, clientKeyFile, clientCert := writeCertificate(t, dir, "client")
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+	server := newTLSServer(t, serverCert, clientCAs)
+
+	assert.Error(t, get(t, Settings{CAFile: caFile}, server.URL), "the server requires a client certificate")
+	assert.NoError(t, get(t, Settings{CAFile: caFile, ClientCert: clientCertFile, ClientKey: clientKeyFile}, server.URL))
+}
+
+func TestRoundTripper_InsecureSkipVerify(t *testing.T) {
+	_, _, cert := writeCertificate(t, t.TempDir(), "server")
+	server := newTLSServer(t, cert, nil)
+
+	assert.NoError(t, get(t, Settings{InsecureSkipVerify: true}, server.URL))
+}
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeCertificate(t, dir, "client")
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	assert.NoError(t, Settings{CAFile: certFile, ClientCert: certFile, ClientKey: keyFile}.Validate())
+
+	tests := map[string]struct {
+		settings Settings
+		message  string
+	}{
+		"missing CA file":     {Settings{CAFile: filepath.Join(dir, "missing.pem")}, "failed to read CA file"},
+		"CA file without PEM": {Settings{CAFile: notPEM}, "contains no PEM certificates"},
+		"certificate alone":   {Settings{ClientCert: certFile}, "has no client key"},
+		"key alone":           {Settings{ClientKey: keyFile}, "has no client certificate"},
+		"invalid key":         {Settings{ClientCert: certFile, ClientKey: notPEM}, "failed to load client certificate"},
+		"key as the CA file":  {Settings{CAFile: keyFile}, "contains no PEM certificates"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/client"
 	colorpkg "github.com/dash0hq/dash0-cli/internal/color"
+	"github.com/dash0hq/dash0-cli/internal/config"
 	"github.com/dash0hq/dash0-cli/internal/otlp"
 	"github.com/dash0hq/dash0-cli/internal/output"
 	"github.com/dash0hq/dash0-cli/internal/query"
@@ -197,7 +198,7 @@ func runQuery(cmd *cobra.Command, flags *queryFlags) error {
 
 	dataset := client.ResolveDataset(ctx, flags.Dataset)
 
-	filters, err := query.ParseFilters(flags.Filter)
+	filters, err := query.ParseFilters(config.QueryFilters(ctx, "logs", flags.Filter))
 	if err != nil {
 		return err
 	}
@@ -33,16 +33,30 @@ type createFlags struct {
 	ResourceDroppedAttributesCount uint32
 	ScopeDroppedAttributesCount    uint32
 	LogDroppedAttributesCount      uint32
+	File                           string
+	Format                         string
+	TimeKeys                       []string
+	TimeFormat                     string
+	SeverityKeys                   []string
+	BodyKeys                       []string
+	Pattern                        string
+	BatchSize                      int
+	FlushInterval                  time.Duration
 }
 
 func newSendCmd() *cobra.Command {
 	flags := &createFlags{}
 
 	cmd := &cobra.Command{
-		Use:     "send <body>",
+		Use:     "send [<body> | -]",
 		Aliases: []string{"create"},
 		Short:   "Send a log record to Dash0",
-		Long:    `Send a log record to Dash0 via OTLP.` + internal.CONFIG_HINT,
+		Long: `Send a log record to Dash0 via OTLP.
+
+With --file (or '-' as the body), read log records line by line from a file or stdin instead.
+Each line is parsed as JSON, logfmt, or plain text, and records are sent in batches as they are read.
+Syslog (RFC 5424 and RFC 3164), 'journalctl -o json', and Docker json-file lines keep their original timestamp and severity, and their host, systemd unit, or container become resource attributes.
+Record-level flags such as --severity-text and --log-attribute apply to every record that does not set the value itself.` + internal.CONFIG_HINT,
 		Example: `  # Send a simple log message
   dash0 logs send "Application started"
 
@@ -61,10 +75,24 @@ func newSendCmd() *cobra.Command {
   # Send with trace context
   dash0 logs send "Request processed" \
       --trace-id 0af7651916cd43dd8448eb211c80319c \
-      --span-id b7ad6b7169203331`,
-		Args: cobra.ExactArgs(1),
+      --span-id b7ad6b7169203331
+
+  # Ship a JSON-lines log file from a CI job
+  dash0 logs send -f app.log --resource-attribute service.name=my-service
+
+  # Stream the output of a command
+  make test 2>&1 | dash0 logs send - --resource-attribute service.name=ci
+
+  # Upload the system logs of a server for a post-mortem
+  dash0 logs send -f /var/log/syslog --format syslog
+  journalctl -u checkout --since "1 hour ago" -o json | dash0 logs send -
+
+  # Extract timestamp and level from plain-text lines
+  dash0 logs send -f build.log --format text \
+      --pattern '^(?P<time>\S+) \[(?P<severity>\w+)\] (?P<body>.*)$'`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCreate(cmd, args[0], flags)
+			return runCreate(cmd, args, flags)
 		},
 	}
 
@@ -77,8 +105,8 @@ func newSendCmd() *cobra.Command {
 	cmd.Flags().StringVar(&flags.SeverityText, "severity-text", "", "Severity text (e.g., INFO, WARN, ERROR); this is separate from severity-number and can be used to provide custom severity levels the way logging libraries do")
 	cmd.Flags().StringVar(&flags.Time, "time", "", "Log record timestamp in RFC3339 format, e.g. '2024-03-15T10:30:00.123456789Z'; defaults to now")
 	cmd.Flags().StringVar(&flags.ObservedTime, "observed-time", "", "Observed timestamp in RFC3339 format, e.g. '2024-03-15T10:30:00.123456789Z'; defaults to now")
-	cmd.Flags().StringVar(&flags.TraceID, "trace-id", "", "Trace ID (32 hex characters)")
-	cmd.Flags().StringVar(&flags.SpanID, "span-id", "", "Span ID (16 hex characters)")
+	cmd.Flags().StringVar(&flags.TraceID, "trace-id", "", "Trace ID (32 hex characters); defaults to the trace in TRACEPARENT")
+	cmd.Flags().StringVar(&flags.SpanID, "span-id", "", "Span ID (16 hex characters); defaults to the span in TRACEPARENT")
 	cmd.Flags().StringVar(&flags.EventName, "event-name", "", "Event name")
 	cmd.Flags().Uint32Var(&flags.Flags, "flags", 0, "Log record flags")
 	cmd.Flags().StringVar(&flags.ScopeName, "scope-name", "dash0-cli", "Instrumentation scope name; defaults to 'dash0-cli'")
@@ -87,15 +115,34 @@ func newSendCmd() *cobra.Command {
 	cmd.Flags().Uint32Var(&flags.ResourceDroppedAttributesCount, "resource-dropped-attributes-count", 0, "Number of dropped resource attributes")
 	cmd.Flags().Uint32Var(&flags.ScopeDroppedAttributesCount, "scope-dropped-attributes-count", 0, "Number of dropped instrumentation scope attributes")
 	cmd.Flags().Uint32Var(&flags.LogDroppedAttributesCount, "log-dropped-attributes-count", 0, "Number of dropped log record attributes")
+	cmd.Flags().StringVarP(&flags.File, "file", "f", "", "Read log records line by line from a file ('-' for stdin) instead of sending a single body")
+	cmd.Flags().StringVar(&flags.Format, "format", "auto", "Line format with --file: auto, json, logfmt, text, syslog, journald, docker")
+	cmd.Flags().StringSliceVar(&flags.TimeKeys, "time-key", nil, "Field holding the timestamp in JSON and logfmt lines (repeatable); defaults to time, timestamp, ts, @timestamp")
+	cmd.Flags().StringVar(&flags.TimeFormat, "time-format", "auto", "Timestamp format with --file: auto, rfc3339, unix, unix_ms, unix_ns, or a Go time layout")
+	cmd.Flags().StringSliceVar(&flags.SeverityKeys, "severity-key", nil, "Field holding the level in JSON and logfmt lines (repeatable); defaults to level, severity, lvl, log.level")
+	cmd.Flags().StringSliceVar(&flags.BodyKeys, "body-key", nil, "Field holding the message in JSON and logfmt lines (repeatable); defaults to msg, message")
+	cmd.Flags().StringVar(&flags.Pattern, "pattern", "", "Regular expression for text lines; the named groups 'time', 'severity', and 'body' are extracted and other named groups become attributes")
+	cmd.Flags().IntVar(&flags.BatchSize, "batch-size", 1000, "Maximum number of log records per OTLP request with --file")
+	cmd.Flags().DurationVar(&flags.FlushInterval, "flush-interval", 5*time.Second, "Maximum time a read log record waits before it is sent with --file")
 
 	return cmd
 }
 
-func runCreate(cmd *cobra.Command, body string, flags *createFlags) error {
+func runCreate(cmd *cobra.Command, args []string, flags *createFlags) error {
 	ctx := cmd.Context()
 
 	otlp.ResolveScopeDefaults(cmd, &flags.ScopeName, &flags.ScopeVersion)
 
+	input := flags.File
+	switch {
+	case input != "" && len(args) == 1:
+		return fmt.Errorf("a log body cannot be combined with --file")
+	case input == "" && len(args) == 0:
+		return fmt.Errorf("requires a log body, --file <path>, or '-' to read from stdin")
+	case input == "" && args[0] == "-":
+		input = "-"
+	}
+
 	resourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
 	if err != nil {
 		return fmt.Errorf("invalid resource attribute: %w", err)
@@ -113,7 +160,7 @@ func runCreate(cmd *cobra.Command, body string, flags *createFlags) error {
 
 	now := time.Now()
 
-	logTimestamp := now
+	var logTimestamp time.Time
 	if flags.Time != "" {
 		logTimestamp, err = time.Parse(time.RFC3339Nano, flags.Time)
 		if err != nil {
@@ -129,8 +176,94 @@ func runCreate(cmd *cobra.Command, body string, flags *createFlags) error {
 		}
 	}
 
-	// Build plog.Logs
+	if (flags.TraceID != "") != (flags.SpanID != "") {
+		return fmt.Errorf("both --trace-id and --span-id must be specified together")
+	}
+
+	defaults := recordDefaults{flags: flags, attributes: logAttrs, timestamp: logTimestamp}
+	if flags.TraceID != "" {
+		defaults.traceID, err = otlp.ParseTraceID(flags.TraceID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.SpanID != "" {
+		defaults.spanID, err = otlp.ParseSpanID(flags.SpanID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Without explicit trace context, records belong to the span in
+	// TRACEPARENT, such as that of an enclosing `dash0 spans exec`.
+	if flags.TraceID == "" {
+		if parent, ok := otlp.TraceparentFromEnv(); ok {
+			defaults.traceID, defaults.spanID = parent.TraceID, parent.SpanID
+		}
+	}
+
+	if input != "" {
+		parser, err := newLineParser(flags)
+		if err != nil {
+			return err
+		}
+		return runSendFile(ctx, input, flags, &logShipper{
+			parser:   parser,
+			defaults: defaults,
+			newRecords: func(logs plog.Logs, found map[string]string) plog.LogRecordSlice {
+				// Resource attributes given as flags win over those of a line.
+				attrs := make(map[string]string, len(found)+len(resourceAttrs))
+				for k, v := range found {
+					attrs[k] = v
+				}
+				for k, v := range resourceAttrs {
+					attrs[k] = v
+				}
+				return appendResourceLogs(logs, flags, attrs, scopeAttrs)
+			},
+			batchSize:     flags.BatchSize,
+			flushInterval: flags.FlushInterval,
+		})
+	}
+
+	logs, records := newLogs(flags, resourceAttrs, scopeAttrs)
+
+	// Build log record
+	lr := records.AppendEmpty()
+	lr.Body().SetStr(args[0])
+	if logTimestamp.IsZero() {
+		logTimestamp = now
+	}
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(logTimestamp))
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(observedTimestamp))
+	defaults.apply(lr)
+
+	// Create OTLP client and send
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	defer apiClient.Close(ctx)
+
+	if err := apiClient.SendLogs(ctx, logs, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
+		return fmt.Errorf("failed to send log record: %w", err)
+	}
+
+	fmt.Println("Log record sent")
+	return nil
+}
+
+// newLogs returns a payload with the resource and scope from flags, and the
+// record slice to append log records to.
+func newLogs(flags *createFlags, resourceAttrs, scopeAttrs map[string]string) (plog.Logs, plog.LogRecordSlice) {
 	logs := plog.NewLogs()
+	return logs, appendResourceLogs(logs, flags, resourceAttrs, scopeAttrs)
+}
+
+// appendResourceLogs adds a resource with resourceAttrs and a scope from
+// flags to logs, and returns the record slice to append log records to.
+func appendResourceLogs(logs plog.Logs, flags *createFlags, resourceAttrs, scopeAttrs map[string]string) plog.LogRecordSlice {
 	rl := logs.ResourceLogs().AppendEmpty()
 
 	// Set resource attributes
@@ -154,66 +287,52 @@ func runCreate(cmd *cobra.Command, body string, flags *createFlags) error {
 		scope.SetDroppedAttributesCount(flags.ScopeDroppedAttributesCount)
 	}
 
-	// Build log record
-	lr := sl.LogRecords().AppendEmpty()
-	lr.Body().SetStr(body)
-	lr.SetTimestamp(pcommon.NewTimestampFromTime(logTimestamp))
-	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(observedTimestamp))
+	return sl.LogRecords()
+}
 
-	if flags.SeverityNumber != 0 {
-		lr.SetSeverityNumber(plog.SeverityNumber(flags.SeverityNumber))
-	}
-	if flags.SeverityText != "" {
-		lr.SetSeverityText(flags.SeverityText)
-	}
+// recordDefaults holds the record-level values given as flags. They apply to
+// the single record of `logs send <body>`, and to every record read from a
+// file that does not set the value itself.
+type recordDefaults struct {
+	flags      *createFlags
+	attributes map[string]string
+	timestamp  time.Time
+	traceID    pcommon.TraceID
+	spanID     pcommon.SpanID
+}
 
-	for k, v := range logAttrs {
-		lr.Attributes().PutStr(k, v)
+func (d recordDefaults) apply(lr plog.LogRecord) {
+	if lr.Timestamp() == 0 && !d.timestamp.IsZero() {
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(d.timestamp))
 	}
 
-	if (flags.TraceID != "") != (flags.SpanID != "") {
-		return fmt.Errorf("both --trace-id and --span-id must be specified together")
+	if lr.SeverityNumber() == plog.SeverityNumberUnspecified && d.flags.SeverityNumber != 0 {
+		lr.SetSeverityNumber(plog.SeverityNumber(d.flags.SeverityNumber))
 	}
-
-	if flags.TraceID != "" {
-		traceID, err := otlp.ParseTraceID(flags.TraceID)
-		if err != nil {
-			return err
-		}
-		lr.SetTraceID(traceID)
+	if lr.SeverityText() == "" && d.flags.SeverityText != "" {
+		lr.SetSeverityText(d.flags.SeverityText)
 	}
 
-	if flags.SpanID != "" {
-		spanID, err := otlp.ParseSpanID(flags.SpanID)
-		if err != nil {
-			return err
+	for k, v := range d.attributes {
+		if _, exists := lr.Attributes().Get(k); !exists {
+			lr.Attributes().PutStr(k, v)
 		}
-		lr.SetSpanID(spanID)
-	}
-
-	if flags.EventName != "" {
-		lr.SetEventName(flags.EventName)
 	}
 
-	if flags.Flags != 0 {
-		lr.SetFlags(plog.LogRecordFlags(flags.Flags))
+	if lr.TraceID().IsEmpty() && !d.traceID.IsEmpty() {
+		lr.SetTraceID(d.traceID)
+		lr.SetSpanID(d.spanID)
 	}
 
-	if flags.LogDroppedAttributesCount != 0 {
-		lr.SetDroppedAttributesCount(flags.LogDroppedAttributesCount)
+	if lr.EventName() == "" && d.flags.EventName != "" {
+		lr.SetEventName(d.flags.EventName)
 	}
 
-	// Create OTLP client and send
-	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
-	if err != nil {
-		return err
+	if d.flags.Flags != 0 {
+		lr.SetFlags(plog.LogRecordFlags(d.flags.Flags))
 	}
-	defer apiClient.Close(ctx)
 
-	if err := apiClient.SendLogs(ctx, logs, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
-		return fmt.Errorf("failed to send log record: %w", err)
+	if d.flags.LogDroppedAttributesCount != 0 {
+		lr.SetDroppedAttributesCount(d.flags.LogDroppedAttributesCount)
 	}
-
-	fmt.Println("Log record sent")
-	return nil
 }
@@ -0,0 +1,657 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// maxLogLineBytes bounds the memory used for a single input line. Longer
+// lines are cut and flagged with the log.record.truncated attribute.
+const maxLogLineBytes = 1 << 20
+
+var (
+	defaultTimeKeys     = []string{"time", "timestamp", "ts", "@timestamp"}
+	defaultSeverityKeys = []string{"level", "severity", "lvl", "log.level"}
+	defaultBodyKeys     = []string{"msg", "message"}
+	traceIDKeys         = []string{"trace_id", "traceId", "trace.id"}
+	spanIDKeys          = []string{"span_id", "spanId", "span.id"}
+)
+
+type lineFormat string
+
+const (
+	lineFormatAuto     lineFormat = "auto"
+	lineFormatJSON     lineFormat = "json"
+	lineFormatLogfmt   lineFormat = "logfmt"
+	lineFormatText     lineFormat = "text"
+	lineFormatSyslog   lineFormat = "syslog"
+	lineFormatJournald lineFormat = "journald"
+	lineFormatDocker   lineFormat = "docker"
+)
+
+// lineParser turns one input line into a log record.
+type lineParser struct {
+	format       lineFormat
+	timeKeys     []string
+	timeFormat   string
+	severityKeys []string
+	bodyKeys     []string
+	pattern      *regexp.Regexp
+	// fileName is the base name of the input file, which names the
+	// container of a Docker log; empty for stdin.
+	fileName string
+}
+
+func newLineParser(flags *createFlags) (*lineParser, error) {
+	p := &lineParser{
+		format:       lineFormat(strings.ToLower(flags.Format)),
+		timeKeys:     flags.TimeKeys,
+		timeFormat:   flags.TimeFormat,
+		severityKeys: flags.SeverityKeys,
+		bodyKeys:     flags.BodyKeys,
+	}
+	switch p.format {
+	case lineFormatAuto, lineFormatJSON, lineFormatLogfmt, lineFormatText, lineFormatSyslog, lineFormatJournald, lineFormatDocker:
+	default:
+		return nil, fmt.Errorf("unknown format %q (valid values: auto, json, logfmt, text, syslog, journald, docker)", flags.Format)
+	}
+	if len(p.timeKeys) == 0 {
+		p.timeKeys = defaultTimeKeys
+	}
+	if len(p.severityKeys) == 0 {
+		p.severityKeys = defaultSeverityKeys
+	}
+	if len(p.bodyKeys) == 0 {
+		p.bodyKeys = defaultBodyKeys
+	}
+	if p.timeFormat == "" {
+		p.timeFormat = "auto"
+	}
+	if flags.Pattern != "" {
+		if p.format != lineFormatText && p.format != lineFormatAuto && p.format != lineFormatDocker {
+			return nil, fmt.Errorf("--pattern can only be used with --format text, docker, or auto")
+		}
+		re, err := regexp.Compile(flags.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		p.pattern = re
+	}
+	return p, nil
+}
+
+// parse fills lr from line and returns the resource attributes the line
+// names, such as the host of a syslog line; nil if it names none. It never
+// fails: a line that does not match the expected format is sent verbatim as
+// the body.
+func (p *lineParser) parse(line string, lr plog.LogRecord) map[string]string {
+	switch p.format {
+	case lineFormatJSON:
+		if fields, ok := parseJSONLine(line); ok {
+			p.fillFromFields(line, fields, lr)
+			return nil
+		}
+	case lineFormatLogfmt:
+		if fields, _, ok := parseLogfmtLine(line); ok {
+			p.fillFromFields(line, fields, lr)
+			return nil
+		}
+	case lineFormatSyslog:
+		if msg, ok := parseSyslogLine(line, time.Now()); ok {
+			return fillFromSyslog(msg, lr)
+		}
+	case lineFormatJournald:
+		if fields, ok := parseJSONLine(line); ok {
+			return fillFromJournald(fields, lr)
+		}
+	case lineFormatDocker:
+		if fields, ok := parseJSONLine(line); ok && isDockerEntry(fields) {
+			return p.fillFromDocker(fields, lr)
+		}
+	case lineFormatAuto:
+		if fields, ok := parseJSONLine(line); ok {
+			switch {
+			case isJournaldEntry(fields):
+				return fillFromJournald(fields, lr)
+			case isDockerEntry(fields):
+				return p.fillFromDocker(fields, lr)
+			}
+			p.fillFromFields(line, fields, lr)
+			return nil
+		}
+		// Only syslog lines with a priority are told apart from plain text.
+		if strings.HasPrefix(line, "<") {
+			if msg, ok := parseSyslogLine(line, time.Now()); ok {
+				return fillFromSyslog(msg, lr)
+			}
+		}
+		p.fillFromPlainLine(line, lr)
+		return nil
+	}
+	p.fillFromText(line, lr)
+	return nil
+}
+
+// fillFromPlainLine fills lr from a line that is not JSON: logfmt if the
+// whole line is, and text otherwise.
+func (p *lineParser) fillFromPlainLine(line string, lr plog.LogRecord) {
+	// Plain text often contains a stray word=value, so auto-detection
+	// only treats a line as logfmt if every token is a key=value pair.
+	if p.pattern == nil {
+		if fields, bareKeys, ok := parseLogfmtLine(line); ok && bareKeys == 0 {
+			p.fillFromFields(line, fields, lr)
+			return
+		}
+	}
+	p.fillFromText(line, lr)
+}
+
+func (p *lineParser) fillFromFields(line string, fields map[string]any, lr plog.LogRecord) {
+	if key, v, ok := takeField(fields, p.timeKeys); ok {
+		if ts, ok := parseLogTime(v, p.timeFormat); ok {
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		} else {
+			fields[key] = v
+		}
+	}
+	if key, v, ok := takeField(fields, p.severityKeys); ok && !setSeverity(lr, v) {
+		fields[key] = v
+	}
+	if key, v, ok := takeField(fields, traceIDKeys); ok {
+		if id, err := otlp.ParseTraceID(fmt.Sprint(v)); err == nil {
+			lr.SetTraceID(id)
+		} else {
+			fields[key] = v
+		}
+	}
+	if key, v, ok := takeField(fields, spanIDKeys); ok {
+		if id, err := otlp.ParseSpanID(fmt.Sprint(v)); err == nil {
+			lr.SetSpanID(id)
+		} else {
+			fields[key] = v
+		}
+	}
+	if _, v, ok := takeField(fields, p.bodyKeys); ok {
+		if s, isString := v.(string); isString {
+			lr.Body().SetStr(s)
+		} else {
+			_ = lr.Body().FromRaw(v)
+		}
+	} else {
+		// Without a message field the line itself is the most faithful body.
+		lr.Body().SetStr(line)
+	}
+	for k, v := range fields {
+		_ = lr.Attributes().PutEmpty(k).FromRaw(v)
+	}
+}
+
+func (p *lineParser) fillFromText(line string, lr plog.LogRecord) {
+	lr.Body().SetStr(line)
+	if p.pattern == nil {
+		return
+	}
+	match := p.pattern.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	for i, name := range p.pattern.SubexpNames() {
+		if name == "" || i >= len(match) {
+			continue
+		}
+		value := match[i]
+		switch name {
+		case "body":
+			lr.Body().SetStr(value)
+		case "time":
+			if ts, ok := parseLogTime(value, p.timeFormat); ok {
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+			}
+		case "severity":
+			setSeverity(lr, value)
+		default:
+			if value != "" {
+				lr.Attributes().PutStr(name, value)
+			}
+		}
+	}
+}
+
+// takeField removes and returns the first of keys present in fields.
+func takeField(fields map[string]any, keys []string) (string, any, bool) {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			delete(fields, k)
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
+
+// setSeverity sets the severity text and, where the level is recognized, the
+// severity number. Numeric levels follow the scale of pino and bunyan
+// (10 trace, 20 debug, 30 info, 40 warn, 50 error, 60 fatal). It returns
+// false if v is neither a string nor a number.
+func setSeverity(lr plog.LogRecord, v any) bool {
+	switch level := v.(type) {
+	case string:
+		lr.SetSeverityText(level)
+		if n, ok := otlp.SeverityTextToNumber(level); ok {
+			lr.SetSeverityNumber(plog.SeverityNumber(n))
+		}
+		return true
+	case int64:
+		lr.SetSeverityText(strconv.FormatInt(level, 10))
+		if level >= 10 && level <= 60 {
+			lr.SetSeverityNumber(plog.SeverityNumber(1 + 4*((level/10)-1)))
+		}
+		return true
+	case float64:
+		return setSeverity(lr, int64(level))
+	default:
+		return false
+	}
+}
+
+// parseLogTime parses a timestamp field. With format "auto", RFC 3339 and
+// the common "2006-01-02 15:04:05" variants are tried, and numbers are read
+// as Unix seconds, milliseconds, microseconds, or nanoseconds depending on
+// their magnitude.
+func parseLogTime(v any, format string) (time.Time, bool) {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = strings.TrimSpace(t)
+	case int64:
+		s = strconv.FormatInt(t, 10)
+	case float64:
+		s = strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return time.Time{}, false
+	}
+
+	switch format {
+	case "auto":
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999Z07:00", "2006-01-02 15:04:05.999999999"} {
+			if ts, err := time.Parse(layout, s); err == nil {
+				return ts, true
+			}
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f <= 0 {
+			return time.Time{}, false
+		}
+		switch {
+		case f < 1e11:
+			return unixTime(s, 1e9)
+		case f < 1e14:
+			return unixTime(s, 1e6)
+		case f < 1e17:
+			return unixTime(s, 1e3)
+		default:
+			return unixTime(s, 1)
+		}
+	case "rfc3339":
+		ts, err := time.Parse(time.RFC3339Nano, s)
+		return ts, err == nil
+	case "unix":
+		return unixTime(s, 1e9)
+	case "unix_ms":
+		return unixTime(s, 1e6)
+	case "unix_ns":
+		return unixTime(s, 1)
+	default:
+		ts, err := time.Parse(format, s)
+		return ts, err == nil
+	}
+}
+
+// unixTime converts a Unix time given in units of nanosPerUnit nanoseconds.
+// Integers are converted exactly; fractions are rounded to the nanosecond.
+func unixTime(s string, nanosPerUnit int64) (time.Time, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if nanosPerUnit > 1 && (i > math.MaxInt64/nanosPerUnit || i < math.MinInt64/nanosPerUnit) {
+			return time.Time{}, false
+		}
+		return time.Unix(0, i*nanosPerUnit), true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return time.Time{}, false
+	}
+	sec, frac := math.Modf(f * float64(nanosPerUnit) / 1e9)
+	return time.Unix(int64(sec), int64(math.Round(frac*1e9))), true
+}
+
+// parseJSONLine decodes a JSON object line. Integral numbers become int64 so
+// that they are sent as integer attributes.
+func parseJSONLine(line string) (map[string]any, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil || dec.More() {
+		return nil, false
+	}
+	return otlp.NormalizeJSONNumbers(fields).(map[string]any), true
+}
+
+// parseLogfmtLine parses a line of space-separated key=value pairs. Values
+// may be double-quoted with Go escape sequences; a bare key means true and is
+// counted in bareKeys. It returns false if any token is malformed.
+func parseLogfmtLine(line string) (fields map[string]any, bareKeys int, ok bool) {
+	fields = map[string]any{}
+	s := strings.TrimSpace(line)
+	for s != "" {
+		end := strings.IndexAny(s, "= ")
+		if end == 0 {
+			return nil, 0, false
+		}
+		if end < 0 || s[end] == ' ' {
+			key := s
+			if end >= 0 {
+				key = s[:end]
+			}
+			if strings.ContainsRune(key, '"') {
+				return nil, 0, false
+			}
+			fields[key] = true
+			bareKeys++
+			s = strings.TrimLeft(s[len(key):], " ")
+			continue
+		}
+		key := s[:end]
+		if strings.ContainsRune(key, '"') {
+			return nil, 0, false
+		}
+		s = s[end+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			closing := closingQuote(s)
+			if closing < 0 {
+				return nil, 0, false
+			}
+			unquoted, err := strconv.Unquote(s[:closing+1])
+			if err != nil {
+				return nil, 0, false
+			}
+			value = unquoted
+			s = s[closing+1:]
+			if s != "" && s[0] != ' ' {
+				return nil, 0, false
+			}
+		} else {
+			i := strings.IndexByte(s, ' ')
+			if i < 0 {
+				i = len(s)
+			}
+			value = s[:i]
+			if strings.ContainsRune(value, '"') {
+				return nil, 0, false
+			}
+			s = s[i:]
+		}
+		fields[key] = value
+		s = strings.TrimLeft(s, " ")
+	}
+	return fields, bareKeys, len(fields) > 0
+}
+
+// closingQuote returns the index of the quote that closes the string starting
+// at s[0], skipping escaped quotes.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// logShipper reads lines, turns them into log records, and sends them in
+// batches of at most batchSize records. A partial batch is sent once its
+// oldest record has waited flushInterval, so that piping a long-running
+// command shows its logs while it runs. At most one batch is held in memory.
+type logShipper struct {
+	parser   *lineParser
+	defaults recordDefaults
+	// newRecords adds a resource with the attributes a line names (nil for
+	// none), combined with those of the flags, to logs.
+	newRecords    func(logs plog.Logs, resource map[string]string) plog.LogRecordSlice
+	send          func(context.Context, plog.Logs) error
+	batchSize     int
+	flushInterval time.Duration
+	// fileName is recorded as log.file.name on every record; empty for stdin.
+	fileName string
+}
+
+type readLine struct {
+	text      string
+	truncated bool
+}
+
+func runSendFile(ctx context.Context, input string, flags *createFlags, s *logShipper) error {
+	if s.batchSize <= 0 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
+	if s.flushInterval <= 0 {
+		return fmt.Errorf("--flush-interval must be positive")
+	}
+
+	var r io.Reader = os.Stdin
+	if input != "-" {
+		f, err := os.Open(input)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer f.Close()
+		r = f
+		s.fileName = filepath.Base(input)
+		s.parser.fileName = s.fileName
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	// Records already read are still sent after Ctrl+C.
+	sendCtx := context.WithoutCancel(ctx)
+	defer apiClient.Close(sendCtx)
+	dataset := client.ResolveDataset(ctx, flags.Dataset)
+	s.send = func(ctx context.Context, logs plog.Logs) error {
+		return apiClient.SendLogs(ctx, logs, dataset)
+	}
+
+	sent, err := s.run(ctx, r)
+	if err != nil {
+		return fmt.Errorf("failed to send log records (%d sent before the failure): %w", sent, err)
+	}
+	if sent == 1 {
+		fmt.Println("1 log record sent")
+	} else {
+		fmt.Printf("%d log records sent\n", sent)
+	}
+	return nil
+}
+
+// run ships the lines of r until EOF or until ctx is cancelled, and returns
+// the number of records sent.
+func (s *logShipper) run(ctx context.Context, r io.Reader) (int, error) {
+	sendCtx := context.WithoutCancel(ctx)
+	// Stops the reader when run returns early because a send failed.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan readLine)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		readErr <- readLines(r, func(l readLine) bool {
+			select {
+			case lines <- l:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	sent := 0
+	batch := newLogBatch()
+	flushTimer := time.NewTimer(s.flushInterval)
+	flushTimer.Stop()
+	flush := func() error {
+		flushTimer.Stop()
+		if batch.count == 0 {
+			return nil
+		}
+		if err := s.send(sendCtx, batch.logs); err != nil {
+			return err
+		}
+		sent += batch.count
+		batch = newLogBatch()
+		return nil
+	}
+
+	for {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				if err := flush(); err != nil {
+					return sent, err
+				}
+				return sent, <-readErr
+			}
+			if strings.TrimSpace(l.text) == "" {
+				continue
+			}
+			if batch.count == 0 {
+				flushTimer.Reset(s.flushInterval)
+			}
+			s.appendRecord(batch, l)
+			if batch.count >= s.batchSize {
+				if err := flush(); err != nil {
+					return sent, err
+				}
+			}
+		case <-flushTimer.C:
+			if err := flush(); err != nil {
+				return sent, err
+			}
+		case <-ctx.Done():
+			err := flush()
+			return sent, err
+		}
+	}
+}
+
+// logBatch holds the records read since the last send, with the records of
+// each resource named by the lines, such as the hosts of syslog lines,
+// under a resource of their own.
+type logBatch struct {
+	logs      plog.Logs
+	resources map[string]plog.LogRecordSlice
+	count     int
+}
+
+func newLogBatch() *logBatch {
+	return &logBatch{logs: plog.NewLogs(), resources: map[string]plog.LogRecordSlice{}}
+}
+
+func (s *logShipper) appendRecord(batch *logBatch, l readLine) {
+	lr := plog.NewLogRecord()
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	resource := s.parser.parse(l.text, lr)
+	if s.fileName != "" {
+		lr.Attributes().PutStr("log.file.name", s.fileName)
+	}
+	if l.truncated {
+		lr.Attributes().PutBool("log.record.truncated", true)
+	}
+	s.defaults.apply(lr)
+
+	key := resourceKey(resource)
+	records, ok := batch.resources[key]
+	if !ok {
+		records = s.newRecords(batch.logs, resource)
+		batch.resources[key] = records
+	}
+	lr.MoveTo(records.AppendEmpty())
+	batch.count++
+}
+
+// resourceKey identifies a set of resource attributes independent of the
+// order of the map.
+func resourceKey(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(attrs[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// readLines calls emit for every line of r, without the line terminator,
+// until emit returns false. Lines longer than maxLogLineBytes are cut.
+func readLines(r io.Reader, emit func(readLine) bool) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var buf []byte
+	truncated := false
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		if len(chunk) > 0 {
+			if room := maxLogLineBytes - len(buf); len(chunk) > room {
+				chunk = chunk[:max(room, 0)]
+				truncated = true
+			}
+			buf = append(buf, chunk...)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if len(buf) > 0 {
+					emit(readLine{text: string(buf), truncated: truncated})
+				}
+				return nil
+			}
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		if isPrefix {
+			continue
+		}
+		if !emit(readLine{text: string(buf), truncated: truncated}) {
+			return nil
+		}
+		buf = buf[:0]
+		truncated = false
+	}
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// dockerLogFileName matches the files of the Docker json-file logging
+// driver, /var/lib/docker/containers/<id>/<id>-json.log, and their rotated
+// copies.
+var dockerLogFileName = regexp.MustCompile(`^([0-9a-f]{64})-json\.log(\.\d+)?$`)
+
+// isDockerEntry reports whether fields are a line of a Docker json-file log:
+// the log line, its stream, and its time, plus attrs with --log-opt labels
+// or env.
+func isDockerEntry(fields map[string]any) bool {
+	_, isString := fields["log"].(string)
+	_, hasStream := fields["stream"]
+	_, hasTime := fields["time"]
+	_, hasAttrs := fields["attrs"]
+	n := 3
+	if hasAttrs {
+		n++
+	}
+	return isString && hasStream && hasTime && len(fields) == n
+}
+
+// fillFromDocker fills lr from a Docker json-file entry. The line the
+// container wrote is parsed like any other application log line, so a JSON
+// or logfmt line keeps its own time, level, and fields. The resource is the
+// container, if the name of the input file names it.
+func (p *lineParser) fillFromDocker(fields map[string]any, lr plog.LogRecord) map[string]string {
+	line := strings.TrimRight(fields["log"].(string), "\r\n")
+	if inner, ok := parseJSONLine(line); ok {
+		p.fillFromFields(line, inner, lr)
+	} else {
+		p.fillFromPlainLine(line, lr)
+	}
+	if lr.Timestamp() == 0 {
+		if s, ok := fields["time"].(string); ok {
+			if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+			}
+		}
+	}
+	if stream, ok := fields["stream"].(string); ok {
+		lr.Attributes().PutStr("log.iostream", stream)
+	}
+	if attrs, ok := fields["attrs"].(map[string]any); ok {
+		for k, v := range attrs {
+			_ = lr.Attributes().PutEmpty(k).FromRaw(v)
+		}
+	}
+
+	if m := dockerLogFileName.FindStringSubmatch(p.fileName); m != nil {
+		return map[string]string{"container.id": m[1]}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestLineParser_Docker(t *testing.T) {
+	p := newTestLineParser(t, createFlags{Format: "docker"})
+	p.fileName = strings.Repeat("ab", 32) + "-json.log"
+
+	lr := plog.NewLogRecord()
+	resource := p.parse(`{"log":"GET /health 200\n","stream":"stdout","time":"2026-10-18T12:00:00.123456789Z"}`, lr)
+	assert.Equal(t, map[string]string{"container.id": strings.Repeat("ab", 32)}, resource)
+	assert.Equal(t, "GET /health 200", lr.Body().Str())
+	assert.Equal(t, time.Date(2026, 10, 18, 12, 0, 0, 123456789, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{"log.iostream": "stdout"}, lr.Attributes().AsRaw())
+
+	// A JSON line of the container keeps its own time, level, and fields.
+	lr = plog.NewLogRecord()
+	p.parse(`{"log":"{\"time\":\"2026-10-18T11:59:59Z\",\"level\":\"warn\",\"msg\":\"slow\",\"ms\":812}\n","stream":"stderr","time":"2026-10-18T12:00:00Z","attrs":{"tag":"checkout"}}`, lr)
+	assert.Equal(t, "slow", lr.Body().Str())
+	assert.Equal(t, time.Date(2026, 10, 18, 11, 59, 59, 0, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, map[string]any{"ms": int64(812), "log.iostream": "stderr", "tag": "checkout"}, lr.Attributes().AsRaw())
+}
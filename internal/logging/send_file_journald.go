@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// journaldResourceFields map the fields of a journal entry that describe
+// where it comes from to resource attributes.
+var journaldResourceFields = []struct{ field, attribute string }{
+	{"_HOSTNAME", "host.name"},
+	{"_MACHINE_ID", "host.id"},
+	{"_SYSTEMD_UNIT", "systemd.unit"},
+	{"CONTAINER_NAME", "container.name"},
+	{"CONTAINER_ID_FULL", "container.id"},
+}
+
+// journaldRecordFields map the fields of a journal entry that describe the
+// process and the code that logged it to log record attributes.
+var journaldRecordFields = []struct{ field, attribute string }{
+	{"_COMM", "process.executable.name"},
+	{"_EXE", "process.executable.path"},
+	{"CODE_FILE", "code.file.path"},
+	{"CODE_FUNC", "code.function.name"},
+}
+
+// isJournaldEntry reports whether fields are a line of `journalctl -o json`.
+func isJournaldEntry(fields map[string]any) bool {
+	_, hasTimestamp := fields["__REALTIME_TIMESTAMP"]
+	_, hasMessage := fields["MESSAGE"]
+	return hasTimestamp && hasMessage
+}
+
+// fillFromJournald fills lr from a journal entry and returns its resource:
+// the host, the systemd unit, the container, if any, and the syslog
+// identifier as the service. Address fields such as __CURSOR are dropped;
+// other fields are kept as attributes.
+func fillFromJournald(fields map[string]any, lr plog.LogRecord) map[string]string {
+	// The time the entry was logged, if the client recorded it, else the
+	// time the journal received it; both are in microseconds.
+	for _, key := range []string{"_SOURCE_REALTIME_TIMESTAMP", "__REALTIME_TIMESTAMP"} {
+		if v, ok := fields[key]; ok {
+			if ts, ok := unixTime(journaldString(v), 1e3); ok {
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+				break
+			}
+		}
+	}
+	delete(fields, "_SOURCE_REALTIME_TIMESTAMP")
+
+	if v, ok := fields["PRIORITY"]; ok {
+		if n, err := strconv.Atoi(journaldString(v)); err == nil && n >= 0 && n < len(syslogSeverities) {
+			setSeverity(lr, syslogSeverities[n])
+			delete(fields, "PRIORITY")
+		}
+	}
+	if v, ok := fields["SYSLOG_FACILITY"]; ok {
+		if n, err := strconv.Atoi(journaldString(v)); err == nil && n >= 0 && n < len(syslogFacilities) {
+			lr.Attributes().PutStr("syslog.facility", syslogFacilities[n])
+			delete(fields, "SYSLOG_FACILITY")
+		}
+	}
+	lr.Body().SetStr(journaldString(fields["MESSAGE"]))
+	delete(fields, "MESSAGE")
+
+	if v, ok := fields["_PID"]; ok {
+		setProcessID(lr, journaldString(v))
+		delete(fields, "_PID")
+	}
+	if v, ok := fields["CODE_LINE"]; ok {
+		if line, err := strconv.ParseInt(journaldString(v), 10, 64); err == nil {
+			lr.Attributes().PutInt("code.line.number", line)
+			delete(fields, "CODE_LINE")
+		}
+	}
+	for _, m := range journaldRecordFields {
+		if v, ok := fields[m.field]; ok {
+			lr.Attributes().PutStr(m.attribute, journaldString(v))
+			delete(fields, m.field)
+		}
+	}
+
+	resource := map[string]string{}
+	for _, m := range journaldResourceFields {
+		if v, ok := fields[m.field]; ok {
+			resource[m.attribute] = journaldString(v)
+			delete(fields, m.field)
+		}
+	}
+	if v, ok := fields["SYSLOG_IDENTIFIER"]; ok {
+		resource["service.name"] = journaldString(v)
+		delete(fields, "SYSLOG_IDENTIFIER")
+	}
+
+	for k, v := range fields {
+		if !strings.HasPrefix(k, "__") {
+			_ = lr.Attributes().PutEmpty(k).FromRaw(v)
+		}
+	}
+	return resource
+}
+
+// journaldString returns a field value as a string. journalctl writes
+// values as strings, except for binary values, which it writes as arrays of
+// bytes, and fields with several values, which it writes as arrays of
+// strings; those are joined with newlines.
+func journaldString(v any) string {
+	values, ok := v.([]any)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	bytes := make([]byte, 0, len(values))
+	for i := 0; i < len(values); i++ {
+		b, isByte := values[i].(int64)
+		if !isByte || b < 0 || b > 255 {
+			strs := make([]string, len(values))
+			for j := 0; j < len(values); j++ {
+				strs[j] = journaldString(values[j])
+			}
+			return strings.Join(strs, "\n")
+		}
+		bytes = append(bytes, byte(b))
+	}
+	return string(bytes)
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestLineParser_Journald(t *testing.T) {
+	p := newTestLineParser(t, createFlags{})
+	lr := plog.NewLogRecord()
+	resource := p.parse(`{"__CURSOR":"s=1;i=2","__REALTIME_TIMESTAMP":"1760788800123456","__MONOTONIC_TIMESTAMP":"1","_BOOT_ID":"b1","PRIORITY":"3","SYSLOG_FACILITY":"3","SYSLOG_IDENTIFIER":"checkout","_PID":"913","_COMM":"checkout","_HOSTNAME":"web-1","_MACHINE_ID":"m1","_SYSTEMD_UNIT":"checkout.service","MESSAGE":"payment provider unreachable"}`, lr)
+
+	assert.Equal(t, map[string]string{
+		"host.name":    "web-1",
+		"host.id":      "m1",
+		"systemd.unit": "checkout.service",
+		"service.name": "checkout",
+	}, resource)
+	assert.Equal(t, "payment provider unreachable", lr.Body().Str())
+	assert.Equal(t, time.UnixMicro(1760788800123456).UTC(), lr.Timestamp().AsTime())
+	assert.Equal(t, "err", lr.SeverityText())
+	assert.Equal(t, plog.SeverityNumberError, lr.SeverityNumber())
+	assert.Equal(t, map[string]any{
+		"syslog.facility":         "daemon",
+		"process.pid":             int64(913),
+		"process.executable.name": "checkout",
+		"_BOOT_ID":                "b1",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestJournaldString(t *testing.T) {
+	assert.Equal(t, "hi\n", journaldString([]any{int64(104), int64(105), int64(10)}), "binary values are byte arrays")
+	assert.Equal(t, "a\nb", journaldString([]any{"a", "b"}), "repeated fields are string arrays")
+	assert.Equal(t, "6", journaldString("6"))
+}
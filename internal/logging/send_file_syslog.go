@@ -0,0 +1,239 @@
+package logging
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// syslogSeverities are the names of the syslog severities 0 to 7. They are
+// also level names that otlp.SeverityTextToNumber maps to severity numbers.
+var syslogSeverities = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// syslogFacilities are the names of the syslog facilities 0 to 23.
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// syslogMessage is a parsed RFC 5424 or RFC 3164 syslog line. priority is
+// -1 for lines without one, such as those of /var/log/syslog.
+type syslogMessage struct {
+	priority       int
+	timestamp      time.Time
+	hostname       string
+	appName        string
+	procID         string
+	msgID          string
+	structuredData map[string]string
+	message        string
+}
+
+// parseSyslogLine parses an RFC 5424 line, or an RFC 3164 line whose
+// timestamp is either "Jan _2 15:04:05" or RFC 3339. RFC 3164 timestamps
+// have no year and are placed in the year before now if they would
+// otherwise be more than a day in the future.
+func parseSyslogLine(line string, now time.Time) (syslogMessage, bool) {
+	msg := syslogMessage{priority: -1}
+	s := line
+	if strings.HasPrefix(s, "<") {
+		end := strings.IndexByte(s, '>')
+		if end < 2 || end > 4 {
+			return syslogMessage{}, false
+		}
+		pri, err := strconv.Atoi(s[1:end])
+		if err != nil || pri < 0 || pri > 191 {
+			return syslogMessage{}, false
+		}
+		msg.priority = pri
+		s = s[end+1:]
+	}
+
+	if msg.priority >= 0 && strings.HasPrefix(s, "1 ") {
+		return parseRFC5424(msg, s[2:])
+	}
+	return parseRFC3164(msg, s, now)
+}
+
+func parseRFC5424(msg syslogMessage, s string) (syslogMessage, bool) {
+	fields := strings.SplitN(s, " ", 6)
+	if len(fields) < 6 {
+		return syslogMessage{}, false
+	}
+	if fields[0] != "-" {
+		ts, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return syslogMessage{}, false
+		}
+		msg.timestamp = ts
+	}
+	msg.hostname = nilValue(fields[1])
+	msg.appName = nilValue(fields[2])
+	msg.procID = nilValue(fields[3])
+	msg.msgID = nilValue(fields[4])
+
+	rest := fields[5]
+	if strings.HasPrefix(rest, "-") {
+		rest = rest[1:]
+	} else {
+		sd, n, ok := parseStructuredData(rest)
+		if !ok {
+			return syslogMessage{}, false
+		}
+		msg.structuredData = sd
+		rest = rest[n:]
+	}
+	if rest != "" && rest[0] != ' ' {
+		return syslogMessage{}, false
+	}
+	// A UTF-8 message may start with a byte order mark.
+	msg.message = strings.TrimPrefix(strings.TrimPrefix(rest, " "), "\ufeff")
+	return msg, true
+}
+
+// parseStructuredData parses the SD-ELEMENTs at the start of s, such as
+// `[exampleSDID@32473 iut="3" eventSource="Application"]`, into
+// "<sd-id>.<param-name>" keys. It returns the number of bytes consumed.
+func parseStructuredData(s string) (map[string]string, int, bool) {
+	sd := map[string]string{}
+	i := 0
+	for i < len(s) && s[i] == '[' {
+		i++
+		idEnd := strings.IndexAny(s[i:], " ]")
+		if idEnd <= 0 {
+			return nil, 0, false
+		}
+		id := s[i : i+idEnd]
+		i += idEnd
+		for i < len(s) && s[i] == ' ' {
+			i++
+			eq := strings.IndexByte(s[i:], '=')
+			if eq <= 0 || i+eq+1 >= len(s) || s[i+eq+1] != '"' {
+				return nil, 0, false
+			}
+			name := s[i : i+eq]
+			i += eq + 2
+			var value strings.Builder
+			for {
+				if i >= len(s) {
+					return nil, 0, false
+				}
+				c := s[i]
+				i++
+				if c == '"' {
+					break
+				}
+				// Only `"`, `\`, and `]` are escaped; any other backslash is literal.
+				if c == '\\' && i < len(s) && strings.IndexByte(`"\]`, s[i]) >= 0 {
+					c = s[i]
+					i++
+				}
+				value.WriteByte(c)
+			}
+			sd[id+"."+name] = value.String()
+		}
+		if i >= len(s) || s[i] != ']' {
+			return nil, 0, false
+		}
+		i++
+	}
+	return sd, i, i > 0
+}
+
+func parseRFC3164(msg syslogMessage, s string, now time.Time) (syslogMessage, bool) {
+	if len(s) > len(time.Stamp) && s[len(time.Stamp)] == ' ' {
+		if ts, err := time.ParseInLocation(time.Stamp, s[:len(time.Stamp)], now.Location()); err == nil {
+			ts = ts.AddDate(now.Year(), 0, 0)
+			if ts.After(now.AddDate(0, 0, 1)) {
+				ts = ts.AddDate(-1, 0, 0)
+			}
+			msg.timestamp = ts
+			s = s[len(time.Stamp)+1:]
+		}
+	}
+	if msg.timestamp.IsZero() {
+		// rsyslog writes RFC 3339 timestamps with its high-precision format.
+		token, rest, ok := strings.Cut(s, " ")
+		if !ok {
+			return syslogMessage{}, false
+		}
+		ts, err := time.Parse(time.RFC3339Nano, token)
+		if err != nil {
+			return syslogMessage{}, false
+		}
+		msg.timestamp = ts
+		s = rest
+	}
+
+	hostname, rest, ok := strings.Cut(s, " ")
+	if !ok || hostname == "" {
+		return syslogMessage{}, false
+	}
+	msg.hostname = hostname
+
+	// The tag is the program name, optionally followed by "[pid]", and ends
+	// with a colon. A message without one has no tag.
+	msg.message = rest
+	if tag, message, ok := strings.Cut(rest, ": "); ok && !strings.ContainsRune(tag, ' ') {
+		msg.message = message
+		if open := strings.IndexByte(tag, '['); open > 0 && strings.HasSuffix(tag, "]") {
+			msg.procID = tag[open+1 : len(tag)-1]
+			tag = tag[:open]
+		}
+		msg.appName = tag
+	}
+	return msg, true
+}
+
+func nilValue(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
+
+// fillFromSyslog fills lr from msg and returns the resource of the line:
+// the host, and the application as the service.
+func fillFromSyslog(msg syslogMessage, lr plog.LogRecord) map[string]string {
+	lr.Body().SetStr(msg.message)
+	if !msg.timestamp.IsZero() {
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(msg.timestamp))
+	}
+	if msg.priority >= 0 {
+		setSeverity(lr, syslogSeverities[msg.priority%8])
+		lr.Attributes().PutStr("syslog.facility", syslogFacilities[msg.priority/8])
+	}
+	setProcessID(lr, msg.procID)
+	if msg.msgID != "" {
+		lr.Attributes().PutStr("syslog.msgid", msg.msgID)
+	}
+	for k, v := range msg.structuredData {
+		lr.Attributes().PutStr("syslog.structured_data."+k, v)
+	}
+
+	resource := map[string]string{}
+	if msg.hostname != "" {
+		resource["host.name"] = msg.hostname
+	}
+	if msg.appName != "" {
+		resource["service.name"] = msg.appName
+	}
+	return resource
+}
+
+// setProcessID records a process ID as process.pid. IDs that are not
+// numbers, which RFC 5424 allows, are kept as syslog.procid.
+func setProcessID(lr plog.LogRecord, procID string) {
+	if procID == "" {
+		return
+	}
+	if pid, err := strconv.ParseInt(procID, 10, 64); err == nil {
+		lr.Attributes().PutInt("process.pid", pid)
+	} else {
+		lr.Attributes().PutStr("syslog.procid", procID)
+	}
+}
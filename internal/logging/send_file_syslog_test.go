@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestLineParser_SyslogRFC5424(t *testing.T) {
+	p := newTestLineParser(t, createFlags{Format: "syslog"})
+	lr := plog.NewLogRecord()
+	resource := p.parse(`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 1234 ID47 [exampleSDID@32473 iut="3" eventSource="App\]lication"] An application event`, lr)
+
+	assert.Equal(t, map[string]string{"host.name": "mymachine.example.com", "service.name": "evntslog"}, resource)
+	assert.Equal(t, "An application event", lr.Body().Str())
+	assert.Equal(t, time.Date(2003, 10, 11, 22, 14, 15, 3e6, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, "notice", lr.SeverityText())
+	assert.Equal(t, plog.SeverityNumberInfo2, lr.SeverityNumber())
+	assert.Equal(t, map[string]any{
+		"syslog.facility": "local4",
+		"syslog.msgid":    "ID47",
+		"process.pid":     int64(1234),
+		"syslog.structured_data.exampleSDID@32473.iut":         "3",
+		"syslog.structured_data.exampleSDID@32473.eventSource": "App]lication",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_SyslogRFC3164(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	msg, ok := parseSyslogLine("<34>Dec 31 22:14:15 mymachine su[42]: 'su root' failed for lonvick on /dev/pts/8", now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 12, 31, 22, 14, 15, 0, time.UTC), msg.timestamp, "a date in the future is in the previous year")
+	assert.Equal(t, "mymachine", msg.hostname)
+	assert.Equal(t, "su", msg.appName)
+	assert.Equal(t, "42", msg.procID)
+	assert.Equal(t, "'su root' failed for lonvick on /dev/pts/8", msg.message)
+
+	// The file format of rsyslog, without a priority.
+	msg, ok = parseSyslogLine("2026-01-02T09:59:58.123456+01:00 web-1 kernel: Out of memory: Killed process 913", now)
+	require.True(t, ok)
+	assert.Equal(t, -1, msg.priority)
+	assert.Equal(t, "kernel", msg.appName)
+	assert.Equal(t, "Out of memory: Killed process 913", msg.message)
+
+	_, ok = parseSyslogLine("just some text", now)
+	assert.False(t, ok)
+}
+
+func TestLineParser_AutoDetectsSyslogWithPriority(t *testing.T) {
+	p := newTestLineParser(t, createFlags{})
+	lr := plog.NewLogRecord()
+	resource := p.parse("<11>Oct 18 12:00:00 web-1 nginx: upstream timed out", lr)
+	assert.Equal(t, "web-1", resource["host.name"])
+	assert.Equal(t, plog.SeverityNumberError, lr.SeverityNumber())
+
+	lr = plog.NewLogRecord()
+	assert.Nil(t, p.parse("Oct 18 12:00:00 web-1 nginx: upstream timed out", lr), "without a priority the line is text")
+	assert.Equal(t, "Oct 18 12:00:00 web-1 nginx: upstream timed out", lr.Body().Str())
+}
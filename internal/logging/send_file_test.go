@@ -0,0 +1,300 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newTestLineParser(t *testing.T, flags createFlags) *lineParser {
+	t.Helper()
+	if flags.Format == "" {
+		flags.Format = "auto"
+	}
+	p, err := newLineParser(&flags)
+	require.NoError(t, err)
+	return p
+}
+
+func parseTestLine(p *lineParser, line string) plog.LogRecord {
+	lr := plog.NewLogRecord()
+	p.parse(line, lr)
+	return lr
+}
+
+func TestSendFileArgumentValidation(t *testing.T) {
+	cmd := newSendCmd()
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires a log body")
+
+	cmd = newSendCmd()
+	cmd.SetArgs([]string{"body", "--file", "app.log"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined with --file")
+
+	cmd = newSendCmd()
+	cmd.SetArgs([]string{"--file", "app.log", "--format", "xml"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown format")
+
+	cmd = newSendCmd()
+	cmd.SetArgs([]string{"--file", "app.log", "--format", "json", "--pattern", "(?P<body>.*)"})
+	err = cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--pattern")
+}
+
+func TestLineParser_JSON(t *testing.T) {
+	p := newTestLineParser(t, createFlags{})
+	lr := parseTestLine(p, `{"time":"2026-03-15T10:30:00.5Z","level":"warn","msg":"disk almost full","disk.used":0.93,"retries":3,"trace_id":"0af7651916cd43dd8448eb211c80319c","http":{"status":503}}`)
+
+	assert.Equal(t, "disk almost full", lr.Body().Str())
+	assert.Equal(t, time.Date(2026, 3, 15, 10, 30, 0, 5e8, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, "warn", lr.SeverityText())
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", lr.TraceID().String())
+	assert.Equal(t, map[string]any{
+		"disk.used": 0.93,
+		"retries":   int64(3),
+		"http":      map[string]any{"status": int64(503)},
+	}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_JSONWithoutMessage(t *testing.T) {
+	p := newTestLineParser(t, createFlags{Format: "json"})
+	line := `{"level":30,"ts":1773570600123,"event":"started"}`
+	lr := parseTestLine(p, line)
+
+	assert.Equal(t, line, lr.Body().Str())
+	assert.Equal(t, plog.SeverityNumberInfo, lr.SeverityNumber())
+	assert.Equal(t, time.UnixMilli(1773570600123).UTC(), lr.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{"event": "started"}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_CustomKeys(t *testing.T) {
+	p := newTestLineParser(t, createFlags{
+		TimeKeys:     []string{"when"},
+		TimeFormat:   "unix",
+		SeverityKeys: []string{"sev"},
+		BodyKeys:     []string{"text"},
+	})
+	lr := parseTestLine(p, `{"when":1773570600,"sev":"ERROR","text":"boom","time":"kept"}`)
+
+	assert.Equal(t, "boom", lr.Body().Str())
+	assert.Equal(t, time.Unix(1773570600, 0).UTC(), lr.Timestamp().AsTime())
+	assert.Equal(t, plog.SeverityNumberError, lr.SeverityNumber())
+	assert.Equal(t, map[string]any{"time": "kept"}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_UnparseableTimestampIsKept(t *testing.T) {
+	p := newTestLineParser(t, createFlags{})
+	lr := parseTestLine(p, `{"time":"yesterday","msg":"x"}`)
+	assert.Zero(t, lr.Timestamp())
+	assert.Equal(t, map[string]any{"time": "yesterday"}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_Logfmt(t *testing.T) {
+	p := newTestLineParser(t, createFlags{})
+	lr := parseTestLine(p, `ts=2026-03-15T10:30:00Z level=error msg="connection refused: \"db\"" host=db-1 url=http://x/?a=b`)
+
+	assert.Equal(t, `connection refused: "db"`, lr.Body().Str())
+	assert.Equal(t, plog.SeverityNumberError, lr.SeverityNumber())
+	assert.Equal(t, time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{"host": "db-1", "url": "http://x/?a=b"}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_AutoFallsBackToText(t *testing.T) {
+	p := newTestLineParser(t, createFlags{})
+	for _, line := range []string{
+		"Retrying in 5s attempt=2",
+		`{"unterminated": `,
+		"plain text",
+	} {
+		lr := parseTestLine(p, line)
+		assert.Equal(t, line, lr.Body().Str(), line)
+		assert.Equal(t, 0, lr.Attributes().Len(), line)
+	}
+
+	// With an explicit logfmt format, bare keys are accepted as true.
+	p = newTestLineParser(t, createFlags{Format: "logfmt"})
+	lr := parseTestLine(p, "msg=ok cached")
+	assert.Equal(t, "ok", lr.Body().Str())
+	assert.Equal(t, map[string]any{"cached": true}, lr.Attributes().AsRaw())
+}
+
+func TestLineParser_TextPattern(t *testing.T) {
+	p := newTestLineParser(t, createFlags{
+		Format:  "text",
+		Pattern: `^(?P<time>\S+) \[(?P<severity>\w+)\] (?P<component>\w+): (?P<body>.*)$`,
+	})
+	lr := parseTestLine(p, "2026-03-15T10:30:00Z [WARNING] cache: eviction storm")
+
+	assert.Equal(t, "eviction storm", lr.Body().Str())
+	assert.Equal(t, "WARNING", lr.SeverityText())
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, map[string]any{"component": "cache"}, lr.Attributes().AsRaw())
+
+	lr = parseTestLine(p, "no match here")
+	assert.Equal(t, "no match here", lr.Body().Str())
+}
+
+func TestParseLogTime_Magnitudes(t *testing.T) {
+	want := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	for _, v := range []any{int64(1773570600), int64(1773570600000), int64(1773570600000000), int64(1773570600000000000), "1773570600.0"} {
+		ts, ok := parseLogTime(v, "auto")
+		require.True(t, ok, v)
+		assert.True(t, want.Equal(ts), "%v parsed as %v", v, ts)
+	}
+	ts, ok := parseLogTime("15/03/2026 10:30", "02/01/2006 15:04")
+	require.True(t, ok)
+	assert.True(t, want.Equal(ts))
+}
+
+func TestReadLines_TruncatesLongLines(t *testing.T) {
+	input := "short\r\n" + strings.Repeat("x", maxLogLineBytes+10) + "\nlast"
+	var got []readLine
+	require.NoError(t, readLines(strings.NewReader(input), func(l readLine) bool {
+		got = append(got, l)
+		return true
+	}))
+	require.Len(t, got, 3)
+	assert.Equal(t, readLine{text: "short"}, got[0])
+	assert.Len(t, got[1].text, maxLogLineBytes)
+	assert.True(t, got[1].truncated)
+	assert.Equal(t, readLine{text: "last"}, got[2])
+}
+
+type recordingSender struct {
+	mu      sync.Mutex
+	batches []plog.Logs
+	err     error
+}
+
+func (s *recordingSender) send(_ context.Context, logs plog.Logs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, logs)
+	return nil
+}
+
+func (s *recordingSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.batches)
+}
+
+func newTestShipper(t *testing.T, sender *recordingSender, batchSize int, flushInterval time.Duration) *logShipper {
+	flags := &createFlags{ScopeName: "dash0-cli", SeverityText: "INFO", SeverityNumber: 9}
+	return &logShipper{
+		parser:   newTestLineParser(t, createFlags{}),
+		defaults: recordDefaults{flags: flags, attributes: map[string]string{"ci.job": "unit"}},
+		newRecords: func(logs plog.Logs, _ map[string]string) plog.LogRecordSlice {
+			return appendResourceLogs(logs, flags, map[string]string{"service.name": "ci"}, nil)
+		},
+		send:          sender.send,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		fileName:      "app.log",
+	}
+}
+
+func TestLogShipper_Batches(t *testing.T) {
+	sender := &recordingSender{}
+	s := newTestShipper(t, sender, 2, time.Hour)
+
+	sent, err := s.run(context.Background(), strings.NewReader("one\n\n{\"msg\":\"two\",\"level\":\"error\"}\nthree\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	require.Len(t, sender.batches, 2)
+	assert.Equal(t, 2, sender.batches[0].LogRecordCount())
+	assert.Equal(t, 1, sender.batches[1].LogRecordCount())
+
+	rl := sender.batches[0].ResourceLogs().At(0)
+	serviceName, _ := rl.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "ci", serviceName.Str())
+	records := rl.ScopeLogs().At(0).LogRecords()
+	first := records.At(0)
+	assert.Equal(t, "one", first.Body().Str())
+	assert.Equal(t, "INFO", first.SeverityText(), "flags apply as defaults")
+	assert.NotZero(t, first.ObservedTimestamp())
+	assert.Equal(t, map[string]any{"ci.job": "unit", "log.file.name": "app.log"}, first.Attributes().AsRaw())
+	second := records.At(1)
+	assert.Equal(t, "error", second.SeverityText(), "the line wins over the flag")
+	assert.Equal(t, plog.SeverityNumberError, second.SeverityNumber())
+}
+
+func TestLogShipper_GroupsRecordsByResource(t *testing.T) {
+	sender := &recordingSender{}
+	flags := &createFlags{ScopeName: "dash0-cli"}
+	s := &logShipper{
+		parser:   newTestLineParser(t, createFlags{Format: "syslog"}),
+		defaults: recordDefaults{flags: flags},
+		newRecords: func(logs plog.Logs, found map[string]string) plog.LogRecordSlice {
+			return appendResourceLogs(logs, flags, found, nil)
+		},
+		send:          sender.send,
+		batchSize:     10,
+		flushInterval: time.Hour,
+	}
+
+	sent, err := s.run(context.Background(), strings.NewReader(
+		"<30>Oct 18 12:00:00 web-1 nginx: started\n<30>Oct 18 12:00:01 web-2 nginx: started\n<27>Oct 18 12:00:02 web-1 nginx: failed\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	require.Len(t, sender.batches, 1)
+	resources := sender.batches[0].ResourceLogs()
+	require.Equal(t, 2, resources.Len())
+	host, _ := resources.At(0).Resource().Attributes().Get("host.name")
+	assert.Equal(t, "web-1", host.Str())
+	assert.Equal(t, 2, resources.At(0).ScopeLogs().At(0).LogRecords().Len())
+	assert.Equal(t, 1, resources.At(1).ScopeLogs().At(0).LogRecords().Len())
+}
+
+func TestLogShipper_FlushesOnInterval(t *testing.T) {
+	sender := &recordingSender{}
+	s := newTestShipper(t, sender, 100, 10*time.Millisecond)
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = s.run(context.Background(), r)
+	}()
+
+	_, _ = w.Write([]byte("first\n"))
+	// The pipe stays open, so only the flush interval can send the record.
+	require.Eventually(t, func() bool {
+		select {
+		case <-done:
+			return false
+		default:
+		}
+		return sender.count() == 1
+	}, time.Second, 5*time.Millisecond)
+	_ = w.Close()
+	<-done
+}
+
+func TestLogShipper_SendError(t *testing.T) {
+	sender := &recordingSender{err: errors.New("unauthorized")}
+	s := newTestShipper(t, sender, 1, time.Hour)
+	sent, err := s.run(context.Background(), strings.NewReader("one\ntwo\n"))
+	require.Error(t, err)
+	assert.Equal(t, 0, sent)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
@@ -0,0 +1,263 @@
+package login
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal/config"
+	"github.com/dash0hq/dash0-cli/internal/oauth"
+)
+
+// deviceCodeGrantType is the grant type of the OAuth 2.0 device
+// authorization grant (RFC 8628 §3.4). Kept untyped so it converts to
+// dash0api.OAuthGrantType for dynamic client registration.
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Polling defaults from RFC 8628: clients wait 5 seconds between token
+// requests unless the server says otherwise, and add 5 seconds every time
+// the server answers `slow_down`.
+const (
+	defaultDevicePollInterval = 5
+	deviceSlowDownIncrement   = 5
+)
+
+// devicePollUnit is the unit of the polling interval advertised by the
+// authorization server. Override in tests to poll in milliseconds instead
+// of seconds.
+var devicePollUnit = time.Second
+
+// deviceEndpoints is the subset of the OAuth Authorization Server Metadata
+// (RFC 8414) the device flow needs. The metadata is fetched directly rather
+// than through the API client because `device_authorization_endpoint` is
+// an RFC 8628 extension of the metadata document.
+type deviceEndpoints struct {
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+	GrantTypesSupported         []string `json:"grant_types_supported"`
+}
+
+// deviceAuthorization is the response of the device authorization endpoint
+// (RFC 8628 §3.2).
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+	oauthErrorResponse
+}
+
+// deviceTokenResponse is the response of the token endpoint to a device
+// code poll: either the issued tokens or an OAuth error (RFC 8628 §3.5).
+type deviceTokenResponse struct {
+	AccessToken  string  `json:"access_token"`
+	RefreshToken *string `json:"refresh_token"`
+	ExpiresIn    int64   `json:"expires_in"`
+	oauthErrorResponse
+}
+
+// oauthErrorResponse is the error body of the OAuth endpoints (RFC 6749
+// §5.2), shared by the device authorization and token responses.
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// asError renders the AS-supplied error through SanitizeASText and caps
+// each field at 512 chars, like validateCallbackResult does for the
+// browser flow.
+func (r oauthErrorResponse) asError() error {
+	errCode := truncate(oauth.SanitizeASText(r.Error), 512)
+	errDesc := truncate(oauth.SanitizeASText(r.ErrorDescription), 512)
+	if errDesc != "" {
+		return fmt.Errorf("authorization server returned an error: %s: %s", errCode, errDesc)
+	}
+	return fmt.Errorf("authorization server returned an error: %s", errCode)
+}
+
+// runDeviceLogin performs the OAuth 2.0 device authorization grant
+// (RFC 8628) for machines without a browser: it shows a user code and a
+// verification URL to open on any other device, polls the token endpoint
+// until the user approves the login, and then persists the tokens exactly
+// like the browser flow does.
+func runDeviceLogin(ctx context.Context, opts loginOptions, target *loginTarget, oauthClient dash0api.OAuthClient) error {
+	endpoints, err := discoverDeviceEndpoints(ctx, target.APIURL)
+	if err != nil {
+		return err
+	}
+
+	entry, err := ensureRegisteredClient(ctx, oauthClient, target.APIURL, "")
+	if err != nil {
+		return err
+	}
+
+	authz, err := requestDeviceAuthorization(ctx, target.APIURL, endpoints.DeviceAuthorizationEndpoint, entry.ClientID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "To log in to %s, open this URL in a browser on any device:\n  %s\n", target.APIURL, oauth.SanitizeASText(authz.VerificationURI))
+	fmt.Fprintf(os.Stderr, "and enter the code: %s\n", oauth.SanitizeASText(authz.UserCode))
+	if authz.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "Or open this URL, which has the code filled in:\n  %s\n", oauth.SanitizeASText(authz.VerificationURIComplete))
+	}
+	fmt.Fprintln(os.Stderr, "Waiting for the login to be approved...")
+
+	// The device code bounds the flow. An explicit --timeout can only
+	// shorten it; the browser-flow default of two minutes is too short to
+	// walk over to another device.
+	deadline := time.Now().Add(time.Duration(authz.ExpiresIn) * time.Second)
+	if opts.TimeoutExplicit || authz.ExpiresIn <= 0 {
+		timeout := opts.Timeout
+		if timeout == 0 {
+			timeout = DefaultCallbackTimeout
+		}
+		if explicitDeadline := time.Now().Add(timeout); authz.ExpiresIn <= 0 || explicitDeadline.Before(deadline) {
+			deadline = explicitDeadline
+		}
+	}
+
+	tokenResp, err := pollDeviceToken(ctx, deadline, endpoints.TokenEndpoint, entry.ClientID, authz, target)
+	if err != nil {
+		return err
+	}
+
+	accessToken, refreshToken, expiresAt, err := validateTokenResponse(ctx, target.APIURL, tokenResp.AccessToken, tokenResp.RefreshToken, tokenResp.ExpiresIn)
+	if err != nil {
+		return err
+	}
+
+	return persistAndRevokeOld(ctx, target, accessToken, refreshToken, entry.ClientID, expiresAt)
+}
+
+// discoverDeviceEndpoints fetches the authorization server metadata and
+// checks that the server implements the device authorization grant, so an
+// unsupported server fails before a client is registered.
+func discoverDeviceEndpoints(ctx context.Context, apiURL string) (*deviceEndpoints, error) {
+	var endpoints deviceEndpoints
+	status, err := oauth.Request(ctx, http.MethodGet, oauth.MetadataURL(apiURL), nil, &endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OAuth metadata from %s: %w", apiURL, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to discover OAuth metadata from %s: unexpected status %d", apiURL, status)
+	}
+	if endpoints.DeviceAuthorizationEndpoint == "" ||
+		(endpoints.GrantTypesSupported != nil && !slices.Contains(endpoints.GrantTypesSupported, deviceCodeGrantType)) {
+		return nil, fmt.Errorf("the authorization server at %s does not support the device authorization grant; run `dash0 login` without --device", apiURL)
+	}
+	if endpoints.TokenEndpoint == "" {
+		return nil, fmt.Errorf("the authorization server at %s does not advertise a token endpoint", apiURL)
+	}
+	return &endpoints, nil
+}
+
+// requestDeviceAuthorization asks the authorization server for a device
+// code and the user code to display (RFC 8628 §3.1).
+func requestDeviceAuthorization(ctx context.Context, apiURL, endpoint, clientID string) (*deviceAuthorization, error) {
+	var authz deviceAuthorization
+	status, err := oauth.Request(ctx, http.MethodPost, endpoint, url.Values{"client_id": {clientID}}, &authz)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	if authz.Error == "invalid_client" {
+		invalidateOAuthClient(apiURL)
+	}
+	if authz.Error != "" {
+		return nil, authz.asError()
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("device authorization request failed with status %d", status)
+	}
+	if authz.DeviceCode == "" || authz.UserCode == "" || authz.VerificationURI == "" {
+		return nil, errors.New("the authorization server returned an incomplete device authorization response; aborting")
+	}
+	return &authz, nil
+}
+
+// pollDeviceToken polls the token endpoint until the user approves or
+// denies the login, the device code expires, or ctx is cancelled. It backs
+// off by five seconds whenever the server answers `slow_down`, as
+// RFC 8628 §3.5 requires.
+func pollDeviceToken(
+	ctx context.Context,
+	deadline time.Time,
+	tokenEndpoint, clientID string,
+	authz *deviceAuthorization,
+	target *loginTarget,
+) (*deviceTokenResponse, error) {
+	interval := time.Duration(authz.Interval) * devicePollUnit
+	if authz.Interval <= 0 {
+		interval = defaultDevicePollInterval * devicePollUnit
+	}
+
+	pollCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {authz.DeviceCode},
+		"client_id":   {clientID},
+	}
+	for {
+		timer := time.NewTimer(interval)
+		select {
+		case <-pollCtx.Done():
+			timer.Stop()
+			if errors.Is(pollCtx.Err(), context.DeadlineExceeded) {
+				return nil, deviceExpiredError(target)
+			}
+			return nil, errors.New("login aborted")
+		case <-timer.C:
+		}
+
+		var resp deviceTokenResponse
+		status, err := oauth.Request(pollCtx, http.MethodPost, tokenEndpoint, form, &resp)
+		if err != nil {
+			if pollCtx.Err() != nil {
+				continue // Reported by the select above.
+			}
+			return nil, fmt.Errorf("token request failed: %w", err)
+		}
+		if status == http.StatusOK && resp.Error == "" {
+			return &resp, nil
+		}
+
+		switch resp.Error {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += deviceSlowDownIncrement * devicePollUnit
+			continue
+		case "access_denied":
+			return nil, errors.New("the login was denied in the browser")
+		case "expired_token":
+			return nil, deviceExpiredError(target)
+		case "invalid_client":
+			// Same recovery as the browser flow: forget the stale
+			// registration so the next login re-registers cleanly.
+			invalidateOAuthClient(target.APIURL)
+		case "":
+			return nil, fmt.Errorf("token request failed with status %d", status)
+		}
+		return nil, resp.asError()
+	}
+}
+
+// deviceExpiredError is returned when the device code expires (or the
+// --timeout elapses) before the user approved the login.
+func deviceExpiredError(target *loginTarget) error {
+	displayName := ""
+	if target.PassedExplicit {
+		displayName = target.Name
+	}
+	return fmt.Errorf("timed out waiting for the login to be approved; re-run `dash0 login --device%s` to try again", config.ProfileFlagFragment(displayName))
+}
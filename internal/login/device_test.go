@@ -0,0 +1,121 @@
+package login
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDeviceTokenEndpoint answers successive device-code polls with the
+// given OAuth error codes, then with tokens. It records when each poll
+// arrived so tests can check the polling cadence.
+type fakeDeviceTokenEndpoint struct {
+	mu     sync.Mutex
+	errors []string
+	polls  []time.Time
+}
+
+func (f *fakeDeviceTokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = r.ParseForm()
+	if r.Form.Get("grant_type") != deviceCodeGrantType || r.Form.Get("device_code") != "dev-code" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+	n := len(f.polls)
+	f.polls = append(f.polls, time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	if n < len(f.errors) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": f.errors[n], "error_description": "\x1b[2Jfake"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token":  "dash0_at_device",
+		"refresh_token": "dash0_rt_device",
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+	})
+}
+
+func pollFakeDeviceTokenEndpoint(t *testing.T, errors ...string) (*deviceTokenResponse, *fakeDeviceTokenEndpoint, error) {
+	t.Helper()
+	prev := devicePollUnit
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = prev })
+
+	endpoint := &fakeDeviceTokenEndpoint{errors: errors}
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+
+	target := &loginTarget{Name: "staging", APIURL: server.URL, PassedExplicit: true}
+	authz := &deviceAuthorization{DeviceCode: "dev-code", Interval: 1}
+	resp, err := pollDeviceToken(context.Background(), time.Now().Add(5*time.Second), server.URL, "client-1", authz, target)
+	return resp, endpoint, err
+}
+
+func TestPollDeviceToken_PendingThenSlowDown(t *testing.T) {
+	resp, endpoint, err := pollFakeDeviceTokenEndpoint(t, "authorization_pending", "slow_down")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.AccessToken != "dash0_at_device" || resp.RefreshToken == nil || *resp.RefreshToken != "dash0_rt_device" {
+		t.Fatalf("unexpected tokens: %+v", resp)
+	}
+	if len(endpoint.polls) != 3 {
+		t.Fatalf("expected 3 polls, got %d", len(endpoint.polls))
+	}
+	// slow_down raises the interval from 1 to 1+5 units.
+	if gap := endpoint.polls[2].Sub(endpoint.polls[1]); gap < 6*time.Millisecond {
+		t.Fatalf("expected the interval to grow after slow_down, got %s", gap)
+	}
+}
+
+func TestPollDeviceToken_Denied(t *testing.T) {
+	_, _, err := pollFakeDeviceTokenEndpoint(t, "authorization_pending", "access_denied")
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected a denial error, got %v", err)
+	}
+}
+
+func TestPollDeviceToken_Expired(t *testing.T) {
+	_, _, err := pollFakeDeviceTokenEndpoint(t, "expired_token")
+	if err == nil || !strings.Contains(err.Error(), "re-run `dash0 login --device --profile staging`") {
+		t.Fatalf("expected an expiry error with a retry hint, got %v", err)
+	}
+}
+
+func TestPollDeviceToken_SanitizesUnknownErrors(t *testing.T) {
+	_, _, err := pollFakeDeviceTokenEndpoint(t, "server_error")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), "\x1b") {
+		t.Fatalf("error contains an escape sequence: %q", err.Error())
+	}
+	if !strings.Contains(err.Error(), "server_error") {
+		t.Fatalf("expected the OAuth error code in %q", err.Error())
+	}
+}
+
+func TestDiscoverDeviceEndpoints_Unsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token_endpoint":        "https://example.com/oauth/token",
+			"grant_types_supported": []string{"authorization_code", "refresh_token"},
+		})
+	}))
+	defer server.Close()
+
+	_, err := discoverDeviceEndpoints(context.Background(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "does not support the device authorization grant") {
+		t.Fatalf("expected an unsupported-grant error, got %v", err)
+	}
+}
@@ -27,7 +27,8 @@ import (
 
 // fakeOAuthServer implements just enough of the Dash0 OAuth surface to drive
 // runLogin and runLogout end-to-end: discovery, dynamic client registration,
-// authorize, token (auth_code + refresh_token), and revoke.
+// authorize, device authorization, token (auth_code + device_code +
+// refresh_token), and revoke.
 type fakeOAuthServer struct {
 	t        *testing.T
 	mux      *http.ServeMux
@@ -51,6 +52,11 @@ type fakeOAuthServer struct {
 	tokenErrorCode     atomic.Value // string -- 400 with this OAuth error code
 	tokenOmitRefresh   atomic.Bool  // 200 OK with access_token but no refresh_token
 	tokenExpiresIn     atomic.Int64 // when > 0, overrides the 3600s default expires_in
+
+	// devicePending is the number of device-code polls answered with
+	// authorization_pending before the fake approves the login.
+	devicePending atomic.Int32
+	devicePolls   atomic.Int32
 }
 
 func newFakeOAuthServer(t *testing.T) *fakeOAuthServer {
@@ -63,6 +69,7 @@ func newFakeOAuthServer(t *testing.T) *fakeOAuthServer {
 	f.mux.HandleFunc("/.well-known/oauth-authorization-server", f.handleDiscovery)
 	f.mux.HandleFunc("/oauth/register", f.handleRegister)
 	f.mux.HandleFunc("/oauth/authorize", f.handleAuthorize)
+	f.mux.HandleFunc("/oauth/device_authorization", f.handleDeviceAuthorization)
 	f.mux.HandleFunc("/oauth/token", f.handleToken)
 	f.mux.HandleFunc("/oauth/revoke", f.handleRevoke)
 	f.server = httptest.NewServer(f.mux)
@@ -88,8 +95,9 @@ func (f *fakeOAuthServer) handleDiscovery(w http.ResponseWriter, _ *http.Request
 		"token_endpoint":                        f.server.URL + "/oauth/token",
 		"registration_endpoint":                 f.server.URL + "/oauth/register",
 		"revocation_endpoint":                   f.server.URL + "/oauth/revoke",
+		"device_authorization_endpoint":         f.server.URL + "/oauth/device_authorization",
 		"response_types_supported":              []string{"code"},
-		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token", deviceCodeGrantType},
 		"code_challenge_methods_supported":      []string{"S256"},
 		"token_endpoint_auth_methods_supported": []string{"none"},
 	})
@@ -152,8 +160,33 @@ func (f *fakeOAuthServer) handleAuthorize(w http.ResponseWriter, r *http.Request
 	http.Redirect(w, r, u.String(), http.StatusFound)
 }
 
+func (f *fakeOAuthServer) handleDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	require.NoError(f.t, r.ParseForm())
+	require.Equal(f.t, f.clientID, r.Form.Get("client_id"))
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"device_code":               "device-code-test-1",
+		"user_code":                 "WDJB-MJHT",
+		"verification_uri":          f.server.URL + "/device",
+		"verification_uri_complete": f.server.URL + "/device?user_code=WDJB-MJHT",
+		"expires_in":                600,
+		"interval":                  1,
+	})
+}
+
 func (f *fakeOAuthServer) handleToken(w http.ResponseWriter, r *http.Request) {
 	require.NoError(f.t, r.ParseForm())
+	if r.Form.Get("grant_type") == deviceCodeGrantType {
+		require.Equal(f.t, "device-code-test-1", r.Form.Get("device_code"))
+		if f.devicePolls.Add(1) <= f.devicePending.Load() {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": "authorization_pending"})
+			return
+		}
+		f.writeTokens(w)
+		return
+	}
 	if r.Form.Get("grant_type") != "authorization_code" {
 		http.Error(w, "unsupported_grant_type", http.StatusBadRequest)
 		return
@@ -184,6 +217,10 @@ func (f *fakeOAuthServer) handleToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	f.writeTokens(w)
+}
+
+func (f *fakeOAuthServer) writeTokens(w http.ResponseWriter) {
 	n := f.tokenCounter.Add(1)
 	access := fmt.Sprintf("dash0_at_test_access_v%d", n)
 	refresh := fmt.Sprintf("dash0_rt_test_refresh_v%d", n)
@@ -399,6 +436,45 @@ func TestRunLogin_OnOAuthActive_RevokesOldRefresh(t *testing.T) {
 	require.Equal(t, "dash0_rt_OLD_to_be_revoked", revoked[0])
 }
 
+func TestRunLogin_Device_PollsThenPersists(t *testing.T) {
+	// No TTY: the device flow must not need one.
+	prev := isTerminal
+	isTerminal = func() bool { return false }
+	t.Cleanup(func() { isTerminal = prev })
+	t.Setenv("DASH0_AGENT_MODE", "0")
+	t.Setenv("DASH0_CONFIG_DIR", t.TempDir())
+
+	prevUnit := devicePollUnit
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = prevUnit })
+
+	server := newFakeOAuthServer(t)
+	defer server.Close()
+	server.devicePending.Store(2)
+
+	store, _ := profiles.NewStore()
+	require.NoError(t, store.AddProfile(profiles.Profile{
+		Name:          "headless",
+		Configuration: profiles.Configuration{ApiUrl: server.URL(), OAuth: &profiles.OAuthState{}},
+	}))
+
+	err := runLogin(context.Background(), loginOptions{
+		ProfileName: "headless",
+		Device:      true,
+		Timeout:     DefaultCallbackTimeout,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), server.devicePolls.Load())
+
+	all, err := store.GetProfiles()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.NotNil(t, all[0].Configuration.OAuth)
+	assert.Equal(t, "dash0_rt_test_refresh_v1", all[0].Configuration.OAuth.RefreshToken)
+	assert.Equal(t, "client-fake-1234", all[0].Configuration.OAuth.ClientID)
+	assert.Equal(t, "dash0_at_test_access_v1", all[0].Configuration.AuthToken)
+}
+
 func TestRunLogin_RejectsInAgentMode(t *testing.T) {
 	prev := isTerminal
 	isTerminal = func() bool { return false }
@@ -14,6 +14,8 @@ import (
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/config"
 	"github.com/dash0hq/dash0-cli/internal/confirmation"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
 	"github.com/dash0hq/dash0-cli/internal/oauth"
 	"github.com/dash0hq/dash0-cli/internal/version"
 	"golang.org/x/term"
@@ -28,6 +30,9 @@ type loginOptions struct {
 	ProfileName string
 	Port        int
 	Timeout     time.Duration
+	// Device selects the OAuth 2.0 device authorization grant (RFC 8628)
+	// instead of the browser flow with a loopback callback listener.
+	Device bool
 	// TimeoutExplicit reports whether the user passed --timeout on the
 	// command line. If they did, runLogin treats it as a hard deadline for
 	// the full flow (callback + token exchange). If not, the default
@@ -111,9 +116,15 @@ func subjectPhraseFor(name string, passedExplicit bool) string {
 // The function is intentionally an orchestrator: each phase is its own
 // helper so the top-to-bottom flow stays scannable.
 func runLogin(ctx context.Context, opts loginOptions) error {
-	if agentmode.Enabled || !isTerminal() {
+	// The device flow only prints a code and polls, so it also works
+	// without a TTY (e.g. `docker exec` without -t). Agent mode is still
+	// rejected: it would auto-confirm the profile-conversion prompts.
+	if agentmode.Enabled || (!opts.Device && !isTerminal()) {
 		return errors.New(nonInteractiveErrorMessage())
 	}
+	if opts.Device && opts.Port != 0 {
+		return errors.New("--port cannot be used with --device")
+	}
 
 	target, err := resolveLoginTarget(opts)
 	if err != nil {
@@ -124,15 +135,24 @@ func runLogin(ctx context.Context, opts loginOptions) error {
 		return err
 	}
 
+	httpClient, err := httptransport.Client(ctx, 0)
+	if err != nil {
+		return err
+	}
 	oauthClient, err := dash0api.NewOAuthClient(
 		dash0api.WithApiUrl(target.APIURL),
 		dash0api.WithUserAgent(version.UserAgent()),
+		dash0api.WithHTTPClient(httpClient),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create OAuth client: %w", err)
 	}
 	defer func() { _ = oauthClient.Close(context.Background()) }()
 
+	if opts.Device {
+		return runDeviceLogin(ctx, opts, target, oauthClient)
+	}
+
 	// Discovery up front so we surface a clear error before we bind a socket.
 	if err := discoverAndVerifyPKCE(ctx, oauthClient, target.APIURL); err != nil {
 		return err
@@ -162,7 +182,7 @@ func runLogin(ctx context.Context, opts loginOptions) error {
 		return err
 	}
 
-	return persistAndRevokeOld(target, accessToken, refreshToken, authz.ClientID, expiresAt)
+	return persistAndRevokeOld(ctx, target, accessToken, refreshToken, authz.ClientID, expiresAt)
 }
 
 // resolveLoginTarget figures out which profile we're logging into, what
@@ -400,12 +420,8 @@ func validateCallbackResult(result callbackResult, stateParam string) error {
 }
 
 // exchangeAndValidateTokens runs the OAuth token-exchange RPC and validates
-// the response. On success it returns (accessToken, refreshToken,
-// expiresAt) ready for persistence. On any validation failure
-// post-exchange, it best-effort revokes the just-issued refresh token
-// before returning the error — the AS state must match the discarded
-// local state. The function applies the 24h expires_in cap and warns to
-// stderr when truncation occurs.
+// the response via validateTokenResponse. On success it returns
+// (accessToken, refreshToken, expiresAt) ready for persistence.
 func exchangeAndValidateTokens(
 	ctx context.Context,
 	budget loginBudget,
@@ -455,20 +471,30 @@ func exchangeAndValidateTokens(
 		}
 		return "", "", time.Time{}, fmt.Errorf("token exchange failed: %w", err)
 	}
-	if tokenResp.RefreshToken == nil || *tokenResp.RefreshToken == "" {
+	return validateTokenResponse(ctx, apiURL, tokenResp.AccessToken, tokenResp.RefreshToken, int64(tokenResp.ExpiresIn))
+}
+
+// validateTokenResponse checks the tokens issued by the token endpoint for
+// both the authorization-code and the device-code grant. On any validation
+// failure it best-effort revokes the just-issued refresh token before
+// returning the error — the AS state must match the discarded local state.
+// The function applies the 24h expires_in cap and warns to stderr when
+// truncation occurs.
+func validateTokenResponse(ctx context.Context, apiURL, accessToken string, refreshToken *string, expiresIn int64) (string, string, time.Time, error) {
+	if refreshToken == nil || *refreshToken == "" {
 		return "", "", time.Time{}, errors.New("token exchange succeeded but the server did not return a refresh token; aborting")
 	}
 	// Past this point we hold a non-empty refresh token. Any abort here must
 	// revoke it best-effort so the AS state matches the discarded local
 	// state — same compensation pattern as the persist-failure branch in
 	// persistAndRevokeOld.
-	if tokenResp.AccessToken == "" {
-		oauth.Revoke(apiURL, *tokenResp.RefreshToken)
+	if accessToken == "" {
+		oauth.Revoke(ctx, apiURL, *refreshToken)
 		return "", "", time.Time{}, errors.New("token exchange succeeded but the server returned an empty access token; aborting")
 	}
-	if tokenResp.ExpiresIn <= 0 {
-		oauth.Revoke(apiURL, *tokenResp.RefreshToken)
-		return "", "", time.Time{}, fmt.Errorf("token exchange succeeded but the server returned a non-positive expires_in (%d); aborting", tokenResp.ExpiresIn)
+	if expiresIn <= 0 {
+		oauth.Revoke(ctx, apiURL, *refreshToken)
+		return "", "", time.Time{}, fmt.Errorf("token exchange succeeded but the server returned a non-positive expires_in (%d); aborting", expiresIn)
 	}
 
 	// Cap expires_in defensively so a hostile or buggy AS that returns a
@@ -477,14 +503,14 @@ func exchangeAndValidateTokens(
 	// the refresh-before-expiry SDK logic). 24 hours is well above any
 	// reasonable access-token lifetime. Surface the clamp on stderr so a
 	// misconfigured AS is visible rather than silently truncated.
-	cappedExpiresIn := tokenResp.ExpiresIn
+	cappedExpiresIn := expiresIn
 	if cappedExpiresIn > maxAccessTokenLifetimeSeconds {
-		fmt.Fprintf(os.Stderr, "warning: authorization server returned an access-token lifetime of %d seconds; clamping to %d seconds (24h) defensively.\n", tokenResp.ExpiresIn, maxAccessTokenLifetimeSeconds)
+		fmt.Fprintf(os.Stderr, "warning: authorization server returned an access-token lifetime of %d seconds; clamping to %d seconds (24h) defensively.\n", expiresIn, maxAccessTokenLifetimeSeconds)
 		cappedExpiresIn = maxAccessTokenLifetimeSeconds
 	}
-	expiresAt = time.Now().Add(time.Duration(cappedExpiresIn) * time.Second)
+	expiresAt := time.Now().Add(time.Duration(cappedExpiresIn) * time.Second)
 
-	return tokenResp.AccessToken, *tokenResp.RefreshToken, expiresAt, nil
+	return accessToken, *refreshToken, expiresAt, nil
 }
 
 // persistAndRevokeOld writes the new OAuth tokens to the profile store,
@@ -496,6 +522,7 @@ func exchangeAndValidateTokens(
 // MUST be sent to the AS that issued it (recorded in
 // target.ExistingAPIURL), never to the new apiURL.
 func persistAndRevokeOld(
+	ctx context.Context,
 	target *loginTarget,
 	accessToken, refreshToken, clientID string,
 	expiresAt time.Time,
@@ -506,7 +533,7 @@ func persistAndRevokeOld(
 		// indefinitely. Best-effort revoke it so the AS state matches what
 		// the user sees locally. Old refresh token is intentionally left
 		// alone: it is still the active session on disk.
-		if !oauth.Revoke(target.APIURL, refreshToken) {
+		if !oauth.Revoke(ctx, target.APIURL, refreshToken) {
 			return fmt.Errorf(
 				"login succeeded but the new tokens could not be persisted and the compensating revoke also failed; the newly-issued refresh token may still be valid on the authorization server — visit your Dash0 account settings to revoke active sessions manually: %w",
 				err,
@@ -527,7 +554,7 @@ func persistAndRevokeOld(
 		if target.ExistingAPIURL == "" {
 			oldRevoked = false
 		} else {
-			oldRevoked = oauth.Revoke(target.ExistingAPIURL, target.OldRefresh)
+			oldRevoked = oauth.Revoke(ctx, target.ExistingAPIURL, target.OldRefresh)
 		}
 	}
 
@@ -568,7 +595,13 @@ func classifyProfile(name string) (state profileState, oldRefreshToken, existing
 		case p.Configuration.OAuth.RefreshToken == "":
 			return profileStateOAuthEmpty, "", existingAPIURL, nil
 		default:
-			return profileStateOAuthActive, p.Configuration.OAuth.RefreshToken, existingAPIURL, nil
+			// Open a sealed refresh token now: persisting the new login
+			// overwrites the credential-store entry it refers to.
+			oldRefreshToken, err := credentials.Open(p.Configuration.OAuth.RefreshToken)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: the previous refresh token cannot be revoked: %v\n", err)
+			}
+			return profileStateOAuthActive, oldRefreshToken, existingAPIURL, nil
 		}
 	}
 	return profileStateMissing, "", "", nil
@@ -638,7 +671,9 @@ func requirePKCEs256(meta *dash0api.OAuthAuthorizationServerMetadata) error {
 
 // ensureRegisteredClient returns a DCR cache record for apiURL, registering
 // a fresh client when there is no cached entry or when the cached entry's
-// redirect URI does not match the listener we just bound.
+// redirect URI does not match the listener we just bound. An empty
+// redirectURI registers a device-flow client, which has no redirect URI
+// and uses the device-code grant instead of the authorization-code grant.
 func ensureRegisteredClient(ctx context.Context, oauthClient dash0api.OAuthClient, apiURL, redirectURI string) (profiles.OAuthClientRecord, error) {
 	store, storeErr := profiles.NewOAuthClientStore()
 	if storeErr == nil {
@@ -647,14 +682,23 @@ func ensureRegisteredClient(ctx context.Context, oauthClient dash0api.OAuthClien
 		}
 	}
 
+	redirectURIs := []string{redirectURI}
+	grantTypes := []dash0api.OAuthGrantType{dash0api.OAuthGrantTypeAuthorizationCode, dash0api.OAuthGrantTypeRefreshToken}
+	responseTypes := &[]dash0api.OAuthResponseType{dash0api.Code}
+	if redirectURI == "" {
+		redirectURIs = []string{}
+		grantTypes = []dash0api.OAuthGrantType{deviceCodeGrantType, dash0api.OAuthGrantTypeRefreshToken}
+		responseTypes = nil
+	}
+
 	authMethod := dash0api.None
 	clientURI := "https://github.com/dash0hq/dash0-cli"
 	resp, err := oauthClient.RegisterClient(ctx, &dash0api.OAuthClientRegistrationRequest{
 		ClientName:              clientName,
 		ClientUri:               &clientURI,
-		RedirectUris:            []string{redirectURI},
-		GrantTypes:              &[]dash0api.OAuthGrantType{dash0api.OAuthGrantTypeAuthorizationCode, dash0api.OAuthGrantTypeRefreshToken},
-		ResponseTypes:           &[]dash0api.OAuthResponseType{dash0api.Code},
+		RedirectUris:            redirectURIs,
+		GrantTypes:              &grantTypes,
+		ResponseTypes:           responseTypes,
 		TokenEndpointAuthMethod: &authMethod,
 	})
 	if err != nil {
@@ -686,7 +730,9 @@ func invalidateOAuthClient(apiURL string) {
 }
 
 // persistProfile creates or updates the named profile with the freshly-issued
-// OAuth tokens. Existing OtlpUrl / Dataset values are preserved.
+// OAuth tokens. Existing OtlpUrl / Dataset values are preserved. The refresh
+// token goes to the credential store of the profile's previous refresh token,
+// or to the one selected via DASH0_CREDENTIAL_STORE.
 func persistProfile(name, apiURL, accessToken, refreshToken, clientID string, expiresAt time.Time) error {
 	store, err := profiles.NewStore()
 	if err != nil {
@@ -699,18 +745,35 @@ func persistProfile(name, apiURL, accessToken, refreshToken, clientID string, ex
 	}
 	for _, p := range existing {
 		if p.Name == name {
-			return store.UpdateProfile(name, func(c *profiles.Configuration) {
+			previousRefresh := ""
+			if p.Configuration.OAuth != nil {
+				previousRefresh = p.Configuration.OAuth.RefreshToken
+			}
+			sealed, err := credentials.Seal(name, credentials.KindRefreshToken, refreshToken, previousRefresh)
+			if err != nil {
+				return err
+			}
+			if err := store.UpdateProfile(name, func(c *profiles.Configuration) {
 				c.ApiUrl = apiURL
 				c.AuthToken = accessToken
 				c.OAuth = &profiles.OAuthState{
 					ClientID:     clientID,
-					RefreshToken: refreshToken,
+					RefreshToken: sealed,
 					ExpiresAt:    expiresAt,
 				}
-			})
+			}); err != nil {
+				return err
+			}
+			// A static profile turned OAuth no longer needs its auth token.
+			credentials.Remove(p.Configuration.AuthToken)
+			return nil
 		}
 	}
 
+	sealed, err := credentials.Seal(name, credentials.KindRefreshToken, refreshToken, "")
+	if err != nil {
+		return err
+	}
 	return store.AddProfile(profiles.Profile{
 		Name: name,
 		Configuration: profiles.Configuration{
@@ -718,7 +781,7 @@ func persistProfile(name, apiURL, accessToken, refreshToken, clientID string, ex
 			AuthToken: accessToken,
 			OAuth: &profiles.OAuthState{
 				ClientID:     clientID,
-				RefreshToken: refreshToken,
+				RefreshToken: sealed,
 				ExpiresAt:    expiresAt,
 			},
 		},
@@ -726,10 +789,11 @@ func persistProfile(name, apiURL, accessToken, refreshToken, clientID string, ex
 }
 
 // nonInteractiveErrorMessage is the error shown when login is invoked in
-// agent mode or without a TTY.
+// agent mode, or without a TTY and without --device.
 func nonInteractiveErrorMessage() string {
 	return "dash0 login requires an interactive terminal and cannot run in agent mode or non-TTY environments\n" +
-		"Hint: Use a static auth token instead:\n" +
+		"Hint: On a machine without a browser, run `dash0 login --device` and approve the login from another device,\n" +
+		"or use a static auth token instead:\n" +
 		"  dash0 config profiles create <name> --api-url <url> --auth-token <auth_...>\n" +
 		"or set DASH0_AUTH_TOKEN in the environment."
 }
@@ -17,6 +17,7 @@ func NewLoginCmd() *cobra.Command {
 		profileName string
 		port        int
 		timeout     time.Duration
+		device      bool
 	)
 
 	cmd := &cobra.Command{
@@ -29,9 +30,13 @@ resulting access and refresh tokens into a profile. Subsequent commands use
 that profile transparently; access tokens are refreshed automatically before
 they expire.
 
-This command requires an interactive terminal and cannot be used in CI or
-in agent mode — set DASH0_AUTH_TOKEN to a static auth token in those
-environments instead.`,
+On machines without a browser (SSH sessions, containers), use --device: the
+CLI prints a short code and a URL to open on any other device, and waits
+until the login is approved there (OAuth 2.0 device authorization grant).
+
+This command cannot be used in CI or in agent mode, and without --device it
+requires an interactive terminal — set DASH0_AUTH_TOKEN to a static auth
+token in those environments instead.`,
 		Example: `  # Log in against the API URL of the active profile
   dash0 login
 
@@ -39,7 +44,10 @@ environments instead.`,
   dash0 login --api-url https://api.eu-west-1.aws.dash0.com
 
   # Save the resulting tokens under a named profile
-  dash0 login --profile staging --api-url https://api.us-west-2.aws.dash0.com`,
+  dash0 login --profile staging --api-url https://api.us-west-2.aws.dash0.com
+
+  # Log in from a machine without a browser, approving on another device
+  dash0 login --device`,
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -49,6 +57,7 @@ environments instead.`,
 				Port:            port,
 				Timeout:         timeout,
 				TimeoutExplicit: cmd.Flags().Changed("timeout"),
+				Device:          device,
 			})
 		},
 	}
@@ -56,7 +65,8 @@ environments instead.`,
 	cmd.Flags().StringVar(&apiURL, "api-url", "", "Dash0 API URL to authenticate against (overrides the active profile and DASH0_API_URL)")
 	cmd.Flags().StringVar(&profileName, "profile", "", "Profile name to save the resulting tokens under (default: the active profile, or \"default\" if no profile exists)")
 	cmd.Flags().IntVar(&port, "port", 0, "Local TCP port to use for the OAuth callback listener (default: an OS-assigned ephemeral port)")
-	cmd.Flags().DurationVar(&timeout, "timeout", DefaultCallbackTimeout, "How long to wait for the browser callback before aborting")
+	cmd.Flags().DurationVar(&timeout, "timeout", DefaultCallbackTimeout, "How long to wait for the browser callback before aborting (with --device, the default is the lifetime of the device code)")
+	cmd.Flags().BoolVar(&device, "device", false, "Log in with the device authorization grant: show a code to enter in a browser on another device instead of opening a local browser")
 
 	return cmd
 }
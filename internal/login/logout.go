@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/dash0hq/dash0-api-client-go/profiles"
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/config"
 	"github.com/dash0hq/dash0-cli/internal/confirmation"
+	"github.com/dash0hq/dash0-cli/internal/credentials"
 	"github.com/dash0hq/dash0-cli/internal/oauth"
 )
 
@@ -133,7 +135,12 @@ func runLogout(ctx context.Context, opts logoutOptions) error {
 	}
 
 	// Best-effort revocation before we clear the in-memory copy on disk.
-	revoked := oauth.Revoke(cfg.ApiUrl, cfg.OAuth.RefreshToken)
+	revoked := false
+	if refreshToken, err := credentials.Open(cfg.OAuth.RefreshToken); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	} else {
+		revoked = oauth.Revoke(ctx, cfg.ApiUrl, refreshToken)
+	}
 
 	if err := store.UpdateProfile(name, func(c *profiles.Configuration) {
 		c.AuthToken = ""
@@ -143,6 +150,7 @@ func runLogout(ctx context.Context, opts logoutOptions) error {
 	}); err != nil {
 		return fmt.Errorf("failed to clear OAuth state on profile %q: %w", name, err)
 	}
+	credentials.Remove(cfg.OAuth.RefreshToken)
 
 	if passedExplicit {
 		fmt.Printf("Logged out of profile %q.\n", name)
@@ -145,7 +145,7 @@ func runInstant(cmd *cobra.Command, flags *instantFlags) error {
 	if dataset != "" {
 		datasetPtr = &dataset
 	}
-	response, err := runInstantQuery(apiURL, authToken, promql, evalTime, datasetPtr)
+	response, err := runInstantQuery(cmd.Context(), cfg.HTTPClient, apiURL, authToken, promql, evalTime, datasetPtr)
 	if err != nil {
 		return err
 	}
@@ -8,11 +8,12 @@ import (
 func NewMetricsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "metrics",
-		Short: "Query Dash0 metrics",
-		Long:  `Query metrics from the Dash0 API`,
+		Short: "Send and query metrics",
+		Long:  `Send metrics to Dash0 via OTLP and query them from the Dash0 API`,
 	}
 
 	cmd.AddCommand(newInstantCmd())
+	cmd.AddCommand(newSendCmd())
 
 	return cmd
 }
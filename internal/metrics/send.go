@@ -0,0 +1,332 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/dash0hq/dash0-cli/internal/version"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// defaultHistogramBounds are the explicit bucket boundaries the
+// OpenTelemetry SDKs use when a histogram has no view configured.
+var defaultHistogramBounds = []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}
+
+type sendFlags struct {
+	OtlpUrl            string
+	AuthToken          string
+	Dataset            string
+	Name               string
+	Type               string
+	Values             []string
+	Unit               string
+	Description        string
+	Temporality        string
+	NonMonotonic       bool
+	BucketBounds       []float64
+	Time               string
+	StartTime          string
+	Attributes         []string
+	ResourceAttributes []string
+	ScopeName          string
+	ScopeVersion       string
+	ScopeAttributes    []string
+}
+
+func newSendCmd() *cobra.Command {
+	flags := &sendFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send a metric data point to Dash0",
+		Long: `Send a single gauge, sum, or histogram data point to Dash0 via OTLP.
+
+A gauge or sum takes one --value. Integer values are sent as integers and
+anything else as a double. A histogram takes one --value per observation
+and is sent as a single data point that aggregates them.` + internal.CONFIG_HINT,
+		Example: `  # Record how long a CI build took
+  dash0 metrics send --name ci.build.duration --type gauge --value 312.4 --unit s \
+      --resource-attribute service.name=checkout \
+      --attribute ci.pipeline=main
+
+  # Count the tests that ran in this CI job
+  dash0 metrics send --name ci.tests --type sum --value 1284 --unit "{test}" \
+      --attribute test.status=passed
+
+  # Send the durations of individual test suites as a histogram
+  dash0 metrics send --name ci.test_suite.duration --type histogram --unit s \
+      --value 1.2 --value 0.4 --value 7.9 \
+      --bucket-bounds 0.5,1,2.5,5,10`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runSend(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset name")
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Metric name (required)")
+	_ = cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&flags.Type, "type", "gauge", "Metric type: gauge, sum, histogram")
+	cmd.Flags().StringArrayVar(&flags.Values, "value", nil, "Data point value; repeat once per observation for a histogram (required)")
+	_ = cmd.MarkFlagRequired("value")
+	cmd.Flags().StringVar(&flags.Unit, "unit", "", "Unit in UCUM notation (e.g. 's', 'By', '{test}')")
+	cmd.Flags().StringVar(&flags.Description, "description", "", "Metric description")
+	cmd.Flags().StringVar(&flags.Temporality, "temporality", "delta", "Aggregation temporality of a sum or histogram: delta, cumulative")
+	cmd.Flags().BoolVar(&flags.NonMonotonic, "non-monotonic", false, "Mark a sum as non-monotonic (it can decrease, like an up-down counter)")
+	cmd.Flags().Float64SliceVar(&flags.BucketBounds, "bucket-bounds", nil, "Comma-separated, increasing histogram bucket boundaries; defaults to the OpenTelemetry SDK defaults")
+	cmd.Flags().StringVar(&flags.Time, "time", "", "Data point timestamp in RFC3339 format; defaults to now")
+	cmd.Flags().StringVar(&flags.StartTime, "start-time", "", "Start of the interval a sum or histogram covers, in RFC3339 format; defaults to --time")
+	cmd.Flags().StringArrayVar(&flags.Attributes, "attribute", nil, "Data point attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil, "Resource attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringVar(&flags.ScopeName, "scope-name", otlp.DefaultScopeName, "Instrumentation scope name; defaults to 'dash0-cli'")
+	cmd.Flags().StringVar(&flags.ScopeVersion, "scope-version", version.Version, "Instrumentation scope version; defaults to the dash0 CLI version")
+	cmd.Flags().StringArrayVar(&flags.ScopeAttributes, "scope-attribute", nil, "Instrumentation scope attribute as 'key=value' (repeatable)")
+
+	return cmd
+}
+
+func runSend(cmd *cobra.Command, flags *sendFlags) error {
+	ctx := cmd.Context()
+
+	otlp.ResolveScopeDefaults(cmd, &flags.ScopeName, &flags.ScopeVersion)
+
+	metrics, err := buildMetrics(flags, time.Now())
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	defer apiClient.Close(ctx)
+
+	if err := apiClient.SendMetrics(ctx, metrics, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
+		return fmt.Errorf("failed to send metric: %w", err)
+	}
+
+	fmt.Println("Metric sent")
+	return nil
+}
+
+// buildMetrics validates the flags and builds a payload holding a single
+// metric with a single data point.
+func buildMetrics(flags *sendFlags, now time.Time) (pmetric.Metrics, error) {
+	metricType := strings.ToLower(flags.Type)
+	switch metricType {
+	case "gauge", "sum", "histogram":
+	default:
+		return pmetric.Metrics{}, fmt.Errorf("unknown metric type %q (valid: gauge, sum, histogram)", flags.Type)
+	}
+
+	temporality, err := parseTemporality(flags.Temporality)
+	if err != nil {
+		return pmetric.Metrics{}, err
+	}
+	if metricType != "sum" && flags.NonMonotonic {
+		return pmetric.Metrics{}, fmt.Errorf("--non-monotonic only applies to --type sum")
+	}
+	if metricType != "histogram" && len(flags.BucketBounds) > 0 {
+		return pmetric.Metrics{}, fmt.Errorf("--bucket-bounds only applies to --type histogram")
+	}
+	if metricType != "histogram" && len(flags.Values) != 1 {
+		return pmetric.Metrics{}, fmt.Errorf("a %s takes exactly one --value; got %d", metricType, len(flags.Values))
+	}
+
+	attrs, err := otlp.ParseKeyValuePairs(flags.Attributes)
+	if err != nil {
+		return pmetric.Metrics{}, fmt.Errorf("invalid attribute: %w", err)
+	}
+	resourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return pmetric.Metrics{}, fmt.Errorf("invalid resource attribute: %w", err)
+	}
+	scopeAttrs, err := otlp.ParseKeyValuePairs(flags.ScopeAttributes)
+	if err != nil {
+		return pmetric.Metrics{}, fmt.Errorf("invalid scope attribute: %w", err)
+	}
+
+	timestamp := now
+	if flags.Time != "" {
+		timestamp, err = time.Parse(time.RFC3339Nano, flags.Time)
+		if err != nil {
+			return pmetric.Metrics{}, fmt.Errorf("invalid time format (expected RFC3339): %w", err)
+		}
+	}
+	startTime := timestamp
+	if flags.StartTime != "" {
+		if metricType == "gauge" {
+			return pmetric.Metrics{}, fmt.Errorf("--start-time only applies to --type sum and --type histogram")
+		}
+		startTime, err = time.Parse(time.RFC3339Nano, flags.StartTime)
+		if err != nil {
+			return pmetric.Metrics{}, fmt.Errorf("invalid start-time format (expected RFC3339): %w", err)
+		}
+		if startTime.After(timestamp) {
+			return pmetric.Metrics{}, fmt.Errorf("--start-time must not be after --time")
+		}
+	}
+
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	for k, v := range resourceAttrs {
+		rm.Resource().Attributes().PutStr(k, v)
+	}
+
+	sm := rm.ScopeMetrics().AppendEmpty()
+	scope := sm.Scope()
+	scope.SetName(flags.ScopeName)
+	scope.SetVersion(flags.ScopeVersion)
+	for k, v := range scopeAttrs {
+		scope.Attributes().PutStr(k, v)
+	}
+
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(flags.Name)
+	m.SetUnit(flags.Unit)
+	m.SetDescription(flags.Description)
+
+	var dpAttrs pcommon.Map
+	var exemplars pmetric.ExemplarSlice
+	switch metricType {
+	case "gauge":
+		dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+		if err := setNumberValue(dp, flags.Values[0]); err != nil {
+			return pmetric.Metrics{}, err
+		}
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		dpAttrs = dp.Attributes()
+		exemplars = dp.Exemplars()
+	case "sum":
+		sum := m.SetEmptySum()
+		sum.SetAggregationTemporality(temporality)
+		sum.SetIsMonotonic(!flags.NonMonotonic)
+		dp := sum.DataPoints().AppendEmpty()
+		if err := setNumberValue(dp, flags.Values[0]); err != nil {
+			return pmetric.Metrics{}, err
+		}
+		negative := dp.IntValue() < 0 || dp.DoubleValue() < 0
+		if !flags.NonMonotonic && negative {
+			return pmetric.Metrics{}, fmt.Errorf("a monotonic sum cannot be negative\nHint: pass --non-monotonic for a value that can decrease")
+		}
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		dpAttrs = dp.Attributes()
+		exemplars = dp.Exemplars()
+	case "histogram":
+		hist := m.SetEmptyHistogram()
+		hist.SetAggregationTemporality(temporality)
+		dp := hist.DataPoints().AppendEmpty()
+		if err := setHistogramObservations(dp, flags.Values, flags.BucketBounds); err != nil {
+			return pmetric.Metrics{}, err
+		}
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+		dpAttrs = dp.Attributes()
+		exemplars = dp.Exemplars()
+	}
+	for k, v := range attrs {
+		dpAttrs.PutStr(k, v)
+	}
+
+	// A value recorded inside a sampled span, such as that of an enclosing
+	// `dash0 spans exec`, links to the span with an exemplar.
+	if parent, ok := otlp.TraceparentFromEnv(); ok && parent.Sampled {
+		for _, v := range flags.Values {
+			e := exemplars.AppendEmpty()
+			e.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil && metricType != "histogram" {
+				e.SetIntValue(i)
+			} else {
+				f, _ := parseFiniteFloat(v)
+				e.SetDoubleValue(f)
+			}
+			e.SetTraceID(parent.TraceID)
+			e.SetSpanID(parent.SpanID)
+		}
+	}
+
+	return metrics, nil
+}
+
+func parseTemporality(s string) (pmetric.AggregationTemporality, error) {
+	switch strings.ToLower(s) {
+	case "delta":
+		return pmetric.AggregationTemporalityDelta, nil
+	case "cumulative":
+		return pmetric.AggregationTemporalityCumulative, nil
+	default:
+		return pmetric.AggregationTemporalityUnspecified, fmt.Errorf("unknown temporality %q (valid: delta, cumulative)", s)
+	}
+}
+
+// setNumberValue stores v as an integer when it parses as one, and as a
+// double otherwise, so counts stay integers in Dash0.
+func setNumberValue(dp pmetric.NumberDataPoint, v string) error {
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		dp.SetIntValue(i)
+		return nil
+	}
+	f, err := parseFiniteFloat(v)
+	if err != nil {
+		return err
+	}
+	dp.SetDoubleValue(f)
+	return nil
+}
+
+// setHistogramObservations aggregates the observations into dp's count,
+// sum, min, max, and bucket counts.
+func setHistogramObservations(dp pmetric.HistogramDataPoint, values []string, bounds []float64) error {
+	if len(bounds) == 0 {
+		bounds = defaultHistogramBounds
+	}
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			return fmt.Errorf("--bucket-bounds must be strictly increasing")
+		}
+	}
+
+	counts := make([]uint64, len(bounds)+1)
+	var sum float64
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, raw := range values {
+		v, err := parseFiniteFloat(raw)
+		if err != nil {
+			return err
+		}
+		// Buckets are upper-inclusive: bucket i holds bounds[i-1] < v <= bounds[i].
+		i, _ := slices.BinarySearch(bounds, v)
+		counts[i]++
+		sum += v
+		minValue = math.Min(minValue, v)
+		maxValue = math.Max(maxValue, v)
+	}
+
+	dp.SetCount(uint64(len(values)))
+	dp.SetSum(sum)
+	dp.SetMin(minValue)
+	dp.SetMax(maxValue)
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(counts)
+	return nil
+}
+
+func parseFiniteFloat(v string) (float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid value %q: expected a number", v)
+	}
+	return f, nil
+}
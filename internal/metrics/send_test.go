@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+var sendTestNow = time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+
+func newMetricsSendCmd() *cobra.Command {
+	root := &cobra.Command{Use: "dash0"}
+	root.AddCommand(NewMetricsCmd())
+	return root
+}
+
+func TestSendRequiresNameAndValue(t *testing.T) {
+	root := newMetricsSendCmd()
+	root.SetArgs([]string{"metrics", "send", "--value", "1"})
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name")
+
+	root = newMetricsSendCmd()
+	root.SetArgs([]string{"metrics", "send", "--name", "ci.tests"})
+	err = root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "value")
+}
+
+func TestBuildMetrics_Gauge(t *testing.T) {
+	md, err := buildMetrics(&sendFlags{
+		Name:               "ci.build.duration",
+		Type:               "gauge",
+		Values:             []string{"312.4"},
+		Unit:               "s",
+		Temporality:        "delta",
+		Attributes:         []string{"ci.pipeline=main"},
+		ResourceAttributes: []string{"service.name=checkout"},
+		ScopeName:          "dash0-cli",
+		ScopeVersion:       "1.0.0",
+	}, sendTestNow)
+	require.NoError(t, err)
+
+	rm := md.ResourceMetrics().At(0)
+	serviceName, _ := rm.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "checkout", serviceName.Str())
+	assert.Equal(t, "dash0-cli", rm.ScopeMetrics().At(0).Scope().Name())
+
+	m := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "ci.build.duration", m.Name())
+	assert.Equal(t, "s", m.Unit())
+	require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+	dp := m.Gauge().DataPoints().At(0)
+	assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+	assert.Equal(t, 312.4, dp.DoubleValue())
+	assert.Equal(t, sendTestNow, dp.Timestamp().AsTime())
+	pipeline, _ := dp.Attributes().Get("ci.pipeline")
+	assert.Equal(t, "main", pipeline.Str())
+}
+
+func TestBuildMetrics_Sum(t *testing.T) {
+	md, err := buildMetrics(&sendFlags{
+		Name:        "ci.tests",
+		Type:        "sum",
+		Values:      []string{"1284"},
+		Temporality: "delta",
+		StartTime:   "2026-03-15T10:25:00Z",
+	}, sendTestNow)
+	require.NoError(t, err)
+
+	sum := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+	assert.True(t, sum.IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, sum.AggregationTemporality())
+	dp := sum.DataPoints().At(0)
+	assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+	assert.Equal(t, int64(1284), dp.IntValue())
+	assert.Equal(t, sendTestNow.Add(-5*time.Minute), dp.StartTimestamp().AsTime())
+}
+
+func TestBuildMetrics_Histogram(t *testing.T) {
+	md, err := buildMetrics(&sendFlags{
+		Name:         "ci.test_suite.duration",
+		Type:         "histogram",
+		Values:       []string{"1.2", "0.4", "7.9", "1", "12"},
+		Temporality:  "cumulative",
+		BucketBounds: []float64{0.5, 1, 2.5, 5, 10},
+	}, sendTestNow)
+	require.NoError(t, err)
+
+	hist := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, hist.AggregationTemporality())
+	dp := hist.DataPoints().At(0)
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.InDelta(t, 22.5, dp.Sum(), 1e-9)
+	assert.Equal(t, 0.4, dp.Min())
+	assert.Equal(t, 12.0, dp.Max())
+	assert.Equal(t, []float64{0.5, 1, 2.5, 5, 10}, dp.ExplicitBounds().AsRaw())
+	// 0.4 | 1 (upper-inclusive) | 1.2 | - | 7.9 | 12
+	assert.Equal(t, []uint64{1, 1, 1, 0, 1, 1}, dp.BucketCounts().AsRaw())
+}
+
+func TestBuildMetrics_HistogramDefaultBounds(t *testing.T) {
+	md, err := buildMetrics(&sendFlags{Name: "h", Type: "histogram", Values: []string{"3"}, Temporality: "delta"}, sendTestNow)
+	require.NoError(t, err)
+	dp := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, defaultHistogramBounds, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, uint64(1), dp.BucketCounts().At(1))
+}
+
+func TestBuildMetrics_ExemplarsFromTraceparent(t *testing.T) {
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	md, err := buildMetrics(&sendFlags{Name: "ci.tests", Type: "sum", Values: []string{"12"}, Temporality: "delta"}, sendTestNow)
+	require.NoError(t, err)
+	exemplars := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0).Exemplars()
+	require.Equal(t, 1, exemplars.Len())
+	assert.Equal(t, int64(12), exemplars.At(0).IntValue())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", exemplars.At(0).TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", exemplars.At(0).SpanID().String())
+
+	md, err = buildMetrics(&sendFlags{Name: "h", Type: "histogram", Values: []string{"3", "0.5"}, Temporality: "delta"}, sendTestNow)
+	require.NoError(t, err)
+	exemplars = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0).Exemplars()
+	require.Equal(t, 2, exemplars.Len())
+	assert.Equal(t, 3.0, exemplars.At(0).DoubleValue())
+
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	md, err = buildMetrics(&sendFlags{Name: "g", Type: "gauge", Values: []string{"1"}, Temporality: "delta"}, sendTestNow)
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).Exemplars().Len(), "an unsampled span is not recorded")
+}
+
+func TestBuildMetrics_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   sendFlags
+		wantErr string
+	}{
+		{"unknown type", sendFlags{Type: "counter", Values: []string{"1"}}, "unknown metric type"},
+		{"unknown temporality", sendFlags{Type: "sum", Values: []string{"1"}, Temporality: "monthly"}, "unknown temporality"},
+		{"gauge with two values", sendFlags{Type: "gauge", Values: []string{"1", "2"}}, "exactly one --value"},
+		{"not a number", sendFlags{Type: "gauge", Values: []string{"fast"}}, "invalid value"},
+		{"NaN", sendFlags{Type: "gauge", Values: []string{"NaN"}}, "invalid value"},
+		{"negative monotonic sum", sendFlags{Type: "sum", Values: []string{"-3"}}, "cannot be negative"},
+		{"non-monotonic gauge", sendFlags{Type: "gauge", Values: []string{"1"}, NonMonotonic: true}, "--non-monotonic"},
+		{"bounds on a sum", sendFlags{Type: "sum", Values: []string{"1"}, BucketBounds: []float64{1}}, "--bucket-bounds"},
+		{"decreasing bounds", sendFlags{Type: "histogram", Values: []string{"1"}, BucketBounds: []float64{5, 1}}, "strictly increasing"},
+		{"start time on a gauge", sendFlags{Type: "gauge", Values: []string{"1"}, StartTime: "2026-03-15T10:00:00Z"}, "--start-time"},
+		{"start after time", sendFlags{Type: "sum", Values: []string{"1"}, StartTime: "2026-03-15T11:00:00Z"}, "must not be after"},
+		{"invalid attribute", sendFlags{Type: "gauge", Values: []string{"1"}, Attributes: []string{"novalue"}}, "invalid attribute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.flags.Temporality == "" {
+				tt.flags.Temporality = "delta"
+			}
+			_, err := buildMetrics(&tt.flags, sendTestNow)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := buildMetrics(&sendFlags{Type: "sum", Values: []string{"-3"}, NonMonotonic: true, Temporality: "delta"}, sendTestNow)
+	assert.NoError(t, err, "a non-monotonic sum may be negative")
+}
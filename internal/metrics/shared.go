@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -19,6 +20,9 @@ import (
 	versionpkg "github.com/dash0hq/dash0-cli/internal/version"
 )
 
+// prometheusRequestTimeout bounds a request to the Prometheus API.
+const prometheusRequestTimeout = 30 * time.Second
+
 // QueryInstantResponse represents the response from the Prometheus instant query API.
 type QueryInstantResponse struct {
 	Status string `json:"status"`
@@ -138,7 +142,7 @@ func resolveMetricColumns(columns []string, format queryFormat) ([]query.ColumnD
 }
 
 // runInstantQuery executes an instant PromQL query against the Prometheus API.
-func runInstantQuery(apiURL, authToken, promql, evalTime string, dataset *string) (*QueryInstantResponse, error) {
+func runInstantQuery(ctx context.Context, httpClient *http.Client, apiURL, authToken, promql, evalTime string, dataset *string) (*QueryInstantResponse, error) {
 	parsedURL, err := url.Parse(apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid API URL: %w", err)
@@ -157,7 +161,7 @@ func runInstantQuery(apiURL, authToken, promql, evalTime string, dataset *string
 	}
 	parsedURL.RawQuery = params.Encode()
 
-	body, err := executePrometheusRequest(parsedURL.String(), authToken)
+	body, err := executePrometheusRequest(ctx, httpClient, parsedURL.String(), authToken)
 	if err != nil {
 		return nil, err
 	}
@@ -174,9 +178,12 @@ func runInstantQuery(apiURL, authToken, promql, evalTime string, dataset *string
 	return &response, nil
 }
 
-// executePrometheusRequest sends an authenticated GET request and returns the response body.
-func executePrometheusRequest(requestURL, authToken string) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+// executePrometheusRequest sends an authenticated GET request with httpClient and returns the response body.
+func executePrometheusRequest(ctx context.Context, httpClient *http.Client, requestURL, authToken string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, prometheusRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -184,8 +191,7 @@ func executePrometheusRequest(requestURL, authToken string) ([]byte, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", authToken))
 	req.Header.Set("User-Agent", versionpkg.UserAgent())
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
 	}
@@ -0,0 +1,68 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
+	"github.com/dash0hq/dash0-cli/internal/version"
+)
+
+// requestTimeout bounds each individual request to the authorization server.
+// Flows that span several requests are bounded separately by their callers.
+const requestTimeout = 30 * time.Second
+
+// Request sends a GET (form == nil) or a form-encoded POST to the
+// authorization server and decodes the JSON response body into out,
+// regardless of the status code: OAuth error responses are JSON as well.
+// The request uses the transport of ctx (see httptransport.FromContext).
+func Request(ctx context.Context, method, endpoint string, form url.Values, out any) (int, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(reqCtx, method, endpoint, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
+
+	httpClient, err := httptransport.Client(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Bound the body so a runaway server cannot exhaust memory.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// MetadataURL returns the URL of the authorization server metadata
+// (RFC 8414) of the Dash0 API at apiURL.
+func MetadataURL(apiURL string) string {
+	return strings.TrimSuffix(apiURL, "/") + "/.well-known/oauth-authorization-server"
+}
@@ -1,7 +1,8 @@
 // Package oauth holds OAuth helpers shared across the CLI: refresh-token
 // revocation used by `login`, `logout`, and `config profiles update
-// --oauth=false`; and sanitization of OAuth-server-supplied strings before
-// they reach a terminal.
+// --oauth=false`; raw requests to the authorization server used by the
+// device flow and the OTLP ingestion-token exchange; and sanitization of
+// OAuth-server-supplied strings before they reach a terminal.
 //
 // Why a separate package: `internal/login` already imports `internal/config`
 // for profile-hint helpers, so neither package can host shared OAuth code
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
 )
 
 // revokeTimeout bounds the revocation HTTP call. A slow or unresponsive
@@ -27,18 +29,25 @@ const revokeTimeout = 5 * time.Second
 // returns true on success (or no-op) and false on failure so callers can
 // optionally append a note to their success message. Callers rely on
 // this function returning promptly regardless of outcome.
-// No-ops (and returns true) when either argument is empty.
-func Revoke(apiURL, refreshToken string) (ok bool) {
+// No-ops (and returns true) when either argument is empty. ctx selects the
+// transport of the request; its cancellation does not cut the revocation
+// short.
+func Revoke(ctx context.Context, apiURL, refreshToken string) (ok bool) {
 	if refreshToken == "" || apiURL == "" {
 		return true
 	}
-	client, err := dash0api.NewOAuthClient(dash0api.WithApiUrl(apiURL))
+	httpClient, err := httptransport.Client(ctx, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to construct OAuth client to revoke refresh token: %v\n", err)
+		return false
+	}
+	client, err := dash0api.NewOAuthClient(dash0api.WithApiUrl(apiURL), dash0api.WithHTTPClient(httpClient))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to construct OAuth client to revoke refresh token: %v\n", err)
 		return false
 	}
 	defer func() { _ = client.Close(context.Background()) }()
-	ctx, cancel := context.WithTimeout(context.Background(), revokeTimeout)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revokeTimeout)
 	defer cancel()
 	hint := dash0api.OAuthTokenTypeRefreshToken
 	if err := client.RevokeToken(ctx, &dash0api.OAuthRevocationRequest{
@@ -0,0 +1,75 @@
+package otlp
+
+import (
+	"os"
+	"strings"
+)
+
+// CIResourceAttributes returns resource attributes that describe the CI
+// pipeline run the CLI executes in, following the OpenTelemetry `cicd.*`
+// and `vcs.*` semantic conventions. GitHub Actions, GitLab CI, and Jenkins
+// are recognized by their environment variables; outside of CI, or on other
+// CI systems, the result is empty.
+func CIResourceAttributes() map[string]string {
+	return ciResourceAttributes(os.Getenv)
+}
+
+func ciResourceAttributes(getenv func(string) string) map[string]string {
+	attrs := map[string]string{}
+	put := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			attrs[key] = value
+		}
+	}
+
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		repoURL := ""
+		if server, repo := getenv("GITHUB_SERVER_URL"), getenv("GITHUB_REPOSITORY"); server != "" && repo != "" {
+			repoURL = strings.TrimRight(server, "/") + "/" + repo
+		}
+		put("vcs.provider.name", "github")
+		put("cicd.pipeline.name", getenv("GITHUB_WORKFLOW"))
+		put("cicd.pipeline.run.id", getenv("GITHUB_RUN_ID"))
+		if repoURL != "" && getenv("GITHUB_RUN_ID") != "" {
+			put("cicd.pipeline.run.url.full", repoURL+"/actions/runs/"+getenv("GITHUB_RUN_ID"))
+		}
+		put("cicd.pipeline.task.name", getenv("GITHUB_JOB"))
+		put("vcs.repository.url.full", repoURL)
+		put("vcs.ref.head.revision", getenv("GITHUB_SHA"))
+		// GITHUB_HEAD_REF is only set for pull requests, where GITHUB_REF_NAME
+		// is the merge ref rather than the branch.
+		put("vcs.ref.head.name", firstNonEmpty(getenv("GITHUB_HEAD_REF"), getenv("GITHUB_REF_NAME")))
+
+	case getenv("GITLAB_CI") == "true":
+		put("vcs.provider.name", "gitlab")
+		put("cicd.pipeline.name", firstNonEmpty(getenv("CI_PIPELINE_NAME"), getenv("CI_PROJECT_PATH")))
+		put("cicd.pipeline.run.id", getenv("CI_PIPELINE_ID"))
+		put("cicd.pipeline.run.url.full", getenv("CI_PIPELINE_URL"))
+		put("cicd.pipeline.task.name", getenv("CI_JOB_NAME"))
+		put("cicd.pipeline.task.run.id", getenv("CI_JOB_ID"))
+		put("cicd.pipeline.task.run.url.full", getenv("CI_JOB_URL"))
+		put("vcs.repository.url.full", getenv("CI_PROJECT_URL"))
+		put("vcs.ref.head.revision", getenv("CI_COMMIT_SHA"))
+		put("vcs.ref.head.name", firstNonEmpty(getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"), getenv("CI_COMMIT_REF_NAME")))
+
+	case getenv("JENKINS_URL") != "":
+		put("cicd.pipeline.name", getenv("JOB_NAME"))
+		put("cicd.pipeline.run.id", getenv("BUILD_NUMBER"))
+		put("cicd.pipeline.run.url.full", getenv("BUILD_URL"))
+		put("cicd.pipeline.task.name", getenv("STAGE_NAME"))
+		put("vcs.repository.url.full", getenv("GIT_URL"))
+		put("vcs.ref.head.revision", getenv("GIT_COMMIT"))
+		put("vcs.ref.head.name", firstNonEmpty(getenv("CHANGE_BRANCH"), getenv("BRANCH_NAME"), getenv("GIT_BRANCH")))
+	}
+	return attrs
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
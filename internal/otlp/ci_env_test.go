@@ -0,0 +1,85 @@
+package otlp
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestCIResourceAttributes(t *testing.T) {
+	cases := map[string]struct {
+		env  map[string]string
+		want map[string]string
+	}{
+		"github pull request": {
+			env: map[string]string{
+				"GITHUB_ACTIONS":    "true",
+				"GITHUB_SERVER_URL": "https://github.com",
+				"GITHUB_REPOSITORY": "acme/shop",
+				"GITHUB_WORKFLOW":   "CI",
+				"GITHUB_RUN_ID":     "1234",
+				"GITHUB_JOB":        "test",
+				"GITHUB_SHA":        "4f1c2e",
+				"GITHUB_HEAD_REF":   "feature/cart",
+				"GITHUB_REF_NAME":   "42/merge",
+			},
+			want: map[string]string{
+				"vcs.provider.name":          "github",
+				"cicd.pipeline.name":         "CI",
+				"cicd.pipeline.run.id":       "1234",
+				"cicd.pipeline.run.url.full": "https://github.com/acme/shop/actions/runs/1234",
+				"cicd.pipeline.task.name":    "test",
+				"vcs.repository.url.full":    "https://github.com/acme/shop",
+				"vcs.ref.head.revision":      "4f1c2e",
+				"vcs.ref.head.name":          "feature/cart",
+			},
+		},
+		"gitlab": {
+			env: map[string]string{
+				"GITLAB_CI":          "true",
+				"CI_PROJECT_PATH":    "acme/shop",
+				"CI_PIPELINE_ID":     "77",
+				"CI_PIPELINE_URL":    "https://gitlab.com/acme/shop/-/pipelines/77",
+				"CI_JOB_NAME":        "unit",
+				"CI_JOB_ID":          "991",
+				"CI_COMMIT_REF_NAME": "main",
+			},
+			want: map[string]string{
+				"vcs.provider.name":          "gitlab",
+				"cicd.pipeline.name":         "acme/shop",
+				"cicd.pipeline.run.id":       "77",
+				"cicd.pipeline.run.url.full": "https://gitlab.com/acme/shop/-/pipelines/77",
+				"cicd.pipeline.task.name":    "unit",
+				"cicd.pipeline.task.run.id":  "991",
+				"vcs.ref.head.name":          "main",
+			},
+		},
+		"jenkins": {
+			env: map[string]string{
+				"JENKINS_URL":  "https://ci.example.com/",
+				"JOB_NAME":     "shop/main",
+				"BUILD_NUMBER": "12",
+				"GIT_COMMIT":   "4f1c2e",
+				"GIT_BRANCH":   "origin/main",
+				"BRANCH_NAME":  "main",
+			},
+			want: map[string]string{
+				"cicd.pipeline.name":    "shop/main",
+				"cicd.pipeline.run.id":  "12",
+				"vcs.ref.head.revision": "4f1c2e",
+				"vcs.ref.head.name":     "main",
+			},
+		},
+		"no CI": {
+			env:  map[string]string{"HOME": "/home/dev"},
+			want: map[string]string{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ciResourceAttributes(func(key string) string { return tc.env[key] })
+			if !maps.Equal(got, tc.want) {
+				t.Errorf("ciResourceAttributes() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package otlp
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // OtlpLogSeverityRange represents the OpenTelemetry log severity ranges.
 // Each range covers a band of four severity numbers as defined by the
@@ -39,3 +42,28 @@ func SeverityNumberToRange(n int32) string {
 		return fmt.Sprintf("SEVERITY_%d", n)
 	}
 }
+
+// SeverityTextToNumber maps a level name as emitted by common logging
+// libraries (case-insensitive, e.g. "warn", "WARNING", "err") to the first
+// OTel severity number of the matching range. It returns false for names it
+// does not recognize.
+func SeverityTextToNumber(s string) (int32, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace", "finest", "finer", "verbose":
+		return 1, true
+	case "debug", "dbg", "fine":
+		return 5, true
+	case "info", "information", "informational":
+		return 9, true
+	case "notice":
+		return 10, true
+	case "warn", "warning":
+		return 13, true
+	case "error", "err":
+		return 17, true
+	case "fatal", "critical", "crit", "alert", "emerg", "emergency", "panic":
+		return 21, true
+	default:
+		return 0, false
+	}
+}
@@ -4,7 +4,8 @@ import "github.com/spf13/cobra"
 
 // NewOtlpCmd creates the otlp parent command. The proxy subcommand exposes a
 // local OTLP forwarder that brokers traffic from local OpenTelemetry SDKs to
-// the active Dash0 profile.
+// the active Dash0 profile; send ships a recorded payload and generate
+// synthetic telemetry.
 func NewOtlpCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "otlp",
@@ -14,10 +15,16 @@ func NewOtlpCmd() *cobra.Command {
 The proxy subcommand exposes the standard OTLP/HTTP and OTLP/gRPC endpoints
 on the loopback interface, brokers credentials from the active Dash0
 profile, and forwards inbound telemetry to Dash0. It is a local-dev
-shortcut, not a replacement for the OpenTelemetry Collector.`,
+shortcut, not a replacement for the OpenTelemetry Collector.
+
+The send subcommand sends a recorded OTLP/JSON or OTLP/protobuf payload,
+such as a test fixture or the output of the collector's file exporter. The
+generate subcommand sends synthetic telemetry of simulated services.`,
 	}
 
 	cmd.AddCommand(newProxyCmd())
+	cmd.AddCommand(newSendCmd())
+	cmd.AddCommand(newGenerateCmd())
 
 	return cmd
 }
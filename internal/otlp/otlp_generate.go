@@ -0,0 +1,261 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/experimental"
+	"github.com/spf13/cobra"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// generateInterval is how often `otlp generate` sends the telemetry of the
+// interval that just ended.
+const generateInterval = 1 * time.Second
+
+type generateFlags struct {
+	Scenario      string
+	Services      int
+	Rate          string
+	Duration      time.Duration
+	Seed          uint64
+	PrintScenario bool
+
+	Traces  bool
+	Logs    bool
+	Metrics bool
+
+	OtlpUrl            string
+	AuthToken          string
+	Dataset            string
+	ResourceAttributes []string
+}
+
+// newGenerateCmd creates the experimental `dash0 otlp generate` command.
+func newGenerateCmd() *cobra.Command {
+	flags := &generateFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "[experimental] Send synthetic traces, logs, and metrics to Dash0",
+		Long: `Generate realistic telemetry of a set of services and send it to Dash0, to
+build dashboards and alerts before the services exist or to load-test them.
+
+Each generated trace enters the system at one endpoint and follows the calls
+between services, with a server span per endpoint and a client span per
+call. Every server span has a log record with its trace context, and the
+durations of the server spans feed the http.server.request.duration
+histogram of each service (rate, errors, and duration).
+
+The services, their endpoints, the calls between them, error rates, and
+latency distributions come from a scenario YAML file (--scenario). Without
+one, a built-in scenario of --services services is used; print it with
+--print-scenario to start your own from it.
+
+Pass --traces, --logs, or --metrics to send only those signals; by default
+all three are sent. The command runs for --duration, or until interrupted.`,
+		Example: `  # 50 traces per second from 5 services for two minutes
+  dash0 -X otlp generate --traces --rate 50/s --services 5 --duration 2m
+
+  # All signals of your own scenario until Ctrl+C
+  dash0 -X otlp generate --scenario shop.yaml --rate 600/m
+
+  # Start a scenario file from the built-in one
+  dash0 -X otlp generate --services 4 --print-scenario > shop.yaml
+
+  # The same data on every run, tagged so it is easy to find and delete
+  dash0 -X otlp generate --seed 42 --duration 5m \
+      --resource-attribute deployment.environment.name=synthetic`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := experimental.RequireExperimental(cmd); err != nil {
+				return err
+			}
+			return runGenerate(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Scenario, "scenario", "", "Scenario YAML file with the services, endpoints, calls, error rates, and latencies to simulate")
+	cmd.Flags().IntVar(&flags.Services, "services", 3, "Number of services of the built-in scenario; cannot be combined with --scenario")
+	cmd.Flags().StringVar(&flags.Rate, "rate", "10/s", "Traces to generate per second ('50/s' or '50'), minute ('600/m'), or hour ('10/h')")
+	cmd.Flags().DurationVar(&flags.Duration, "duration", 0, "How long to generate telemetry (default: until interrupted)")
+	cmd.Flags().Uint64Var(&flags.Seed, "seed", 0, "Seed of the random generator, for the same telemetry on every run (default: random)")
+	cmd.Flags().BoolVar(&flags.PrintScenario, "print-scenario", false, "Print the built-in scenario as YAML and exit without sending anything")
+	cmd.Flags().BoolVar(&flags.Traces, "traces", false, "Send traces")
+	cmd.Flags().BoolVar(&flags.Logs, "logs", false, "Send log records correlated with the traces")
+	cmd.Flags().BoolVar(&flags.Metrics, "metrics", false, "Send http.server.request.duration histograms")
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset to send the telemetry to (overrides active profile)")
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil,
+		"Resource attribute as 'key=value' to set on every service (repeatable)")
+
+	return cmd
+}
+
+func runGenerate(cmd *cobra.Command, flags *generateFlags) error {
+	ctx := cmd.Context()
+
+	if flags.Scenario != "" && cmd.Flags().Changed("services") {
+		return fmt.Errorf("--services cannot be combined with --scenario; the scenario file lists the services")
+	}
+	if flags.Services < 1 {
+		return fmt.Errorf("--services must be at least 1")
+	}
+	rate, err := parseRate(flags.Rate)
+	if err != nil {
+		return err
+	}
+	if flags.Duration < 0 {
+		return fmt.Errorf("--duration must not be negative")
+	}
+	resourceAttrs, err := ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return fmt.Errorf("--resource-attribute: %w", err)
+	}
+
+	if flags.PrintScenario {
+		if flags.Scenario != "" {
+			return fmt.Errorf("--print-scenario prints the built-in scenario and cannot be combined with --scenario")
+		}
+		return printScenario(builtinScenario(flags.Services))
+	}
+	var s *scenario
+	if flags.Scenario != "" {
+		s, err = loadScenarioFile(flags.Scenario)
+	} else {
+		s, err = builtinScenario(flags.Services).compile()
+	}
+	if err != nil {
+		return err
+	}
+
+	// No signal flag means all signals.
+	if !flags.Traces && !flags.Logs && !flags.Metrics {
+		flags.Traces, flags.Logs, flags.Metrics = true, true, true
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	// The last interval is still sent after Ctrl+C.
+	sendCtx := context.WithoutCancel(ctx)
+	defer apiClient.Close(sendCtx)
+	dataset := client.ResolveDataset(ctx, flags.Dataset)
+
+	seed := flags.Seed
+	if !cmd.Flags().Changed("seed") {
+		seed = rand.Uint64()
+	}
+	start := time.Now()
+	g := newTelemetryGenerator(s, seed, resourceAttrs, start)
+
+	if flags.Duration > 0 {
+		fmt.Fprintf(os.Stderr, "Generating traces at %s from %d services for %s\n", flags.Rate, len(s.services), flags.Duration)
+	} else {
+		fmt.Fprintf(os.Stderr, "Generating traces at %s from %d services; press Ctrl+C to stop\n", flags.Rate, len(s.services))
+	}
+
+	var totals generateTotals
+	ticker := time.NewTicker(generateInterval)
+	defer ticker.Stop()
+	last := start
+	for done := false; !done; {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			now, done = time.Now(), true
+		case now = <-ticker.C:
+		}
+		if flags.Duration > 0 && !now.Before(start.Add(flags.Duration)) {
+			now, done = start.Add(flags.Duration), true
+		}
+
+		// Rounding down per interval would lose traces at low rates, so the
+		// count is derived from the total due since the start.
+		n := int(rate*now.Sub(start).Seconds()) - totals.traces
+		batch := g.generate(last, now.Sub(last), n)
+		last = now
+
+		if flags.Traces && batch.traceCount > 0 {
+			if err := apiClient.SendTraces(sendCtx, batch.traces, dataset); err != nil {
+				return fmt.Errorf("failed to send spans (%s): %w", totals, err)
+			}
+			totals.spans += batch.traces.SpanCount()
+		}
+		if flags.Logs && batch.traceCount > 0 {
+			if err := apiClient.SendLogs(sendCtx, batch.logs, dataset); err != nil {
+				return fmt.Errorf("failed to send log records (%s): %w", totals, err)
+			}
+			totals.logRecords += batch.logs.LogRecordCount()
+		}
+		if flags.Metrics {
+			metrics := g.metrics(now)
+			if metrics.DataPointCount() > 0 {
+				if err := apiClient.SendMetrics(sendCtx, metrics, dataset); err != nil {
+					return fmt.Errorf("failed to send metrics (%s): %w", totals, err)
+				}
+				totals.dataPoints += metrics.DataPointCount()
+			}
+		}
+		totals.traces += batch.traceCount
+	}
+
+	fmt.Printf("Generated %d traces: %s\n", totals.traces, totals)
+	return nil
+}
+
+// generateTotals counts what `otlp generate` has sent so far.
+type generateTotals struct {
+	traces     int
+	spans      int
+	logRecords int
+	dataPoints int
+}
+
+func (t generateTotals) String() string {
+	return fmt.Sprintf("%d spans, %d log records, and %d metric data points sent", t.spans, t.logRecords, t.dataPoints)
+}
+
+// parseRate parses a --rate value such as "50/s", "600/m", "10/h", or a
+// bare number of traces per second.
+func parseRate(s string) (float64, error) {
+	count, unit, hasUnit := strings.Cut(strings.TrimSpace(s), "/")
+	per := time.Second
+	if hasUnit {
+		switch strings.ToLower(strings.TrimSpace(unit)) {
+		case "s", "sec", "second":
+			per = time.Second
+		case "m", "min", "minute":
+			per = time.Minute
+		case "h", "hour":
+			per = time.Hour
+		default:
+			return 0, fmt.Errorf("invalid --rate %q; use a number of traces per second, minute, or hour, such as 50/s", s)
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil || n <= 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid --rate %q; use a number of traces per second, minute, or hour, such as 50/s", s)
+	}
+	return n / per.Seconds(), nil
+}
+
+// printScenario writes a scenario as YAML, so the built-in scenario can
+// serve as the start of a scenario file.
+func printScenario(f *generateScenarioFile) error {
+	data, err := sigsyaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode the scenario: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,313 @@
+package otlp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// Latency defaults of a scenario endpoint that does not set them.
+const (
+	defaultLatencyP50 = 20 * time.Millisecond
+	// defaultLatencyTail is the p99/p50 ratio used when only p50 is set.
+	defaultLatencyTail = 5
+)
+
+// z99 is the 99th percentile of the standard normal distribution. A
+// log-normal latency with median p50 and 99th percentile p99 has
+// sigma = ln(p99/p50) / z99.
+const z99 = 2.3263
+
+// generateScenarioFile is the schema of the `otlp generate --scenario`
+// file. Keys use the same kebab-case as the proxy's --config file.
+type generateScenarioFile struct {
+	Services []scenarioServiceFile `json:"services"`
+}
+
+type scenarioServiceFile struct {
+	Name               string                 `json:"name"`
+	ResourceAttributes map[string]any         `json:"resource-attributes,omitempty"`
+	Endpoints          []scenarioEndpointFile `json:"endpoints"`
+}
+
+type scenarioEndpointFile struct {
+	// Name is the HTTP method and route, such as "GET /cart/{id}".
+	Name string `json:"name"`
+	// Weight is the endpoint's share of the generated traces. Endpoints
+	// without a weight are reached only through the calls of others.
+	Weight    float64             `json:"weight,omitempty"`
+	Latency   scenarioLatencyFile `json:"latency,omitempty"`
+	ErrorRate float64             `json:"error-rate,omitempty"`
+	Calls     []scenarioCallFile  `json:"calls,omitempty"`
+}
+
+type scenarioLatencyFile struct {
+	P50 string `json:"p50,omitempty"`
+	P99 string `json:"p99,omitempty"`
+}
+
+type scenarioCallFile struct {
+	Service  string `json:"service"`
+	Endpoint string `json:"endpoint"`
+}
+
+// scenario is a validated scenario file with its calls resolved.
+type scenario struct {
+	services    []*scenarioService
+	entrypoints []*scenarioEndpoint
+	totalWeight float64
+}
+
+type scenarioService struct {
+	name               string
+	resourceAttributes map[string]string
+	endpoints          []*scenarioEndpoint
+}
+
+type scenarioEndpoint struct {
+	service *scenarioService
+	name    string
+	method  string
+	route   string
+	weight  float64
+	// mu and sigma describe the log-normal latency in nanoseconds.
+	mu        float64
+	sigma     float64
+	errorRate float64
+	calls     []*scenarioEndpoint
+}
+
+// loadScenarioFile reads and validates a --scenario file. Unknown keys are
+// rejected so a typo does not silently leave a setting at its default.
+func loadScenarioFile(path string) (*scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+	s, err := parseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func parseScenario(data []byte) (*scenario, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("the scenario has no services")
+	}
+	file := &generateScenarioFile{}
+	if err := sigsyaml.UnmarshalStrict(data, file); err != nil {
+		return nil, err
+	}
+	return file.compile()
+}
+
+// compile validates the file and resolves the calls between endpoints.
+func (f *generateScenarioFile) compile() (*scenario, error) {
+	if len(f.Services) == 0 {
+		return nil, fmt.Errorf("the scenario has no services")
+	}
+
+	s := &scenario{}
+	endpoints := map[string]*scenarioEndpoint{}
+	for i, svcFile := range f.Services {
+		if svcFile.Name == "" {
+			return nil, fmt.Errorf("service %d has no name", i+1)
+		}
+		for _, other := range s.services {
+			if other.name == svcFile.Name {
+				return nil, fmt.Errorf("service %q is defined twice", svcFile.Name)
+			}
+		}
+		if len(svcFile.Endpoints) == 0 {
+			return nil, fmt.Errorf("service %q has no endpoints", svcFile.Name)
+		}
+		// Attribute values become strings, as in the proxy's --config file.
+		pairs, err := attributePairs(svcFile.ResourceAttributes)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: resource-attributes: %w", svcFile.Name, err)
+		}
+		resourceAttrs, err := ParseKeyValuePairs(pairs)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: resource-attributes: %w", svcFile.Name, err)
+		}
+		svc := &scenarioService{name: svcFile.Name, resourceAttributes: resourceAttrs}
+		for _, epFile := range svcFile.Endpoints {
+			ep, err := compileEndpoint(svc, epFile)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", svc.name, err)
+			}
+			key := endpointKey(svc.name, ep.name)
+			if _, exists := endpoints[key]; exists {
+				return nil, fmt.Errorf("service %q: endpoint %q is defined twice", svc.name, ep.name)
+			}
+			endpoints[key] = ep
+			svc.endpoints = append(svc.endpoints, ep)
+			if ep.weight > 0 {
+				s.entrypoints = append(s.entrypoints, ep)
+				s.totalWeight += ep.weight
+			}
+		}
+		s.services = append(s.services, svc)
+	}
+	if len(s.entrypoints) == 0 {
+		return nil, fmt.Errorf("no endpoint has a weight; set weight on the endpoints that receive external traffic")
+	}
+
+	for i, svcFile := range f.Services {
+		for j, epFile := range svcFile.Endpoints {
+			ep := s.services[i].endpoints[j]
+			for _, call := range epFile.Calls {
+				target, ok := endpoints[endpointKey(call.Service, call.Endpoint)]
+				if !ok {
+					return nil, fmt.Errorf("service %q: endpoint %q calls unknown endpoint %q of service %q",
+						svcFile.Name, ep.name, call.Endpoint, call.Service)
+				}
+				ep.calls = append(ep.calls, target)
+			}
+		}
+	}
+	for _, svc := range s.services {
+		for _, ep := range svc.endpoints {
+			if err := checkCallCycle(ep, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+func compileEndpoint(svc *scenarioService, f scenarioEndpointFile) (*scenarioEndpoint, error) {
+	method, route, ok := strings.Cut(strings.TrimSpace(f.Name), " ")
+	route = strings.TrimSpace(route)
+	if !ok || method == "" || !strings.HasPrefix(route, "/") {
+		return nil, fmt.Errorf("endpoint %q: name must be an HTTP method and route, such as \"GET /cart/{id}\"", f.Name)
+	}
+	ep := &scenarioEndpoint{
+		service:   svc,
+		name:      strings.ToUpper(method) + " " + route,
+		method:    strings.ToUpper(method),
+		route:     route,
+		weight:    f.Weight,
+		errorRate: f.ErrorRate,
+	}
+	if f.Weight < 0 {
+		return nil, fmt.Errorf("endpoint %q: weight must not be negative", ep.name)
+	}
+	if f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return nil, fmt.Errorf("endpoint %q: error-rate must be between 0 and 1", ep.name)
+	}
+
+	p50 := defaultLatencyP50
+	if f.Latency.P50 != "" {
+		d, err := time.ParseDuration(f.Latency.P50)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("endpoint %q: invalid latency p50 %q", ep.name, f.Latency.P50)
+		}
+		p50 = d
+	}
+	p99 := p50 * defaultLatencyTail
+	if f.Latency.P99 != "" {
+		d, err := time.ParseDuration(f.Latency.P99)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("endpoint %q: invalid latency p99 %q", ep.name, f.Latency.P99)
+		}
+		if d < p50 {
+			return nil, fmt.Errorf("endpoint %q: latency p99 must not be less than p50", ep.name)
+		}
+		p99 = d
+	}
+	ep.mu = math.Log(float64(p50))
+	ep.sigma = math.Log(float64(p99)/float64(p50)) / z99
+	return ep, nil
+}
+
+func endpointKey(service, endpoint string) string {
+	method, route, _ := strings.Cut(strings.TrimSpace(endpoint), " ")
+	return service + "\x00" + strings.ToUpper(method) + " " + strings.TrimSpace(route)
+}
+
+// checkCallCycle returns an error if ep can reach itself through its calls;
+// a cycle would make every trace through it infinitely deep.
+func checkCallCycle(ep *scenarioEndpoint, path []*scenarioEndpoint) error {
+	for i, seen := range path {
+		if seen == ep {
+			names := make([]string, 0, len(path)-i+1)
+			for _, p := range path[i:] {
+				names = append(names, p.service.name+" "+p.name)
+			}
+			names = append(names, ep.service.name+" "+ep.name)
+			return fmt.Errorf("calls form a cycle: %s", strings.Join(names, " → "))
+		}
+	}
+	path = append(path, ep)
+	for _, call := range ep.calls {
+		if err := checkCallCycle(call, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// builtinServiceNames names the services of the built-in scenario; further
+// services are numbered.
+var builtinServiceNames = []string{
+	"frontend", "checkout", "cart", "payment", "inventory",
+	"shipping", "users", "recommendations", "notifications", "search",
+}
+
+// builtinScenario returns the scenario used without --scenario: a frontend
+// with two entry points and n-1 backend services arranged as a tree, each
+// calling up to two others. The leaves are faster and fail more often.
+func builtinScenario(n int) *generateScenarioFile {
+	name := func(i int) string {
+		if i < len(builtinServiceNames) {
+			return builtinServiceNames[i]
+		}
+		return fmt.Sprintf("service-%d", i+1)
+	}
+
+	f := &generateScenarioFile{}
+	for i := 0; i < n; i++ {
+		svc := scenarioServiceFile{Name: name(i)}
+		var children []int
+		for _, c := range []int{2*i + 1, 2*i + 2} {
+			if c < n {
+				children = append(children, c)
+			}
+		}
+
+		for _, method := range []string{"GET", "POST"} {
+			ep := scenarioEndpointFile{
+				Name:      fmt.Sprintf("%s /api/%s", method, name(i)),
+				Latency:   scenarioLatencyFile{P50: "20ms", P99: "200ms"},
+				ErrorRate: 0.01,
+			}
+			if i == 0 {
+				// Browsing is three times as common as checking out.
+				ep.Name, ep.Weight = "GET /products/{id}", 3
+				if method == "POST" {
+					ep.Name, ep.Weight = "POST /checkout", 1
+				}
+			}
+			if len(children) == 0 {
+				ep.Latency = scenarioLatencyFile{P50: "5ms", P99: "80ms"}
+				ep.ErrorRate = 0.03
+			}
+			for _, c := range children {
+				ep.Calls = append(ep.Calls, scenarioCallFile{
+					Service:  name(c),
+					Endpoint: fmt.Sprintf("%s /api/%s", method, name(c)),
+				})
+			}
+			svc.Endpoints = append(svc.Endpoints, ep)
+		}
+		f.Services = append(f.Services, svc)
+	}
+	return f
+}
@@ -0,0 +1,148 @@
+package otlp
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+const shopScenario = `
+services:
+  - name: frontend
+    resource-attributes:
+      service.version: 1.4
+    endpoints:
+      - name: GET /products/{id}
+        weight: 3
+        latency: {p50: 40ms, p99: 400ms}
+        calls:
+          - service: catalog
+            endpoint: GET /items/{id}
+      - name: post /checkout
+        weight: 1
+        error-rate: 0.05
+  - name: catalog
+    endpoints:
+      - name: GET /items/{id}
+        latency: {p50: 5ms}
+`
+
+func TestParseScenario(t *testing.T) {
+	s, err := parseScenario([]byte(shopScenario))
+	if err != nil {
+		t.Fatalf("parseScenario: %v", err)
+	}
+	if len(s.services) != 2 || len(s.entrypoints) != 2 || s.totalWeight != 4 {
+		t.Fatalf("got %d services, %d entry points, total weight %v; want 2, 2, 4", len(s.services), len(s.entrypoints), s.totalWeight)
+	}
+	if got := s.services[0].resourceAttributes["service.version"]; got != "1.4" {
+		t.Errorf("service.version = %q; want 1.4", got)
+	}
+
+	products := s.services[0].endpoints[0]
+	if len(products.calls) != 1 || products.calls[0] != s.services[1].endpoints[0] {
+		t.Errorf("GET /products/{id} calls = %v; want the catalog endpoint", products.calls)
+	}
+	if median := time.Duration(math.Exp(products.mu)); median != 40*time.Millisecond {
+		t.Errorf("median latency = %v; want 40ms", median)
+	}
+	if p99 := time.Duration(math.Exp(products.mu + z99*products.sigma)); p99.Round(time.Millisecond) != 400*time.Millisecond {
+		t.Errorf("p99 latency = %v; want 400ms", p99)
+	}
+
+	checkout := s.services[0].endpoints[1]
+	if checkout.method != "POST" || checkout.route != "/checkout" || checkout.name != "POST /checkout" {
+		t.Errorf("checkout endpoint = %q (%s %s); want the method upper-cased", checkout.name, checkout.method, checkout.route)
+	}
+
+	items := s.services[1].endpoints[0]
+	if p99 := time.Duration(math.Exp(items.mu + z99*items.sigma)); p99.Round(time.Millisecond) != 25*time.Millisecond {
+		t.Errorf("default p99 = %v; want 5 times p50", p99)
+	}
+}
+
+func TestParseScenario_Invalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		scenario string
+		wantErr  string
+	}{
+		{"empty", "", "no services"},
+		{"unknown key", "services:\n- name: a\n  endpoint: []", "unknown field"},
+		{"no name", "services:\n- endpoints: [{name: GET /}]", "service 1 has no name"},
+		{"duplicate service", "services:\n- {name: a, endpoints: [{name: GET /, weight: 1}]}\n- {name: a, endpoints: [{name: GET /}]}", `service "a" is defined twice`},
+		{"no endpoints", "services:\n- name: a", "has no endpoints"},
+		{"not HTTP", "services:\n- {name: a, endpoints: [{name: process payment, weight: 1}]}", "must be an HTTP method and route"},
+		{"error rate", "services:\n- {name: a, endpoints: [{name: GET /, weight: 1, error-rate: 5}]}", "error-rate must be between 0 and 1"},
+		{"latency", "services:\n- {name: a, endpoints: [{name: GET /, weight: 1, latency: {p50: 1s, p99: 10ms}}]}", "p99 must not be less than p50"},
+		{"no weight", "services:\n- {name: a, endpoints: [{name: GET /}]}", "no endpoint has a weight"},
+		{"unknown call", "services:\n- {name: a, endpoints: [{name: GET /, weight: 1, calls: [{service: b, endpoint: GET /}]}]}", `calls unknown endpoint "GET /" of service "b"`},
+		{
+			"cycle",
+			"services:\n- {name: a, endpoints: [{name: GET /, weight: 1, calls: [{service: b, endpoint: GET /}]}]}\n- {name: b, endpoints: [{name: GET /, calls: [{service: a, endpoint: get /}]}]}",
+			"calls form a cycle: a GET / → b GET / → a GET /",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseScenario([]byte(tc.scenario))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v; want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuiltinScenario(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 12} {
+		f := builtinScenario(n)
+		s, err := f.compile()
+		if err != nil {
+			t.Fatalf("builtinScenario(%d): %v", n, err)
+		}
+		if len(s.services) != n {
+			t.Errorf("builtinScenario(%d) has %d services", n, len(s.services))
+		}
+
+		// The printed scenario must be a valid scenario file.
+		data, err := sigsyaml.Marshal(f)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if _, err := parseScenario(data); err != nil {
+			t.Errorf("printed builtinScenario(%d) does not parse: %v\n%s", n, err, data)
+		}
+	}
+
+	s, _ := builtinScenario(5).compile()
+	if got := s.services[4].name; got != "inventory" {
+		t.Errorf("fifth service = %q; want inventory", got)
+	}
+	if calls := s.services[1].endpoints[0].calls; len(calls) != 2 || calls[0].service.name != "payment" {
+		t.Errorf("checkout calls %d endpoints; want 2, starting with payment", len(calls))
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	cases := map[string]float64{
+		"50/s":    50,
+		"50":      50,
+		"600/m":   10,
+		"36/h":    0.01,
+		"2.5/sec": 2.5,
+	}
+	for in, want := range cases {
+		got, err := parseRate(in)
+		if err != nil || math.Abs(got-want) > 1e-9 {
+			t.Errorf("parseRate(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0/s", "-1", "fast", "5/d", "NaN", "Inf", "+Inf", "-Inf/m", "1e400"} {
+		if _, err := parseRate(in); err == nil {
+			t.Errorf("parseRate(%q) succeeded; want an error", in)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// Timing of the calls between services inside a generated trace.
+const (
+	// generateNetworkDelay separates a client span from the server span it
+	// calls, on the way in and on the way out.
+	generateNetworkDelay = 300 * time.Microsecond
+	// generateCallGap separates the start of a server span from its first
+	// call, and each call from the next.
+	generateCallGap = 200 * time.Microsecond
+)
+
+// durationBucketBounds are the explicit bucket boundaries, in seconds, that
+// the OpenTelemetry semantic conventions recommend for
+// http.server.request.duration.
+var durationBucketBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10}
+
+// telemetryGenerator turns a scenario into traces, logs correlated with the
+// traces, and RED metrics of the server spans. It is not safe for concurrent
+// use.
+type telemetryGenerator struct {
+	scenario      *scenario
+	rng           *rand.Rand
+	resourceAttrs map[string]string
+	// start is the start timestamp of the cumulative metrics.
+	start      time.Time
+	histograms map[histogramKey]*histogramState
+}
+
+// histogramKey identifies one http.server.request.duration series.
+type histogramKey struct {
+	endpoint   *scenarioEndpoint
+	statusCode int
+}
+
+type histogramState struct {
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+func newTelemetryGenerator(s *scenario, seed uint64, resourceAttrs map[string]string, start time.Time) *telemetryGenerator {
+	return &telemetryGenerator{
+		scenario:      s,
+		rng:           rand.New(rand.NewPCG(seed, seed>>1|1)),
+		resourceAttrs: resourceAttrs,
+		start:         start,
+		histograms:    map[histogramKey]*histogramState{},
+	}
+}
+
+// generatedBatch holds the traces and logs of one generate call. The
+// resources map each service to its span and log record slices, so that
+// every service appears once per batch.
+type generatedBatch struct {
+	traces     ptrace.Traces
+	logs       plog.Logs
+	traceCount int
+
+	spans   map[*scenarioService]ptrace.SpanSlice
+	records map[*scenarioService]plog.LogRecordSlice
+}
+
+// generate produces n traces that start at random times within
+// [from, from+window), each with one log record per server span.
+func (g *telemetryGenerator) generate(from time.Time, window time.Duration, n int) *generatedBatch {
+	b := &generatedBatch{
+		traces:  ptrace.NewTraces(),
+		logs:    plog.NewLogs(),
+		spans:   map[*scenarioService]ptrace.SpanSlice{},
+		records: map[*scenarioService]plog.LogRecordSlice{},
+	}
+	for i := 0; i < n; i++ {
+		start := from
+		if window > 0 {
+			start = from.Add(time.Duration(g.rng.Int64N(int64(window))))
+		}
+		var traceID pcommon.TraceID
+		g.fill(traceID[:])
+		g.serve(b, g.pickEntrypoint(), traceID, pcommon.SpanID{}, start)
+		b.traceCount++
+	}
+	return b
+}
+
+func (g *telemetryGenerator) pickEntrypoint() *scenarioEndpoint {
+	r := g.rng.Float64() * g.scenario.totalWeight
+	for _, ep := range g.scenario.entrypoints {
+		if r < ep.weight {
+			return ep
+		}
+		r -= ep.weight
+	}
+	return g.scenario.entrypoints[len(g.scenario.entrypoints)-1]
+}
+
+// serve adds the server span of ep starting at start, the client and server
+// spans of its calls, and its log record. It returns the end of the server
+// span and whether it failed. A failed call fails the caller as well.
+func (g *telemetryGenerator) serve(b *generatedBatch, ep *scenarioEndpoint, traceID pcommon.TraceID, parent pcommon.SpanID, start time.Time) (time.Time, bool) {
+	var spanID pcommon.SpanID
+	g.fill(spanID[:])
+	failed := g.rng.Float64() < ep.errorRate
+	causedFailure := failed
+
+	cursor := start.Add(generateCallGap)
+	for _, callee := range ep.calls {
+		clientStart := cursor
+		var clientID pcommon.SpanID
+		g.fill(clientID[:])
+		serverEnd, calleeFailed := g.serve(b, callee, traceID, clientID, clientStart.Add(generateNetworkDelay))
+		clientEnd := serverEnd.Add(generateNetworkDelay)
+
+		client := b.spanSlice(g, ep.service).AppendEmpty()
+		fillHTTPSpan(client, callee, traceID, clientID, spanID, clientStart, clientEnd, calleeFailed)
+		client.SetKind(ptrace.SpanKindClient)
+		client.Attributes().PutStr("server.address", callee.service.name)
+		client.Attributes().PutStr("url.full", "http://"+callee.service.name+callee.route)
+
+		failed = failed || calleeFailed
+		cursor = clientEnd.Add(generateCallGap)
+	}
+
+	end := start.Add(g.latency(ep))
+	if end.Before(cursor) {
+		end = cursor
+	}
+
+	server := b.spanSlice(g, ep.service).AppendEmpty()
+	fillHTTPSpan(server, ep, traceID, spanID, parent, start, end, failed)
+	server.SetKind(ptrace.SpanKindServer)
+	server.Attributes().PutStr("url.path", ep.route)
+	if causedFailure {
+		event := server.Events().AppendEmpty()
+		event.SetName("exception")
+		event.SetTimestamp(pcommon.NewTimestampFromTime(end))
+		event.Attributes().PutStr("exception.type", "SimulatedError")
+		event.Attributes().PutStr("exception.message", "simulated failure of "+ep.name)
+	}
+
+	g.addLogRecord(b, ep, traceID, spanID, end, end.Sub(start), failed, causedFailure)
+	g.recordDuration(ep, statusCode(failed), end.Sub(start))
+	return end, failed
+}
+
+// fillHTTPSpan sets the fields that client and server spans of an endpoint
+// share.
+func fillHTTPSpan(span ptrace.Span, ep *scenarioEndpoint, traceID pcommon.TraceID, spanID, parent pcommon.SpanID, start, end time.Time, failed bool) {
+	span.SetName(ep.name)
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parent)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+	span.Attributes().PutStr("http.request.method", ep.method)
+	span.Attributes().PutStr("http.route", ep.route)
+	span.Attributes().PutInt("http.response.status_code", int64(statusCode(failed)))
+	if failed {
+		span.Status().SetCode(ptrace.StatusCodeError)
+		span.Status().SetMessage(fmt.Sprintf("HTTP %d", statusCode(failed)))
+	}
+}
+
+func (g *telemetryGenerator) addLogRecord(b *generatedBatch, ep *scenarioEndpoint, traceID pcommon.TraceID, spanID pcommon.SpanID, at time.Time, took time.Duration, failed, causedFailure bool) {
+	lr := b.recordSlice(g, ep.service).AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(at))
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(at))
+	lr.SetTraceID(traceID)
+	lr.SetSpanID(spanID)
+	switch {
+	case causedFailure:
+		lr.SetSeverityNumber(plog.SeverityNumberError)
+		lr.SetSeverityText("ERROR")
+		lr.Body().SetStr(fmt.Sprintf("%s failed: simulated failure", ep.name))
+	case failed:
+		lr.SetSeverityNumber(plog.SeverityNumberWarn)
+		lr.SetSeverityText("WARN")
+		lr.Body().SetStr(fmt.Sprintf("%s failed: a downstream call failed", ep.name))
+	default:
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+		lr.SetSeverityText("INFO")
+		lr.Body().SetStr(fmt.Sprintf("%s completed in %s", ep.name, took.Round(time.Microsecond)))
+	}
+	lr.Attributes().PutStr("http.request.method", ep.method)
+	lr.Attributes().PutStr("http.route", ep.route)
+	lr.Attributes().PutInt("http.response.status_code", int64(statusCode(failed)))
+}
+
+func (g *telemetryGenerator) recordDuration(ep *scenarioEndpoint, code int, d time.Duration) {
+	key := histogramKey{endpoint: ep, statusCode: code}
+	h, ok := g.histograms[key]
+	if !ok {
+		h = &histogramState{bucketCounts: make([]uint64, len(durationBucketBounds)+1)}
+		g.histograms[key] = h
+	}
+	seconds := d.Seconds()
+	h.count++
+	h.sum += seconds
+	h.bucketCounts[sort.SearchFloat64s(durationBucketBounds, seconds)]++
+}
+
+// metrics returns the cumulative http.server.request.duration histograms of
+// all server spans generated so far, one data point per service, endpoint,
+// and status code.
+func (g *telemetryGenerator) metrics(now time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, svc := range g.scenario.services {
+		var dataPoints pmetric.HistogramDataPointSlice
+		hasMetric := false
+		for _, ep := range svc.endpoints {
+			for _, code := range []int{200, 500} {
+				h, ok := g.histograms[histogramKey{endpoint: ep, statusCode: code}]
+				if !ok {
+					continue
+				}
+				if !hasMetric {
+					hasMetric = true
+					rm := md.ResourceMetrics().AppendEmpty()
+					g.fillResource(rm.Resource(), svc)
+					sm := rm.ScopeMetrics().AppendEmpty()
+					sm.Scope().SetName(DefaultScopeName)
+					sm.Scope().SetVersion(DefaultScopeVersion())
+					m := sm.Metrics().AppendEmpty()
+					m.SetName("http.server.request.duration")
+					m.SetDescription("Duration of HTTP server requests.")
+					m.SetUnit("s")
+					histogram := m.SetEmptyHistogram()
+					histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+					dataPoints = histogram.DataPoints()
+				}
+				dp := dataPoints.AppendEmpty()
+				dp.SetStartTimestamp(pcommon.NewTimestampFromTime(g.start))
+				dp.SetTimestamp(pcommon.NewTimestampFromTime(now))
+				dp.SetCount(h.count)
+				dp.SetSum(h.sum)
+				dp.ExplicitBounds().FromRaw(durationBucketBounds)
+				dp.BucketCounts().FromRaw(h.bucketCounts)
+				dp.Attributes().PutStr("http.request.method", ep.method)
+				dp.Attributes().PutStr("http.route", ep.route)
+				dp.Attributes().PutInt("http.response.status_code", int64(code))
+			}
+		}
+	}
+	return md
+}
+
+func (b *generatedBatch) spanSlice(g *telemetryGenerator, svc *scenarioService) ptrace.SpanSlice {
+	spans, ok := b.spans[svc]
+	if !ok {
+		rs := b.traces.ResourceSpans().AppendEmpty()
+		g.fillResource(rs.Resource(), svc)
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName(DefaultScopeName)
+		ss.Scope().SetVersion(DefaultScopeVersion())
+		spans = ss.Spans()
+		b.spans[svc] = spans
+	}
+	return spans
+}
+
+func (b *generatedBatch) recordSlice(g *telemetryGenerator, svc *scenarioService) plog.LogRecordSlice {
+	records, ok := b.records[svc]
+	if !ok {
+		rl := b.logs.ResourceLogs().AppendEmpty()
+		g.fillResource(rl.Resource(), svc)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName(DefaultScopeName)
+		sl.Scope().SetVersion(DefaultScopeVersion())
+		records = sl.LogRecords()
+		b.records[svc] = records
+	}
+	return records
+}
+
+// fillResource sets service.name, the service's resource attributes from
+// the scenario, and the --resource-attribute flags, which win.
+func (g *telemetryGenerator) fillResource(resource pcommon.Resource, svc *scenarioService) {
+	attrs := resource.Attributes()
+	attrs.PutStr("service.name", svc.name)
+	for k, v := range svc.resourceAttributes {
+		attrs.PutStr(k, v)
+	}
+	for k, v := range g.resourceAttrs {
+		attrs.PutStr(k, v)
+	}
+}
+
+// latency samples the log-normal latency of ep.
+func (g *telemetryGenerator) latency(ep *scenarioEndpoint) time.Duration {
+	return time.Duration(math.Exp(ep.mu + ep.sigma*g.rng.NormFloat64()))
+}
+
+func (g *telemetryGenerator) fill(id []byte) {
+	for i := range id {
+		id[i] = byte(g.rng.Uint32())
+	}
+}
+
+func statusCode(failed bool) int {
+	if failed {
+		return 500
+	}
+	return 200
+}
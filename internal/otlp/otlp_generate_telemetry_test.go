@@ -0,0 +1,185 @@
+package otlp
+
+import (
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func newTestGenerator(t *testing.T, scenarioYAML string) *telemetryGenerator {
+	t.Helper()
+	s, err := parseScenario([]byte(scenarioYAML))
+	if err != nil {
+		t.Fatalf("parseScenario: %v", err)
+	}
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	return newTelemetryGenerator(s, 42, map[string]string{"deployment.environment.name": "synthetic"}, start)
+}
+
+func TestTelemetryGenerator_TraceShape(t *testing.T) {
+	g := newTestGenerator(t, `
+services:
+  - name: frontend
+    endpoints:
+      - name: GET /
+        weight: 1
+        latency: {p50: 1ms, p99: 2ms}
+        calls:
+          - {service: cart, endpoint: GET /cart}
+  - name: cart
+    endpoints:
+      - name: GET /cart
+        latency: {p50: 30ms, p99: 40ms}
+`)
+	from := g.start
+	b := g.generate(from, time.Second, 1)
+
+	if b.traceCount != 1 || b.traces.SpanCount() != 3 {
+		t.Fatalf("got %d traces with %d spans; want 1 with 3", b.traceCount, b.traces.SpanCount())
+	}
+	if b.traces.ResourceSpans().Len() != 2 {
+		t.Errorf("resources = %d; want one per service", b.traces.ResourceSpans().Len())
+	}
+
+	spans := map[string]ptrace.Span{}
+	for i := 0; i < b.traces.ResourceSpans().Len(); i++ {
+		rs := b.traces.ResourceSpans().At(i)
+		service, _ := rs.Resource().Attributes().Get("service.name")
+		env, _ := rs.Resource().Attributes().Get("deployment.environment.name")
+		if env.Str() != "synthetic" {
+			t.Errorf("service %s lacks the --resource-attribute", service.Str())
+		}
+		ss := rs.ScopeSpans().At(0).Spans()
+		for j := 0; j < ss.Len(); j++ {
+			spans[service.Str()+" "+ss.At(j).Kind().String()] = ss.At(j)
+		}
+	}
+	root, client, server := spans["frontend Server"], spans["frontend Client"], spans["cart Server"]
+	if !root.ParentSpanID().IsEmpty() {
+		t.Errorf("the entry span has a parent")
+	}
+	if client.ParentSpanID() != root.SpanID() || server.ParentSpanID() != client.SpanID() {
+		t.Errorf("spans are not chained: root %s, client %s → %s, server %s → %s",
+			root.SpanID(), client.SpanID(), client.ParentSpanID(), server.SpanID(), server.ParentSpanID())
+	}
+	if root.TraceID() != server.TraceID() || root.TraceID().IsEmpty() {
+		t.Errorf("spans do not share a trace ID")
+	}
+	if server.StartTimestamp() <= client.StartTimestamp() || server.EndTimestamp() >= client.EndTimestamp() ||
+		client.EndTimestamp() >= root.EndTimestamp() {
+		t.Errorf("the callee does not nest inside the caller")
+	}
+	if start := root.StartTimestamp().AsTime(); start.Before(from) || !start.Before(from.Add(time.Second)) {
+		t.Errorf("trace starts at %v; want within the window", start)
+	}
+	if got := root.EndTimestamp().AsTime().Sub(root.StartTimestamp().AsTime()); got < 30*time.Millisecond {
+		t.Errorf("root duration = %v; want it to cover the slower call", got)
+	}
+	if name, _ := client.Attributes().Get("server.address"); name.Str() != "cart" || client.Name() != "GET /cart" {
+		t.Errorf("client span %q calls %q; want GET /cart on cart", client.Name(), name.Str())
+	}
+
+	// One log record per server span, with its trace context.
+	if b.logs.LogRecordCount() != 2 {
+		t.Fatalf("log records = %d; want 2", b.logs.LogRecordCount())
+	}
+	for i := 0; i < b.logs.ResourceLogs().Len(); i++ {
+		lr := b.logs.ResourceLogs().At(i).ScopeLogs().At(0).LogRecords().At(0)
+		if lr.TraceID() != root.TraceID() || (lr.SpanID() != root.SpanID() && lr.SpanID() != server.SpanID()) {
+			t.Errorf("log record %q is not correlated with a server span", lr.Body().Str())
+		}
+	}
+}
+
+func TestTelemetryGenerator_ErrorsPropagate(t *testing.T) {
+	g := newTestGenerator(t, `
+services:
+  - name: frontend
+    endpoints:
+      - {name: GET /, weight: 1, calls: [{service: payment, endpoint: POST /pay}]}
+  - name: payment
+    endpoints:
+      - {name: POST /pay, error-rate: 1}
+`)
+	b := g.generate(g.start, time.Second, 1)
+	rss := b.traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		spans := rss.At(i).ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			span := spans.At(j)
+			if span.Status().Code() != ptrace.StatusCodeError {
+				t.Errorf("%s span %q has status %s; want Error", span.Kind(), span.Name(), span.Status().Code())
+			}
+			code, _ := span.Attributes().Get("http.response.status_code")
+			if code.Int() != 500 {
+				t.Errorf("%s span %q has status code %d; want 500", span.Kind(), span.Name(), code.Int())
+			}
+			wantEvents := 0
+			if span.Name() == "POST /pay" && span.Kind() == ptrace.SpanKindServer {
+				wantEvents = 1
+			}
+			if span.Events().Len() != wantEvents {
+				t.Errorf("%s span %q has %d exception events; want %d", span.Kind(), span.Name(), span.Events().Len(), wantEvents)
+			}
+		}
+	}
+}
+
+func TestTelemetryGenerator_CumulativeMetrics(t *testing.T) {
+	g := newTestGenerator(t, shopScenario)
+	g.generate(g.start, time.Second, 20)
+	now := g.start.Add(time.Second)
+	first := g.metrics(now)
+
+	var requests uint64
+	rms := first.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		m := rms.At(i).ScopeMetrics().At(0).Metrics().At(0)
+		if m.Name() != "http.server.request.duration" || m.Unit() != "s" {
+			t.Fatalf("metric = %s [%s]; want http.server.request.duration [s]", m.Name(), m.Unit())
+		}
+		dps := m.Histogram().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			if dp.StartTimestamp() != pcommon.NewTimestampFromTime(g.start) || dp.Timestamp() != pcommon.NewTimestampFromTime(now) {
+				t.Errorf("data point covers %v to %v; want the generator start to now", dp.StartTimestamp(), dp.Timestamp())
+			}
+			var inBuckets uint64
+			for k := 0; k < dp.BucketCounts().Len(); k++ {
+				inBuckets += dp.BucketCounts().At(k)
+			}
+			if inBuckets != dp.Count() {
+				t.Errorf("bucket counts add up to %d; want the count %d", inBuckets, dp.Count())
+			}
+			route, _ := dp.Attributes().Get("http.route")
+			if route.Str() != "/items/{id}" {
+				requests += dp.Count()
+			}
+		}
+	}
+	if requests != 20 {
+		t.Errorf("frontend requests = %d; want one per trace", requests)
+	}
+
+	g.generate(now, time.Second, 10)
+	before := totalHistogramCount(first)
+	if after := totalHistogramCount(g.metrics(now.Add(time.Second))); after < before+10 {
+		t.Errorf("server requests went from %d to %d after 10 more traces; want the histograms to be cumulative", before, after)
+	}
+}
+
+// totalHistogramCount sums the counts of all histogram data points in md.
+func totalHistogramCount(md pmetric.Metrics) uint64 {
+	var total uint64
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		dps := rms.At(i).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			total += dps.At(j).Count()
+		}
+	}
+	return total
+}
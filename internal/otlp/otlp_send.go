@@ -0,0 +1,719 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/experimental"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// maxSendResponseBytes caps how much of an export response is read. A
+// response carries at most a partial-success message.
+const maxSendResponseBytes = 64 * 1024
+
+type sendFlags struct {
+	File              string
+	Signal            string
+	RewriteTimestamps bool
+
+	OtlpUrl   string
+	AuthToken string
+	Dataset   string
+
+	// Decoration, with the same meaning as on `otlp proxy`.
+	ResourceAttributes []string
+	ScopeAttributes    []string
+	ScopeName          string
+	ScopeVersion       string
+	LogAttributes      []string
+	SpanAttributes     []string
+	MetricAttributes   []string
+}
+
+// newSendCmd creates the experimental `dash0 otlp send` command.
+func newSendCmd() *cobra.Command {
+	flags := &sendFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "send -f <file>",
+		Short: "[experimental] Send a recorded OTLP payload to Dash0",
+		Long: `Send a recorded OTLP/JSON or OTLP/protobuf payload to Dash0 as-is, for
+example the output of the collector's file exporter or a test fixture.
+
+The encoding is detected from the file extension (.json, .jsonl, .ndjson
+for JSON; .pb, .binpb for protobuf) and otherwise from the content. A JSON
+file may hold several payloads, one after the other, as the collector's
+file exporter writes them. The signal of each payload is detected from its
+content; pass --signal when a protobuf payload is ambiguous.
+
+Pass --rewrite-timestamps to move all timestamps so that the latest one is
+now, keeping their relative offsets, so that an old recording shows up in
+the current time range. The decoration flags of 'otlp proxy' apply to every
+payload.
+
+The command reports how many records Dash0 accepted and rejected, including
+rejections reported through an OTLP partial-success response, and exits
+non-zero if any record was rejected.`,
+		Example: `  # Send a trace fixture
+  dash0 -X otlp send -f fixtures/checkout-trace.json
+
+  # Replay a recording from the collector's file exporter as if it happened now
+  dash0 -X otlp send -f otel-export.jsonl --rewrite-timestamps
+
+  # Send a protobuf payload and tag it so it is easy to find
+  dash0 -X otlp send -f payload.pb --signal metrics \
+      --resource-attribute deployment.environment.name=replay
+
+  # Read the payload from stdin
+  cat payload.json | dash0 -X otlp send -f -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := experimental.RequireExperimental(cmd); err != nil {
+				return err
+			}
+			return runSend(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.File, "file", "f", "", "OTLP/JSON or OTLP/protobuf file to send; '-' reads from stdin")
+	cmd.Flags().StringVar(&flags.Signal, "signal", "", "Signal of the payload: logs, traces, or metrics (default: detected from the content)")
+	cmd.Flags().BoolVar(&flags.RewriteTimestamps, "rewrite-timestamps", false,
+		"Shift all timestamps so that the latest one is now, keeping their relative offsets")
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset to send the payload to (overrides active profile)")
+
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil,
+		"Resource attribute as 'key=value' to upsert into every payload (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.ScopeAttributes, "scope-attribute", nil,
+		"Instrumentation-scope attribute as 'key=value' to upsert into every payload (repeatable)")
+	cmd.Flags().StringVar(&flags.ScopeName, "scope-name", "",
+		"Instrumentation-scope name to set on every payload (default: preserve the recorded value)")
+	cmd.Flags().StringVar(&flags.ScopeVersion, "scope-version", "",
+		"Instrumentation-scope version to set on every payload (default: preserve the recorded value)")
+	cmd.Flags().StringArrayVar(&flags.LogAttributes, "log-attribute", nil,
+		"Attribute as 'key=value' to upsert on every log record (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.SpanAttributes, "span-attribute", nil,
+		"Attribute as 'key=value' to upsert on every span (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.MetricAttributes, "metric-attribute", nil,
+		"Attribute as 'key=value' to upsert on every metric data point (repeatable)")
+
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runSend(cmd *cobra.Command, flags *sendFlags) error {
+	ctx := cmd.Context()
+
+	var signal *Signal
+	if flags.Signal != "" {
+		s, err := parseSendSignal(flags.Signal)
+		if err != nil {
+			return err
+		}
+		signal = &s
+	}
+
+	// The proxy's decoration flags have the same names and meaning here.
+	decorator, err := buildDecorator(&proxyFlags{
+		ResourceAttributes: flags.ResourceAttributes,
+		ScopeAttributes:    flags.ScopeAttributes,
+		ScopeName:          flags.ScopeName,
+		ScopeVersion:       flags.ScopeVersion,
+		LogAttributes:      flags.LogAttributes,
+		SpanAttributes:     flags.SpanAttributes,
+		MetricAttributes:   flags.MetricAttributes,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := readSendFile(flags.File)
+	if err != nil {
+		return err
+	}
+
+	// Every payload is decoded before the first one is sent, so a broken
+	// file sends nothing.
+	payloads, err := decodePayloads(data, encodingFromFileName(flags.File), signal)
+	if err != nil {
+		return err
+	}
+	if flags.RewriteTimestamps {
+		rewriteTimestamps(payloads, time.Now())
+	}
+	for _, p := range payloads {
+		p.decorate(decorator)
+	}
+
+	cfg, err := client.NewRawOtlpConfig(ctx, flags.OtlpUrl, flags.AuthToken, flags.Dataset)
+	if err != nil {
+		return err
+	}
+
+	var results sendResults
+	for i, p := range payloads {
+		result, err := exportPayload(ctx, cfg, p)
+		if err != nil {
+			results.print()
+			return fmt.Errorf("failed to send payload %d of %d: %w", i+1, len(payloads), client.HandleAPIError(err))
+		}
+		results.add(p.signal, result)
+	}
+	results.print()
+
+	if sent, rejected := results.totals(); rejected > 0 {
+		return fmt.Errorf("%d of %d records were rejected", rejected, sent)
+	}
+	return nil
+}
+
+// parseSendSignal parses the --signal flag. "spans" is accepted as an alias
+// of "traces".
+func parseSendSignal(s string) (Signal, error) {
+	switch strings.ToLower(s) {
+	case "logs":
+		return SignalLogs, nil
+	case "traces", "spans":
+		return SignalSpans, nil
+	case "metrics":
+		return SignalMetrics, nil
+	default:
+		return 0, fmt.Errorf("unknown signal %q; valid values are logs, traces, and metrics", s)
+	}
+}
+
+func readSendFile(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload file: %w", err)
+	}
+	return data, nil
+}
+
+// payloadEncoding is the wire encoding of an OTLP payload file.
+type payloadEncoding int
+
+const (
+	encodingUnknown payloadEncoding = iota
+	encodingJSON
+	encodingProtobuf
+)
+
+// encodingFromFileName returns the encoding implied by the file extension,
+// or encodingUnknown when the content has to decide.
+func encodingFromFileName(path string) payloadEncoding {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson":
+		return encodingJSON
+	case ".pb", ".binpb", ".proto", ".protobuf":
+		return encodingProtobuf
+	default:
+		return encodingUnknown
+	}
+}
+
+// sendPayload is one decoded export request. Only the field matching signal
+// is set.
+type sendPayload struct {
+	signal  Signal
+	logs    plog.Logs
+	traces  ptrace.Traces
+	metrics pmetric.Metrics
+}
+
+// count returns the number of records in the payload: log records, spans,
+// or metric data points.
+func (p sendPayload) count() int {
+	switch p.signal {
+	case SignalLogs:
+		return p.logs.LogRecordCount()
+	case SignalSpans:
+		return p.traces.SpanCount()
+	default:
+		return p.metrics.DataPointCount()
+	}
+}
+
+func (p sendPayload) decorate(d *Decorator) {
+	switch p.signal {
+	case SignalLogs:
+		d.DecorateLogs(p.logs)
+	case SignalSpans:
+		d.DecorateTraces(p.traces)
+	default:
+		d.DecorateMetrics(p.metrics)
+	}
+}
+
+// decodePayloads decodes data in the given encoding. With encodingUnknown,
+// data that starts like JSON and decodes as JSON is JSON; anything else is
+// protobuf. A non-nil signal skips signal detection.
+func decodePayloads(data []byte, encoding payloadEncoding, signal *Signal) ([]sendPayload, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("the payload file is empty")
+	}
+	switch encoding {
+	case encodingJSON:
+		return decodeJSONPayloads(data, signal)
+	case encodingProtobuf:
+		p, err := decodeProtobufPayload(data, signal)
+		if err != nil {
+			return nil, err
+		}
+		return []sendPayload{p}, nil
+	}
+
+	// A protobuf export request starts with 0x0a (field 1, length-delimited),
+	// which is a newline, so only the first byte after whitespace is a hint.
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		payloads, jsonErr := decodeJSONPayloads(data, signal)
+		if jsonErr == nil {
+			return payloads, nil
+		}
+		if p, err := decodeProtobufPayload(data, signal); err == nil {
+			return []sendPayload{p}, nil
+		}
+		return nil, jsonErr
+	}
+	p, err := decodeProtobufPayload(data, signal)
+	if err != nil {
+		return nil, err
+	}
+	return []sendPayload{p}, nil
+}
+
+// decodeJSONPayloads decodes a stream of OTLP/JSON export requests.
+func decodeJSONPayloads(data []byte, signal *Signal) ([]sendPayload, error) {
+	var payloads []sendPayload
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for n := 1; ; n++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("payload %d: invalid JSON: %w", n, err)
+		}
+		p, err := decodeJSONPayload(raw, signal)
+		if err != nil {
+			return nil, fmt.Errorf("payload %d: %w", n, err)
+		}
+		payloads = append(payloads, p)
+	}
+	return payloads, nil
+}
+
+func decodeJSONPayload(raw json.RawMessage, signal *Signal) (sendPayload, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return sendPayload{}, fmt.Errorf("not an OTLP/JSON export request: %w", err)
+	}
+	var detected []Signal
+	for _, c := range []struct {
+		key    string
+		signal Signal
+	}{
+		{"resourceLogs", SignalLogs},
+		{"resourceSpans", SignalSpans},
+		{"resourceMetrics", SignalMetrics},
+	} {
+		if _, ok := fields[c.key]; ok {
+			detected = append(detected, c.signal)
+		}
+	}
+	if len(detected) != 1 {
+		return sendPayload{}, errors.New("not an OTLP/JSON export request; expected exactly one of resourceLogs, resourceSpans, or resourceMetrics")
+	}
+	if signal != nil && *signal != detected[0] {
+		return sendPayload{}, fmt.Errorf("payload holds %s, not %s", detected[0], *signal)
+	}
+
+	p := sendPayload{signal: detected[0]}
+	var err error
+	switch p.signal {
+	case SignalLogs:
+		p.logs, err = (&plog.JSONUnmarshaler{}).UnmarshalLogs(raw)
+	case SignalSpans:
+		p.traces, err = (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(raw)
+	default:
+		p.metrics, err = (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(raw)
+	}
+	if err != nil {
+		return sendPayload{}, fmt.Errorf("invalid OTLP/JSON %s payload: %w", p.signal, err)
+	}
+	return p, nil
+}
+
+// decodeProtobufPayload decodes a single OTLP/protobuf export request. The
+// three request types share their outer layout, so without --signal the
+// payload is decoded as each of them and the one that decodes cleanly into
+// plausible records wins.
+func decodeProtobufPayload(data []byte, signal *Signal) (sendPayload, error) {
+	signals := []Signal{SignalLogs, SignalSpans, SignalMetrics}
+	if signal != nil {
+		signals = []Signal{*signal}
+	}
+
+	var candidates []sendPayload
+	var lastErr error
+	for _, s := range signals {
+		p := sendPayload{signal: s}
+		var err error
+		switch s {
+		case SignalLogs:
+			p.logs, err = (&plog.ProtoUnmarshaler{}).UnmarshalLogs(data)
+		case SignalSpans:
+			p.traces, err = (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(data)
+		default:
+			p.metrics, err = (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(data)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if signal == nil && !p.plausible() {
+			continue
+		}
+		candidates = append(candidates, p)
+	}
+
+	switch {
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case len(candidates) > 1:
+		return sendPayload{}, errors.New("cannot tell the signal of the protobuf payload; pass --signal logs, traces, or metrics")
+	case signal != nil:
+		return sendPayload{}, fmt.Errorf("invalid OTLP/protobuf %s payload: %w", *signal, lastErr)
+	default:
+		return sendPayload{}, errors.New("the file is neither an OTLP/JSON nor an OTLP/protobuf export request")
+	}
+}
+
+// plausible reports whether a payload decoded under a guessed signal looks
+// like real telemetry: it has records, every span has IDs, and every metric
+// has a type.
+func (p sendPayload) plausible() bool {
+	if p.count() == 0 {
+		return false
+	}
+	switch p.signal {
+	case SignalSpans:
+		rss := p.traces.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			sss := rss.At(i).ScopeSpans()
+			for j := 0; j < sss.Len(); j++ {
+				spans := sss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					if spans.At(k).TraceID().IsEmpty() || spans.At(k).SpanID().IsEmpty() {
+						return false
+					}
+				}
+			}
+		}
+	case SignalMetrics:
+		rms := p.metrics.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			sms := rms.At(i).ScopeMetrics()
+			for j := 0; j < sms.Len(); j++ {
+				metrics := sms.At(j).Metrics()
+				for k := 0; k < metrics.Len(); k++ {
+					if metrics.At(k).Type() == pmetric.MetricTypeEmpty {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// rewriteTimestamps shifts every non-zero timestamp of all payloads by the
+// same amount, so that the latest one becomes now.
+func rewriteTimestamps(payloads []sendPayload, now time.Time) {
+	var latest pcommon.Timestamp
+	for _, p := range payloads {
+		p.visitTimestamps(func(ts pcommon.Timestamp) pcommon.Timestamp {
+			latest = max(latest, ts)
+			return ts
+		})
+	}
+	if latest == 0 {
+		return
+	}
+	delta := now.UnixNano() - int64(latest)
+	for _, p := range payloads {
+		p.visitTimestamps(func(ts pcommon.Timestamp) pcommon.Timestamp {
+			return pcommon.Timestamp(int64(ts) + delta)
+		})
+	}
+}
+
+// visitTimestamps replaces each non-zero timestamp in the payload with the
+// result of fn.
+func (p sendPayload) visitTimestamps(fn func(pcommon.Timestamp) pcommon.Timestamp) {
+	visit := func(get func() pcommon.Timestamp, set func(pcommon.Timestamp)) {
+		if ts := get(); ts != 0 {
+			set(fn(ts))
+		}
+	}
+
+	switch p.signal {
+	case SignalLogs:
+		rls := p.logs.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			sls := rls.At(i).ScopeLogs()
+			for j := 0; j < sls.Len(); j++ {
+				records := sls.At(j).LogRecords()
+				for k := 0; k < records.Len(); k++ {
+					lr := records.At(k)
+					visit(lr.Timestamp, lr.SetTimestamp)
+					visit(lr.ObservedTimestamp, lr.SetObservedTimestamp)
+				}
+			}
+		}
+	case SignalSpans:
+		rss := p.traces.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			sss := rss.At(i).ScopeSpans()
+			for j := 0; j < sss.Len(); j++ {
+				spans := sss.At(j).Spans()
+				for k := 0; k < spans.Len(); k++ {
+					span := spans.At(k)
+					visit(span.StartTimestamp, span.SetStartTimestamp)
+					visit(span.EndTimestamp, span.SetEndTimestamp)
+					events := span.Events()
+					for e := 0; e < events.Len(); e++ {
+						visit(events.At(e).Timestamp, events.At(e).SetTimestamp)
+					}
+				}
+			}
+		}
+	default:
+		rms := p.metrics.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			sms := rms.At(i).ScopeMetrics()
+			for j := 0; j < sms.Len(); j++ {
+				metrics := sms.At(j).Metrics()
+				for k := 0; k < metrics.Len(); k++ {
+					visitMetricTimestamps(metrics.At(k), visit)
+				}
+			}
+		}
+	}
+}
+
+func visitMetricTimestamps(m pmetric.Metric, visit func(func() pcommon.Timestamp, func(pcommon.Timestamp))) {
+	visitExemplars := func(exemplars pmetric.ExemplarSlice) {
+		for i := 0; i < exemplars.Len(); i++ {
+			visit(exemplars.At(i).Timestamp, exemplars.At(i).SetTimestamp)
+		}
+	}
+	visitNumberDataPoints := func(dps pmetric.NumberDataPointSlice) {
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			visit(dp.StartTimestamp, dp.SetStartTimestamp)
+			visit(dp.Timestamp, dp.SetTimestamp)
+			visitExemplars(dp.Exemplars())
+		}
+	}
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		visitNumberDataPoints(m.Gauge().DataPoints())
+	case pmetric.MetricTypeSum:
+		visitNumberDataPoints(m.Sum().DataPoints())
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			visit(dp.StartTimestamp, dp.SetStartTimestamp)
+			visit(dp.Timestamp, dp.SetTimestamp)
+			visitExemplars(dp.Exemplars())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			visit(dp.StartTimestamp, dp.SetStartTimestamp)
+			visit(dp.Timestamp, dp.SetTimestamp)
+			visitExemplars(dp.Exemplars())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			visit(dp.StartTimestamp, dp.SetStartTimestamp)
+			visit(dp.Timestamp, dp.SetTimestamp)
+		}
+	}
+}
+
+// exportResult is the outcome of one accepted export request.
+type exportResult struct {
+	sent     int
+	rejected int
+	// message is the partial-success error message, if any.
+	message string
+}
+
+// exportPayload posts p as OTLP/protobuf to the signal's path below the OTLP
+// URL and reads the partial-success part of the response. Non-2xx responses
+// are returned as *dash0api.APIError.
+func exportPayload(ctx context.Context, cfg *client.RawOtlpConfig, p sendPayload) (exportResult, error) {
+	var path string
+	var body []byte
+	var err error
+	switch p.signal {
+	case SignalLogs:
+		path = "/v1/logs"
+		body, err = plogotlp.NewExportRequestFromLogs(p.logs).MarshalProto()
+	case SignalSpans:
+		path = "/v1/traces"
+		body, err = ptraceotlp.NewExportRequestFromTraces(p.traces).MarshalProto()
+	default:
+		path = "/v1/metrics"
+		body, err = pmetricotlp.NewExportRequestFromMetrics(p.metrics).MarshalProto()
+	}
+	if err != nil {
+		return exportResult{}, fmt.Errorf("failed to encode %s: %w", p.signal, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.OtlpUrl+path, bytes.NewReader(body))
+	if err != nil {
+		return exportResult{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Authorization", "Bearer "+cfg.AuthToken)
+	req.Header.Set("User-Agent", cfg.UserAgent)
+	if cfg.Dataset != nil {
+		req.Header.Set("Dash0-Dataset", *cfg.Dataset)
+	}
+
+	resp, err := cfg.HTTPClient.Do(req)
+	if err != nil {
+		return exportResult{}, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxSendResponseBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if len(respBody) > maxErrorBodyBytes {
+			respBody = respBody[:maxErrorBodyBytes]
+		}
+		return exportResult{}, &dash0api.APIError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(respBody),
+		}
+	}
+
+	result := exportResult{sent: p.count()}
+	rejected, message := parsePartialSuccess(p.signal, respBody, strings.Contains(resp.Header.Get("Content-Type"), "json"))
+	result.rejected = min(int(rejected), result.sent)
+	result.message = message
+	return result, nil
+}
+
+// parsePartialSuccess reads the rejected count and error message of an
+// export response. A body that cannot be decoded counts as full success:
+// the request was accepted, and OTLP receivers may answer with an empty
+// body.
+func parsePartialSuccess(signal Signal, body []byte, isJSON bool) (int64, string) {
+	if len(body) == 0 {
+		return 0, ""
+	}
+	switch signal {
+	case SignalLogs:
+		resp := plogotlp.NewExportResponse()
+		if err := unmarshalResponse(resp.UnmarshalJSON, resp.UnmarshalProto, body, isJSON); err != nil {
+			return 0, ""
+		}
+		return resp.PartialSuccess().RejectedLogRecords(), resp.PartialSuccess().ErrorMessage()
+	case SignalSpans:
+		resp := ptraceotlp.NewExportResponse()
+		if err := unmarshalResponse(resp.UnmarshalJSON, resp.UnmarshalProto, body, isJSON); err != nil {
+			return 0, ""
+		}
+		return resp.PartialSuccess().RejectedSpans(), resp.PartialSuccess().ErrorMessage()
+	default:
+		resp := pmetricotlp.NewExportResponse()
+		if err := unmarshalResponse(resp.UnmarshalJSON, resp.UnmarshalProto, body, isJSON); err != nil {
+			return 0, ""
+		}
+		return resp.PartialSuccess().RejectedDataPoints(), resp.PartialSuccess().ErrorMessage()
+	}
+}
+
+func unmarshalResponse(fromJSON, fromProto func([]byte) error, body []byte, isJSON bool) error {
+	if isJSON {
+		return fromJSON(body)
+	}
+	return fromProto(body)
+}
+
+// sendResults sums the export results per signal.
+type sendResults struct {
+	sent     [signalCount]int
+	rejected [signalCount]int
+	messages [signalCount][]string
+}
+
+func (r *sendResults) add(signal Signal, result exportResult) {
+	r.sent[signal] += result.sent
+	r.rejected[signal] += result.rejected
+	if result.message != "" {
+		r.messages[signal] = append(r.messages[signal], result.message)
+	}
+}
+
+// totals returns the number of records sent and rejected across signals.
+func (r *sendResults) totals() (sent, rejected int) {
+	for s := Signal(0); s < signalCount; s++ {
+		sent += r.sent[s]
+		rejected += r.rejected[s]
+	}
+	return sent, rejected
+}
+
+// print writes one line per signal that was sent, followed by the
+// partial-success messages of that signal.
+func (r *sendResults) print() {
+	units := [signalCount]string{"log records", "spans", "metric data points"}
+	for s := Signal(0); s < signalCount; s++ {
+		if r.sent[s] == 0 {
+			continue
+		}
+		fmt.Printf("%s: %d accepted, %d rejected\n", units[s], r.sent[s]-r.rejected[s], r.rejected[s])
+		for _, message := range r.messages[s] {
+			fmt.Printf("  %s\n", message)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// newIdentifiedTracesBatch constructs a ptrace.Traces containing n spans
+// with trace and span IDs and timestamps one second apart.
+func newIdentifiedTracesBatch(n int) ptrace.Traces {
+	td := newTracesBatch(n)
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		span := spans.At(i)
+		span.SetTraceID(pcommon.TraceID{1, 2, 3})
+		span.SetSpanID(pcommon.SpanID{byte(i + 1)})
+		start := time.Date(2026, 3, 15, 10, 30, i, 0, time.UTC)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(500 * time.Millisecond)))
+	}
+	return td
+}
+
+func TestDecodePayloads_JSONStream(t *testing.T) {
+	logs, err := (&plog.JSONMarshaler{}).MarshalLogs(newLogsBatch(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	traces, err := (&ptrace.JSONMarshaler{}).MarshalTraces(newIdentifiedTracesBatch(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := string(logs) + "\n" + string(traces) + "\n"
+
+	payloads, err := decodePayloads([]byte(data), encodingUnknown, nil)
+	if err != nil {
+		t.Fatalf("decodePayloads: %v", err)
+	}
+	if len(payloads) != 2 {
+		t.Fatalf("payloads = %d; want 2", len(payloads))
+	}
+	if payloads[0].signal != SignalLogs || payloads[0].count() != 2 {
+		t.Errorf("payload 1 = %s with %d records; want logs with 2", payloads[0].signal, payloads[0].count())
+	}
+	if payloads[1].signal != SignalSpans || payloads[1].count() != 3 {
+		t.Errorf("payload 2 = %s with %d records; want spans with 3", payloads[1].signal, payloads[1].count())
+	}
+
+	metrics := SignalMetrics
+	if _, err := decodePayloads([]byte(data), encodingJSON, &metrics); err == nil || !strings.Contains(err.Error(), "payload 1: payload holds logs, not metrics") {
+		t.Errorf("error = %v; want a signal mismatch on payload 1", err)
+	}
+}
+
+func TestDecodePayloads_ProtobufSignalDetection(t *testing.T) {
+	logs, _ := plogotlp.NewExportRequestFromLogs(newLogsBatch(2)).MarshalProto()
+	traces, _ := ptraceotlp.NewExportRequestFromTraces(newIdentifiedTracesBatch(3)).MarshalProto()
+	metrics, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(newMetricsBatch(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		data   []byte
+		signal Signal
+		count  int
+	}{
+		{"logs", logs, SignalLogs, 2},
+		{"traces", traces, SignalSpans, 3},
+		{"metrics", metrics, SignalMetrics, 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			payloads, err := decodePayloads(tc.data, encodingUnknown, nil)
+			if err != nil {
+				t.Fatalf("decodePayloads: %v", err)
+			}
+			if len(payloads) != 1 || payloads[0].signal != tc.signal || payloads[0].count() != tc.count {
+				t.Errorf("got %d payloads, first %s with %d records; want one %s payload with %d",
+					len(payloads), payloads[0].signal, payloads[0].count(), tc.signal, tc.count)
+			}
+		})
+	}
+}
+
+func TestDecodePayloads_Invalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		encoding payloadEncoding
+		wantErr  string
+	}{
+		{"empty", " \n", encodingUnknown, "empty"},
+		{"not OTLP", `{"name":"a"}`, encodingJSON, "expected exactly one of resourceLogs"},
+		{"broken JSON", `{"resourceLogs":`, encodingJSON, "payload 1: invalid JSON"},
+		{"garbage", "\x00\x01\x02 not a payload", encodingUnknown, "neither an OTLP/JSON nor an OTLP/protobuf"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := decodePayloads([]byte(tc.data), tc.encoding, nil)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v; want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncodingFromFileName(t *testing.T) {
+	cases := map[string]payloadEncoding{
+		"payload.json":    encodingJSON,
+		"export.NDJSON":   encodingJSON,
+		"payload.pb":      encodingProtobuf,
+		"payload.binpb":   encodingProtobuf,
+		"payload":         encodingUnknown,
+		"-":               encodingUnknown,
+		"dir.json/export": encodingUnknown,
+	}
+	for name, want := range cases {
+		if got := encodingFromFileName(name); got != want {
+			t.Errorf("encodingFromFileName(%q) = %d; want %d", name, got, want)
+		}
+	}
+}
+
+func TestRewriteTimestamps(t *testing.T) {
+	traces := sendPayload{signal: SignalSpans, traces: newIdentifiedTracesBatch(2)}
+	logs := sendPayload{signal: SignalLogs, logs: newLogsBatch(1)}
+	lr := logs.logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2026, 3, 15, 10, 29, 0, 0, time.UTC)))
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	rewriteTimestamps([]sendPayload{traces, logs}, now)
+
+	spans := traces.traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	if got := spans.At(1).EndTimestamp().AsTime(); !got.Equal(now) {
+		t.Errorf("latest timestamp = %v; want %v", got, now)
+	}
+	if got := spans.At(0).StartTimestamp().AsTime(); !got.Equal(now.Add(-1500 * time.Millisecond)) {
+		t.Errorf("first span start = %v; want the original offset to the latest timestamp", got)
+	}
+	if got := lr.Timestamp().AsTime(); !got.Equal(now.Add(-61500 * time.Millisecond)) {
+		t.Errorf("log timestamp = %v; want it shifted by the same amount", got)
+	}
+	if lr.ObservedTimestamp() != 0 {
+		t.Errorf("unset timestamps must stay unset; got %v", lr.ObservedTimestamp())
+	}
+}
+
+func TestExportPayload_PartialSuccess(t *testing.T) {
+	var gotPath, gotAuth, gotDataset string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotDataset = r.Header.Get("Dash0-Dataset")
+		body, _ := io.ReadAll(r.Body)
+		req := ptraceotlp.NewExportRequest()
+		if err := req.UnmarshalProto(body); err != nil {
+			t.Errorf("request body is not an OTLP traces export request: %v", err)
+		}
+
+		resp := ptraceotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedSpans(1)
+		resp.PartialSuccess().SetErrorMessage("span 01 has no name")
+		out, _ := resp.MarshalProto()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(out)
+	}))
+	defer server.Close()
+
+	dataset := "staging"
+	cfg := &client.RawOtlpConfig{
+		HTTPClient: server.Client(),
+		OtlpUrl:    server.URL,
+		AuthToken:  "auth_test",
+		Dataset:    &dataset,
+		UserAgent:  "test",
+	}
+	result, err := exportPayload(context.Background(), cfg, sendPayload{signal: SignalSpans, traces: newIdentifiedTracesBatch(3)})
+	if err != nil {
+		t.Fatalf("exportPayload: %v", err)
+	}
+	if gotPath != "/v1/traces" || gotAuth != "Bearer auth_test" || gotDataset != "staging" {
+		t.Errorf("request = %s (Authorization %q, Dash0-Dataset %q)", gotPath, gotAuth, gotDataset)
+	}
+	want := exportResult{sent: 3, rejected: 1, message: "span 01 has no name"}
+	if result != want {
+		t.Errorf("result = %+v; want %+v", result, want)
+	}
+}
+
+func TestExportPayload_JSONResponseAndErrors(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"partialSuccess":{"rejectedLogRecords":"2","errorMessage":"too old"}}`))
+	}))
+	defer server.Close()
+
+	cfg := &client.RawOtlpConfig{HTTPClient: server.Client(), OtlpUrl: server.URL, AuthToken: "auth_test"}
+	p := sendPayload{signal: SignalLogs, logs: newLogsBatch(5)}
+	result, err := exportPayload(context.Background(), cfg, p)
+	if err != nil {
+		t.Fatalf("exportPayload: %v", err)
+	}
+	if result.rejected != 2 || result.message != "too old" {
+		t.Errorf("result = %+v; want 2 rejected with message", result)
+	}
+
+	status = http.StatusUnauthorized
+	if _, err := exportPayload(context.Background(), cfg, p); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("error = %v; want the 401 status", err)
+	}
+}
+
+func TestSendResults_Totals(t *testing.T) {
+	var r sendResults
+	r.add(SignalLogs, exportResult{sent: 5, rejected: 2, message: "too old"})
+	r.add(SignalLogs, exportResult{sent: 3})
+	r.add(SignalMetrics, exportResult{sent: 4})
+	sent, rejected := r.totals()
+	if sent != 12 || rejected != 2 {
+		t.Errorf("totals = %d sent, %d rejected; want 12 and 2", sent, rejected)
+	}
+}
+
+func TestSendRequiresFile(t *testing.T) {
+	root := &cobra.Command{Use: "dash0"}
+	root.PersistentFlags().BoolP("experimental", "X", false, "")
+	root.AddCommand(NewOtlpCmd())
+
+	root.SetArgs([]string{"-X", "otlp", "send", "--signal", "logs"})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), `"file" not set`) {
+		t.Errorf("error = %v; want the missing --file flag", err)
+	}
+
+	root.SetArgs([]string{"-X", "otlp", "send", "-f", "payload.json", "--signal", "profiles"})
+	err = root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "unknown signal") {
+		t.Errorf("error = %v; want an unknown signal error", err)
+	}
+}
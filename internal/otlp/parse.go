@@ -2,6 +2,7 @@ package otlp
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -57,6 +58,33 @@ func ParseSpanID(s string) (pcommon.SpanID, error) {
 	return sid, nil
 }
 
+// NormalizeJSONNumbers converts the json.Number values produced by a
+// json.Decoder with UseNumber, at any depth of v, into int64 where the number
+// is integral and float64 otherwise. The result can be passed to
+// pcommon.Value.FromRaw, and integers keep their integer type.
+func NormalizeJSONNumbers(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]any:
+		for k, e := range t {
+			t[k] = NormalizeJSONNumbers(e)
+		}
+		return t
+	case []any:
+		for i, e := range t {
+			t[i] = NormalizeJSONNumbers(e)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
 // ResolveScopeDefaults clears the default value for scope-name or scope-version
 // when only the other flag is explicitly set. This avoids pairing a custom scope
 // name with the dash0-cli version (or vice versa).
@@ -1,6 +1,8 @@
 package otlp
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/dash0hq/dash0-cli/internal/version"
@@ -137,3 +139,16 @@ func TestResolveScopeDefaults(t *testing.T) {
 		assert.Equal(t, "2.0.0", ver)
 	})
 }
+
+func TestNormalizeJSONNumbers(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"count":3,"ratio":0.5,"nested":{"big":9007199254740993},"list":[1,2.5,"x"]}`))
+	dec.UseNumber()
+	var v any
+	require.NoError(t, dec.Decode(&v))
+	assert.Equal(t, map[string]any{
+		"count":  int64(3),
+		"ratio":  0.5,
+		"nested": map[string]any{"big": int64(9007199254740993)},
+		"list":   []any{int64(1), 2.5, "x"},
+	}, NormalizeJSONNumbers(v))
+}
@@ -25,12 +25,15 @@ const (
 //
 //  1. explicit --flag on the command line
 //  2. DASH0_OTLP_PROXY_* env var
-//  3. OTEL_EXPORTER_OTLP_ENDPOINT (parsed; routed to HTTP or gRPC based on
+//  3. the --config file
+//  4. OTEL_EXPORTER_OTLP_ENDPOINT (parsed; routed to HTTP or gRPC based on
 //     OTEL_EXPORTER_OTLP_PROTOCOL)
-//  4. built-in default (4318 HTTP, 4317 gRPC)
+//  5. built-in default (4318 HTTP, 4317 gRPC)
 const (
-	envHTTPPort = "DASH0_OTLP_PROXY_HTTP_PORT"
-	envGRPCPort = "DASH0_OTLP_PROXY_GRPC_PORT"
+	envHTTPPort  = "DASH0_OTLP_PROXY_HTTP_PORT"
+	envGRPCPort  = "DASH0_OTLP_PROXY_GRPC_PORT"
+	envQueryPort = "DASH0_OTLP_PROXY_QUERY_PORT"
+	envAdminPort = "DASH0_OTLP_PROXY_ADMIN_PORT"
 
 	envOTELEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
 	envOTELProtocol = "OTEL_EXPORTER_OTLP_PROTOCOL"
@@ -42,6 +45,13 @@ const (
 // resolved into this struct post-parse via resolveEnvOverrides, and the
 // supervisor reads only this struct.
 type proxyFlags struct {
+	// ConfigFile is the optional --config YAML file. Its values apply to
+	// every flag not given on the command line; fileConfig and fileData
+	// hold it once loaded by resolveEnvOverrides.
+	ConfigFile string
+	fileConfig *proxyFileConfig
+	fileData   []byte
+
 	// Connection
 	OtlpUrl   string
 	AuthToken string
@@ -54,6 +64,15 @@ type proxyFlags struct {
 	// Visibility
 	Tail bool
 
+	// Local query API. RecentRecords bounds the per-signal ring buffer
+	// that `dash0 otlp proxy recent` searches; 0 disables both the buffer
+	// and the QueryPort listener.
+	QueryPort     int
+	RecentRecords int
+
+	// Admin endpoint (/healthz, /readyz, /metrics). 0 disables it.
+	AdminPort int
+
 	// Outbound decoration. Mirrors the same flags on
 	// `dash0 logs send` and `dash0 spans send`. Upsert into each
 	// resource / scope / record on every forwarded batch. Unlike the
@@ -65,6 +84,14 @@ type proxyFlags struct {
 	ScopeName          string
 	ScopeVersion       string
 
+	// Resource detection. Detect names the detectors run once at startup;
+	// their results land in detected and are added to each resource that
+	// lacks the key, or replace the SDK's value when DetectOverwrite is set.
+	// --resource-attribute always wins over a detected value.
+	Detect          []string
+	DetectOverwrite bool
+	detected        map[string]string
+
 	// Per-signal attribute upserts. Applied to each individual record
 	// (LogRecord / Span / metric data point) on its respective
 	// signal type. A flag is signal-specific so the user can tag one
@@ -72,6 +99,14 @@ type proxyFlags struct {
 	LogAttributes    []string
 	SpanAttributes   []string
 	MetricAttributes []string
+
+	// Fan-out. Every inbound batch is also forwarded to each of these
+	// destinations, each with its own queue and failure counters. AlsoForward
+	// holds plain OTLP/HTTP base URLs (no Dash0 credentials); AlsoForwardProfile
+	// holds names of configured profiles whose OTLP URL, token, and dataset
+	// are used.
+	AlsoForward        []string
+	AlsoForwardProfile []string
 }
 
 // newProxyCmd creates the experimental `dash0 otlp proxy` command.
@@ -90,6 +125,29 @@ env-var change. When a port is already in use, the proxy exits non-zero
 with an error that names the holding process; pass --http-port or
 --grpc-port to use a different port.
 
+Pass --also-forward or --also-forward-profile to send a copy of every
+batch to additional destinations. Each destination has its own queue, so a
+slow or failing destination never holds back the others.
+
+Pass --detect to add resource attributes that describe the developer's
+environment, such as host.name, the git branch and commit, and the container
+ID. Detection runs once at startup, and detected values do not replace
+those the SDK set unless --detect-overwrite is given.
+
+The proxy keeps the most recent records of each signal in memory and serves
+them on a local query API (127.0.0.1:4319 by default). Search them with
+'dash0 -X otlp proxy recent' without waiting for a round-trip through Dash0.
+
+Pass --admin-port to serve /healthz, /readyz, and Prometheus /metrics, for
+example as a health check in a docker-compose stack. /readyz succeeds once
+the pipeline has started and Dash0 has accepted the proxy's credentials.
+
+Pass --config to read settings from a YAML file whose keys mirror the flag
+names. Flags and DASH0_OTLP_PROXY_* variables take precedence over the file.
+The proxy watches the file and applies decoration changes (attributes and
+scope identity) to the next batch without a restart; other changes are
+reported and take effect on the next start.
+
 The proxy is a local-dev shortcut, not a replacement for the OpenTelemetry
 Collector. It does not buffer outbound on Dash0 outages; backpressure
 surfaces to SDKs as HTTP 503 / gRPC UNAVAILABLE.`,
@@ -122,7 +180,23 @@ surfaces to SDKs as HTTP 503 / gRPC UNAVAILABLE.`,
   # batch as coming through your proxy (preserved by default).
   dash0 -X otlp proxy \
       --scope-name dash0-cli-otlp-proxy \
-      --scope-version v1`,
+      --scope-version v1
+
+  # Tag every resource with the host, git checkout, and user, unless the
+  # SDK already set the attribute.
+  dash0 -X otlp proxy --detect host,git,process
+
+  # Read settings from a file; edit its attributes while the proxy runs.
+  dash0 -X otlp proxy --config proxy.yaml
+
+  # Serve /healthz, /readyz, and Prometheus /metrics on port 9464.
+  dash0 -X otlp proxy --admin-port 9464
+
+  # Also send a copy of everything to a local Jaeger instance.
+  dash0 -X otlp proxy --also-forward http://localhost:14318
+
+  # Mirror telemetry into a second Dash0 organization.
+  dash0 -X otlp proxy --also-forward-profile staging`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := experimental.RequireExperimental(cmd); err != nil {
@@ -138,6 +212,8 @@ surfaces to SDKs as HTTP 503 / gRPC UNAVAILABLE.`,
 		},
 	}
 
+	cmd.Flags().StringVar(&flags.ConfigFile, "config", "",
+		"YAML file with proxy settings; keys mirror the flag names, and decoration changes are applied without a restart")
 	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
 	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
 	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset to route forwarded telemetry to (overrides active profile)")
@@ -147,6 +223,13 @@ surfaces to SDKs as HTTP 503 / gRPC UNAVAILABLE.`,
 	cmd.Flags().IntVar(&flags.GRPCPort, "grpc-port", defaultGRPCPort,
 		fmt.Sprintf("OTLP/gRPC listener port (default %d, the standard OTLP/gRPC port; set 0 for OS-assigned; env: %s)",
 			defaultGRPCPort, envGRPCPort))
+	cmd.Flags().IntVar(&flags.QueryPort, "query-port", defaultQueryPort,
+		fmt.Sprintf("Local query API port used by 'otlp proxy recent' (default %d; set 0 for OS-assigned; env: %s)",
+			defaultQueryPort, envQueryPort))
+	cmd.Flags().IntVar(&flags.RecentRecords, "recent-records", defaultRecentRecords,
+		"Number of recent records kept in memory per signal for 'otlp proxy recent' (0 disables the local query API)")
+	cmd.Flags().IntVar(&flags.AdminPort, "admin-port", 0,
+		fmt.Sprintf("Port for the /healthz, /readyz, and Prometheus /metrics endpoints (default: disabled; env: %s)", envAdminPort))
 	cmd.Flags().BoolVar(&flags.Tail, "tail", false,
 		"Print each forwarded record in collector-debug-exporter style on stdout (incompatible with --agent-mode; use the NDJSON event stream instead)")
 
@@ -163,6 +246,11 @@ surfaces to SDKs as HTTP 503 / gRPC UNAVAILABLE.`,
 	cmd.Flags().StringVar(&flags.ScopeVersion, "scope-version", "",
 		"Instrumentation-scope version to set on every forwarded batch (default: preserve the SDK's value)")
 
+	cmd.Flags().StringSliceVar(&flags.Detect, "detect", nil,
+		fmt.Sprintf("Resource detectors to run at startup, comma-separated (%s); detected attributes are added where the SDK did not set them", strings.Join(resourceDetectorNames, ", ")))
+	cmd.Flags().BoolVar(&flags.DetectOverwrite, "detect-overwrite", false,
+		"Let detected resource attributes replace values the SDK already set")
+
 	cmd.Flags().StringArrayVar(&flags.LogAttributes, "log-attribute", nil,
 		"Attribute as 'key=value' to upsert on every forwarded log record (repeatable)")
 	cmd.Flags().StringArrayVar(&flags.SpanAttributes, "span-attribute", nil,
@@ -170,18 +258,40 @@ surfaces to SDKs as HTTP 503 / gRPC UNAVAILABLE.`,
 	cmd.Flags().StringArrayVar(&flags.MetricAttributes, "metric-attribute", nil,
 		"Attribute as 'key=value' to upsert on every forwarded metric data point (repeatable)")
 
+	cmd.Flags().StringArrayVar(&flags.AlsoForward, "also-forward", nil,
+		"Additional OTLP/HTTP endpoint URL to forward every batch to, without Dash0 credentials (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.AlsoForwardProfile, "also-forward-profile", nil,
+		"Name of an additional profile whose OTLP URL, auth token, and dataset receive every batch (repeatable)")
+
+	cmd.AddCommand(newProxyRecentCmd())
+
 	return cmd
 }
 
-// resolveEnvOverrides applies environment-variable overrides for flags that
-// were not explicitly set on the command line.
+// resolveEnvOverrides applies the --config file and environment-variable
+// overrides for flags that were not explicitly set on the command line. It
+// is idempotent: the file is read once and later calls reuse it.
 //
-// Precedence per port: --flag > DASH0_OTLP_PROXY_* > OTEL_EXPORTER_OTLP_* > default.
-// Standard OpenTelemetry exporter env vars (OTEL_EXPORTER_OTLP_ENDPOINT plus
-// OTEL_EXPORTER_OTLP_PROTOCOL) feed in as the lowest-priority override so a
-// shell already configured for an SDK against a non-default port carries
-// through to the proxy listener without explicit flags.
+// Precedence per port: --flag > DASH0_OTLP_PROXY_* > --config file >
+// OTEL_EXPORTER_OTLP_* > default. Standard OpenTelemetry exporter env vars
+// (OTEL_EXPORTER_OTLP_ENDPOINT plus OTEL_EXPORTER_OTLP_PROTOCOL) feed in as
+// the lowest-priority override so a shell already configured for an SDK
+// against a non-default port carries through to the proxy listener without
+// explicit flags. Every other setting: --flag > --config file > default.
 func resolveEnvOverrides(cmd *cobra.Command, flags *proxyFlags) error {
+	if flags.ConfigFile != "" && flags.fileConfig == nil {
+		cfg, data, err := loadProxyConfigFile(flags.ConfigFile)
+		if err != nil {
+			return err
+		}
+		flags.fileConfig, flags.fileData = cfg, data
+	}
+	file := &proxyFileConfig{}
+	if flags.fileConfig != nil {
+		file = flags.fileConfig
+		applyProxyConfig(cmd, flags, file)
+	}
+
 	httpFromOTEL, grpcFromOTEL, err := parseOTELExporterEnv()
 	if err != nil {
 		return err
@@ -194,6 +304,8 @@ func resolveEnvOverrides(cmd *cobra.Command, flags *proxyFlags) error {
 				return err
 			}
 			flags.HTTPPort = port
+		} else if file.HTTPPort != nil {
+			flags.HTTPPort = *file.HTTPPort
 		} else if httpFromOTEL != nil {
 			flags.HTTPPort = *httpFromOTEL
 		}
@@ -205,10 +317,34 @@ func resolveEnvOverrides(cmd *cobra.Command, flags *proxyFlags) error {
 				return err
 			}
 			flags.GRPCPort = port
+		} else if file.GRPCPort != nil {
+			flags.GRPCPort = *file.GRPCPort
 		} else if grpcFromOTEL != nil {
 			flags.GRPCPort = *grpcFromOTEL
 		}
 	}
+	if !cmd.Flags().Changed("query-port") {
+		if v, ok := nonEmptyEnv(envQueryPort); ok {
+			port, err := parsePort(envQueryPort, v)
+			if err != nil {
+				return err
+			}
+			flags.QueryPort = port
+		} else if file.QueryPort != nil {
+			flags.QueryPort = *file.QueryPort
+		}
+	}
+	if !cmd.Flags().Changed("admin-port") {
+		if v, ok := nonEmptyEnv(envAdminPort); ok {
+			port, err := parsePort(envAdminPort, v)
+			if err != nil {
+				return err
+			}
+			flags.AdminPort = port
+		} else if file.AdminPort != nil {
+			flags.AdminPort = *file.AdminPort
+		}
+	}
 	return nil
 }
 
@@ -334,6 +470,10 @@ func parsePort(source, raw string) (int, error) {
 // Listed validations:
 //   - HTTPPort / GRPCPort must be within the valid TCP port range.
 //   - HTTP and gRPC listeners cannot share a TCP port (KTD6).
+//   - The query API and admin ports, when enabled, must differ from the
+//     OTLP listeners and from each other.
+//   - --also-forward URLs must be absolute http(s) URLs, and no destination
+//     may be listed twice.
 func validateFlags(flags *proxyFlags) error {
 	if flags.HTTPPort < 0 || flags.HTTPPort > 65535 {
 		return fmt.Errorf("--http-port %d is out of range (0-65535)", flags.HTTPPort)
@@ -347,6 +487,60 @@ func validateFlags(flags *proxyFlags) error {
 	if flags.HTTPPort != 0 && flags.HTTPPort == flags.GRPCPort {
 		return errors.New("HTTP and gRPC listeners cannot share a port (--http-port and --grpc-port must differ)")
 	}
+	if flags.RecentRecords < 0 {
+		return fmt.Errorf("--recent-records %d must not be negative", flags.RecentRecords)
+	}
+	if flags.RecentRecords > 0 {
+		if flags.QueryPort < 0 || flags.QueryPort > 65535 {
+			return fmt.Errorf("--query-port %d is out of range (0-65535)", flags.QueryPort)
+		}
+		if flags.QueryPort != 0 && (flags.QueryPort == flags.HTTPPort || flags.QueryPort == flags.GRPCPort) {
+			return errors.New("the query API cannot share a port with the OTLP listeners (--query-port must differ from --http-port and --grpc-port)")
+		}
+	}
+	if flags.AdminPort < 0 || flags.AdminPort > 65535 {
+		return fmt.Errorf("--admin-port %d is out of range (0-65535)", flags.AdminPort)
+	}
+	if flags.AdminPort != 0 {
+		if flags.AdminPort == flags.HTTPPort || flags.AdminPort == flags.GRPCPort {
+			return errors.New("the admin endpoint cannot share a port with the OTLP listeners (--admin-port must differ from --http-port and --grpc-port)")
+		}
+		if flags.RecentRecords > 0 && flags.AdminPort == flags.QueryPort {
+			return errors.New("the admin endpoint cannot share a port with the query API (--admin-port must differ from --query-port)")
+		}
+	}
+	if err := validateDetectors(flags.Detect); err != nil {
+		return err
+	}
+	return validateAlsoForward(flags)
+}
+
+// validateAlsoForward checks the fan-out destination flags. Profile names
+// are only checked for duplicates here; whether they exist is resolved at
+// startup, where the "profile not found" error can list the alternatives.
+func validateAlsoForward(flags *proxyFlags) error {
+	seen := make(map[string]bool, len(flags.AlsoForward))
+	for _, raw := range flags.AlsoForward {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("--also-forward %q is not a valid OTLP/HTTP endpoint URL\nHint: use the base URL of the receiver, for example http://localhost:4318", raw)
+		}
+		key := strings.TrimRight(raw, "/")
+		if seen[key] {
+			return fmt.Errorf("--also-forward %q is listed more than once", raw)
+		}
+		seen[key] = true
+	}
+	seenProfiles := make(map[string]bool, len(flags.AlsoForwardProfile))
+	for _, name := range flags.AlsoForwardProfile {
+		if name == "" {
+			return errors.New("--also-forward-profile must not be empty")
+		}
+		if seenProfiles[name] {
+			return fmt.Errorf("--also-forward-profile %q is listed more than once", name)
+		}
+		seenProfiles[name] = true
+	}
 	return nil
 }
 
@@ -0,0 +1,274 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Paths served by the admin endpoint.
+const (
+	adminHealthzPath = "/healthz"
+	adminReadyzPath  = "/readyz"
+	adminMetricsPath = "/metrics"
+)
+
+// authProbeInterval is how long the proxy waits between credential probes
+// while Dash0 cannot be reached. Once a probe (or a forwarded batch) gets
+// an answer, probing stops and forwarding outcomes keep the state current.
+const authProbeInterval = 5 * time.Second
+
+// authState is the proxy's knowledge of whether the primary destination
+// accepts its credentials.
+type authState int
+
+const (
+	authUnknown authState = iota
+	authVerified
+	authRejected
+)
+
+// Health tracks the readiness conditions served on /readyz: the pipeline
+// has started, and Dash0 has accepted the proxy's credentials at least
+// once since the last rejection. A nil *Health ignores every update, so
+// the worker pool can report outcomes unconditionally.
+type Health struct {
+	started atomic.Bool
+
+	mu   sync.Mutex
+	auth authState
+}
+
+// NewHealth returns a Health that is not ready yet.
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// markStarted records that Pipeline.Start succeeded.
+func (h *Health) markStarted() {
+	if h == nil {
+		return
+	}
+	h.started.Store(true)
+}
+
+// markStopping takes the proxy out of readiness at the start of shutdown,
+// so orchestrators stop routing traffic before the listeners close.
+func (h *Health) markStopping() {
+	if h == nil {
+		return
+	}
+	h.started.Store(false)
+}
+
+// recordAuthOutcome updates the credential state from the result of a send
+// to the primary destination. Success verifies the credentials, a 401 or
+// 403 rejects them, and any other failure says nothing about them.
+func (h *Health) recordAuthOutcome(err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		h.auth = authVerified
+		return
+	}
+	if kind, _ := classifyError(err); kind == ErrorKindUpstream4xxAuth {
+		h.auth = authRejected
+	}
+}
+
+// authKnown reports whether Dash0 has answered for the credentials either
+// way.
+func (h *Health) authKnown() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.auth != authUnknown
+}
+
+// Ready reports whether the proxy is ready and, when it is not, why.
+func (h *Health) Ready() (bool, string) {
+	if !h.started.Load() {
+		return false, "OTLP pipeline not started"
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch h.auth {
+	case authVerified:
+		return true, ""
+	case authRejected:
+		return false, "authentication to Dash0 failed"
+	default:
+		return false, "verifying credentials with Dash0"
+	}
+}
+
+// verifyAuth probes the primary destination with an empty metrics export
+// until Dash0 has answered for the credentials, either through a probe or
+// through a forwarded batch. An empty export carries no data, so a success
+// costs nothing, and Dash0 authenticates the request before looking at the
+// payload.
+func verifyAuth(ctx context.Context, forwarder Forwarder, dataset *string, health *Health) {
+	ticker := time.NewTicker(authProbeInterval)
+	defer ticker.Stop()
+	for {
+		if health.authKnown() {
+			return
+		}
+		health.recordAuthOutcome(forwarder.SendMetrics(ctx, pmetric.NewMetrics(), dataset))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AdminServer serves health, readiness, and Prometheus self-metrics for
+// the proxy on the loopback interface:
+//
+//	GET /healthz  200 while the process is serving
+//	GET /readyz   200 once the pipeline has started and Dash0 accepted the
+//	              credentials; 503 with the reason otherwise
+//	GET /metrics  Prometheus text exposition format
+type AdminServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewAdminServer binds the admin endpoint on 127.0.0.1:port. Binding
+// happens here rather than in Start so a port collision fails startup
+// before the banner, like the OTLP listeners.
+func NewAdminServer(port int, health *Health, workers *WorkerPool, sampler *RateSampler) (*AdminServer, error) {
+	ln, err := net.Listen("tcp", net.JoinHostPort(proxyListenHost, strconv.Itoa(port)))
+	if err != nil {
+		return nil, portInUseError("admin", port, err)
+	}
+	return &AdminServer{
+		listener: ln,
+		server: &http.Server{
+			Handler:           newAdminHandler(health, workers, sampler),
+			ReadHeaderTimeout: recentServerReadHeaderTimeout,
+		},
+	}, nil
+}
+
+// Endpoint returns the host:port the server is bound to.
+func (s *AdminServer) Endpoint() string {
+	return s.listener.Addr().String()
+}
+
+// Start serves requests in the background until Shutdown is called. It is
+// started before the pipeline so /healthz answers, and /readyz reports
+// why the proxy is not ready yet, from the earliest possible moment.
+func (s *AdminServer) Start() {
+	go func() { _ = s.server.Serve(s.listener) }()
+}
+
+// Shutdown stops the server, waiting for in-flight requests up to ctx's
+// deadline.
+func (s *AdminServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// Close releases the listener immediately. It is safe to call after
+// Shutdown and on a server that was never started.
+func (s *AdminServer) Close() error {
+	_ = s.server.Close()
+	return s.listener.Close()
+}
+
+func newAdminHandler(health *Health, workers *WorkerPool, sampler *RateSampler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+adminHealthzPath, func(w http.ResponseWriter, r *http.Request) {
+		writeAdminText(w, http.StatusOK, "ok")
+	})
+	mux.HandleFunc("GET "+adminReadyzPath, func(w http.ResponseWriter, r *http.Request) {
+		if ready, reason := health.Ready(); !ready {
+			writeAdminText(w, http.StatusServiceUnavailable, reason)
+			return
+		}
+		writeAdminText(w, http.StatusOK, "ready")
+	})
+	mux.HandleFunc("GET "+adminMetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		writePrometheusMetrics(w, health, workers.Destinations(), sampler)
+	})
+	return mux
+}
+
+func writeAdminText(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintln(w, body)
+}
+
+// writePrometheusMetrics renders the proxy's counters in the Prometheus
+// text exposition format. Counters are labelled by destination, so a run
+// with --also-forward shows each upstream separately; the rate gauge
+// reflects what the SDKs send and is only labelled by signal.
+func writePrometheusMetrics(w io.Writer, health *Health, destinations []*Destination, sampler *RateSampler) {
+	ready := 0
+	if ok, _ := health.Ready(); ok {
+		ready = 1
+	}
+	writePrometheusHeader(w, "dash0_otlp_proxy_ready", "gauge", "Whether the proxy is ready: the pipeline started and Dash0 accepted the credentials.")
+	fmt.Fprintf(w, "dash0_otlp_proxy_ready %d\n", ready)
+
+	writePrometheusHeader(w, "dash0_otlp_proxy_forwarded_total", "counter", "Records accepted for forwarding, per signal and destination.")
+	for _, d := range destinations {
+		for sig := Signal(0); sig < signalCount; sig++ {
+			fmt.Fprintf(w, "dash0_otlp_proxy_forwarded_total{destination=%s,signal=%q} %d\n",
+				prometheusLabelValue(d.Name()), sig.String(), d.Stats().Forwarded(sig))
+		}
+	}
+
+	writePrometheusHeader(w, "dash0_otlp_proxy_failed_total", "counter", "Records that could not be delivered, per signal and destination.")
+	for _, d := range destinations {
+		for sig := Signal(0); sig < signalCount; sig++ {
+			fmt.Fprintf(w, "dash0_otlp_proxy_failed_total{destination=%s,signal=%q} %d\n",
+				prometheusLabelValue(d.Name()), sig.String(), d.Stats().Failed(sig))
+		}
+	}
+
+	writePrometheusHeader(w, "dash0_otlp_proxy_errors_total", "counter", "Outbound failures, per error kind and destination.")
+	for _, d := range destinations {
+		for _, kind := range errorKinds {
+			fmt.Fprintf(w, "dash0_otlp_proxy_errors_total{destination=%s,kind=%q} %d\n",
+				prometheusLabelValue(d.Name()), string(kind), d.Stats().Errors(kind))
+		}
+	}
+
+	var latest SnapshotWithRate
+	if history := sampler.History(); len(history) > 0 {
+		latest = history[len(history)-1]
+	}
+	writePrometheusHeader(w, "dash0_otlp_proxy_rate", "gauge", "Records per second received from SDKs over the last sampling interval, per signal.")
+	for sig := Signal(0); sig < signalCount; sig++ {
+		fmt.Fprintf(w, "dash0_otlp_proxy_rate{signal=%q} %s\n",
+			sig.String(), strconv.FormatFloat(latest.Rate[sig], 'g', -1, 64))
+	}
+}
+
+func writePrometheusHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// prometheusLabelValue quotes a label value per the exposition format,
+// which escapes only backslash, double quote, and line feed. Destination
+// names are user-supplied URLs and profile names, so %q's Go escaping
+// would not round-trip.
+func prometheusLabelValue(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(v) + `"`
+}
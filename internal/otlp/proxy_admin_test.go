@@ -0,0 +1,176 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+)
+
+func TestHealth_ReadyRequiresStartAndVerifiedAuth(t *testing.T) {
+	h := NewHealth()
+	if ready, reason := h.Ready(); ready || reason != "OTLP pipeline not started" {
+		t.Errorf("before start: ready=%v reason=%q", ready, reason)
+	}
+
+	h.markStarted()
+	if ready, reason := h.Ready(); ready || reason != "verifying credentials with Dash0" {
+		t.Errorf("started, auth unknown: ready=%v reason=%q", ready, reason)
+	}
+
+	// Unreachable says nothing about the credentials.
+	h.recordAuthOutcome(errors.New("dial tcp: connection refused"))
+	if ready, _ := h.Ready(); ready {
+		t.Error("network error must not make the proxy ready")
+	}
+
+	h.recordAuthOutcome(nil)
+	if ready, reason := h.Ready(); !ready {
+		t.Errorf("after verified auth: ready=false reason=%q", reason)
+	}
+
+	h.recordAuthOutcome(&dash0api.APIError{StatusCode: 403})
+	if ready, reason := h.Ready(); ready || reason != "authentication to Dash0 failed" {
+		t.Errorf("after 403: ready=%v reason=%q", ready, reason)
+	}
+
+	h.recordAuthOutcome(nil)
+	h.markStopping()
+	if ready, _ := h.Ready(); ready {
+		t.Error("stopping proxy must not be ready")
+	}
+}
+
+func TestHealth_NilIgnoresUpdates(t *testing.T) {
+	var h *Health
+	// Must not panic: the worker pool reports outcomes unconditionally.
+	h.markStarted()
+	h.recordAuthOutcome(nil)
+	h.markStopping()
+}
+
+func TestVerifyAuth_StopsOnceDash0Answers(t *testing.T) {
+	forwarder := &fakeForwarder{
+		metricsErrs: []error{&dash0api.APIError{StatusCode: 401}},
+	}
+	h := NewHealth()
+
+	done := make(chan struct{})
+	go func() {
+		verifyAuth(context.Background(), forwarder, nil, h)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("verifyAuth did not return after a definitive answer")
+	}
+	if got := forwarder.metricsCalls.Load(); got != 1 {
+		t.Errorf("probe calls = %d; want 1", got)
+	}
+	h.markStarted()
+	if _, reason := h.Ready(); reason != "authentication to Dash0 failed" {
+		t.Errorf("reason = %q; want rejected credentials", reason)
+	}
+}
+
+func TestVerifyAuth_ReturnsOnCancel(t *testing.T) {
+	forwarder := &fakeForwarder{
+		metricsErrs: []error{errors.New("connection refused")},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		verifyAuth(ctx, forwarder, nil, NewHealth())
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("verifyAuth did not return after cancellation")
+	}
+}
+
+func TestWorkerPool_PrimaryOutcomesDriveReadiness(t *testing.T) {
+	secondary := &fakeForwarder{logsErrs: []error{&dash0api.APIError{StatusCode: 401}}}
+	stats := &Stats{}
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	pool := NewWorkerPool(&fakeForwarder{}, nil, stats, NewEmitter("inst", nil), consumer, nil, nil)
+	dest := pool.AddDestination("staging", secondary, nil)
+	h := NewHealth()
+	h.markStarted()
+	pool.setHealth(h)
+
+	pool.sendLogs(context.Background(), dest, newLogsBatch(1))
+	if _, reason := h.Ready(); reason != "verifying credentials with Dash0" {
+		t.Errorf("a secondary destination's 401 must not affect readiness; reason = %q", reason)
+	}
+
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
+	if ready, reason := h.Ready(); !ready {
+		t.Errorf("a primary success should verify the credentials; reason = %q", reason)
+	}
+}
+
+func TestAdminHandler(t *testing.T) {
+	stats := &Stats{}
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	primary := &fakeForwarder{logsErrs: []error{&dash0api.APIError{StatusCode: 503}}}
+	pool := NewWorkerPool(primary, nil, stats, NewEmitter("inst", nil), consumer, nil, nil)
+	pool.AddDestination(`http://localhost:14318/"odd"`, &fakeForwarder{}, nil)
+	h := NewHealth()
+	pool.setHealth(h)
+	sampler := NewRateSampler(stats, time.Second, 2)
+
+	if err := consumer.ConsumeLogs(context.Background(), newLogsBatch(3)); err != nil {
+		t.Fatalf("ConsumeLogs: %v", err)
+	}
+	pool.sendLogs(context.Background(), pool.destinations[0], <-pool.destinations[0].queues.logs)
+	sampler.Sample()
+
+	server := httptest.NewServer(newAdminHandler(h, pool, sampler))
+	defer server.Close()
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get(adminHealthzPath); code != http.StatusOK || strings.TrimSpace(body) != "ok" {
+		t.Errorf("/healthz = %d %q; want 200 ok", code, body)
+	}
+	if code, body := get(adminReadyzPath); code != http.StatusServiceUnavailable || !strings.Contains(body, "not started") {
+		t.Errorf("/readyz before start = %d %q; want 503 with reason", code, body)
+	}
+
+	code, body := get(adminMetricsPath)
+	if code != http.StatusOK {
+		t.Fatalf("/metrics status = %d", code)
+	}
+	for _, want := range []string{
+		"# TYPE dash0_otlp_proxy_forwarded_total counter",
+		`dash0_otlp_proxy_forwarded_total{destination="primary",signal="logs"} 3`,
+		`dash0_otlp_proxy_forwarded_total{destination="http://localhost:14318/\"odd\"",signal="logs"} 3`,
+		`dash0_otlp_proxy_failed_total{destination="primary",signal="logs"} 3`,
+		`dash0_otlp_proxy_errors_total{destination="primary",kind="upstream_5xx"} 1`,
+		`dash0_otlp_proxy_errors_total{destination="primary",kind="upstream_4xx_auth"} 0`,
+		`dash0_otlp_proxy_rate{signal="spans"} 0`,
+		"dash0_otlp_proxy_ready 0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics missing %q; got:\n%s", want, body)
+		}
+	}
+}
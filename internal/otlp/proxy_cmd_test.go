@@ -295,6 +295,117 @@ func TestValidateFlags_PortRange(t *testing.T) {
 	}
 }
 
+func TestValidateFlags_AlsoForward(t *testing.T) {
+	cases := []struct {
+		name     string
+		urls     []string
+		profiles []string
+		wantErr  string
+	}{
+		{"none", nil, nil, ""},
+		{"http url", []string{"http://localhost:14318"}, nil, ""},
+		{"https url with path", []string{"https://otlp.example.com/otlp"}, nil, ""},
+		{"profiles", nil, []string{"staging", "prod"}, ""},
+		{"missing scheme", []string{"localhost:14318"}, nil, "not a valid OTLP/HTTP endpoint URL"},
+		{"grpc scheme", []string{"grpc://localhost:4317"}, nil, "not a valid OTLP/HTTP endpoint URL"},
+		{"duplicate url", []string{"http://localhost:14318", "http://localhost:14318/"}, nil, "listed more than once"},
+		{"duplicate profile", nil, []string{"staging", "staging"}, "listed more than once"},
+		{"empty profile", nil, []string{""}, "must not be empty"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := &proxyFlags{HTTPPort: 4318, GRPCPort: 4317, AlsoForward: tc.urls, AlsoForwardProfile: tc.profiles}
+			err := validateFlags(flags)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q; got nil", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %q; want it to contain %q", err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFlags_RecentBuffer(t *testing.T) {
+	cases := []struct {
+		name          string
+		queryPort     int
+		recentRecords int
+		wantErr       string
+	}{
+		{"defaults", defaultQueryPort, defaultRecentRecords, ""},
+		{"disabled ignores port", 4318, 0, ""},
+		{"negative records", defaultQueryPort, -1, "must not be negative"},
+		{"collides with http", 4318, 100, "--query-port"},
+		{"collides with grpc", 4317, 100, "--query-port"},
+		{"out of range", 70000, 100, "out of range"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := &proxyFlags{HTTPPort: 4318, GRPCPort: 4317, QueryPort: tc.queryPort, RecentRecords: tc.recentRecords}
+			err := validateFlags(flags)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q; got nil", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %q; want it to contain %q", err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFlags_AdminPort(t *testing.T) {
+	cases := []struct {
+		name    string
+		flags   proxyFlags
+		wantErr string
+	}{
+		{"disabled", proxyFlags{HTTPPort: 4318, GRPCPort: 4317}, ""},
+		{"enabled", proxyFlags{HTTPPort: 4318, GRPCPort: 4317, AdminPort: 9464}, ""},
+		{"out of range", proxyFlags{HTTPPort: 4318, GRPCPort: 4317, AdminPort: 70000}, "out of range"},
+		{"collides with http", proxyFlags{HTTPPort: 4318, GRPCPort: 4317, AdminPort: 4318}, "--admin-port"},
+		{"collides with query", proxyFlags{HTTPPort: 4318, GRPCPort: 4317, QueryPort: 4319, RecentRecords: 10, AdminPort: 4319}, "--query-port"},
+		{"query disabled frees its port", proxyFlags{HTTPPort: 4318, GRPCPort: 4317, QueryPort: 4319, AdminPort: 4319}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateFlags(&tc.flags)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v; want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFlags_Detect(t *testing.T) {
+	valid := proxyFlags{HTTPPort: 4318, GRPCPort: 4317, Detect: []string{"host", "git"}}
+	if err := validateFlags(&valid); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	invalid := proxyFlags{HTTPPort: 4318, GRPCPort: 4317, Detect: []string{"hostname"}}
+	if err := validateFlags(&invalid); err == nil || !strings.Contains(err.Error(), "--detect") {
+		t.Errorf("error = %v; want it to name --detect", err)
+	}
+}
+
 func TestRequiresExperimentalFlag(t *testing.T) {
 	root := &cobra.Command{Use: "dash0"}
 	root.PersistentFlags().BoolP("experimental", "X", false, "")
@@ -466,6 +577,8 @@ func TestEnvVarNameConstantsAreStable(t *testing.T) {
 	cases := []struct{ got, want string }{
 		{envHTTPPort, "DASH0_OTLP_PROXY_HTTP_PORT"},
 		{envGRPCPort, "DASH0_OTLP_PROXY_GRPC_PORT"},
+		{envQueryPort, "DASH0_OTLP_PROXY_QUERY_PORT"},
+		{envAdminPort, "DASH0_OTLP_PROXY_ADMIN_PORT"},
 		{envOTELEndpoint, "OTEL_EXPORTER_OTLP_ENDPOINT"},
 		{envOTELProtocol, "OTEL_EXPORTER_OTLP_PROTOCOL"},
 	}
@@ -546,11 +659,12 @@ func readFlagsFromCmd(t *testing.T, cmd *cobra.Command) *proxyFlags {
 	}
 
 	return &proxyFlags{
-		OtlpUrl:   mustString("otlp-url"),
-		AuthToken: mustString("auth-token"),
-		Dataset:   mustString("dataset"),
-		HTTPPort:  mustInt("http-port"),
-		GRPCPort:  mustInt("grpc-port"),
-		Tail:      mustBool("tail"),
+		ConfigFile: mustString("config"),
+		OtlpUrl:    mustString("otlp-url"),
+		AuthToken:  mustString("auth-token"),
+		Dataset:    mustString("dataset"),
+		HTTPPort:   mustInt("http-port"),
+		GRPCPort:   mustInt("grpc-port"),
+		Tail:       mustBool("tail"),
 	}
 }
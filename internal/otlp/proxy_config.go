@@ -0,0 +1,401 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// configReloadInterval is how often the proxy checks the --config file for
+// changes. Polling the file's size and modification time is portable,
+// copes with editors that save by renaming a temporary file over the
+// original, and costs one stat per second.
+const configReloadInterval = 1 * time.Second
+
+// proxyFileConfig is the schema of the `--config` file. Keys mirror the
+// proxy's flag names; attribute flags become maps. Pointer fields
+// distinguish "not set" from an explicit zero, so `recent-records: 0` in the
+// file disables the buffer instead of being ignored.
+//
+// Credentials are deliberately absent: the auth token comes from the
+// profile, the environment, or --auth-token, so the file can be committed
+// alongside a project.
+type proxyFileConfig struct {
+	OtlpUrl string `json:"otlp-url,omitempty"`
+	Dataset string `json:"dataset,omitempty"`
+
+	HTTPPort      *int  `json:"http-port,omitempty"`
+	GRPCPort      *int  `json:"grpc-port,omitempty"`
+	QueryPort     *int  `json:"query-port,omitempty"`
+	RecentRecords *int  `json:"recent-records,omitempty"`
+	AdminPort     *int  `json:"admin-port,omitempty"`
+	Tail          *bool `json:"tail,omitempty"`
+
+	ResourceAttributes map[string]any `json:"resource-attributes,omitempty"`
+	ScopeAttributes    map[string]any `json:"scope-attributes,omitempty"`
+	ScopeName          string         `json:"scope-name,omitempty"`
+	ScopeVersion       string         `json:"scope-version,omitempty"`
+	LogAttributes      map[string]any `json:"log-attributes,omitempty"`
+	SpanAttributes     map[string]any `json:"span-attributes,omitempty"`
+	MetricAttributes   map[string]any `json:"metric-attributes,omitempty"`
+
+	Detect          []string `json:"detect,omitempty"`
+	DetectOverwrite *bool    `json:"detect-overwrite,omitempty"`
+
+	AlsoForward        []string `json:"also-forward,omitempty"`
+	AlsoForwardProfile []string `json:"also-forward-profile,omitempty"`
+}
+
+// loadProxyConfigFile reads and parses a --config file. Unknown keys are
+// rejected so a typo does not silently leave a setting at its default.
+func loadProxyConfigFile(path string) (*proxyFileConfig, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	cfg, err := parseProxyConfig(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, data, nil
+}
+
+func parseProxyConfig(data []byte) (*proxyFileConfig, error) {
+	cfg := &proxyFileConfig{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return cfg, nil
+	}
+	if err := sigsyaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, err
+	}
+	// Convert the attribute maps up front so a nested value fails at load
+	// time, not on the first reload that happens to touch it.
+	for name, attrs := range map[string]map[string]any{
+		"resource-attributes": cfg.ResourceAttributes,
+		"scope-attributes":    cfg.ScopeAttributes,
+		"log-attributes":      cfg.LogAttributes,
+		"span-attributes":     cfg.SpanAttributes,
+		"metric-attributes":   cfg.MetricAttributes,
+	} {
+		if _, err := attributePairs(attrs); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// attributePairs turns a config attribute map into the flags' `key=value`
+// form, sorted by key for a deterministic result. Scalar YAML values are
+// accepted so `service.version: 1.2` does not need quoting.
+func attributePairs(attrs map[string]any) ([]string, error) {
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			return nil, errors.New("empty attribute key")
+		}
+		var v string
+		switch value := attrs[k].(type) {
+		case string:
+			v = value
+		case bool:
+			v = strconv.FormatBool(value)
+		case float64:
+			v = strconv.FormatFloat(value, 'f', -1, 64)
+		case nil:
+			v = ""
+		default:
+			return nil, fmt.Errorf("attribute %q must be a string, number, or boolean", k)
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+	return pairs, nil
+}
+
+// applyProxyConfig copies the config file's values into flags for every
+// flag that was not set on the command line. Ports are handled by
+// resolveEnvOverrides, which slots the file between the DASH0_OTLP_PROXY_*
+// variables and the OTEL_EXPORTER_OTLP_* ones.
+func applyProxyConfig(cmd *cobra.Command, flags *proxyFlags, cfg *proxyFileConfig) {
+	changed := cmd.Flags().Changed
+	if !changed("otlp-url") && cfg.OtlpUrl != "" {
+		flags.OtlpUrl = cfg.OtlpUrl
+	}
+	if !changed("dataset") && cfg.Dataset != "" {
+		flags.Dataset = cfg.Dataset
+	}
+	if !changed("recent-records") && cfg.RecentRecords != nil {
+		flags.RecentRecords = *cfg.RecentRecords
+	}
+	if !changed("tail") && cfg.Tail != nil {
+		flags.Tail = *cfg.Tail
+	}
+	if !changed("detect") && cfg.Detect != nil {
+		flags.Detect = cfg.Detect
+	}
+	if !changed("detect-overwrite") && cfg.DetectOverwrite != nil {
+		flags.DetectOverwrite = *cfg.DetectOverwrite
+	}
+	if !changed("also-forward") && cfg.AlsoForward != nil {
+		flags.AlsoForward = cfg.AlsoForward
+	}
+	if !changed("also-forward-profile") && cfg.AlsoForwardProfile != nil {
+		flags.AlsoForwardProfile = cfg.AlsoForwardProfile
+	}
+	applyDecorationConfig(changed, flags, cfg)
+}
+
+// applyDecorationConfig sets the decoration fields of flags from cfg,
+// except for those given on the command line. Unlike the other settings, a
+// decoration key missing from the file clears the value: on reload,
+// deleting a line from the file removes that decoration.
+func applyDecorationConfig(changed func(string) bool, flags *proxyFlags, cfg *proxyFileConfig) {
+	// Errors were reported when the file was parsed.
+	if !changed("resource-attribute") {
+		flags.ResourceAttributes, _ = attributePairs(cfg.ResourceAttributes)
+	}
+	if !changed("scope-attribute") {
+		flags.ScopeAttributes, _ = attributePairs(cfg.ScopeAttributes)
+	}
+	if !changed("scope-name") {
+		flags.ScopeName = cfg.ScopeName
+	}
+	if !changed("scope-version") {
+		flags.ScopeVersion = cfg.ScopeVersion
+	}
+	if !changed("log-attribute") {
+		flags.LogAttributes, _ = attributePairs(cfg.LogAttributes)
+	}
+	if !changed("span-attribute") {
+		flags.SpanAttributes, _ = attributePairs(cfg.SpanAttributes)
+	}
+	if !changed("metric-attribute") {
+		flags.MetricAttributes, _ = attributePairs(cfg.MetricAttributes)
+	}
+}
+
+// buildDecorator parses the decoration flags into a Decorator, together
+// with the resource attributes found by --detect. Errors name the offending
+// flag.
+func buildDecorator(flags *proxyFlags) (*Decorator, error) {
+	resourceAttrs, err := ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("--resource-attribute: %w", err)
+	}
+	scopeAttrs, err := ParseKeyValuePairs(flags.ScopeAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("--scope-attribute: %w", err)
+	}
+	logAttrs, err := ParseKeyValuePairs(flags.LogAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("--log-attribute: %w", err)
+	}
+	spanAttrs, err := ParseKeyValuePairs(flags.SpanAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("--span-attribute: %w", err)
+	}
+	metricAttrs, err := ParseKeyValuePairs(flags.MetricAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("--metric-attribute: %w", err)
+	}
+	var defaults map[string]string
+	if len(flags.detected) > 0 {
+		if flags.DetectOverwrite {
+			merged := maps.Clone(flags.detected)
+			maps.Copy(merged, resourceAttrs)
+			resourceAttrs = merged
+		} else {
+			defaults = flags.detected
+		}
+	}
+	return NewDecorator(
+		resourceAttrs, scopeAttrs,
+		flags.ScopeName, flags.ScopeVersion,
+		logAttrs, spanAttrs, metricAttrs,
+	).withResourceDefaults(defaults), nil
+}
+
+// ConfigWatcher re-applies the decoration settings of a --config file to
+// the running worker pool whenever the file changes. Only decoration is
+// reloaded: listeners, destinations, and buffers are fixed at startup, and
+// changes to them are reported as needing a restart. A file that fails to
+// parse is reported and otherwise ignored, so the proxy keeps forwarding
+// with the last good decoration.
+type ConfigWatcher struct {
+	path        string
+	cmd         *cobra.Command
+	flags       proxyFlags
+	workers     *WorkerPool
+	lifecycleCh chan<- LifecycleEvent
+
+	// last is the content the running proxy reflects; lastCfg is its
+	// parsed form, used to detect changes that need a restart.
+	last    []byte
+	lastCfg *proxyFileConfig
+	size    int64
+	modTime time.Time
+
+	interval time.Duration
+}
+
+// NewConfigWatcher returns a watcher for path. flags are the resolved
+// startup flags, and data and cfg the file content they were built from.
+func NewConfigWatcher(path string, cmd *cobra.Command, flags *proxyFlags, data []byte, cfg *proxyFileConfig, workers *WorkerPool, lifecycleCh chan<- LifecycleEvent) *ConfigWatcher {
+	w := &ConfigWatcher{
+		path:        path,
+		cmd:         cmd,
+		flags:       *flags,
+		workers:     workers,
+		lifecycleCh: lifecycleCh,
+		last:        data,
+		lastCfg:     cfg,
+		interval:    configReloadInterval,
+	}
+	if info, err := os.Stat(path); err == nil {
+		w.size, w.modTime = info.Size(), info.ModTime()
+	}
+	return w
+}
+
+// Run polls the file until ctx is done.
+func (w *ConfigWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the file if its size or modification time changed since
+// the last check.
+func (w *ConfigWatcher) check() {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		// Editors briefly remove the file while saving; wait for it to
+		// come back rather than reporting every missed tick.
+		return
+	}
+	if info.Size() == w.size && info.ModTime().Equal(w.modTime) {
+		return
+	}
+	w.size, w.modTime = info.Size(), info.ModTime()
+	w.reload()
+}
+
+func (w *ConfigWatcher) reload() {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		w.report(LifecycleError, fmt.Sprintf("config reload failed: %v; keeping the previous decoration", err))
+		return
+	}
+	if bytes.Equal(data, w.last) {
+		return
+	}
+	cfg, err := parseProxyConfig(data)
+	if err != nil {
+		w.report(LifecycleError, fmt.Sprintf("config reload failed: invalid config file %s: %v; keeping the previous decoration", w.path, err))
+		return
+	}
+	next := w.flags
+	applyDecorationConfig(w.cmd.Flags().Changed, &next, cfg)
+	decorator, err := buildDecorator(&next)
+	if err != nil {
+		w.report(LifecycleError, fmt.Sprintf("config reload failed: %v; keeping the previous decoration", err))
+		return
+	}
+
+	w.workers.SetDecorator(decorator)
+	w.flags = next
+	w.last = data
+	restart := restartRequiredKeys(w.lastCfg, cfg)
+	w.lastCfg = cfg
+
+	w.report(LifecycleInfo, fmt.Sprintf("Reloaded decoration from %s", w.path))
+	if len(restart) > 0 {
+		w.report(LifecycleWarning, fmt.Sprintf("config changes to %s take effect after a restart", strings.Join(restart, ", ")))
+	}
+}
+
+func (w *ConfigWatcher) report(kind LifecycleKind, message string) {
+	if w.lifecycleCh == nil {
+		return
+	}
+	select {
+	case w.lifecycleCh <- LifecycleEvent{Kind: kind, Message: message}:
+	default:
+	}
+}
+
+// restartRequiredKeys lists the settings that differ between two versions
+// of the file but are only read at startup.
+func restartRequiredKeys(before, after *proxyFileConfig) []string {
+	var keys []string
+	if before.OtlpUrl != after.OtlpUrl {
+		keys = append(keys, "otlp-url")
+	}
+	if before.Dataset != after.Dataset {
+		keys = append(keys, "dataset")
+	}
+	for _, p := range []struct {
+		key           string
+		before, after *int
+	}{
+		{"http-port", before.HTTPPort, after.HTTPPort},
+		{"grpc-port", before.GRPCPort, after.GRPCPort},
+		{"query-port", before.QueryPort, after.QueryPort},
+		{"recent-records", before.RecentRecords, after.RecentRecords},
+		{"admin-port", before.AdminPort, after.AdminPort},
+	} {
+		if ptrChanged(p.before, p.after) {
+			keys = append(keys, p.key)
+		}
+	}
+	if ptrChanged(before.Tail, after.Tail) {
+		keys = append(keys, "tail")
+	}
+	if !slices.Equal(before.Detect, after.Detect) {
+		keys = append(keys, "detect")
+	}
+	if ptrChanged(before.DetectOverwrite, after.DetectOverwrite) {
+		keys = append(keys, "detect-overwrite")
+	}
+	if !slices.Equal(before.AlsoForward, after.AlsoForward) {
+		keys = append(keys, "also-forward")
+	}
+	if !slices.Equal(before.AlsoForwardProfile, after.AlsoForwardProfile) {
+		keys = append(keys, "also-forward-profile")
+	}
+	return keys
+}
+
+// ptrChanged reports whether an optional setting was added, removed, or
+// changed between two versions of the file.
+func ptrChanged[T comparable](before, after *T) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+	return *before != *after
+}
@@ -0,0 +1,268 @@
+package otlp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func writeProxyConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "proxy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+// resolveWithConfig parses args on a fresh proxy command and resolves the
+// config file plus env overrides, like the command's RunE does.
+func resolveWithConfig(t *testing.T, args ...string) (*proxyFlags, error) {
+	t.Helper()
+	cmd := newProxyCmd()
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	flags := readFlagsFromCmd(t, cmd)
+	flags.QueryPort, _ = cmd.Flags().GetInt("query-port")
+	flags.RecentRecords, _ = cmd.Flags().GetInt("recent-records")
+	flags.ResourceAttributes, _ = cmd.Flags().GetStringArray("resource-attribute")
+	flags.ScopeName, _ = cmd.Flags().GetString("scope-name")
+	return flags, resolveEnvOverrides(cmd, flags)
+}
+
+func TestParseProxyConfig(t *testing.T) {
+	cfg, err := parseProxyConfig([]byte(`
+http-port: 5318
+recent-records: 0
+resource-attributes:
+  deployment.environment.name: local
+  service.version: 1.20
+  feature.enabled: true
+detect: [host, git]
+detect-overwrite: true
+also-forward:
+  - http://localhost:14318
+`))
+	if err != nil {
+		t.Fatalf("parseProxyConfig: %v", err)
+	}
+	if cfg.HTTPPort == nil || *cfg.HTTPPort != 5318 {
+		t.Errorf("http-port = %v; want 5318", cfg.HTTPPort)
+	}
+	if cfg.RecentRecords == nil || *cfg.RecentRecords != 0 {
+		t.Errorf("recent-records = %v; want an explicit 0", cfg.RecentRecords)
+	}
+	if cfg.GRPCPort != nil {
+		t.Errorf("grpc-port = %v; want unset", *cfg.GRPCPort)
+	}
+	if strings.Join(cfg.Detect, ",") != "host,git" || cfg.DetectOverwrite == nil || !*cfg.DetectOverwrite {
+		t.Errorf("detect = %v, detect-overwrite = %v; want host,git and true", cfg.Detect, cfg.DetectOverwrite)
+	}
+	pairs, err := attributePairs(cfg.ResourceAttributes)
+	if err != nil {
+		t.Fatalf("attributePairs: %v", err)
+	}
+	want := "deployment.environment.name=local,feature.enabled=true,service.version=1.2"
+	if got := strings.Join(pairs, ","); got != want {
+		t.Errorf("resource attribute pairs = %q; want %q", got, want)
+	}
+}
+
+func TestParseProxyConfig_Errors(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown key", "http_port: 5318\n", "http_port"},
+		{"credentials are not accepted", "auth-token: secret\n", "auth-token"},
+		{"nested attribute value", "span-attributes:\n  a:\n    b: c\n", "span-attributes"},
+		{"wrong type", "http-port: high\n", "http-port"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseProxyConfig([]byte(tc.content))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v; want it to mention %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseProxyConfig_EmptyFile(t *testing.T) {
+	if _, err := parseProxyConfig([]byte("\n")); err != nil {
+		t.Errorf("empty file should be valid; got %v", err)
+	}
+}
+
+func TestResolveEnvOverrides_ConfigFilePrecedence(t *testing.T) {
+	clearOTELEnv(t)
+	t.Setenv(envHTTPPort, "")
+	t.Setenv(envGRPCPort, "8317")
+	t.Setenv(envQueryPort, "")
+	t.Setenv(envAdminPort, "")
+	path := writeProxyConfig(t, `
+http-port: 5318
+grpc-port: 5317
+query-port: 5319
+recent-records: 0
+scope-name: from-file
+resource-attributes:
+  team: file
+`)
+
+	flags, err := resolveWithConfig(t, "--config", path, "--query-port", "6319", "--resource-attribute", "team=flag")
+	if err != nil {
+		t.Fatalf("resolveEnvOverrides: %v", err)
+	}
+	if flags.HTTPPort != 5318 {
+		t.Errorf("HTTPPort = %d; want 5318 from the file", flags.HTTPPort)
+	}
+	if flags.GRPCPort != 8317 {
+		t.Errorf("GRPCPort = %d; want 8317 (env wins over the file)", flags.GRPCPort)
+	}
+	if flags.QueryPort != 6319 {
+		t.Errorf("QueryPort = %d; want 6319 (flag wins over the file)", flags.QueryPort)
+	}
+	if flags.RecentRecords != 0 {
+		t.Errorf("RecentRecords = %d; want 0 from the file", flags.RecentRecords)
+	}
+	if flags.ScopeName != "from-file" {
+		t.Errorf("ScopeName = %q; want from-file", flags.ScopeName)
+	}
+	if got := strings.Join(flags.ResourceAttributes, ","); got != "team=flag" {
+		t.Errorf("ResourceAttributes = %q; want the flag's value", got)
+	}
+}
+
+func TestResolveEnvOverrides_ConfigFileBeatsOTELEnv(t *testing.T) {
+	t.Setenv(envHTTPPort, "")
+	t.Setenv(envOTELEndpoint, "http://localhost:7318")
+	t.Setenv(envOTELProtocol, "http/protobuf")
+	path := writeProxyConfig(t, "http-port: 5318\n")
+
+	flags, err := resolveWithConfig(t, "--config", path)
+	if err != nil {
+		t.Fatalf("resolveEnvOverrides: %v", err)
+	}
+	if flags.HTTPPort != 5318 {
+		t.Errorf("HTTPPort = %d; want 5318 (file) over 7318 (OTEL env)", flags.HTTPPort)
+	}
+}
+
+func TestResolveEnvOverrides_InvalidConfigFile(t *testing.T) {
+	clearOTELEnv(t)
+	path := writeProxyConfig(t, "tail: maybe\n")
+
+	_, err := resolveWithConfig(t, "--config", path)
+	if err == nil || !strings.Contains(err.Error(), "invalid config file") {
+		t.Errorf("error = %v; want invalid config file", err)
+	}
+	_, err = resolveWithConfig(t, "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "failed to read config file") {
+		t.Errorf("error = %v; want failed to read", err)
+	}
+}
+
+func TestConfigWatcher_ReloadsDecoration(t *testing.T) {
+	clearOTELEnv(t)
+	path := writeProxyConfig(t, "resource-attributes:\n  team: before\n")
+	cmd := newProxyCmd()
+	if err := cmd.ParseFlags([]string{"--config", path, "--scope-name", "pinned"}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	flags := readFlagsFromCmd(t, cmd)
+	flags.ScopeName = "pinned"
+	if err := resolveEnvOverrides(cmd, flags); err != nil {
+		t.Fatalf("resolveEnvOverrides: %v", err)
+	}
+	decorator, err := buildDecorator(flags)
+	if err != nil {
+		t.Fatalf("buildDecorator: %v", err)
+	}
+	stats := &Stats{}
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	pool := NewWorkerPool(&fakeForwarder{}, nil, stats, NewEmitter("inst", nil), consumer, nil, decorator)
+	lifecycleCh := make(chan LifecycleEvent, 4)
+	w := NewConfigWatcher(path, cmd, flags, flags.fileData, flags.fileConfig, pool, lifecycleCh)
+
+	decorate := func() plog.Logs {
+		ld := newLogsBatch(1)
+		pool.decorator.Load().DecorateLogs(ld)
+		return ld
+	}
+	resourceTeam := func(ld plog.Logs) string {
+		v, _ := ld.ResourceLogs().At(0).Resource().Attributes().Get("team")
+		return v.AsString()
+	}
+	if got := resourceTeam(decorate()); got != "before" {
+		t.Fatalf("team before reload = %q; want before", got)
+	}
+
+	// A new modification time guarantees the change is seen even on
+	// filesystems with coarse timestamps.
+	if err := os.WriteFile(path, []byte("resource-attributes:\n  team: after\nscope-name: ignored\nhttp-port: 9999\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	w.check()
+
+	ld := decorate()
+	if got := resourceTeam(ld); got != "after" {
+		t.Errorf("team after reload = %q; want after", got)
+	}
+	if got := ld.ResourceLogs().At(0).ScopeLogs().At(0).Scope().Name(); got != "pinned" {
+		t.Errorf("scope name = %q; want the --scope-name flag to keep winning over the file", got)
+	}
+	var messages []string
+	for len(lifecycleCh) > 0 {
+		messages = append(messages, (<-lifecycleCh).Message)
+	}
+	joined := strings.Join(messages, "\n")
+	if !strings.Contains(joined, "Reloaded decoration") || !strings.Contains(joined, "http-port") {
+		t.Errorf("lifecycle messages = %q; want a reload notice and a restart warning for http-port", joined)
+	}
+
+	// An invalid edit keeps the previous decoration.
+	if err := os.WriteFile(path, []byte("resource-attributes: [\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := future.Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	w.check()
+	if got := resourceTeam(decorate()); got != "after" {
+		t.Errorf("team after invalid edit = %q; want after (previous decoration kept)", got)
+	}
+	if ev := <-lifecycleCh; ev.Kind != LifecycleError || !strings.Contains(ev.Message, "config reload failed") {
+		t.Errorf("lifecycle event = %+v; want a reload failure", ev)
+	}
+}
+
+func TestConfigWatcher_RunStopsOnCancel(t *testing.T) {
+	path := writeProxyConfig(t, "")
+	w := NewConfigWatcher(path, newProxyCmd(), &proxyFlags{}, nil, &proxyFileConfig{}, nil, nil)
+	w.interval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+}
@@ -38,9 +38,10 @@ var errQueueFull = errors.New("proxy queue full")
 //      sparkline (U7) and the agent-mode stats event (U8).
 //   2. agent-mode `dash0.cli.otlp_proxy.forwarded` event emitted (U8).
 //   3. --tail rendering pushed to TailCh when --tail is enabled (U7/U9).
-//   4. Non-blocking enqueue to the per-signal channel.
-//   5. Non-empty channel → return nil (200). Full channel → return retryable
-//      error (503 + SDK exponential backoff).
+//   4. Non-blocking enqueue to every destination's per-signal channel.
+//   5. At least one destination accepted → return nil (200). Every
+//      destination full → return retryable error (503 + SDK exponential
+//      backoff).
 type ProxyConsumer struct {
 	stats   *Stats
 	emitter *Emitter
@@ -50,36 +51,83 @@ type ProxyConsumer struct {
 	// hot path.
 	tailCh chan<- string
 
-	// Per-signal channels read by the worker pool (U4). Exposed via
-	// accessor methods so the workers can pick them up without holding a
-	// direct reference to the consumer.
-	logsCh    chan plog.Logs
-	tracesCh  chan ptrace.Traces
-	metricsCh chan pmetric.Metrics
+	// queues holds one set of per-signal channels per destination. Index 0
+	// is the primary destination and always exists; --also-forward
+	// destinations are appended by the worker pool before the pipeline
+	// starts, so the slice is never mutated on the hot path.
+	queues []*signalQueues
+
+	// recent, when non-nil, receives a copy of every accepted batch for
+	// the local query API. Set before the pipeline starts.
+	recent *RecentBuffer
+}
+
+// signalQueues is one destination's set of per-signal channels. Keeping a
+// separate set per destination means a slow upstream only ever fills its
+// own channels and never stalls delivery to the others.
+type signalQueues struct {
+	logs    chan plog.Logs
+	traces  chan ptrace.Traces
+	metrics chan pmetric.Metrics
+
+	// stats, when non-nil, receives the forwarded count for every batch
+	// this destination accepts. nil for the primary destination, whose
+	// forwarded counter is the proxy-wide one bumped in observe.
+	stats *Stats
+
+	// onDrop is called when this destination's channel was full while
+	// another destination accepted the batch. May be nil.
+	onDrop func(sig Signal, count int)
 }
 
-// NewProxyConsumer constructs the consumer plus its three per-signal
-// channels. tailCh may be nil; when non-nil the consumer pushes the
-// debug-exporter-style rendering of each inbound batch to it.
+func newSignalQueues() *signalQueues {
+	return &signalQueues{
+		logs:    make(chan plog.Logs, signalQueueDepth),
+		traces:  make(chan ptrace.Traces, signalQueueDepth),
+		metrics: make(chan pmetric.Metrics, signalQueueDepth),
+	}
+}
+
+// NewProxyConsumer constructs the consumer plus the primary destination's
+// three per-signal channels. tailCh may be nil; when non-nil the consumer
+// pushes the debug-exporter-style rendering of each inbound batch to it.
 func NewProxyConsumer(stats *Stats, emitter *Emitter, tailCh chan<- string) *ProxyConsumer {
 	return &ProxyConsumer{
-		stats:     stats,
-		emitter:   emitter,
-		tailCh:    tailCh,
-		logsCh:    make(chan plog.Logs, signalQueueDepth),
-		tracesCh:  make(chan ptrace.Traces, signalQueueDepth),
-		metricsCh: make(chan pmetric.Metrics, signalQueueDepth),
+		stats:   stats,
+		emitter: emitter,
+		tailCh:  tailCh,
+		queues:  []*signalQueues{newSignalQueues()},
 	}
 }
 
-// LogsChannel returns the channel the worker pool drains for log batches.
-func (c *ProxyConsumer) LogsChannel() <-chan plog.Logs { return c.logsCh }
+// LogsChannel returns the channel the worker pool drains for log batches
+// bound for the primary destination.
+func (c *ProxyConsumer) LogsChannel() <-chan plog.Logs { return c.queues[0].logs }
+
+// TracesChannel returns the channel the worker pool drains for trace
+// batches bound for the primary destination.
+func (c *ProxyConsumer) TracesChannel() <-chan ptrace.Traces { return c.queues[0].traces }
+
+// MetricsChannel returns the channel the worker pool drains for metric
+// batches bound for the primary destination.
+func (c *ProxyConsumer) MetricsChannel() <-chan pmetric.Metrics { return c.queues[0].metrics }
 
-// TracesChannel returns the channel the worker pool drains for trace batches.
-func (c *ProxyConsumer) TracesChannel() <-chan ptrace.Traces { return c.tracesCh }
+// addQueues registers an additional destination and returns its channels.
+// Must be called before the pipeline starts; the consumer reads the queue
+// list without locking.
+func (c *ProxyConsumer) addQueues(stats *Stats, onDrop func(sig Signal, count int)) *signalQueues {
+	q := newSignalQueues()
+	q.stats = stats
+	q.onDrop = onDrop
+	c.queues = append(c.queues, q)
+	return q
+}
 
-// MetricsChannel returns the channel the worker pool drains for metric batches.
-func (c *ProxyConsumer) MetricsChannel() <-chan pmetric.Metrics { return c.metricsCh }
+// setRecentBuffer makes the consumer keep a copy of every accepted batch
+// in buf. Must be called before the pipeline starts.
+func (c *ProxyConsumer) setRecentBuffer(buf *RecentBuffer) {
+	c.recent = buf
+}
 
 // Capabilities reports MutatesData=false so the receiver knows it can hand
 // us the pdata directly without making a defensive copy. The consumer
@@ -94,12 +142,36 @@ func (c *ProxyConsumer) ConsumeLogs(_ context.Context, ld plog.Logs) error {
 	count := ld.LogRecordCount()
 	c.observe(SignalLogs, count, func() string { return RenderLogs(ld) })
 
-	select {
-	case c.logsCh <- ld:
-		return nil
-	default:
-		return consumererror.NewRetryableError(errQueueFull)
+	batches := make([]plog.Logs, len(c.queues))
+	for i := range batches {
+		batches[i] = ld
+		if i > 0 {
+			// Every extra destination gets its own copy: workers decorate
+			// batches in place, and two workers mutating one pdata tree
+			// would race.
+			batches[i] = plog.NewLogs()
+			ld.CopyTo(batches[i])
+		}
+	}
+	// Copy for the recent buffer before enqueueing: once a worker holds
+	// the batch it may decorate it concurrently.
+	var recent plog.Logs
+	if c.recent != nil {
+		recent = plog.NewLogs()
+		ld.CopyTo(recent)
 	}
+	err := c.fanOut(SignalLogs, count, func(q *signalQueues, i int) bool {
+		select {
+		case q.logs <- batches[i]:
+			return true
+		default:
+			return false
+		}
+	})
+	if err == nil {
+		c.recent.addLogs(recent)
+	}
+	return err
 }
 
 // ConsumeTraces implements consumer.Traces.
@@ -107,12 +179,31 @@ func (c *ProxyConsumer) ConsumeTraces(_ context.Context, td ptrace.Traces) error
 	count := td.SpanCount()
 	c.observe(SignalSpans, count, func() string { return RenderTraces(td) })
 
-	select {
-	case c.tracesCh <- td:
-		return nil
-	default:
-		return consumererror.NewRetryableError(errQueueFull)
+	batches := make([]ptrace.Traces, len(c.queues))
+	for i := range batches {
+		batches[i] = td
+		if i > 0 {
+			batches[i] = ptrace.NewTraces()
+			td.CopyTo(batches[i])
+		}
+	}
+	var recent ptrace.Traces
+	if c.recent != nil {
+		recent = ptrace.NewTraces()
+		td.CopyTo(recent)
+	}
+	err := c.fanOut(SignalSpans, count, func(q *signalQueues, i int) bool {
+		select {
+		case q.traces <- batches[i]:
+			return true
+		default:
+			return false
+		}
+	})
+	if err == nil {
+		c.recent.addTraces(recent)
 	}
+	return err
 }
 
 // ConsumeMetrics implements consumer.Metrics.
@@ -120,12 +211,60 @@ func (c *ProxyConsumer) ConsumeMetrics(_ context.Context, md pmetric.Metrics) er
 	count := md.DataPointCount()
 	c.observe(SignalMetrics, count, func() string { return RenderMetrics(md) })
 
-	select {
-	case c.metricsCh <- md:
-		return nil
-	default:
+	batches := make([]pmetric.Metrics, len(c.queues))
+	for i := range batches {
+		batches[i] = md
+		if i > 0 {
+			batches[i] = pmetric.NewMetrics()
+			md.CopyTo(batches[i])
+		}
+	}
+	var recent pmetric.Metrics
+	if c.recent != nil {
+		recent = pmetric.NewMetrics()
+		md.CopyTo(recent)
+	}
+	err := c.fanOut(SignalMetrics, count, func(q *signalQueues, i int) bool {
+		select {
+		case q.metrics <- batches[i]:
+			return true
+		default:
+			return false
+		}
+	})
+	if err == nil {
+		c.recent.addMetrics(recent)
+	}
+	return err
+}
+
+// fanOut offers one batch to every destination via the non-blocking
+// enqueue callback. The SDK only sees backpressure when no destination had
+// room: as long as one accepted, the batch counts as delivered to this hop
+// and each destination that was full records a drop against its own
+// stats. With a single destination this reduces to the original "full
+// channel → 503" contract.
+func (c *ProxyConsumer) fanOut(sig Signal, count int, enqueue func(q *signalQueues, i int) bool) error {
+	accepted := make([]bool, len(c.queues))
+	anyAccepted := false
+	for i, q := range c.queues {
+		accepted[i] = enqueue(q, i)
+		if accepted[i] {
+			anyAccepted = true
+		}
+	}
+	if !anyAccepted {
 		return consumererror.NewRetryableError(errQueueFull)
 	}
+	for i, q := range c.queues {
+		switch {
+		case accepted[i] && q.stats != nil:
+			q.stats.RecordForwarded(sig, count)
+		case !accepted[i] && q.onDrop != nil:
+			q.onDrop(sig, count)
+		}
+	}
+	return nil
 }
 
 // observe fans the lifecycle side-effects of an accepted batch out to the
@@ -274,3 +274,76 @@ func indexOf(s, sub string) int {
 	}
 	return -1
 }
+
+func TestProxyConsumer_FanOutCopiesBatchPerDestination(t *testing.T) {
+	stats := &Stats{}
+	c := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	extraStats := &Stats{}
+	extra := c.addQueues(extraStats, nil)
+
+	if err := c.ConsumeLogs(context.Background(), newLogsBatch(2)); err != nil {
+		t.Fatalf("ConsumeLogs: %v", err)
+	}
+
+	primary := <-c.LogsChannel()
+	copied := <-extra.logs
+	if copied.LogRecordCount() != 2 {
+		t.Errorf("extra destination got %d records; want 2", copied.LogRecordCount())
+	}
+	// Workers decorate in place, so the destinations must not share a
+	// pdata tree.
+	primary.ResourceLogs().At(0).Resource().Attributes().PutStr("only.primary", "x")
+	if _, ok := copied.ResourceLogs().At(0).Resource().Attributes().Get("only.primary"); ok {
+		t.Error("extra destination shares pdata with the primary; want an independent copy")
+	}
+	if got := extraStats.Forwarded(SignalLogs); got != 2 {
+		t.Errorf("extra Forwarded(logs) = %d; want 2", got)
+	}
+}
+
+func TestProxyConsumer_FanOutOneQueueFullStillAccepts(t *testing.T) {
+	stats := &Stats{}
+	c := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	var dropped int
+	extra := c.addQueues(&Stats{}, func(sig Signal, count int) {
+		if sig != SignalLogs {
+			t.Errorf("drop signal = %v; want logs", sig)
+		}
+		dropped += count
+	})
+
+	// Fill only the extra destination's channel.
+	for i := 0; i < signalQueueDepth; i++ {
+		extra.logs <- newLogsBatch(1)
+	}
+
+	if err := c.ConsumeLogs(context.Background(), newLogsBatch(3)); err != nil {
+		t.Fatalf("ConsumeLogs should succeed while the primary has room; got %v", err)
+	}
+	if dropped != 3 {
+		t.Errorf("dropped = %d; want 3 reported for the full destination", dropped)
+	}
+	if len(c.LogsChannel()) != 1 {
+		t.Errorf("primary queue length = %d; want 1", len(c.LogsChannel()))
+	}
+}
+
+func TestProxyConsumer_FanOutAllQueuesFullReturnsRetryableError(t *testing.T) {
+	stats := &Stats{}
+	c := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	extra := c.addQueues(&Stats{}, func(Signal, int) {
+		t.Error("onDrop must not fire when the batch is rejected outright")
+	})
+	for i := 0; i < signalQueueDepth; i++ {
+		c.queues[0].traces <- newTracesBatch(1)
+		extra.traces <- newTracesBatch(1)
+	}
+
+	err := c.ConsumeTraces(context.Background(), newTracesBatch(1))
+	if err == nil {
+		t.Fatal("ConsumeTraces should error when every destination is full")
+	}
+	if consumererror.IsPermanent(err) {
+		t.Error("all-full error should be retryable")
+	}
+}
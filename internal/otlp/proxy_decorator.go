@@ -31,6 +31,11 @@ type Decorator struct {
 	scopeName     string
 	scopeVersion  string
 
+	// resourceDefaults are resource attributes set only where the SDK did
+	// not already provide the key, such as the values found by --detect.
+	// resourceAttrs are applied after them and win on conflicts.
+	resourceDefaults map[string]string
+
 	// Per-signal record-level attribute upserts. Each is applied to
 	// every individual record of its respective signal type:
 	//   logAttrs    → every LogRecord.Attributes()
@@ -61,12 +66,21 @@ func NewDecorator(
 	}
 }
 
+// withResourceDefaults sets resource attributes that are added to a
+// resource only when it lacks the key. It must be called before the
+// decorator is handed to the worker pool.
+func (d *Decorator) withResourceDefaults(defaults map[string]string) *Decorator {
+	d.resourceDefaults = defaults
+	return d
+}
+
 // IsEmpty reports whether the decorator carries any user-provided
 // changes. Callers can skip the decoration call entirely when true,
 // avoiding the iteration cost.
 func (d *Decorator) IsEmpty() bool {
 	return d == nil ||
 		(len(d.resourceAttrs) == 0 &&
+			len(d.resourceDefaults) == 0 &&
 			len(d.scopeAttrs) == 0 &&
 			d.scopeName == "" &&
 			d.scopeVersion == "" &&
@@ -192,6 +206,11 @@ func (d *Decorator) upsertMap(attrs pcommon.Map, src map[string]string) {
 }
 
 func (d *Decorator) upsertResource(attrs pcommon.Map) {
+	for k, v := range d.resourceDefaults {
+		if _, ok := attrs.Get(k); !ok {
+			attrs.PutStr(k, v)
+		}
+	}
 	for k, v := range d.resourceAttrs {
 		attrs.PutStr(k, v)
 	}
@@ -0,0 +1,211 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/vcs"
+)
+
+// Names accepted by --detect.
+const (
+	detectorHost      = "host"
+	detectorGit       = "git"
+	detectorProcess   = "process"
+	detectorContainer = "container"
+)
+
+// resourceDetectorNames lists the detectors in the order they run, which is
+// also the order used in help texts and error messages.
+var resourceDetectorNames = []string{detectorHost, detectorGit, detectorProcess, detectorContainer}
+
+// detectTimeout bounds the whole detection run at startup. Only the git
+// detector spawns processes; the others read local state.
+const detectTimeout = 3 * time.Second
+
+// resourceDetector returns the resource attributes it found, or an error
+// explaining why it found none.
+type resourceDetector func(ctx context.Context) (map[string]string, error)
+
+var resourceDetectors = map[string]resourceDetector{
+	detectorHost:      detectHost,
+	detectorGit:       func(ctx context.Context) (map[string]string, error) { return vcs.Detect(ctx, "") },
+	detectorProcess:   detectProcess,
+	detectorContainer: detectContainer,
+}
+
+// detectResource runs the named detectors once and merges their results.
+// A detector that fails contributes a warning rather than an error, so a
+// proxy started outside a git checkout or a container still runs.
+func detectResource(ctx context.Context, names []string) (map[string]string, []string) {
+	ctx, cancel := context.WithTimeout(ctx, detectTimeout)
+	defer cancel()
+
+	attrs := make(map[string]string)
+	var warnings []string
+	for _, name := range resourceDetectorNames {
+		if !slices.Contains(names, name) {
+			continue
+		}
+		found, err := resourceDetectors[name](ctx)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s detector skipped: %v", name, err))
+			continue
+		}
+		for k, v := range found {
+			attrs[k] = v
+		}
+	}
+	return attrs, warnings
+}
+
+// detectedPairs renders detected attributes as sorted `key=value` pairs
+// for the startup message.
+func detectedPairs(attrs map[string]string) []string {
+	pairs := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// validateDetectors checks the --detect values against the known
+// detectors.
+func validateDetectors(names []string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := resourceDetectors[name]; !ok {
+			return fmt.Errorf("--detect %q is not a known detector\nHint: use one or more of %s", name, strings.Join(resourceDetectorNames, ", "))
+		}
+		if seen[name] {
+			return fmt.Errorf("--detect %q is listed more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// detectHost describes the machine the proxy runs on, which in local
+// development is the machine the instrumented application runs on.
+func detectHost(context.Context) (map[string]string, error) {
+	attrs := make(map[string]string)
+	if name, err := os.Hostname(); err == nil && name != "" {
+		attrs["host.name"] = name
+	}
+	if arch, ok := hostArch(runtime.GOARCH); ok {
+		attrs["host.arch"] = arch
+	}
+	attrs["os.type"] = osType(runtime.GOOS)
+	return attrs, nil
+}
+
+// hostArch maps a GOARCH value to the semantic-convention host.arch value.
+func hostArch(goarch string) (string, bool) {
+	switch goarch {
+	case "amd64", "arm64", "s390x":
+		return goarch, true
+	case "arm":
+		return "arm32", true
+	case "386":
+		return "x86", true
+	case "ppc64", "ppc64le":
+		return "ppc64", true
+	default:
+		return "", false
+	}
+}
+
+// osType maps a GOOS value to the semantic-convention os.type value.
+func osType(goos string) string {
+	switch goos {
+	case "dragonfly":
+		return "dragonflybsd"
+	case "illumos":
+		return "solaris"
+	case "zos":
+		return "z_os"
+	default:
+		return goos
+	}
+}
+
+// detectProcess describes the user running the proxy, which in local
+// development is the developer whose telemetry it forwards. process.pid and
+// the executable are not reported: they would describe the proxy, not the
+// application that produced the telemetry.
+func detectProcess(context.Context) (map[string]string, error) {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return map[string]string{"process.owner": u.Username}, nil
+	}
+	for _, name := range []string{"USER", "USERNAME"} {
+		if v := os.Getenv(name); v != "" {
+			return map[string]string{"process.owner": v}, nil
+		}
+	}
+	return nil, errors.New("cannot determine the current user")
+}
+
+// Paths read by the container detector; variables so tests can point them
+// at fixtures.
+var (
+	procSelfCgroup    = "/proc/self/cgroup"
+	procSelfMountinfo = "/proc/self/mountinfo"
+)
+
+var (
+	// cgroupContainerID matches the 64-hex-digit container ID at the end of
+	// a cgroup v1 path, as written by Docker, containerd, CRI-O, and
+	// Podman ("/docker/<id>", "/kubepods/.../<id>", "docker-<id>.scope").
+	cgroupContainerID = regexp.MustCompile(`([0-9a-f]{64})(?:\.scope)?$`)
+	// mountinfoContainerID matches the container directory that backs the
+	// /etc/hostname bind mount under cgroup v2, where /proc/self/cgroup
+	// only contains "0::/".
+	mountinfoContainerID = regexp.MustCompile(`containers/([0-9a-f]{64})/`)
+)
+
+// detectContainer reports the ID of the container the proxy runs in, for
+// example when it runs as a service in a docker-compose stack.
+func detectContainer(context.Context) (map[string]string, error) {
+	if data, err := os.ReadFile(procSelfCgroup); err == nil {
+		if id := containerIDFromCgroup(string(data)); id != "" {
+			return map[string]string{"container.id": id}, nil
+		}
+	}
+	if data, err := os.ReadFile(procSelfMountinfo); err == nil {
+		if id := containerIDFromMountinfo(string(data)); id != "" {
+			return map[string]string{"container.id": id}, nil
+		}
+	}
+	return nil, errors.New("not running in a container")
+}
+
+func containerIDFromCgroup(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if m := cgroupContainerID.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func containerIDFromMountinfo(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.Contains(line, "/hostname") {
+			continue
+		}
+		if m := mountinfoContainerID.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
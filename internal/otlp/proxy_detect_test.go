@@ -0,0 +1,150 @@
+package otlp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const testContainerID = "3f1c0e7d9b2a4c6e8f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6a"
+
+func TestValidateDetectors(t *testing.T) {
+	if err := validateDetectors([]string{"host", "git", "process", "container"}); err != nil {
+		t.Errorf("all detectors: %v", err)
+	}
+	err := validateDetectors([]string{"host", "cloud"})
+	if err == nil || !strings.Contains(err.Error(), `"cloud"`) || !strings.Contains(err.Error(), "host, git, process, container") {
+		t.Errorf("unknown detector error = %v", err)
+	}
+	if err := validateDetectors([]string{"git", "git"}); err == nil {
+		t.Error("a duplicate detector should be rejected")
+	}
+}
+
+func TestDetectHost(t *testing.T) {
+	attrs, err := detectHost(context.Background())
+	if err != nil {
+		t.Fatalf("detectHost: %v", err)
+	}
+	if attrs["os.type"] == "" {
+		t.Error("os.type should always be set")
+	}
+	if hostname, _ := os.Hostname(); attrs["host.name"] != hostname {
+		t.Errorf("host.name = %q; want %q", attrs["host.name"], hostname)
+	}
+}
+
+func TestHostArchAndOSType(t *testing.T) {
+	for goarch, want := range map[string]string{"amd64": "amd64", "arm": "arm32", "386": "x86", "ppc64le": "ppc64"} {
+		if got, ok := hostArch(goarch); !ok || got != want {
+			t.Errorf("hostArch(%q) = %q, %v; want %q", goarch, got, ok, want)
+		}
+	}
+	if _, ok := hostArch("wasm"); ok {
+		t.Error("hostArch(wasm) should have no semantic-convention value")
+	}
+	if got := osType("dragonfly"); got != "dragonflybsd" {
+		t.Errorf("osType(dragonfly) = %q", got)
+	}
+	if got := osType("darwin"); got != "darwin" {
+		t.Errorf("osType(darwin) = %q", got)
+	}
+}
+
+func TestContainerIDFromCgroup(t *testing.T) {
+	cases := map[string]string{
+		"12:memory:/docker/" + testContainerID + "\n":                         testContainerID,
+		"1:name=systemd:/system.slice/docker-" + testContainerID + ".scope\n": testContainerID,
+		"11:pids:/kubepods/besteffort/pod1234/" + testContainerID + "\n":      testContainerID,
+		"0::/\n": "",
+		"0::/user.slice/user-1000.slice/session-2.scope\n": "",
+	}
+	for in, want := range cases {
+		if got := containerIDFromCgroup(in); got != want {
+			t.Errorf("containerIDFromCgroup(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestContainerIDFromMountinfo(t *testing.T) {
+	docker := "612 590 254:1 /docker/containers/" + testContainerID + "/hostname /etc/hostname rw,relatime - ext4 /dev/vda1 rw\n"
+	if got := containerIDFromMountinfo(docker); got != testContainerID {
+		t.Errorf("docker mountinfo: got %q", got)
+	}
+	podman := "700 650 0:45 /containers/storage/overlay-containers/" + testContainerID + "/userdata/hostname /etc/hostname rw - tmpfs tmpfs rw\n"
+	if got := containerIDFromMountinfo(podman); got != testContainerID {
+		t.Errorf("podman mountinfo: got %q", got)
+	}
+	if got := containerIDFromMountinfo("25 1 8:1 / / rw,relatime - ext4 /dev/sda1 rw\n"); got != "" {
+		t.Errorf("host mountinfo: got %q; want none", got)
+	}
+}
+
+func TestDetectResource_FailingDetectorWarns(t *testing.T) {
+	dir := t.TempDir()
+	procSelfCgroup, procSelfMountinfo = filepath.Join(dir, "cgroup"), filepath.Join(dir, "mountinfo")
+	t.Cleanup(func() { procSelfCgroup, procSelfMountinfo = "/proc/self/cgroup", "/proc/self/mountinfo" })
+
+	attrs, warnings := detectResource(context.Background(), []string{"container", "host"})
+	if attrs["host.name"] == "" && attrs["os.type"] == "" {
+		t.Errorf("host attributes missing: %v", attrs)
+	}
+	if len(warnings) != 1 || warnings[0] != "container detector skipped: not running in a container" {
+		t.Errorf("warnings = %q", warnings)
+	}
+
+	if err := os.WriteFile(procSelfCgroup, []byte("12:memory:/docker/"+testContainerID+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	attrs, warnings = detectResource(context.Background(), []string{"container"})
+	if attrs["container.id"] != testContainerID || len(warnings) != 0 {
+		t.Errorf("attrs = %v, warnings = %q", attrs, warnings)
+	}
+}
+
+func TestBuildDecorator_DetectedAttributes(t *testing.T) {
+	sdkResource := func() plog.Logs {
+		ld := plog.NewLogs()
+		attrs := ld.ResourceLogs().AppendEmpty().Resource().Attributes()
+		attrs.PutStr("host.name", "from-sdk")
+		return ld
+	}
+	detected := map[string]string{"host.name": "laptop", "vcs.ref.head.name": "main", "process.owner": "alice"}
+
+	flags := &proxyFlags{detected: detected, ResourceAttributes: []string{"process.owner=bob"}}
+	d, err := buildDecorator(flags)
+	if err != nil {
+		t.Fatalf("buildDecorator: %v", err)
+	}
+	ld := sdkResource()
+	d.DecorateLogs(ld)
+	attrs := ld.ResourceLogs().At(0).Resource().Attributes().AsRaw()
+	if attrs["host.name"] != "from-sdk" {
+		t.Errorf("host.name = %v; the SDK's value must be kept without --detect-overwrite", attrs["host.name"])
+	}
+	if attrs["vcs.ref.head.name"] != "main" {
+		t.Errorf("vcs.ref.head.name = %v; want the detected value", attrs["vcs.ref.head.name"])
+	}
+	if attrs["process.owner"] != "bob" {
+		t.Errorf("process.owner = %v; --resource-attribute must win over detection", attrs["process.owner"])
+	}
+
+	flags.DetectOverwrite = true
+	d, err = buildDecorator(flags)
+	if err != nil {
+		t.Fatalf("buildDecorator: %v", err)
+	}
+	ld = sdkResource()
+	d.DecorateLogs(ld)
+	attrs = ld.ResourceLogs().At(0).Resource().Attributes().AsRaw()
+	if attrs["host.name"] != "laptop" {
+		t.Errorf("host.name = %v; want the detected value with --detect-overwrite", attrs["host.name"])
+	}
+	if attrs["process.owner"] != "bob" {
+		t.Errorf("process.owner = %v; --resource-attribute must win over detection", attrs["process.owner"])
+	}
+}
@@ -32,8 +32,33 @@ const (
 	ErrorKindUpstream4xxAuth     ErrorKind = "upstream_4xx_auth"
 	ErrorKindUpstream4xxOther    ErrorKind = "upstream_4xx_other"
 	ErrorKindInternalPanic       ErrorKind = "internal_panic"
+	// ErrorKindQueueFull marks a batch dropped for one destination because
+	// its queue was full while another destination still accepted it.
+	ErrorKindQueueFull ErrorKind = "queue_full"
 )
 
+// errorKinds lists every ErrorKind in a stable order. Stats keeps one
+// counter per entry, and the admin /metrics endpoint exports them in this
+// order so scrapes diff cleanly.
+var errorKinds = [...]ErrorKind{
+	ErrorKindUpstreamUnreachable,
+	ErrorKindUpstream5xx,
+	ErrorKindUpstream4xxAuth,
+	ErrorKindUpstream4xxOther,
+	ErrorKindInternalPanic,
+	ErrorKindQueueFull,
+}
+
+// index returns k's position in errorKinds, or -1 for an unknown kind.
+func (k ErrorKind) index() int {
+	for i, kind := range errorKinds {
+		if kind == k {
+			return i
+		}
+	}
+	return -1
+}
+
 // Emitter builds OTLP/JSON event records about the proxy's own lifecycle and
 // sends them on a channel. The actual stdout write is performed by a
 // separate writer goroutine (StdoutWriter) so the hot path (consumer +
@@ -119,11 +144,21 @@ func (e *Emitter) EmitStats(snap SnapshotWithRate) {
 // EmitError emits an error event with KTD14 classification. code is the
 // HTTP/gRPC status code from upstream when applicable; pass 0 when not.
 func (e *Emitter) EmitError(kind ErrorKind, reason string, code int) {
+	e.EmitDestinationError("", kind, reason, code)
+}
+
+// EmitDestinationError is EmitError with the name of the destination the
+// failure belongs to. An empty destination omits the attribute, which is
+// what single-destination proxies emit.
+func (e *Emitter) EmitDestinationError(destination string, kind ErrorKind, reason string, code int) {
 	if e == nil || e.ch == nil {
 		return
 	}
 	ld := e.build(eventError, func(attrs pcommon.Map) {
 		attrs.PutStr("error.kind", string(kind))
+		if destination != "" {
+			attrs.PutStr("destination", destination)
+		}
 		if reason != "" {
 			attrs.PutStr("reason", reason)
 		}
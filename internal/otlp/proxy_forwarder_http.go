@@ -0,0 +1,101 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"github.com/dash0hq/dash0-cli/internal/version"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// otlpHTTPForwardTimeout bounds a single export to a plain OTLP/HTTP
+// destination. Local backends answer in milliseconds; anything slower is
+// treated as unreachable rather than left to stall the destination's worker.
+const otlpHTTPForwardTimeout = 30 * time.Second
+
+// maxErrorBodyBytes caps how much of a non-2xx response body is kept for
+// the error event's reason.
+const maxErrorBodyBytes = 1024
+
+// otlpHTTPForwarder implements Forwarder for `--also-forward` destinations:
+// any OTLP/HTTP endpoint (a local collector, Jaeger, another vendor's
+// ingest) reached without Dash0 credentials. Payloads are sent as
+// protobuf to `<base>/v1/{logs,traces,metrics}`, which every OTLP/HTTP
+// receiver accepts. The dataset argument is ignored — it is a Dash0
+// concept that other backends do not understand.
+type otlpHTTPForwarder struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// newOtlpHTTPForwarder returns the forwarder to baseURL; requests go through
+// transport.
+func newOtlpHTTPForwarder(baseURL string, transport http.RoundTripper) *otlpHTTPForwarder {
+	return &otlpHTTPForwarder{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Transport: transport, Timeout: otlpHTTPForwardTimeout},
+	}
+}
+
+func (f *otlpHTTPForwarder) SendLogs(ctx context.Context, ld plog.Logs, _ *string) error {
+	body, err := plogotlp.NewExportRequestFromLogs(ld).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to encode logs: %w", err)
+	}
+	return f.post(ctx, "/v1/logs", body)
+}
+
+func (f *otlpHTTPForwarder) SendTraces(ctx context.Context, td ptrace.Traces, _ *string) error {
+	body, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to encode traces: %w", err)
+	}
+	return f.post(ctx, "/v1/traces", body)
+}
+
+func (f *otlpHTTPForwarder) SendMetrics(ctx context.Context, md pmetric.Metrics, _ *string) error {
+	body, err := pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	return f.post(ctx, "/v1/metrics", body)
+}
+
+// post sends one export request. Non-2xx responses are returned as
+// *dash0api.APIError so classifyError buckets them exactly like failures
+// from the primary Dash0 destination.
+func (f *otlpHTTPForwarder) post(ctx context.Context, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", version.UserAgent())
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &dash0api.APIError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(respBody),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+)
+
+func TestOtlpHTTPForwarder_SendLogsPostsProtobuf(t *testing.T) {
+	var gotPath, gotContentType string
+	var gotRecords int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotContentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		req := plogotlp.NewExportRequest()
+		if err := req.UnmarshalProto(body); err != nil {
+			t.Errorf("request body is not an OTLP logs export request: %v", err)
+		}
+		gotRecords = req.Logs().LogRecordCount()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	f := newOtlpHTTPForwarder(server.URL+"/", http.DefaultTransport)
+	if err := f.SendLogs(context.Background(), newLogsBatch(3), nil); err != nil {
+		t.Fatalf("SendLogs: %v", err)
+	}
+	if gotPath != "/v1/logs" {
+		t.Errorf("path = %q; want /v1/logs", gotPath)
+	}
+	if gotContentType != "application/x-protobuf" {
+		t.Errorf("Content-Type = %q; want application/x-protobuf", gotContentType)
+	}
+	if gotRecords != 3 {
+		t.Errorf("records received = %d; want 3", gotRecords)
+	}
+}
+
+func TestOtlpHTTPForwarder_SignalPaths(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer server.Close()
+
+	f := newOtlpHTTPForwarder(server.URL+"/otlp", http.DefaultTransport)
+	if err := f.SendTraces(context.Background(), newTracesBatch(1), nil); err != nil {
+		t.Fatalf("SendTraces: %v", err)
+	}
+	if err := f.SendMetrics(context.Background(), newMetricsBatch(1), nil); err != nil {
+		t.Fatalf("SendMetrics: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/otlp/v1/traces" || paths[1] != "/otlp/v1/metrics" {
+		t.Errorf("paths = %v; want [/otlp/v1/traces /otlp/v1/metrics]", paths)
+	}
+}
+
+func TestOtlpHTTPForwarder_Non2xxIsClassifiable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := newOtlpHTTPForwarder(server.URL, http.DefaultTransport).SendLogs(context.Background(), newLogsBatch(1), nil)
+	var apiErr *dash0api.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v; want *dash0api.APIError", err)
+	}
+	if kind, code := classifyError(err); kind != ErrorKindUpstream5xx || code != http.StatusServiceUnavailable {
+		t.Errorf("classifyError = (%s, %d); want (upstream_5xx, 503)", kind, code)
+	}
+}
@@ -1,10 +1,12 @@
 package otlp
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/dash0hq/dash0-cli/internal/agentmode"
 	"github.com/dash0hq/dash0-cli/internal/client"
 	"github.com/dash0hq/dash0-cli/internal/config"
+	"github.com/dash0hq/dash0-cli/internal/httptransport"
 )
 
 // proxyShutdownDeadline bounds graceful shutdown after a signal. Receivers
@@ -38,7 +41,9 @@ const statsTickInterval = 1 * time.Second
 // exits non-zero (KTD5).
 func runProxy(cmd *cobra.Command, flags *proxyFlags) error {
 	cmd.SilenceUsage = true
-	ctx := cmd.Context()
+	// OTEL_EXPORTER_OTLP_* describe how applications export to the proxy,
+	// not how the proxy exports to Dash0.
+	ctx := client.WithoutOtelExporterEnv(cmd.Context())
 
 	// Apply env-var overrides on top of the parsed flags before any work.
 	if err := resolveEnvOverrides(cmd, flags); err != nil {
@@ -98,40 +103,61 @@ func runProxy(cmd *cobra.Command, flags *proxyFlags) error {
 	}
 	consumer := NewProxyConsumer(stats, emitter, tailCh)
 
+	// Resource detection runs once. The results are kept on flags so a
+	// decorator rebuilt on a --config reload carries them too.
+	var detectWarnings []string
+	if len(flags.Detect) > 0 {
+		flags.detected, detectWarnings = detectResource(ctx, flags.Detect)
+	}
+
 	// Build the outbound decorator from the user-facing flags. Empty
 	// flags produce an empty decorator whose Decorate* calls short-
 	// circuit, so the zero-flag case has no per-batch cost.
-	resourceAttrs, err := ParseKeyValuePairs(flags.ResourceAttributes)
-	if err != nil {
-		return fmt.Errorf("--resource-attribute: %w", err)
-	}
-	scopeAttrs, err := ParseKeyValuePairs(flags.ScopeAttributes)
+	decorator, err := buildDecorator(flags)
 	if err != nil {
-		return fmt.Errorf("--scope-attribute: %w", err)
+		return err
 	}
-	logAttrs, err := ParseKeyValuePairs(flags.LogAttributes)
+
+	workers := NewWorkerPool(apiClient, dataset, stats, emitter, consumer, lifecycleChOut, decorator)
+	closeDestinations, err := addFanOutDestinations(ctx, workers, flags)
+	defer closeDestinations()
 	if err != nil {
-		return fmt.Errorf("--log-attribute: %w", err)
+		return err
 	}
-	spanAttrs, err := ParseKeyValuePairs(flags.SpanAttributes)
+
+	pipeline, err := BuildPipeline(ctx, flags.HTTPPort, flags.GRPCPort, consumer)
 	if err != nil {
-		return fmt.Errorf("--span-attribute: %w", err)
+		return fmt.Errorf("build OTLP pipeline: %w", err)
 	}
-	metricAttrs, err := ParseKeyValuePairs(flags.MetricAttributes)
-	if err != nil {
-		return fmt.Errorf("--metric-attribute: %w", err)
+
+	// Local query API over the recent buffer. Bound before the receiver
+	// starts so a port collision fails startup like the OTLP listeners do.
+	var recentServer *RecentServer
+	if flags.RecentRecords > 0 {
+		recent := NewRecentBuffer(flags.RecentRecords)
+		consumer.setRecentBuffer(recent)
+		recentServer, err = NewRecentServer(flags.QueryPort, recent)
+		if err != nil {
+			return fmt.Errorf("start local query API: %w", err)
+		}
+		defer func() { _ = recentServer.Close() }()
 	}
-	decorator := NewDecorator(
-		resourceAttrs, scopeAttrs,
-		flags.ScopeName, flags.ScopeVersion,
-		logAttrs, spanAttrs, metricAttrs,
-	)
 
-	workers := NewWorkerPool(apiClient, dataset, stats, emitter, consumer, lifecycleChOut, decorator)
+	sampler := NewRateSampler(stats, statsTickInterval, sparklineHistoryCapacity)
 
-	pipeline, err := BuildPipeline(ctx, flags.HTTPPort, flags.GRPCPort, consumer)
-	if err != nil {
-		return fmt.Errorf("build OTLP pipeline: %w", err)
+	// Admin endpoint. Started right away so /healthz answers and /readyz
+	// explains what it is waiting for while the rest of startup runs.
+	var health *Health
+	var adminServer *AdminServer
+	if flags.AdminPort != 0 {
+		health = NewHealth()
+		workers.setHealth(health)
+		adminServer, err = NewAdminServer(flags.AdminPort, health, workers, sampler)
+		if err != nil {
+			return fmt.Errorf("start admin endpoint: %w", err)
+		}
+		defer func() { _ = adminServer.Close() }()
+		adminServer.Start()
 	}
 
 	// Launch the supporting goroutines before starting the receiver so
@@ -160,8 +186,17 @@ func runProxy(cmd *cobra.Command, flags *proxyFlags) error {
 		workers.Run(supCtx)
 	}()
 
+	// Re-apply decoration when the --config file changes.
+	if flags.ConfigFile != "" {
+		watcher := NewConfigWatcher(flags.ConfigFile, cmd, flags, flags.fileData, flags.fileConfig, workers, lifecycleCh)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			watcher.Run(supCtx)
+		}()
+	}
+
 	// Rate sampler ticks every second and fans snapshots to both writers.
-	sampler := NewRateSampler(stats, statsTickInterval, sparklineHistoryCapacity)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -198,17 +233,62 @@ func runProxy(cmd *cobra.Command, flags *proxyFlags) error {
 		wg.Wait()
 		return fmt.Errorf("start OTLP pipeline: %w", err)
 	}
+	if recentServer != nil {
+		recentServer.Start()
+	}
+	if health != nil {
+		health.markStarted()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verifyAuth(supCtx, apiClient, dataset, health)
+		}()
+	}
 
 	// Banner — humans see it on stderr; agents see the structured
 	// `started` event on stdout. Order matters: announce before signaling
 	// readiness so a tail running against this process sees the banner.
 	endpoints := pipeline.Endpoints()
+	// A dataset pinned by .dash0.yaml counts as given on the command line.
+	dsLabel := datasetLabel(cfg, cmp.Or(flags.Dataset, config.ProjectDataset(ctx)))
+	banner := fmt.Sprintf("dash0 otlp proxy listening — http://%s (OTLP/HTTP), %s (OTLP/gRPC) — profile: %s (dataset: %s)",
+		endpoints.HTTPEndpoint, endpoints.GRPCEndpoint, profileName, dsLabel)
+	if extra := workers.Destinations()[1:]; len(extra) > 0 {
+		names := make([]string, 0, len(extra))
+		for _, d := range extra {
+			names = append(names, d.Name())
+		}
+		banner += " — also forwarding to: " + strings.Join(names, ", ")
+	}
 	lifecycleCh <- LifecycleEvent{
-		Kind: LifecycleBanner,
-		Message: fmt.Sprintf("dash0 otlp proxy listening — http://%s (OTLP/HTTP), %s (OTLP/gRPC) — profile: %s (dataset: %s)",
-			endpoints.HTTPEndpoint, endpoints.GRPCEndpoint, profileName, datasetLabel(cfg, flags.Dataset)),
+		Kind:    LifecycleBanner,
+		Message: banner,
+	}
+	emitter.EmitStarted(endpoints.HTTPEndpoint, endpoints.GRPCEndpoint, dsLabel, profileName)
+	if recentServer != nil {
+		lifecycleCh <- LifecycleEvent{
+			Kind:    LifecycleInfo,
+			Message: fmt.Sprintf("Recent telemetry: http://%s — search it with 'dash0 -X otlp proxy recent'", recentServer.Endpoint()),
+		}
+	}
+	if len(flags.detected) > 0 {
+		lifecycleCh <- LifecycleEvent{
+			Kind:    LifecycleInfo,
+			Message: "Detected resource attributes: " + strings.Join(detectedPairs(flags.detected), ", "),
+		}
+	}
+	for _, w := range detectWarnings {
+		lifecycleCh <- LifecycleEvent{
+			Kind:    LifecycleWarning,
+			Message: w,
+		}
+	}
+	if adminServer != nil {
+		lifecycleCh <- LifecycleEvent{
+			Kind:    LifecycleInfo,
+			Message: fmt.Sprintf("Admin endpoints: http://%s (%s, %s, %s)", adminServer.Endpoint(), adminHealthzPath, adminReadyzPath, adminMetricsPath),
+		}
 	}
-	emitter.EmitStarted(endpoints.HTTPEndpoint, endpoints.GRPCEndpoint, datasetLabel(cfg, flags.Dataset), profileName)
 
 	// Block on signal.
 	sigCh := make(chan os.Signal, 1)
@@ -228,8 +308,15 @@ func runProxy(cmd *cobra.Command, flags *proxyFlags) error {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), proxyShutdownDeadline)
 	defer shutdownCancel()
 
-	// 1. Stop accepting new traffic.
+	// 1. Report not-ready, then stop accepting new traffic and queries.
+	health.markStopping()
 	_ = pipeline.Shutdown(shutdownCtx)
+	if recentServer != nil {
+		_ = recentServer.Shutdown(shutdownCtx)
+	}
+	if adminServer != nil {
+		_ = adminServer.Shutdown(shutdownCtx)
+	}
 
 	// 2. Cancel the supervisor context so workers and writers wind down.
 	supCancel()
@@ -252,6 +339,52 @@ func runProxy(cmd *cobra.Command, flags *proxyFlags) error {
 	return nil
 }
 
+// addFanOutDestinations registers the --also-forward and
+// --also-forward-profile destinations on the worker pool, in flag order
+// (URLs first, then profiles). The returned close function releases the
+// profile destinations' API clients and is safe to call even when an
+// error is returned.
+func addFanOutDestinations(ctx context.Context, workers *WorkerPool, flags *proxyFlags) (func(), error) {
+	var closers []func()
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	if len(flags.AlsoForward) > 0 {
+		transport, err := httptransport.RoundTripper(ctx)
+		if err != nil {
+			return closeAll, fmt.Errorf("--also-forward: %w", err)
+		}
+		for _, rawURL := range flags.AlsoForward {
+			workers.AddDestination(rawURL, newOtlpHTTPForwarder(rawURL, transport), nil)
+		}
+	}
+
+	for _, name := range flags.AlsoForwardProfile {
+		cfg, err := config.ResolveConfigurationForProfile(ctx, name)
+		if err != nil {
+			return closeAll, fmt.Errorf("--also-forward-profile: %w", err)
+		}
+		if cfg.OtlpUrl == "" || cfg.AuthToken == "" {
+			return closeAll, fmt.Errorf("--also-forward-profile %q: otlp-url and auth-token must be set on the profile\nHint: update it with 'dash0 config profiles update %s'", name, name)
+		}
+		pctx := config.WithProfileSelector(profiles.WithConfiguration(ctx, cfg), config.ProfileSelector{Name: name, Source: config.ProfileSourceFlag})
+		// The project dataset is for the primary destination only; each
+		// additional profile forwards to its own dataset.
+		pctx = config.WithProjectConfig(pctx, nil)
+		apiClient, err := client.NewOtlpClientFromContext(pctx, "", "")
+		if err != nil {
+			return closeAll, fmt.Errorf("--also-forward-profile %q: construct OTLP client: %w", name, err)
+		}
+		closers = append(closers, func() { _ = apiClient.Close(ctx) })
+		workers.AddDestination(name, apiClient, client.ResolveDataset(pctx, ""))
+	}
+
+	return closeAll, nil
+}
+
 // statsSink wraps an *Emitter into a chan<- SnapshotWithRate that pushes
 // each snapshot through EmitStats. RateSampler.Run accepts arbitrary
 // sinks; this is the bridge between sampler and agent-mode event stream.
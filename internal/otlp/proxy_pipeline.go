@@ -198,8 +198,13 @@ func preBindPort(label string, requested int) (int, error) {
 // holder themselves.
 func portInUseError(label string, port int, cause error) error {
 	flag := "--http-port"
-	if label == "grpc" {
+	switch label {
+	case "grpc":
 		flag = "--grpc-port"
+	case "query":
+		flag = "--query-port"
+	case "admin":
+		flag = "--admin-port"
 	}
 	if name, pid, ok := lookupPortHolder(port); ok {
 		return fmt.Errorf("%s port %d is already in use by %q (PID %d)\n  Stop that process, or pass %s <N> to use another port (cause: %w)",
@@ -0,0 +1,213 @@
+package otlp
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// defaultRecentRecords is the default per-signal capacity of the recent
+// buffer. A thousand records keeps a few minutes of a typical local
+// service's output while staying in the low megabytes.
+const defaultRecentRecords = 1000
+
+// RecentBuffer keeps the most recently received telemetry in memory so
+// `dash0 otlp proxy recent` can search it without a round-trip through
+// Dash0. Each signal has its own ring of batches bounded by record count:
+// once the total exceeds the capacity, the oldest batches are evicted
+// whole. The newest batch is always kept, even when it alone exceeds the
+// capacity, so a query right after a large export still sees it.
+//
+// Batches are stored as received from the SDK, before decoration. The
+// consumer hands the buffer its own deep copy, because the worker pool
+// decorates the originals in place.
+type RecentBuffer struct {
+	capacity int
+
+	mu      sync.Mutex
+	logs    recentRing[plog.Logs]
+	traces  recentRing[ptrace.Traces]
+	metrics recentRing[pmetric.Metrics]
+}
+
+// recentRing is one signal's batches in arrival order, oldest first.
+type recentRing[T any] struct {
+	batches []T
+	counts  []int
+	total   int
+}
+
+func (r *recentRing[T]) add(batch T, count, capacity int) {
+	r.batches = append(r.batches, batch)
+	r.counts = append(r.counts, count)
+	r.total += count
+	for r.total > capacity && len(r.batches) > 1 {
+		r.total -= r.counts[0]
+		var zero T
+		r.batches[0] = zero
+		r.batches = r.batches[1:]
+		r.counts = r.counts[1:]
+	}
+}
+
+// snapshot returns the batches newest first. The slice is a copy; the
+// batches themselves are shared and must be treated as read-only.
+func (r *recentRing[T]) snapshot() []T {
+	out := make([]T, len(r.batches))
+	for i := range r.batches {
+		out[len(r.batches)-1-i] = r.batches[i]
+	}
+	return out
+}
+
+// NewRecentBuffer returns a buffer holding up to capacity records per
+// signal.
+func NewRecentBuffer(capacity int) *RecentBuffer {
+	return &RecentBuffer{capacity: capacity}
+}
+
+// addLogs stores ld, taking ownership of it: the caller must not touch the
+// batch afterwards. Empty batches are ignored, and a nil buffer ignores
+// everything.
+func (b *RecentBuffer) addLogs(ld plog.Logs) {
+	if b == nil {
+		return
+	}
+	count := ld.LogRecordCount()
+	if count == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logs.add(ld, count, b.capacity)
+}
+
+// addTraces stores td, taking ownership of it: the caller must not touch the
+// batch afterwards. Empty batches are ignored, and a nil buffer ignores
+// everything.
+func (b *RecentBuffer) addTraces(td ptrace.Traces) {
+	if b == nil {
+		return
+	}
+	count := td.SpanCount()
+	if count == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.traces.add(td, count, b.capacity)
+}
+
+// addMetrics stores md, taking ownership of it: the caller must not touch the
+// batch afterwards. Empty batches are ignored, and a nil buffer ignores
+// everything.
+func (b *RecentBuffer) addMetrics(md pmetric.Metrics) {
+	if b == nil {
+		return
+	}
+	count := md.DataPointCount()
+	if count == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.metrics.add(md, count, b.capacity)
+}
+
+// Logs returns the most recent log records that satisfy every filter, at
+// most limit of them (0 means no limit), merged into one batch in arrival
+// order.
+func (b *RecentBuffer) Logs(filters recentFilters, limit int) plog.Logs {
+	b.mu.Lock()
+	batches := b.logs.snapshot()
+	b.mu.Unlock()
+
+	out := plog.NewLogs()
+	remaining := limit
+	// Walk newest first so the limit keeps the latest records, then
+	// prepend each match so the merged result reads oldest to newest.
+	for _, batch := range batches {
+		matched := filterLogs(batch, filters)
+		count := matched.LogRecordCount()
+		if count == 0 {
+			continue
+		}
+		if limit > 0 && count > remaining {
+			dropOldestLogs(matched, count-remaining)
+			count = remaining
+		}
+		out.ResourceLogs().MoveAndAppendTo(matched.ResourceLogs())
+		matched.ResourceLogs().MoveAndAppendTo(out.ResourceLogs())
+		if limit > 0 {
+			remaining -= count
+			if remaining == 0 {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Traces returns the most recent spans that satisfy every filter; see
+// Logs for the limit and ordering semantics.
+func (b *RecentBuffer) Traces(filters recentFilters, limit int) ptrace.Traces {
+	b.mu.Lock()
+	batches := b.traces.snapshot()
+	b.mu.Unlock()
+
+	out := ptrace.NewTraces()
+	remaining := limit
+	for _, batch := range batches {
+		matched := filterTraces(batch, filters)
+		count := matched.SpanCount()
+		if count == 0 {
+			continue
+		}
+		if limit > 0 && count > remaining {
+			dropOldestSpans(matched, count-remaining)
+			count = remaining
+		}
+		out.ResourceSpans().MoveAndAppendTo(matched.ResourceSpans())
+		matched.ResourceSpans().MoveAndAppendTo(out.ResourceSpans())
+		if limit > 0 {
+			remaining -= count
+			if remaining == 0 {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Metrics returns the most recent metric data points that satisfy every
+// filter; see Logs for the limit and ordering semantics.
+func (b *RecentBuffer) Metrics(filters recentFilters, limit int) pmetric.Metrics {
+	b.mu.Lock()
+	batches := b.metrics.snapshot()
+	b.mu.Unlock()
+
+	out := pmetric.NewMetrics()
+	remaining := limit
+	for _, batch := range batches {
+		matched := filterMetrics(batch, filters)
+		count := matched.DataPointCount()
+		if count == 0 {
+			continue
+		}
+		if limit > 0 && count > remaining {
+			dropOldestDataPoints(matched, count-remaining)
+			count = remaining
+		}
+		out.ResourceMetrics().MoveAndAppendTo(matched.ResourceMetrics())
+		matched.ResourceMetrics().MoveAndAppendTo(out.ResourceMetrics())
+		if limit > 0 {
+			remaining -= count
+			if remaining == 0 {
+				break
+			}
+		}
+	}
+	return out
+}
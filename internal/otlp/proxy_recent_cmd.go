@@ -0,0 +1,244 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/dash0hq/dash0-cli/internal/agentmode"
+	"github.com/dash0hq/dash0-cli/internal/experimental"
+)
+
+// recentRequestTimeout bounds a query against the local API. The buffer is
+// in memory on the same machine; anything slower means the proxy is stuck.
+const recentRequestTimeout = 10 * time.Second
+
+// defaultRecentLimit matches the default page size of `logs query` and
+// `spans query`.
+const defaultRecentLimit = 50
+
+type recentFlags struct {
+	Signal    string
+	Filter    []string
+	Limit     int
+	QueryPort int
+	Output    string
+}
+
+// recentFormat represents the output format of `otlp proxy recent`.
+type recentFormat string
+
+const (
+	recentFormatText recentFormat = "text"
+	recentFormatJSON recentFormat = "json"
+)
+
+func parseRecentFormat(s string) (recentFormat, error) {
+	switch strings.ToLower(s) {
+	case "":
+		if agentmode.Enabled {
+			return recentFormatJSON, nil
+		}
+		return recentFormatText, nil
+	case "text":
+		return recentFormatText, nil
+	case "json":
+		return recentFormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown output format: %s (valid formats: text, json)", s)
+	}
+}
+
+// recentSignalPaths maps the --signal values to the query API paths, in
+// output order.
+var recentSignalPaths = []struct {
+	signal string
+	path   string
+}{
+	{"logs", recentLogsPath},
+	{"spans", recentTracesPath},
+	{"metrics", recentMetricsPath},
+}
+
+func newProxyRecentCmd() *cobra.Command {
+	flags := &recentFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "recent",
+		Short: "[experimental] Search the telemetry recently received by a running proxy",
+		Long: `Search the records a running 'dash0 otlp proxy' has received recently,
+without a round-trip through Dash0.
+
+The proxy keeps the most recent records of each signal in memory (see
+--recent-records on the proxy) and serves them on its local query API.
+Filters use the same syntax as 'dash0 logs query --filter'. Keys are looked
+up on the record, then on its instrumentation scope, then on its resource.
+Records are shown as received from the SDK, before any decoration flags
+are applied.`,
+		Example: `  # Show the 50 most recent records of every signal
+  dash0 -X otlp proxy recent
+
+  # Only spans of one service that ended in an error
+  dash0 -X otlp proxy recent --signal spans \
+      --filter "service.name is checkout" \
+      --filter "otel.span.status.code is ERROR"
+
+  # Warnings and errors from the last log records
+  dash0 -X otlp proxy recent --signal logs \
+      --filter "otel.log.severity.range is_one_of WARN ERROR"
+
+  # Output as OTLP/JSON, one document per signal
+  dash0 -X otlp proxy recent -o json
+
+  # Query a proxy started with --query-port 5319
+  dash0 -X otlp proxy recent --query-port 5319`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := experimental.RequireExperimental(cmd); err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("query-port") {
+				if v, ok := nonEmptyEnv(envQueryPort); ok {
+					port, err := parsePort(envQueryPort, v)
+					if err != nil {
+						return err
+					}
+					flags.QueryPort = port
+				}
+			}
+			return runRecent(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Signal, "signal", "", "Signal to search: logs, spans, or metrics (default: all three)")
+	cmd.Flags().StringArrayVar(&flags.Filter, "filter", nil, "Filter expression as 'key [operator] value' (repeatable)")
+	cmd.Flags().IntVar(&flags.Limit, "limit", defaultRecentLimit, "Maximum number of records per signal; the most recent ones are kept (0 for no limit)")
+	cmd.Flags().IntVar(&flags.QueryPort, "query-port", defaultQueryPort,
+		fmt.Sprintf("Local query API port of the running proxy (env: %s)", envQueryPort))
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Output format: text, json (default: text)")
+
+	return cmd
+}
+
+func runRecent(cmd *cobra.Command, flags *recentFlags) error {
+	cmd.SilenceUsage = true
+
+	format, err := parseRecentFormat(flags.Output)
+	if err != nil {
+		return err
+	}
+	if flags.Limit < 0 {
+		return fmt.Errorf("--limit %d must not be negative", flags.Limit)
+	}
+	if flags.QueryPort <= 0 || flags.QueryPort > 65535 {
+		return fmt.Errorf("--query-port %d is out of range (1-65535)", flags.QueryPort)
+	}
+	// Parse locally as well so a typo fails before any network call, with
+	// the same message the query commands print.
+	if _, err := parseRecentFilters(flags.Filter); err != nil {
+		return err
+	}
+
+	signals := recentSignalPaths
+	if flags.Signal != "" {
+		signals = nil
+		for _, s := range recentSignalPaths {
+			if strings.EqualFold(s.signal, flags.Signal) {
+				signals = append(signals, s)
+			}
+		}
+		if len(signals) == 0 {
+			return fmt.Errorf("unknown signal %q (valid signals: logs, spans, metrics)", flags.Signal)
+		}
+	}
+
+	base := "http://" + net.JoinHostPort(proxyListenHost, strconv.Itoa(flags.QueryPort))
+	httpClient := &http.Client{Timeout: recentRequestTimeout}
+	out := cmd.OutOrStdout()
+	for _, s := range signals {
+		body, err := fetchRecent(cmd.Context(), httpClient, base+s.path, flags)
+		if err != nil {
+			return err
+		}
+		if err := writeRecent(out, format, s.signal, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchRecent queries one signal endpoint and returns the OTLP/JSON body.
+func fetchRecent(ctx context.Context, httpClient *http.Client, endpoint string, flags *recentFlags) ([]byte, error) {
+	params := url.Values{}
+	for _, f := range flags.Filter {
+		params.Add("filter", f)
+	}
+	params.Set("limit", strconv.Itoa(flags.Limit))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the proxy's query API at %s\n  %v\nHint: start the proxy with 'dash0 -X otlp proxy', or pass --query-port if it uses a different port", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from the proxy's query API: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the proxy's query API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// writeRecent prints one signal's result. JSON output passes the OTLP/JSON
+// document through on a single line; text output renders it with the same
+// formatter as `otlp proxy --tail`.
+func writeRecent(w io.Writer, format recentFormat, signal string, body []byte) error {
+	if format == recentFormatJSON {
+		_, err := fmt.Fprintln(w, strings.TrimSpace(string(body)))
+		return err
+	}
+
+	var rendered string
+	switch signal {
+	case "logs":
+		ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(body)
+		if err != nil {
+			return fmt.Errorf("failed to decode logs from the proxy's query API: %w", err)
+		}
+		rendered = RenderLogs(ld)
+	case "spans":
+		td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(body)
+		if err != nil {
+			return fmt.Errorf("failed to decode spans from the proxy's query API: %w", err)
+		}
+		rendered = RenderTraces(td)
+	case "metrics":
+		md, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(body)
+		if err != nil {
+			return fmt.Errorf("failed to decode metrics from the proxy's query API: %w", err)
+		}
+		rendered = RenderMetrics(md)
+	}
+	if rendered == "" {
+		return nil
+	}
+	_, err := fmt.Fprint(w, rendered)
+	return err
+}
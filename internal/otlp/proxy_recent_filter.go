@@ -0,0 +1,413 @@
+package otlp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/dash0hq/dash0-cli/internal/query"
+)
+
+// proxy_recent_filter.go evaluates `--filter` expressions against the
+// records held in the RecentBuffer. Expressions are parsed with
+// query.ParseFilters, so the syntax is exactly the one accepted by
+// `dash0 logs query` and `dash0 spans query`; this file only supplies the
+// local evaluation that Dash0 otherwise performs server-side.
+//
+// A key is looked up on the record first, then on its instrumentation
+// scope, then on its resource, so `service.name` works without knowing
+// where the SDK put it. The built-in `otel.*` keys below expose the
+// record's own fields under the names Dash0 uses.
+
+// recentFilters is a compiled filter list. A record matches when every
+// filter matches; an empty list matches everything.
+type recentFilters []recentFilter
+
+type recentFilter struct {
+	key    string
+	op     dash0api.AttributeFilterOperator
+	values []string
+	regex  *regexp.Regexp
+}
+
+// lookupFunc resolves a filter key against one record.
+type lookupFunc func(key string) (string, bool)
+
+// parseRecentFilters parses and compiles filter expressions for local
+// evaluation.
+func parseRecentFilters(exprs []string) (recentFilters, error) {
+	criteria, err := query.ParseFilters(exprs)
+	if err != nil {
+		return nil, err
+	}
+	if criteria == nil {
+		return nil, nil
+	}
+	filters := make(recentFilters, 0, len(*criteria))
+	for _, f := range *criteria {
+		compiled := recentFilter{key: f.Key, op: f.Operator}
+		if f.Value != nil {
+			v, err := f.Value.AsAttributeFilterStringValue()
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract value for filter key %q: %w", f.Key, err)
+			}
+			compiled.values = []string{v}
+		}
+		if f.Values != nil {
+			for i, item := range *f.Values {
+				v, err := item.AsAttributeFilterStringValue()
+				if err != nil {
+					return nil, fmt.Errorf("failed to extract value at index %d for filter key %q: %w", i, f.Key, err)
+				}
+				compiled.values = append(compiled.values, v)
+			}
+		}
+		if f.Operator == dash0api.AttributeFilterOperatorMatches || f.Operator == dash0api.AttributeFilterOperatorDoesNotMatch {
+			if len(compiled.values) == 0 {
+				return nil, fmt.Errorf("filter for key %q with operator %q requires a value", f.Key, f.Operator)
+			}
+			re, err := regexp.Compile(compiled.values[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression for filter key %q: %w", f.Key, err)
+			}
+			compiled.regex = re
+		}
+		filters = append(filters, compiled)
+	}
+	return filters, nil
+}
+
+func (fs recentFilters) match(lookup lookupFunc) bool {
+	for _, f := range fs {
+		if !f.match(lookup) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f recentFilter) match(lookup lookupFunc) bool {
+	actual, ok := lookup(f.key)
+	first := ""
+	if len(f.values) > 0 {
+		first = f.values[0]
+	}
+	switch f.op {
+	case dash0api.AttributeFilterOperatorIsAny:
+		return true
+	case dash0api.AttributeFilterOperatorIsSet:
+		return ok
+	case dash0api.AttributeFilterOperatorIsNotSet:
+		return !ok
+	case dash0api.AttributeFilterOperatorIs:
+		return ok && actual == first
+	case dash0api.AttributeFilterOperatorIsNot:
+		return !ok || actual != first
+	case dash0api.AttributeFilterOperatorContains:
+		return ok && strings.Contains(actual, first)
+	case dash0api.AttributeFilterOperatorDoesNotContain:
+		return !ok || !strings.Contains(actual, first)
+	case dash0api.AttributeFilterOperatorStartsWith:
+		return ok && strings.HasPrefix(actual, first)
+	case dash0api.AttributeFilterOperatorDoesNotStartWith:
+		return !ok || !strings.HasPrefix(actual, first)
+	case dash0api.AttributeFilterOperatorEndsWith:
+		return ok && strings.HasSuffix(actual, first)
+	case dash0api.AttributeFilterOperatorDoesNotEndWith:
+		return !ok || !strings.HasSuffix(actual, first)
+	case dash0api.AttributeFilterOperatorMatches:
+		return ok && f.regex.MatchString(actual)
+	case dash0api.AttributeFilterOperatorDoesNotMatch:
+		return !ok || !f.regex.MatchString(actual)
+	case dash0api.AttributeFilterOperatorIsOneOf:
+		return ok && containsString(f.values, actual)
+	case dash0api.AttributeFilterOperatorIsNotOneOf:
+		return !ok || !containsString(f.values, actual)
+	case dash0api.AttributeFilterOperatorGt,
+		dash0api.AttributeFilterOperatorGte,
+		dash0api.AttributeFilterOperatorLt,
+		dash0api.AttributeFilterOperatorLte:
+		if !ok {
+			return false
+		}
+		return compareNumeric(f.op, actual, first)
+	default:
+		return false
+	}
+}
+
+// compareNumeric compares two values as numbers. Values that do not parse
+// as numbers never match, mirroring how Dash0 treats numeric operators on
+// non-numeric attributes.
+func compareNumeric(op dash0api.AttributeFilterOperator, actual, want string) bool {
+	a, err := strconv.ParseFloat(actual, 64)
+	if err != nil {
+		return false
+	}
+	w, err := strconv.ParseFloat(want, 64)
+	if err != nil {
+		return false
+	}
+	switch op {
+	case dash0api.AttributeFilterOperatorGt:
+		return a > w
+	case dash0api.AttributeFilterOperatorGte:
+		return a >= w
+	case dash0api.AttributeFilterOperatorLt:
+		return a < w
+	default:
+		return a <= w
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// chainLookup resolves key from the record's own fields and attributes,
+// then the scope, then the resource.
+func chainLookup(builtin func(key string) (string, bool), record pcommon.Map, scope pcommon.InstrumentationScope, resource pcommon.Resource) lookupFunc {
+	return func(key string) (string, bool) {
+		if v, ok := builtin(key); ok {
+			return v, true
+		}
+		if v, ok := record.Get(key); ok {
+			return v.AsString(), true
+		}
+		switch key {
+		case "otel.scope.name":
+			return scope.Name(), scope.Name() != ""
+		case "otel.scope.version":
+			return scope.Version(), scope.Version() != ""
+		}
+		if v, ok := scope.Attributes().Get(key); ok {
+			return v.AsString(), true
+		}
+		if v, ok := resource.Attributes().Get(key); ok {
+			return v.AsString(), true
+		}
+		return "", false
+	}
+}
+
+func logBuiltins(lr plog.LogRecord) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		switch key {
+		case "otel.log.body":
+			return lr.Body().AsString(), true
+		case "otel.log.severity.text":
+			return lr.SeverityText(), lr.SeverityText() != ""
+		case "otel.log.severity.number":
+			return strconv.Itoa(int(lr.SeverityNumber())), true
+		case "otel.log.severity.range":
+			return SeverityNumberToRange(int32(lr.SeverityNumber())), true
+		case "otel.log.event.name":
+			return lr.EventName(), lr.EventName() != ""
+		case "otel.trace.id":
+			tid := lr.TraceID()
+			return hex.EncodeToString(tid[:]), !tid.IsEmpty()
+		case "otel.span.id":
+			sid := lr.SpanID()
+			return hex.EncodeToString(sid[:]), !sid.IsEmpty()
+		}
+		return "", false
+	}
+}
+
+func spanBuiltins(sp ptrace.Span) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		switch key {
+		case "otel.span.name":
+			return sp.Name(), true
+		case "otel.span.kind":
+			return strings.ToUpper(sp.Kind().String()), true
+		case "otel.span.status.code":
+			return strings.ToUpper(sp.Status().Code().String()), true
+		case "otel.span.duration":
+			return strconv.FormatInt(int64(sp.EndTimestamp()-sp.StartTimestamp()), 10), true
+		case "otel.trace.id":
+			tid := sp.TraceID()
+			return hex.EncodeToString(tid[:]), true
+		case "otel.span.id":
+			sid := sp.SpanID()
+			return hex.EncodeToString(sid[:]), true
+		case "otel.parent.id":
+			pid := sp.ParentSpanID()
+			return hex.EncodeToString(pid[:]), !pid.IsEmpty()
+		}
+		return "", false
+	}
+}
+
+func metricBuiltins(m pmetric.Metric) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		switch key {
+		case "otel.metric.name":
+			return m.Name(), true
+		case "otel.metric.type":
+			return strings.ToLower(m.Type().String()), true
+		case "otel.metric.unit":
+			return m.Unit(), m.Unit() != ""
+		}
+		return "", false
+	}
+}
+
+// filterLogs returns a copy of ld holding only the matching records.
+// Scopes and resources left without records are dropped.
+func filterLogs(ld plog.Logs, filters recentFilters) plog.Logs {
+	out := plog.NewLogs()
+	ld.CopyTo(out)
+	if len(filters) == 0 {
+		return out
+	}
+	out.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				return !filters.match(chainLookup(logBuiltins(lr), lr.Attributes(), sl.Scope(), rl.Resource()))
+			})
+			return sl.LogRecords().Len() == 0
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+	return out
+}
+
+// filterTraces returns a copy of td holding only the matching spans.
+func filterTraces(td ptrace.Traces, filters recentFilters) ptrace.Traces {
+	out := ptrace.NewTraces()
+	td.CopyTo(out)
+	if len(filters) == 0 {
+		return out
+	}
+	out.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			ss.Spans().RemoveIf(func(sp ptrace.Span) bool {
+				return !filters.match(chainLookup(spanBuiltins(sp), sp.Attributes(), ss.Scope(), rs.Resource()))
+			})
+			return ss.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+	return out
+}
+
+// filterMetrics returns a copy of md holding only the matching data
+// points. Metrics left without data points are dropped.
+func filterMetrics(md pmetric.Metrics, filters recentFilters) pmetric.Metrics {
+	out := pmetric.NewMetrics()
+	md.CopyTo(out)
+	if len(filters) == 0 {
+		return out
+	}
+	out.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				keep := func(attrs pcommon.Map) bool {
+					return filters.match(chainLookup(metricBuiltins(m), attrs, sm.Scope(), rm.Resource()))
+				}
+				return removeDataPoints(m, func(attrs pcommon.Map) bool { return !keep(attrs) }) == 0
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	return out
+}
+
+// removeDataPoints removes the data points of m for which remove returns
+// true and reports how many are left.
+func removeDataPoints(m pmetric.Metric, remove func(attrs pcommon.Map) bool) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len()
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		dps.RemoveIf(func(dp pmetric.SummaryDataPoint) bool { return remove(dp.Attributes()) })
+		return dps.Len()
+	default:
+		return 0
+	}
+}
+
+// dropOldestLogs removes the first n log records of ld in iteration
+// order, which is the order the SDK sent them in.
+func dropOldestLogs(ld plog.Logs, n int) {
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(plog.LogRecord) bool {
+				if n > 0 {
+					n--
+					return true
+				}
+				return false
+			})
+			return sl.LogRecords().Len() == 0
+		})
+		return rl.ScopeLogs().Len() == 0
+	})
+}
+
+// dropOldestSpans removes the first n spans of td in iteration order.
+func dropOldestSpans(td ptrace.Traces, n int) {
+	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			ss.Spans().RemoveIf(func(ptrace.Span) bool {
+				if n > 0 {
+					n--
+					return true
+				}
+				return false
+			})
+			return ss.Spans().Len() == 0
+		})
+		return rs.ScopeSpans().Len() == 0
+	})
+}
+
+// dropOldestDataPoints removes the first n data points of md in iteration
+// order.
+func dropOldestDataPoints(md pmetric.Metrics, n int) {
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				return removeDataPoints(m, func(pcommon.Map) bool {
+					if n > 0 {
+						n--
+						return true
+					}
+					return false
+				}) == 0
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+}
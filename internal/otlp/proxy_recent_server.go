@@ -0,0 +1,147 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// defaultQueryPort is the loopback port of the local query API. It sits
+// next to the standard OTLP ports (4317 gRPC, 4318 HTTP) so it is easy to
+// remember, and is not assigned to anything by the OTLP specification.
+const defaultQueryPort = 4319
+
+// Paths served by the local query API. Each returns the matching records
+// of one signal as an OTLP/JSON export request body.
+const (
+	recentLogsPath    = "/v1/recent/logs"
+	recentTracesPath  = "/v1/recent/traces"
+	recentMetricsPath = "/v1/recent/metrics"
+)
+
+// recentServerReadHeaderTimeout bounds how long a client may take to send
+// request headers. The API is loopback-only, but an unbounded timeout is
+// still an easy way to leak goroutines.
+const recentServerReadHeaderTimeout = 5 * time.Second
+
+// RecentServer exposes a RecentBuffer over HTTP on the loopback
+// interface. It is queried by `dash0 otlp proxy recent`; the endpoints are
+// plain GETs, so curl works too:
+//
+//	GET /v1/recent/logs?filter=service.name+is+api&limit=20
+//
+// `filter` is repeatable and uses the `--filter` syntax; `limit` caps the
+// number of records (the most recent ones win). Invalid filters return
+// 400 with the parse error as a plain-text body.
+type RecentServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewRecentServer binds the query API on 127.0.0.1:port. Binding happens
+// here rather than in Start so a port collision fails startup before the
+// banner, like the OTLP listeners.
+func NewRecentServer(port int, buf *RecentBuffer) (*RecentServer, error) {
+	ln, err := net.Listen("tcp", net.JoinHostPort(proxyListenHost, strconv.Itoa(port)))
+	if err != nil {
+		return nil, portInUseError("query", port, err)
+	}
+	return &RecentServer{
+		listener: ln,
+		server: &http.Server{
+			Handler:           newRecentHandler(buf),
+			ReadHeaderTimeout: recentServerReadHeaderTimeout,
+		},
+	}, nil
+}
+
+// Endpoint returns the host:port the server is bound to.
+func (s *RecentServer) Endpoint() string {
+	return s.listener.Addr().String()
+}
+
+// Start serves requests in the background until Shutdown is called.
+// Serve's error is dropped: it only fails if the listener breaks
+// underneath us, and the proxy keeps forwarding regardless.
+func (s *RecentServer) Start() {
+	go func() { _ = s.server.Serve(s.listener) }()
+}
+
+// Shutdown stops the server, waiting for in-flight queries up to ctx's
+// deadline.
+func (s *RecentServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// Close releases the listener immediately. It is safe to call after
+// Shutdown and on a server that was never started, which is how startup
+// failures after NewRecentServer clean up.
+func (s *RecentServer) Close() error {
+	_ = s.server.Close()
+	return s.listener.Close()
+}
+
+func newRecentHandler(buf *RecentBuffer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+recentLogsPath, func(w http.ResponseWriter, r *http.Request) {
+		filters, limit, ok := parseRecentRequest(w, r)
+		if !ok {
+			return
+		}
+		body, err := (&plog.JSONMarshaler{}).MarshalLogs(buf.Logs(filters, limit))
+		writeRecentResponse(w, body, err)
+	})
+	mux.HandleFunc("GET "+recentTracesPath, func(w http.ResponseWriter, r *http.Request) {
+		filters, limit, ok := parseRecentRequest(w, r)
+		if !ok {
+			return
+		}
+		body, err := (&ptrace.JSONMarshaler{}).MarshalTraces(buf.Traces(filters, limit))
+		writeRecentResponse(w, body, err)
+	})
+	mux.HandleFunc("GET "+recentMetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		filters, limit, ok := parseRecentRequest(w, r)
+		if !ok {
+			return
+		}
+		body, err := (&pmetric.JSONMarshaler{}).MarshalMetrics(buf.Metrics(filters, limit))
+		writeRecentResponse(w, body, err)
+	})
+	return mux
+}
+
+// parseRecentRequest extracts the filter and limit query parameters. On
+// failure it writes a 400 response and returns ok=false.
+func parseRecentRequest(w http.ResponseWriter, r *http.Request) (recentFilters, int, bool) {
+	params := r.URL.Query()
+	filters, err := parseRecentFilters(params["filter"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, 0, false
+	}
+	limit := 0
+	if raw := params.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q: must be a non-negative integer", raw), http.StatusBadRequest)
+			return nil, 0, false
+		}
+	}
+	return filters, limit, true
+}
+
+func writeRecentResponse(w http.ResponseWriter, body []byte, err error) {
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
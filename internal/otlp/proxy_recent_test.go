@@ -0,0 +1,315 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// newServiceLogs builds a one-record batch for service with the given
+// severity and body.
+func newServiceLogs(service string, severity plog.SeverityNumber, body string) plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", service)
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetSeverityNumber(severity)
+	lr.Body().SetStr(body)
+	return ld
+}
+
+func logBodies(ld plog.Logs) []string {
+	var out []string
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		sls := ld.ResourceLogs().At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			lrs := sls.At(j).LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				out = append(out, lrs.At(k).Body().AsString())
+			}
+		}
+	}
+	return out
+}
+
+func mustParseRecentFilters(t *testing.T, exprs ...string) recentFilters {
+	t.Helper()
+	filters, err := parseRecentFilters(exprs)
+	if err != nil {
+		t.Fatalf("parseRecentFilters(%q): %v", exprs, err)
+	}
+	return filters
+}
+
+func TestRecentBuffer_EvictsOldestBatchesBeyondCapacity(t *testing.T) {
+	buf := NewRecentBuffer(3)
+	for i := 0; i < 5; i++ {
+		buf.addLogs(newServiceLogs("api", plog.SeverityNumberInfo, strconv.Itoa(i)))
+	}
+
+	got := logBodies(buf.Logs(nil, 0))
+	want := []string{"2", "3", "4"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("buffered bodies = %v; want %v (oldest evicted, arrival order kept)", got, want)
+	}
+}
+
+func TestRecentBuffer_KeepsOversizedNewestBatch(t *testing.T) {
+	buf := NewRecentBuffer(2)
+	buf.addLogs(newLogsBatch(1))
+	buf.addLogs(newLogsBatch(5))
+
+	if got := buf.Logs(nil, 0).LogRecordCount(); got != 5 {
+		t.Errorf("records = %d; want the 5-record batch kept even though it exceeds capacity", got)
+	}
+}
+
+func TestRecentBuffer_NilIgnoresAdds(t *testing.T) {
+	var buf *RecentBuffer
+	// Must not panic: the consumer calls add* unconditionally.
+	buf.addLogs(plog.Logs{})
+	buf.addTraces(ptrace.Traces{})
+}
+
+func TestRecentBuffer_LimitKeepsMostRecent(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	for _, body := range []string{"a", "b", "c"} {
+		sl.LogRecords().AppendEmpty().Body().SetStr(body)
+	}
+	buf.addLogs(ld)
+	buf.addLogs(newServiceLogs("api", plog.SeverityNumberInfo, "d"))
+
+	got := logBodies(buf.Logs(nil, 2))
+	if strings.Join(got, ",") != "c,d" {
+		t.Errorf("limited bodies = %v; want [c d]", got)
+	}
+}
+
+func TestRecentBuffer_FilterLogs(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	buf.addLogs(newServiceLogs("api", plog.SeverityNumberInfo, "started"))
+	buf.addLogs(newServiceLogs("api", plog.SeverityNumberError, "db timeout"))
+	buf.addLogs(newServiceLogs("worker", plog.SeverityNumberError, "queue full"))
+
+	cases := []struct {
+		name    string
+		filters []string
+		want    string
+	}{
+		{"resource attribute", []string{"service.name is api"}, "started,db timeout"},
+		{"severity range", []string{"otel.log.severity.range is ERROR"}, "db timeout,queue full"},
+		{"combined", []string{"service.name = api", "otel.log.severity.number gte 17"}, "db timeout"},
+		{"body contains", []string{"otel.log.body contains queue"}, "queue full"},
+		{"regex", []string{"otel.log.body ~ ^(db|queue)"}, "db timeout,queue full"},
+		{"one of", []string{"service.name is_one_of worker batch"}, "queue full"},
+		{"negation on missing key", []string{"deployment.environment.name != prod"}, "started,db timeout,queue full"},
+		{"is_set on missing key", []string{"deployment.environment.name is_set"}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := logBodies(buf.Logs(mustParseRecentFilters(t, tc.filters...), 0))
+			if strings.Join(got, ",") != tc.want {
+				t.Errorf("bodies = %v; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRecentBuffer_FilterTraces(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	ok := ss.Spans().AppendEmpty()
+	ok.SetName("GET /health")
+	failed := ss.Spans().AppendEmpty()
+	failed.SetName("POST /checkout")
+	failed.Status().SetCode(ptrace.StatusCodeError)
+	failed.Attributes().PutInt("http.response.status_code", 500)
+	buf.addTraces(td)
+
+	got := buf.Traces(mustParseRecentFilters(t, "otel.span.status.code is ERROR", "http.response.status_code gt 499"), 0)
+	if got.SpanCount() != 1 {
+		t.Fatalf("span count = %d; want 1", got.SpanCount())
+	}
+	if name := got.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name(); name != "POST /checkout" {
+		t.Errorf("span name = %q; want %q", name, "POST /checkout")
+	}
+}
+
+func TestRecentBuffer_FilterMetricsByDataPointAttribute(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	md := newMetricsBatch(0)
+	gauge := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge()
+	for _, route := range []string{"/a", "/b", "/a"} {
+		gauge.DataPoints().AppendEmpty().Attributes().PutStr("http.route", route)
+	}
+	buf.addMetrics(md)
+
+	if got := buf.Metrics(mustParseRecentFilters(t, "http.route is /a"), 0).DataPointCount(); got != 2 {
+		t.Errorf("data points = %d; want 2", got)
+	}
+	if got := buf.Metrics(mustParseRecentFilters(t, "otel.metric.name is other"), 0).DataPointCount(); got != 0 {
+		t.Errorf("data points for another metric name = %d; want 0", got)
+	}
+}
+
+func TestParseRecentFilters_InvalidRegex(t *testing.T) {
+	if _, err := parseRecentFilters([]string{"otel.log.body ~ ("}); err == nil {
+		t.Fatal("expected an error for an invalid regular expression")
+	}
+}
+
+func TestProxyConsumer_RecordsAcceptedBatchesInRecentBuffer(t *testing.T) {
+	stats := &Stats{}
+	c := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	buf := NewRecentBuffer(100)
+	c.setRecentBuffer(buf)
+
+	if err := c.ConsumeLogs(context.Background(), newLogsBatch(2)); err != nil {
+		t.Fatalf("ConsumeLogs: %v", err)
+	}
+	// Mutating the enqueued batch (as the decorator does) must not leak
+	// into the buffer.
+	queued := <-c.LogsChannel()
+	queued.ResourceLogs().At(0).Resource().Attributes().PutStr("decorated", "true")
+
+	for i := 0; i < signalQueueDepth; i++ {
+		_ = c.ConsumeLogs(context.Background(), newLogsBatch(1))
+	}
+	// The queue is now full; this batch is rejected and must not be
+	// recorded, otherwise SDK retries would show up twice.
+	if err := c.ConsumeLogs(context.Background(), newLogsBatch(1)); err == nil {
+		t.Fatal("expected queue-full error")
+	}
+
+	got := buf.Logs(nil, 0)
+	if n := got.LogRecordCount(); n != 2+signalQueueDepth {
+		t.Errorf("buffered records = %d; want %d", n, 2+signalQueueDepth)
+	}
+	if _, ok := got.ResourceLogs().At(0).Resource().Attributes().Get("decorated"); ok {
+		t.Error("buffer shares pdata with the forwarded batch; want an independent copy")
+	}
+}
+
+func TestRecentHandler_ReturnsOTLPJSON(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	buf.addLogs(newServiceLogs("api", plog.SeverityNumberInfo, "hello"))
+	buf.addLogs(newServiceLogs("worker", plog.SeverityNumberInfo, "other"))
+	server := httptest.NewServer(newRecentHandler(buf))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + recentLogsPath + "?filter=" + url.QueryEscape("service.name is api"))
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d; body = %s", resp.StatusCode, body)
+	}
+	ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(body)
+	if err != nil {
+		t.Fatalf("response is not OTLP/JSON logs: %v", err)
+	}
+	if got := logBodies(ld); len(got) != 1 || got[0] != "hello" {
+		t.Errorf("bodies = %v; want [hello]", got)
+	}
+}
+
+func TestRecentHandler_BadFilterIs400(t *testing.T) {
+	server := httptest.NewServer(newRecentHandler(NewRecentBuffer(10)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + recentTracesPath + "?filter=" + url.QueryEscape("key is_set extra"))
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d; want 400", resp.StatusCode)
+	}
+}
+
+// runRecentAgainst runs `otlp proxy recent` against a query API served by
+// handler and returns stdout.
+func runRecentAgainst(t *testing.T, handler http.Handler, flags *recentFlags) (string, error) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	flags.QueryPort = port
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetContext(context.Background())
+	err := runRecent(cmd, flags)
+	return out.String(), err
+}
+
+func TestRunRecent_RendersWithTailFormatter(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	buf.addLogs(newServiceLogs("api", plog.SeverityNumberInfo, "hello from the buffer"))
+
+	out, err := runRecentAgainst(t, newRecentHandler(buf), &recentFlags{Signal: "logs", Limit: 10, Output: "text"})
+	if err != nil {
+		t.Fatalf("runRecent: %v", err)
+	}
+	if !strings.Contains(out, "ResourceLogs #0") || !strings.Contains(out, "hello from the buffer") {
+		t.Errorf("output should use the --tail rendering; got:\n%s", out)
+	}
+}
+
+func TestRunRecent_JSONOnePerSignal(t *testing.T) {
+	buf := NewRecentBuffer(100)
+	buf.addTraces(newTracesBatch(1))
+
+	out, err := runRecentAgainst(t, newRecentHandler(buf), &recentFlags{Limit: 10, Output: "json"})
+	if err != nil {
+		t.Fatalf("runRecent: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one JSON line per signal; got %d:\n%s", len(lines), out)
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("line is not valid JSON: %s", line)
+		}
+	}
+}
+
+func TestRunRecent_UnknownSignal(t *testing.T) {
+	_, err := runRecentAgainst(t, http.NotFoundHandler(), &recentFlags{Signal: "profiles", Output: "text"})
+	if err == nil || !strings.Contains(err.Error(), "unknown signal") {
+		t.Errorf("error = %v; want unknown signal", err)
+	}
+}
+
+func TestRunRecent_ProxyNotRunning(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	server.Close()
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	err := runRecent(cmd, &recentFlags{Signal: "logs", QueryPort: port, Output: "text"})
+	if err == nil || !strings.Contains(err.Error(), "Hint: start the proxy") {
+		t.Errorf("error = %v; want a hint to start the proxy", err)
+	}
+}
@@ -41,6 +41,7 @@ func (s Signal) String() string {
 type Stats struct {
 	forwarded [signalCount]atomic.Int64
 	failed    [signalCount]atomic.Int64
+	errors    [len(errorKinds)]atomic.Int64
 }
 
 // RecordForwarded adds n to the per-signal forwarded counter. The consumer
@@ -63,6 +64,15 @@ func (s *Stats) RecordFailed(sig Signal, n int) {
 	s.failed[sig].Add(int64(n))
 }
 
+// RecordError counts one outbound failure of the given kind. Unlike
+// RecordFailed it counts occurrences, not records: a rejected batch of 50
+// spans is one error.
+func (s *Stats) RecordError(kind ErrorKind) {
+	if i := kind.index(); i >= 0 {
+		s.errors[i].Add(1)
+	}
+}
+
 // Forwarded returns the current forwarded count for a signal (lock-free read).
 func (s *Stats) Forwarded(sig Signal) int64 {
 	if sig < 0 || sig >= signalCount {
@@ -79,6 +89,15 @@ func (s *Stats) Failed(sig Signal) int64 {
 	return s.failed[sig].Load()
 }
 
+// Errors returns the current error count for a kind (lock-free read).
+func (s *Stats) Errors(kind ErrorKind) int64 {
+	i := kind.index()
+	if i < 0 {
+		return 0
+	}
+	return s.errors[i].Load()
+}
+
 // Snapshot is a moment-in-time view of the counters. Captured atomically per
 // signal but not transactionally across signals — a snapshot taken during a
 // burst may see logs from after a span counter increment but spans from
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	dash0api "github.com/dash0hq/dash0-api-client-go"
@@ -28,23 +29,62 @@ type Forwarder interface {
 // communicates the root cause without filling the terminal.
 const authErrorThrottle = 30 * time.Second
 
-// WorkerPool drains the per-signal channels populated by ProxyConsumer (U13),
-// calls the upstream Forwarder, classifies outcomes per KTD14, and surfaces
-// failures via the Stats counters, the agent-mode event channel, and an
-// at-most-once-per-throttle-window stderr line for credential issues.
-//
-// Concurrency is one goroutine per signal — the transport's
-// max-concurrent-requests semaphore (KTD3b) provides the actual outbound
-// concurrency cap, and per-signal goroutines keep batch ordering intact for
-// downstream sanity (e.g., a metrics burst arriving in order goes upstream
-// in order, modulo SDK retries).
-type WorkerPool struct {
+// Destination is one upstream the worker pool forwards to. The primary
+// destination is the proxy's own connection (active profile plus flag
+// overrides); `--also-forward` and `--also-forward-profile` add more. Each
+// destination owns its per-signal queues, failure counters, and
+// auth-warning throttle, so trouble with one upstream is reported — and
+// absorbed — without affecting the others.
+type Destination struct {
+	name      string
 	forwarder Forwarder
 	dataset   *string
 	stats     *Stats
-	emitter   *Emitter
-	consumer  *ProxyConsumer
-	decorator *Decorator
+	queues    *signalQueues
+
+	authMu       sync.Mutex
+	lastAuthWarn time.Time
+}
+
+// Name returns the destination's display name: "primary" for the proxy's
+// own connection, the profile name or OTLP URL for additional ones.
+func (d *Destination) Name() string { return d.name }
+
+// Stats returns the destination's counters. The primary destination shares
+// the proxy-wide Stats; additional destinations count independently.
+func (d *Destination) Stats() *Stats { return d.stats }
+
+// primaryDestinationName labels the proxy's own connection in error events
+// and warnings once more than one destination is configured.
+const primaryDestinationName = "primary"
+
+// WorkerPool drains the per-destination, per-signal channels populated by
+// ProxyConsumer (U13), calls each destination's Forwarder, classifies
+// outcomes per KTD14, and surfaces failures via the Stats counters, the
+// agent-mode event channel, and an at-most-once-per-throttle-window stderr
+// line for credential issues.
+//
+// Concurrency is one goroutine per signal per destination — the
+// transport's max-concurrent-requests semaphore (KTD3b) provides the
+// actual outbound concurrency cap, and per-signal goroutines keep batch
+// ordering intact for downstream sanity (e.g., a metrics burst arriving in
+// order goes upstream in order, modulo SDK retries). Because every
+// destination drains its own channels, a slow destination only delays
+// itself.
+type WorkerPool struct {
+	destinations []*Destination
+	emitter      *Emitter
+	consumer     *ProxyConsumer
+
+	// decorator is swapped atomically by SetDecorator when the --config
+	// file changes. Each batch loads it once, so a batch in flight during
+	// a reload is decorated entirely by either the old or the new rules.
+	decorator atomic.Pointer[Decorator]
+
+	// health receives the primary destination's send outcomes so /readyz
+	// can tell whether Dash0 accepts the proxy's credentials. nil unless
+	// the admin endpoint is enabled.
+	health *Health
 
 	// lifecycleCh receives the at-most-once auth-failure warning. nil in
 	// non-agent / non-TTY runs; the stderr writer also suppresses lifecycle
@@ -55,54 +95,94 @@ type WorkerPool struct {
 	// now is overridable so tests can drive the auth-error throttle without
 	// real sleeps.
 	now func() time.Time
-
-	authMu       sync.Mutex
-	lastAuthWarn time.Time
 }
 
-// NewWorkerPool constructs a pool. No goroutines are started until Run is
-// called. The decorator may be nil — workers treat that the same as an
-// empty decorator and skip the per-batch upsert step.
+// NewWorkerPool constructs a pool whose primary destination is forwarder,
+// fed from the consumer's primary channels. No goroutines are started
+// until Run is called. The decorator may be nil — workers treat that the
+// same as an empty decorator and skip the per-batch upsert step.
 func NewWorkerPool(forwarder Forwarder, dataset *string, stats *Stats, emitter *Emitter, consumer *ProxyConsumer, lifecycleCh chan<- LifecycleEvent, decorator *Decorator) *WorkerPool {
-	return &WorkerPool{
-		forwarder:   forwarder,
-		dataset:     dataset,
-		stats:       stats,
+	p := &WorkerPool{
 		emitter:     emitter,
 		consumer:    consumer,
-		decorator:   decorator,
 		lifecycleCh: lifecycleCh,
 		now:         time.Now,
 	}
+	p.decorator.Store(decorator)
+	primary := &Destination{
+		name:      primaryDestinationName,
+		forwarder: forwarder,
+		dataset:   dataset,
+		stats:     stats,
+		queues:    consumer.queues[0],
+	}
+	primary.queues.onDrop = func(sig Signal, count int) { p.recordDrop(primary, sig, count) }
+	p.destinations = []*Destination{primary}
+	return p
 }
 
-// Run launches one goroutine per signal and blocks until ctx is cancelled.
-// When ctx fires, each worker stops accepting new work from its channel; any
-// in-flight forward finishes (the transport has its own per-request
-// deadline) and the worker returns. Tests that want immediate shutdown
-// should cancel and not wait on queued batches.
+// AddDestination registers an additional upstream with its own counters
+// and returns it. Every batch the consumer accepts from then on is copied
+// to the new destination. Must be called before the pipeline starts and
+// before Run.
+func (p *WorkerPool) AddDestination(name string, forwarder Forwarder, dataset *string) *Destination {
+	d := &Destination{
+		name:      name,
+		forwarder: forwarder,
+		dataset:   dataset,
+		stats:     &Stats{},
+	}
+	d.queues = p.consumer.addQueues(d.stats, func(sig Signal, count int) { p.recordDrop(d, sig, count) })
+	p.destinations = append(p.destinations, d)
+	return d
+}
+
+// SetDecorator replaces the decoration applied to batches dequeued from
+// now on. Safe to call while Run is active; d may be nil.
+func (p *WorkerPool) SetDecorator(d *Decorator) {
+	p.decorator.Store(d)
+}
+
+// setHealth makes the pool report the primary destination's send outcomes
+// to h. Must be called before Run.
+func (p *WorkerPool) setHealth(h *Health) {
+	p.health = h
+}
+
+// Destinations returns the registered destinations, primary first.
+func (p *WorkerPool) Destinations() []*Destination {
+	return p.destinations
+}
+
+// Run launches one goroutine per signal per destination and blocks until
+// ctx is cancelled. When ctx fires, each worker stops accepting new work
+// from its channel; any in-flight forward finishes (the transport has its
+// own per-request deadline) and the worker returns. Tests that want
+// immediate shutdown should cancel and not wait on queued batches.
 func (p *WorkerPool) Run(ctx context.Context) {
 	var wg sync.WaitGroup
-	wg.Add(signalCount)
-	go func() {
-		defer wg.Done()
-		p.drain(ctx, SignalLogs)
-	}()
-	go func() {
-		defer wg.Done()
-		p.drain(ctx, SignalSpans)
-	}()
-	go func() {
-		defer wg.Done()
-		p.drain(ctx, SignalMetrics)
-	}()
+	for _, d := range p.destinations {
+		wg.Add(signalCount)
+		go func() {
+			defer wg.Done()
+			p.drain(ctx, d, SignalLogs)
+		}()
+		go func() {
+			defer wg.Done()
+			p.drain(ctx, d, SignalSpans)
+		}()
+		go func() {
+			defer wg.Done()
+			p.drain(ctx, d, SignalMetrics)
+		}()
+	}
 	wg.Wait()
 }
 
-func (p *WorkerPool) drain(ctx context.Context, sig Signal) {
+func (p *WorkerPool) drain(ctx context.Context, d *Destination, sig Signal) {
 	switch sig {
 	case SignalLogs:
-		ch := p.consumer.LogsChannel()
+		ch := d.queues.logs
 		for {
 			select {
 			case <-ctx.Done():
@@ -111,11 +191,11 @@ func (p *WorkerPool) drain(ctx context.Context, sig Signal) {
 				if !ok {
 					return
 				}
-				p.sendLogs(ctx, ld)
+				p.sendLogs(ctx, d, ld)
 			}
 		}
 	case SignalSpans:
-		ch := p.consumer.TracesChannel()
+		ch := d.queues.traces
 		for {
 			select {
 			case <-ctx.Done():
@@ -124,11 +204,11 @@ func (p *WorkerPool) drain(ctx context.Context, sig Signal) {
 				if !ok {
 					return
 				}
-				p.sendTraces(ctx, td)
+				p.sendTraces(ctx, d, td)
 			}
 		}
 	case SignalMetrics:
-		ch := p.consumer.MetricsChannel()
+		ch := d.queues.metrics
 		for {
 			select {
 			case <-ctx.Done():
@@ -137,80 +217,110 @@ func (p *WorkerPool) drain(ctx context.Context, sig Signal) {
 				if !ok {
 					return
 				}
-				p.sendMetrics(ctx, md)
+				p.sendMetrics(ctx, d, md)
 			}
 		}
 	}
 }
 
-func (p *WorkerPool) sendLogs(ctx context.Context, ld plog.Logs) {
+func (p *WorkerPool) sendLogs(ctx context.Context, d *Destination, ld plog.Logs) {
 	count := ld.LogRecordCount()
-	defer p.recoverPanic(SignalLogs)
-	p.decorator.DecorateLogs(ld)
-	err := p.forwarder.SendLogs(ctx, ld, p.dataset)
-	p.classifyOutcome(SignalLogs, count, err)
+	defer p.recoverPanic(d, SignalLogs)
+	p.decorator.Load().DecorateLogs(ld)
+	err := d.forwarder.SendLogs(ctx, ld, d.dataset)
+	p.classifyOutcome(d, SignalLogs, count, err)
 }
 
-func (p *WorkerPool) sendTraces(ctx context.Context, td ptrace.Traces) {
+func (p *WorkerPool) sendTraces(ctx context.Context, d *Destination, td ptrace.Traces) {
 	count := td.SpanCount()
-	defer p.recoverPanic(SignalSpans)
-	p.decorator.DecorateTraces(td)
-	err := p.forwarder.SendTraces(ctx, td, p.dataset)
-	p.classifyOutcome(SignalSpans, count, err)
+	defer p.recoverPanic(d, SignalSpans)
+	p.decorator.Load().DecorateTraces(td)
+	err := d.forwarder.SendTraces(ctx, td, d.dataset)
+	p.classifyOutcome(d, SignalSpans, count, err)
 }
 
-func (p *WorkerPool) sendMetrics(ctx context.Context, md pmetric.Metrics) {
+func (p *WorkerPool) sendMetrics(ctx context.Context, d *Destination, md pmetric.Metrics) {
 	count := md.DataPointCount()
-	defer p.recoverPanic(SignalMetrics)
-	p.decorator.DecorateMetrics(md)
-	err := p.forwarder.SendMetrics(ctx, md, p.dataset)
-	p.classifyOutcome(SignalMetrics, count, err)
+	defer p.recoverPanic(d, SignalMetrics)
+	p.decorator.Load().DecorateMetrics(md)
+	err := d.forwarder.SendMetrics(ctx, md, d.dataset)
+	p.classifyOutcome(d, SignalMetrics, count, err)
 }
 
 // classifyOutcome turns a Send result into the KTD14 taxonomy and updates
-// the failure counter, agent-mode error event, and the auth-warning
-// lifecycle line. Success is a no-op — Forwarded is incremented by the
+// the destination's failure counter, agent-mode error event, and the
+// auth-warning lifecycle line. Apart from the primary destination's
+// readiness state, success is a no-op — Forwarded is incremented by the
 // consumer at enqueue time, and per-signal "success rate" is
 // `Forwarded - Failed`.
-func (p *WorkerPool) classifyOutcome(sig Signal, count int, err error) {
+func (p *WorkerPool) classifyOutcome(d *Destination, sig Signal, count int, err error) {
+	if d == p.destinations[0] {
+		p.health.recordAuthOutcome(err)
+	}
 	if err == nil {
 		return
 	}
 	kind, code := classifyError(err)
-	p.stats.RecordFailed(sig, count)
-	p.emitter.EmitError(kind, err.Error(), code)
+	d.stats.RecordFailed(sig, count)
+	d.stats.RecordError(kind)
+	p.emitter.EmitDestinationError(p.eventDestination(d), kind, err.Error(), code)
 	if kind == ErrorKindUpstream4xxAuth {
-		p.maybeSurfaceAuthError()
+		p.maybeSurfaceAuthError(d)
+	}
+}
+
+// recordDrop accounts for a batch the consumer could not enqueue for d
+// because d's channel was full while another destination had room. The
+// batch never reaches d, so it counts as failed there.
+func (p *WorkerPool) recordDrop(d *Destination, sig Signal, count int) {
+	d.stats.RecordFailed(sig, count)
+	d.stats.RecordError(ErrorKindQueueFull)
+	reason := fmt.Sprintf("%s queue for %s is full; batch of %d dropped for this destination only", sig, d.name, count)
+	p.emitter.EmitDestinationError(p.eventDestination(d), ErrorKindQueueFull, reason, 0)
+}
+
+// eventDestination returns the destination label for agent-mode error
+// events. Single-destination runs omit the label so their event shape is
+// unchanged from before fan-out existed.
+func (p *WorkerPool) eventDestination(d *Destination) string {
+	if len(p.destinations) < 2 {
+		return ""
 	}
+	return d.name
 }
 
-func (p *WorkerPool) recoverPanic(sig Signal) {
+func (p *WorkerPool) recoverPanic(d *Destination, sig Signal) {
 	if r := recover(); r != nil {
 		// Don't bump the Failed counter — by the time the panic surfaces
 		// the batch's count attribution is unreliable. The error event
 		// itself ensures the panic isn't silently swallowed; downstream
 		// retries are SDK-controlled.
+		d.stats.RecordError(ErrorKindInternalPanic)
 		reason := fmt.Sprintf("worker panic in %s forwarder: %v", sig, r)
-		p.emitter.EmitError(ErrorKindInternalPanic, reason, 0)
+		p.emitter.EmitDestinationError(p.eventDestination(d), ErrorKindInternalPanic, reason, 0)
 	}
 }
 
-func (p *WorkerPool) maybeSurfaceAuthError() {
+func (p *WorkerPool) maybeSurfaceAuthError(d *Destination) {
 	if p.lifecycleCh == nil {
 		return
 	}
-	p.authMu.Lock()
-	defer p.authMu.Unlock()
+	d.authMu.Lock()
+	defer d.authMu.Unlock()
 	now := p.now()
-	if !p.lastAuthWarn.IsZero() && now.Sub(p.lastAuthWarn) < authErrorThrottle {
+	if !d.lastAuthWarn.IsZero() && now.Sub(d.lastAuthWarn) < authErrorThrottle {
 		return
 	}
+	message := "authentication to Dash0 failed; check your profile (re-run `dash0 config show`)"
+	if len(p.destinations) > 1 {
+		message = fmt.Sprintf("authentication to destination %q failed; check its credentials", d.name)
+	}
 	select {
 	case p.lifecycleCh <- LifecycleEvent{
 		Kind:    LifecycleError,
-		Message: "authentication to Dash0 failed; check your profile (re-run `dash0 config show`)",
+		Message: message,
 	}:
-		p.lastAuthWarn = now
+		d.lastAuthWarn = now
 	default:
 		// Lifecycle channel full; skip this warning. The next 401 outside
 		// the throttle window will retry.
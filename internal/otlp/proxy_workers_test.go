@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -214,7 +215,7 @@ func TestWorkerPool_HappyPath_NoFailedNoEvent(t *testing.T) {
 	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, nil, nil)
 
-	pool.sendLogs(context.Background(), newLogsBatch(3))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(3))
 
 	if got := forwarder.logsCalls.Load(); got != 1 {
 		t.Errorf("SendLogs calls = %d; want 1", got)
@@ -237,7 +238,7 @@ func TestWorkerPool_Upstream5xx_BumpsFailedAndEmitsEvent(t *testing.T) {
 	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, nil, nil)
 
-	pool.sendLogs(context.Background(), newLogsBatch(2))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(2))
 
 	if got := stats.Failed(SignalLogs); got != 2 {
 		t.Errorf("Failed(logs) = %d; want 2", got)
@@ -254,7 +255,7 @@ func TestWorkerPool_Upstream5xx_BumpsFailedAndEmitsEvent(t *testing.T) {
 	}
 
 	// Subsequent forwards still work (the worker did not bail).
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 	if got := forwarder.logsCalls.Load(); got != 2 {
 		t.Errorf("SendLogs calls after second send = %d; want 2", got)
 	}
@@ -271,7 +272,7 @@ func TestWorkerPool_Upstream401_EmitsLifecycleWarning(t *testing.T) {
 	lifecycleCh := make(chan LifecycleEvent, 2)
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, lifecycleCh, nil)
 
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 
 	if kind := firstEventKindAttr(t, <-eventCh); kind != string(ErrorKindUpstream4xxAuth) {
 		t.Errorf("error.kind = %q; want %q", kind, ErrorKindUpstream4xxAuth)
@@ -309,9 +310,9 @@ func TestWorkerPool_Upstream401_ThrottleSuppressesRepeat(t *testing.T) {
 	pool.now = func() time.Time { return fixed }
 
 	// Three rapid 401s should produce exactly one lifecycle line.
-	pool.sendLogs(context.Background(), newLogsBatch(1))
-	pool.sendLogs(context.Background(), newLogsBatch(1))
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 
 	// Drain lifecycle ch: exactly one event.
 	count := 0
@@ -331,7 +332,7 @@ loop:
 	// Advance the clock past the throttle window — next 401 surfaces again.
 	pool.now = func() time.Time { return fixed.Add(authErrorThrottle + time.Second) }
 	forwarder.logsErrs = append(forwarder.logsErrs, &dash0api.APIError{StatusCode: 401})
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 	select {
 	case <-lifecycleCh:
 		// expected
@@ -351,7 +352,7 @@ func TestWorkerPool_Upstream400_ClassifiedAs4xxOther(t *testing.T) {
 	lifecycleCh := make(chan LifecycleEvent, 2)
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, lifecycleCh, nil)
 
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 
 	evts := drainEvents(eventCh)
 	if len(evts) != 1 {
@@ -380,7 +381,7 @@ func TestWorkerPool_NetworkError_ClassifiedAsUnreachable(t *testing.T) {
 	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, nil, nil)
 
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 
 	evts := drainEvents(eventCh)
 	if len(evts) != 1 {
@@ -402,7 +403,7 @@ func TestWorkerPool_PanicRecoveredAndReported(t *testing.T) {
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, nil, nil)
 
 	// Should NOT propagate the panic out of sendLogs.
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 
 	evts := drainEvents(eventCh)
 	if len(evts) != 1 {
@@ -420,7 +421,7 @@ func TestWorkerPool_DatasetPassthrough(t *testing.T) {
 
 	staging := "staging"
 	pool := NewWorkerPool(forwarder, &staging, stats, NewEmitter("inst", nil), consumer, nil, nil)
-	pool.sendLogs(context.Background(), newLogsBatch(1))
+	pool.sendLogs(context.Background(), pool.destinations[0], newLogsBatch(1))
 	if forwarder.lastDataset == nil || *forwarder.lastDataset != "staging" {
 		t.Errorf("dataset on call = %v; want pointer to %q", forwarder.lastDataset, "staging")
 	}
@@ -429,7 +430,7 @@ func TestWorkerPool_DatasetPassthrough(t *testing.T) {
 	// the profile's default" per the dash0api convention.
 	forwarder.lastDataset = &staging // sentinel: should be overwritten with nil
 	poolNil := NewWorkerPool(forwarder, nil, stats, NewEmitter("inst", nil), consumer, nil, nil)
-	poolNil.sendLogs(context.Background(), newLogsBatch(1))
+	poolNil.sendLogs(context.Background(), poolNil.destinations[0], newLogsBatch(1))
 	if forwarder.lastDataset != nil {
 		t.Errorf("nil dataset should pass through as nil; got %v", forwarder.lastDataset)
 	}
@@ -449,8 +450,8 @@ func TestWorkerPool_TracesAndMetricsHaveSameOutcomeContract(t *testing.T) {
 	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
 	pool := NewWorkerPool(forwarder, nil, stats, emitter, consumer, nil, nil)
 
-	pool.sendTraces(context.Background(), newTracesBatch(2))
-	pool.sendMetrics(context.Background(), newMetricsBatch(3))
+	pool.sendTraces(context.Background(), pool.destinations[0], newTracesBatch(2))
+	pool.sendMetrics(context.Background(), pool.destinations[0], newMetricsBatch(3))
 
 	if got := stats.Failed(SignalSpans); got != 2 {
 		t.Errorf("Failed(spans) = %d; want 2", got)
@@ -551,3 +552,144 @@ func TestWorkerPool_Run_PanicRecoversInGoroutine(t *testing.T) {
 		}
 	}
 }
+
+func TestWorkerPool_AddDestination_FansOutToEveryForwarder(t *testing.T) {
+	primary := &fakeForwarder{}
+	secondary := &fakeForwarder{}
+	stats := &Stats{}
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	pool := NewWorkerPool(primary, nil, stats, NewEmitter("inst", nil), consumer, nil, nil)
+	staging := "staging"
+	dest := pool.AddDestination("staging", secondary, &staging)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pool.Run(ctx)
+
+	if err := consumer.ConsumeLogs(ctx, newLogsBatch(1)); err != nil {
+		t.Fatalf("ConsumeLogs: %v", err)
+	}
+	if err := consumer.ConsumeMetrics(ctx, newMetricsBatch(1)); err != nil {
+		t.Fatalf("ConsumeMetrics: %v", err)
+	}
+
+	deadline := time.After(500 * time.Millisecond)
+	for primary.logsCalls.Load() < 1 || secondary.logsCalls.Load() < 1 ||
+		primary.metricsCalls.Load() < 1 || secondary.metricsCalls.Load() < 1 {
+		select {
+		case <-deadline:
+			t.Fatalf("not every destination received both batches; primary logs=%d metrics=%d, secondary logs=%d metrics=%d",
+				primary.logsCalls.Load(), primary.metricsCalls.Load(), secondary.logsCalls.Load(), secondary.metricsCalls.Load())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	secondary.mu.Lock()
+	gotDataset := secondary.lastDataset
+	secondary.mu.Unlock()
+	if gotDataset == nil || *gotDataset != "staging" {
+		t.Errorf("secondary dataset = %v; want pointer to %q", gotDataset, "staging")
+	}
+	if got := dest.Stats().Forwarded(SignalLogs); got != 1 {
+		t.Errorf("secondary Forwarded(logs) = %d; want 1", got)
+	}
+	if names := []string{pool.Destinations()[0].Name(), pool.Destinations()[1].Name()}; names[0] != "primary" || names[1] != "staging" {
+		t.Errorf("destination names = %v; want [primary staging]", names)
+	}
+}
+
+func TestWorkerPool_SlowDestinationDoesNotBlockOthers(t *testing.T) {
+	release := make(chan struct{})
+	slow := &fakeForwarder{onSend: func(Signal) { <-release }}
+	fast := &fakeForwarder{}
+	stats := &Stats{}
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	pool := NewWorkerPool(fast, nil, stats, NewEmitter("inst", nil), consumer, nil, nil)
+	pool.AddDestination("http://localhost:14318", slow, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer close(release)
+	go pool.Run(ctx)
+
+	const batches = 5
+	for i := 0; i < batches; i++ {
+		if err := consumer.ConsumeLogs(ctx, newLogsBatch(1)); err != nil {
+			t.Fatalf("ConsumeLogs[%d]: %v", i, err)
+		}
+	}
+
+	deadline := time.After(500 * time.Millisecond)
+	for fast.logsCalls.Load() < batches {
+		select {
+		case <-deadline:
+			t.Fatalf("fast destination stalled behind the slow one; SendLogs called %d times", fast.logsCalls.Load())
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestWorkerPool_DestinationFailuresAreAttributed(t *testing.T) {
+	primary := &fakeForwarder{}
+	secondary := &fakeForwarder{
+		logsErrs: []error{&dash0api.APIError{StatusCode: 401}},
+	}
+	stats := &Stats{}
+	eventCh := make(chan plog.Logs, 4)
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	lifecycleCh := make(chan LifecycleEvent, 2)
+	pool := NewWorkerPool(primary, nil, stats, NewEmitter("inst", eventCh), consumer, lifecycleCh, nil)
+	dest := pool.AddDestination("staging", secondary, nil)
+
+	pool.sendLogs(context.Background(), dest, newLogsBatch(2))
+
+	if got := stats.Failed(SignalLogs); got != 0 {
+		t.Errorf("primary Failed(logs) = %d; want 0", got)
+	}
+	if got := dest.Stats().Failed(SignalLogs); got != 2 {
+		t.Errorf("secondary Failed(logs) = %d; want 2", got)
+	}
+	evts := drainEvents(eventCh)
+	if len(evts) != 1 {
+		t.Fatalf("expected 1 error event; got %d", len(evts))
+	}
+	v, ok := evts[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get("destination")
+	if !ok || v.AsString() != "staging" {
+		t.Errorf("destination attribute = %v (present=%v); want %q", v.AsString(), ok, "staging")
+	}
+	select {
+	case ev := <-lifecycleCh:
+		if !strings.Contains(ev.Message, `"staging"`) {
+			t.Errorf("auth warning should name the destination; got %q", ev.Message)
+		}
+	case <-time.After(50 * time.Millisecond):
+		t.Error("expected auth lifecycle event for the failing destination")
+	}
+}
+
+func TestWorkerPool_QueueFullDropIsRecordedOnDestination(t *testing.T) {
+	stats := &Stats{}
+	eventCh := make(chan plog.Logs, 4)
+	consumer := NewProxyConsumer(stats, NewEmitter("inst", nil), nil)
+	pool := NewWorkerPool(&fakeForwarder{}, nil, stats, NewEmitter("inst", eventCh), consumer, nil, nil)
+	dest := pool.AddDestination("staging", &fakeForwarder{}, nil)
+
+	// Fill the secondary destination's queue without running the pool.
+	for i := 0; i < signalQueueDepth; i++ {
+		dest.queues.traces <- newTracesBatch(1)
+	}
+	if err := consumer.ConsumeTraces(context.Background(), newTracesBatch(4)); err != nil {
+		t.Fatalf("ConsumeTraces: %v", err)
+	}
+
+	if got := dest.Stats().Failed(SignalSpans); got != 4 {
+		t.Errorf("secondary Failed(spans) = %d; want 4", got)
+	}
+	evts := drainEvents(eventCh)
+	if len(evts) != 1 {
+		t.Fatalf("expected 1 error event; got %d", len(evts))
+	}
+	if kind := firstEventKindAttr(t, evts[0]); kind != string(ErrorKindQueueFull) {
+		t.Errorf("error.kind = %q; want %q", kind, ErrorKindQueueFull)
+	}
+}
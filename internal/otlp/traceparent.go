@@ -0,0 +1,102 @@
+package otlp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Environment variables that carry W3C trace context between processes, as
+// used by the OpenTelemetry environment-variable carrier.
+const (
+	EnvTraceparent = "TRACEPARENT"
+	EnvTracestate  = "TRACESTATE"
+)
+
+// Traceparent is a parsed W3C `traceparent` value.
+type Traceparent struct {
+	TraceID pcommon.TraceID
+	SpanID  pcommon.SpanID
+	Sampled bool
+}
+
+// ParseTraceparent parses a W3C `traceparent` value such as
+// `00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01`. Values of a
+// future version are accepted as long as they start with the version-00
+// fields, as the specification requires.
+func ParseTraceparent(s string) (Traceparent, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 {
+		return Traceparent{}, fmt.Errorf("traceparent must have the form 'version-trace_id-parent_id-flags', got %q", s)
+	}
+	version, traceHex, spanHex, flagsHex := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || !isLowerHex(version) || version == "ff" {
+		return Traceparent{}, fmt.Errorf("invalid traceparent version %q", version)
+	}
+	if version == "00" && len(parts) != 4 {
+		return Traceparent{}, fmt.Errorf("traceparent version 00 must have exactly four fields, got %q", s)
+	}
+	if !isLowerHex(traceHex) || !isLowerHex(spanHex) {
+		return Traceparent{}, fmt.Errorf("traceparent IDs must be lowercase hex, got %q", s)
+	}
+	traceID, err := ParseTraceID(traceHex)
+	if err != nil {
+		return Traceparent{}, err
+	}
+	spanID, err := ParseSpanID(spanHex)
+	if err != nil {
+		return Traceparent{}, err
+	}
+	if traceID.IsEmpty() || spanID.IsEmpty() {
+		return Traceparent{}, fmt.Errorf("traceparent IDs must not be all zeros, got %q", s)
+	}
+	flags, err := hex.DecodeString(flagsHex)
+	if err != nil || len(flags) != 1 {
+		return Traceparent{}, fmt.Errorf("invalid traceparent flags %q", flagsHex)
+	}
+	return Traceparent{TraceID: traceID, SpanID: spanID, Sampled: flags[0]&0x01 == 1}, nil
+}
+
+// String formats t as a version-00 `traceparent` value.
+func (t Traceparent) String() string {
+	flags := "00"
+	if t.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(t.TraceID[:]), hex.EncodeToString(t.SpanID[:]), flags)
+}
+
+// TraceparentFromEnv returns the parent context in the TRACEPARENT
+// environment variable. An unset or invalid value yields false: the W3C
+// specification asks for an invalid parent to be ignored rather than to
+// fail the operation.
+func TraceparentFromEnv() (Traceparent, bool) {
+	v := os.Getenv(EnvTraceparent)
+	if v == "" {
+		return Traceparent{}, false
+	}
+	tp, err := ParseTraceparent(v)
+	if err != nil {
+		return Traceparent{}, false
+	}
+	return tp, true
+}
+
+// TracestateFromEnv returns the vendor-specific trace state in the
+// TRACESTATE environment variable. It is only meaningful together with the
+// parent in TRACEPARENT, and is passed on unchanged.
+func TracestateFromEnv() string {
+	return strings.TrimSpace(os.Getenv(EnvTracestate))
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return s != ""
+}
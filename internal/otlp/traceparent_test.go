@@ -0,0 +1,64 @@
+package otlp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const valid = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	tp, err := ParseTraceparent(valid)
+	if err != nil {
+		t.Fatalf("ParseTraceparent: %v", err)
+	}
+	if !tp.Sampled {
+		t.Error("Sampled = false; want true for flags 01")
+	}
+	if got := tp.String(); got != valid {
+		t.Errorf("String() = %q; want %q", got, valid)
+	}
+
+	future, err := ParseTraceparent("cc-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00-extra")
+	if err != nil {
+		t.Fatalf("a future version with extra fields should parse: %v", err)
+	}
+	if future.Sampled {
+		t.Error("Sampled = true; want false for flags 00")
+	}
+}
+
+func TestParseTraceparent_Errors(t *testing.T) {
+	cases := map[string]string{
+		"too few fields":    "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"extra field on 00": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-x",
+		"version ff":        "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"uppercase":         "00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+		"short trace id":    "00-0af7651916cd43dd-b7ad6b7169203331-01",
+		"zero trace id":     "00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"zero span id":      "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"bad flags":         "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-1",
+	}
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseTraceparent(value); err == nil {
+				t.Errorf("ParseTraceparent(%q) succeeded; want an error", value)
+			}
+		})
+	}
+}
+
+func TestTraceparentFromEnv(t *testing.T) {
+	t.Setenv(EnvTraceparent, "")
+	if _, ok := TraceparentFromEnv(); ok {
+		t.Error("unset TRACEPARENT should not yield a parent")
+	}
+	t.Setenv(EnvTraceparent, "garbage")
+	if _, ok := TraceparentFromEnv(); ok {
+		t.Error("an invalid TRACEPARENT should be ignored")
+	}
+	t.Setenv(EnvTraceparent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	tp, ok := TraceparentFromEnv()
+	if !ok || !strings.HasPrefix(tp.String(), "00-0af7651916cd43dd8448eb211c80319c-") {
+		t.Errorf("TraceparentFromEnv() = %v, %v; want the parsed value", tp, ok)
+	}
+}
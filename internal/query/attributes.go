@@ -0,0 +1,38 @@
+package query
+
+import (
+	"strconv"
+
+	dash0api "github.com/dash0hq/dash0-api-client-go"
+)
+
+// FindAttribute looks up key in attrs and returns its string representation.
+// Returns "" if the key is not found or the value is empty.
+func FindAttribute(attrs []dash0api.KeyValue, key string) string {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return AnyValueToString(&kv.Value)
+		}
+	}
+	return ""
+}
+
+// AnyValueToString converts an AnyValue to its string representation.
+func AnyValueToString(v *dash0api.AnyValue) string {
+	if v == nil {
+		return ""
+	}
+	if v.StringValue != nil {
+		return *v.StringValue
+	}
+	if v.IntValue != nil {
+		return *v.IntValue
+	}
+	if v.DoubleValue != nil {
+		return strconv.FormatFloat(*v.DoubleValue, 'f', -1, 64)
+	}
+	if v.BoolValue != nil {
+		return strconv.FormatBool(*v.BoolValue)
+	}
+	return ""
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	dash0api "github.com/dash0hq/dash0-api-client-go"
-	"github.com/dash0hq/dash0-cli/internal/otlp"
 	"github.com/dash0hq/dash0-cli/internal/output"
 )
 
@@ -233,7 +232,7 @@ func BuildValues(predefined map[string]string, cols []ColumnDef, rawAttrs []dash
 	}
 	for _, col := range cols {
 		if _, ok := result[col.Key]; !ok {
-			result[col.Key] = otlp.FindAttribute(rawAttrs, col.Key)
+			result[col.Key] = FindAttribute(rawAttrs, col.Key)
 		}
 	}
 	return result
@@ -72,7 +72,11 @@ var topics = []topicSpec{
 			"config profiles list",
 			"config profiles select",
 			"config profiles delete",
+			"config profiles export",
+			"config profiles import",
 			"config show",
+			"proxies and tls",
+			"doctor",
 		},
 	},
 	{
@@ -82,11 +86,12 @@ var topics = []topicSpec{
 		extraNote: "`dashboards create` also accepts PersesDashboard CRD files (`perses.dev/v1alpha1` and " +
 			"`perses.dev/v1alpha2`).",
 	},
+	{name: "events", sections: []string{"events deployment", "events change", "events incident"}},
 	{name: "failed-checks", sections: []string{"failed-checks query"}},
-	{name: "login", sections: []string{"login", "logout"}},
+	{name: "login", sections: []string{"login", "logout", "auth-tokens list", "auth-tokens create", "auth-tokens revoke"}},
 	{name: "logs", sections: []string{"logs query", "logs send"}},
 	{name: "members", sections: []string{"members list", "members invite", "members remove"}},
-	{name: "metrics", sections: []string{"metrics instant"}},
+	{name: "metrics", sections: []string{"metrics instant", "metrics send"}},
 	{
 		name: "notification-channels",
 		sections: []string{
@@ -97,7 +102,7 @@ var topics = []topicSpec{
 			"notification-channels delete",
 		},
 	},
-	{name: "otlp", sections: []string{"otlp proxy"}},
+	{name: "otlp", sections: []string{"otlp protocol and compression", "otlp send", "otlp generate", "otlp proxy", "otlp proxy recent"}},
 	{
 		name:            "recording-rules",
 		includeQuickRef: true,
@@ -114,7 +119,7 @@ var topics = []topicSpec{
 			"missing value defaults to `v1alpha1`. The `list` endpoint returns v1alpha1 definitions only; use " +
 			"`spam-filters get <id>` to retrieve a filter in its native apiVersion.",
 	},
-	{name: "spans", sections: []string{"spans query", "spans send"}},
+	{name: "spans", sections: []string{"spans query", "spans send", "spans exec", "spans start", "spans end"}},
 	{
 		name:            "synthetic-checks",
 		includeQuickRef: true,
@@ -362,6 +367,7 @@ var alwaysRewriteAnchors = map[string]string{
 // (e.g. metrics.md carries its own `#### Filter syntax`) keep the fast
 // in-file link.
 var intraDocAnchorRewrites = map[string]string{
+	"credential-storage":               "https://github.com/dash0hq/dash0-cli/blob/main/docs/commands.md#credential-storage",
 	"filter-syntax":                    "https://github.com/dash0hq/dash0-cli/blob/main/docs/commands.md#filter-syntax",
 	"precision-mode-adaptive-sampling": "https://github.com/dash0hq/dash0-cli/blob/main/docs/commands.md#precision-mode-adaptive-sampling",
 }
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+)
+
+// StateDir returns the directory in which commands keep state between
+// invocations, such as an event that has started and not yet finished. It
+// is the named directory under the configuration directory (default:
+// ~/.dash0), and is not created.
+func StateDir(name string) (string, error) {
+	dir := os.Getenv(profiles.EnvConfigDir)
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find the home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".dash0")
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -55,6 +55,12 @@ const (
 	FixtureTracesGetSuccess   = "traces/get_success.json"
 	FixtureTracesGetWithLinks = "traces/get_with_links.json"
 
+	// Auth tokens fixtures
+	FixtureAuthTokensListSuccess   = "authtokens/list_success.json"
+	FixtureAuthTokensListEmpty     = "authtokens/list_empty.json"
+	FixtureAuthTokensCreateSuccess = "authtokens/create_success.json"
+	FixtureAuthTokensNotFound      = "authtokens/error_not_found.json"
+
 	// Members fixtures
 	FixtureMembersListSuccess   = "members/list_success.json"
 	FixtureMembersListEmpty     = "members/list_empty.json"
@@ -10,8 +10,11 @@ func NewSpansCmd() *cobra.Command {
 		Long:  `Send and query spans to and from Dash0.`,
 	}
 
+	cmd.AddCommand(newEndCmd())
+	cmd.AddCommand(newExecCmd())
 	cmd.AddCommand(newQueryCmd())
 	cmd.AddCommand(newSendCmd())
+	cmd.AddCommand(newStartCmd())
 
 	return cmd
 }
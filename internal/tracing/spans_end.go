@@ -0,0 +1,184 @@
+package tracing
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/dash0hq/dash0-cli/internal/version"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type endFlags struct {
+	OtlpUrl            string
+	AuthToken          string
+	Dataset            string
+	SpanID             string
+	StatusCode         string
+	StatusMessage      string
+	ResourceAttributes []string
+	SpanAttributes     []string
+	ScopeName          string
+	ScopeVersion       string
+	ScopeAttributes    []string
+	Format             string
+}
+
+func newEndCmd() *cobra.Command {
+	flags := &endFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "end",
+		Short: "End and send a span started with 'dash0 spans start'",
+		Long: `End a span started with 'dash0 spans start' and send it to Dash0 via OTLP.
+
+The span is the one in TRACEPARENT, or the one given with --span-id.
+Attributes given here are added to the ones given when the span started.
+
+The output restores TRACEPARENT to the context the span started in: a shell command to evaluate, or with --format env a 'TRACEPARENT=...' line.` + internal.CONFIG_HINT,
+		Example: `  # End the span in TRACEPARENT
+  eval "$(dash0 spans end --status-code OK)"
+
+  # End a failed span by its ID
+  dash0 spans end --span-id b7ad6b7169203331 \
+      --status-code ERROR --status-message "rollout timed out"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runEnd(cmd, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset name")
+	cmd.Flags().StringVar(&flags.SpanID, "span-id", "", "Span ID of the open span (16 hex characters); defaults to the span in TRACEPARENT")
+	cmd.Flags().StringVar(&flags.StatusCode, "status-code", "UNSET", "Status code: UNSET, OK, ERROR")
+	cmd.Flags().StringVar(&flags.StatusMessage, "status-message", "", "Status message (typically for ERROR status)")
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil, "Resource attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.SpanAttributes, "span-attribute", nil, "Span attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringVar(&flags.ScopeName, "scope-name", otlp.DefaultScopeName, "Instrumentation scope name; defaults to 'dash0-cli'")
+	cmd.Flags().StringVar(&flags.ScopeVersion, "scope-version", version.Version, "Instrumentation scope version; defaults to the dash0 CLI version")
+	cmd.Flags().StringArrayVar(&flags.ScopeAttributes, "scope-attribute", nil, "Instrumentation scope attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringVar(&flags.Format, "format", "shell", "Output format: shell (an export command) or env (a KEY=value line)")
+
+	return cmd
+}
+
+func runEnd(cmd *cobra.Command, flags *endFlags) error {
+	ctx := cmd.Context()
+
+	otlp.ResolveScopeDefaults(cmd, &flags.ScopeName, &flags.ScopeVersion)
+
+	if err := validateEnvFormat(flags.Format); err != nil {
+		return err
+	}
+
+	var spanID pcommon.SpanID
+	if flags.SpanID != "" {
+		id, err := otlp.ParseSpanID(flags.SpanID)
+		if err != nil {
+			return err
+		}
+		spanID = id
+	} else if self, ok := otlp.TraceparentFromEnv(); ok {
+		spanID = self.SpanID
+	} else {
+		return fmt.Errorf("no span to end: TRACEPARENT is not set and --span-id is not given")
+	}
+
+	o, err := loadOpenSpan(spanID)
+	if err != nil {
+		return err
+	}
+	traces, err := o.end(flags, time.Now())
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	defer apiClient.Close(ctx)
+
+	// A span that failed to send stays open, so that ending it can be retried.
+	if err := apiClient.SendTraces(ctx, traces, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
+		return fmt.Errorf("failed to send span: %w", err)
+	}
+	removeOpenSpan(spanID)
+
+	restore := ""
+	if parent, ok := o.parentTraceparent(); ok {
+		restore = parent.String()
+	}
+	fmt.Println(envAssignment(flags.Format, otlp.EnvTraceparent, restore))
+	fmt.Fprintf(os.Stderr, "Span sent (trace-id: %s, span-id: %s)\n", o.TraceID, o.SpanID)
+	return nil
+}
+
+// end returns the payload of o ending at now, with the status and the
+// attributes of flags added.
+func (o openSpan) end(flags *endFlags, now time.Time) (ptrace.Traces, error) {
+	resourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("invalid resource attribute: %w", err)
+	}
+	spanAttrs, err := otlp.ParseKeyValuePairs(flags.SpanAttributes)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("invalid span attribute: %w", err)
+	}
+	scopeAttrs, err := otlp.ParseKeyValuePairs(flags.ScopeAttributes)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("invalid scope attribute: %w", err)
+	}
+
+	attributes := map[string]any{}
+	for k, v := range o.Attributes {
+		attributes[k] = v
+	}
+	for k, v := range spanAttrs {
+		attributes[k] = v
+	}
+	spec := spanSpec{
+		Name:          o.Name,
+		Kind:          o.Kind,
+		StatusCode:    flags.StatusCode,
+		StatusMessage: flags.StatusMessage,
+		StartTime:     o.StartedAt.Format(time.RFC3339Nano),
+		EndTime:       now.Format(time.RFC3339Nano),
+		TraceID:       o.TraceID,
+		SpanID:        o.SpanID,
+		ParentSpanID:  o.ParentSpanID,
+		Attributes:    attributes,
+	}
+
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	for k, v := range o.ResourceAttributes {
+		rs.Resource().Attributes().PutStr(k, v)
+	}
+	for k, v := range resourceAttrs {
+		rs.Resource().Attributes().PutStr(k, v)
+	}
+
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName(flags.ScopeName)
+	ss.Scope().SetVersion(flags.ScopeVersion)
+	for k, v := range scopeAttrs {
+		ss.Scope().Attributes().PutStr(k, v)
+	}
+
+	s := ss.Spans().AppendEmpty()
+	if err := spec.build(s, now); err != nil {
+		return ptrace.Traces{}, err
+	}
+	if o.TraceState != "" {
+		s.TraceState().FromRaw(o.TraceState)
+	}
+	return traces, nil
+}
@@ -0,0 +1,405 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/dash0hq/dash0-cli/internal/version"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// maxTailLineBytes bounds a single captured output line so a child that
+// writes megabytes without a newline cannot grow the span without limit.
+const maxTailLineBytes = 1024
+
+type execFlags struct {
+	OtlpUrl            string
+	AuthToken          string
+	Dataset            string
+	Name               string
+	Kind               string
+	TailLines          int
+	ResourceAttributes []string
+	SpanAttributes     []string
+	ScopeName          string
+	ScopeVersion       string
+	ScopeAttributes    []string
+}
+
+func newExecCmd() *cobra.Command {
+	flags := &execFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- <command> [args...]",
+		Short: "Run a command and send it to Dash0 as a span",
+		Long: `Run a command and send its execution to Dash0 as a span via OTLP.
+
+The span covers the command from start to exit.
+Its status is OK when the command exits with code 0 and ERROR otherwise.
+The last lines of the command's stdout and stderr are attached to the span as events.
+
+The command inherits a TRACEPARENT environment variable pointing at the span, so nested 'dash0 spans send' and 'dash0 spans exec' calls become its children.
+If TRACEPARENT is already set, the span itself joins that trace.
+
+dash0 exits with the command's exit code.
+A failure to send the span is reported as a warning and does not change the exit code.` + internal.CONFIG_HINT,
+		Example: `  # Trace a test run
+  dash0 spans exec --name "integration tests" -- make test
+
+  # Trace a deployment with extra attributes
+  dash0 spans exec --name deploy \
+      --resource-attribute service.name=checkout \
+      --span-attribute deployment.environment.name=production \
+      -- ./deploy.sh production
+
+  # Nested steps become child spans
+  dash0 spans exec --name pipeline -- sh -c \
+      'dash0 spans exec --name build -- make build && dash0 spans exec --name test -- make test'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExec(cmd, flags, args)
+		},
+	}
+
+	// Everything after the first positional argument belongs to the child,
+	// so `dash0 spans exec ls -la` does not try to parse -la.
+	cmd.Flags().SetInterspersed(false)
+
+	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
+	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
+	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset name")
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Span name; defaults to the command line")
+	cmd.Flags().StringVar(&flags.Kind, "kind", "INTERNAL", "Span kind: INTERNAL, SERVER, CLIENT, PRODUCER, CONSUMER")
+	cmd.Flags().IntVar(&flags.TailLines, "tail-lines", 20, "Number of trailing stdout and stderr lines to attach to the span; 0 disables capture")
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil, "Resource attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.SpanAttributes, "span-attribute", nil, "Span attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringVar(&flags.ScopeName, "scope-name", otlp.DefaultScopeName, "Instrumentation scope name; defaults to 'dash0-cli'")
+	cmd.Flags().StringVar(&flags.ScopeVersion, "scope-version", version.Version, "Instrumentation scope version; defaults to the dash0 CLI version")
+	cmd.Flags().StringArrayVar(&flags.ScopeAttributes, "scope-attribute", nil, "Instrumentation scope attribute as 'key=value' (repeatable)")
+
+	return cmd
+}
+
+func runExec(cmd *cobra.Command, flags *execFlags, args []string) error {
+	ctx := cmd.Context()
+
+	otlp.ResolveScopeDefaults(cmd, &flags.ScopeName, &flags.ScopeVersion)
+
+	if flags.TailLines < 0 {
+		return fmt.Errorf("--tail-lines must not be negative")
+	}
+
+	kind, err := ParseSpanKind(flags.Kind)
+	if err != nil {
+		return err
+	}
+
+	resourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid resource attribute: %w", err)
+	}
+
+	spanAttrs, err := otlp.ParseKeyValuePairs(flags.SpanAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid span attribute: %w", err)
+	}
+
+	scopeAttrs, err := otlp.ParseKeyValuePairs(flags.ScopeAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid scope attribute: %w", err)
+	}
+
+	// Resolve the OTLP configuration before running the command, so that a
+	// missing profile is reported up front rather than after a long build.
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	// The span is sent even if dash0 was interrupted, so that a cancelled
+	// run still shows up.
+	sendCtx := context.WithoutCancel(ctx)
+	defer apiClient.Close(sendCtx)
+
+	self := otlp.Traceparent{TraceID: generateTraceID(), SpanID: generateSpanID(), Sampled: true}
+	var parentSpanID pcommon.SpanID
+	if parent, ok := otlp.TraceparentFromEnv(); ok {
+		self.TraceID = parent.TraceID
+		self.Sampled = parent.Sampled
+		parentSpanID = parent.SpanID
+	}
+
+	result := runChild(args, childEnv(os.Environ(), self), flags.TailLines)
+	if result.startErr != nil {
+		// Nothing ran, so there is nothing to trace.
+		return fmt.Errorf("failed to run %s: %w", args[0], result.startErr)
+	}
+
+	traces := buildExecSpan(execSpan{
+		name:          flags.Name,
+		kind:          ptrace.SpanKind(kind),
+		self:          self,
+		parentSpanID:  parentSpanID,
+		args:          args,
+		result:        result,
+		resourceAttrs: resourceAttrs,
+		spanAttrs:     spanAttrs,
+		scopeName:     flags.ScopeName,
+		scopeVersion:  flags.ScopeVersion,
+		scopeAttrs:    scopeAttrs,
+	})
+
+	// The command owns stdout; dash0's own messages go to stderr.
+	traceIDHex := hex.EncodeToString(self.TraceID[:])
+	spanIDHex := hex.EncodeToString(self.SpanID[:])
+	if err := apiClient.SendTraces(sendCtx, traces, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send span: %v\n", err)
+	} else {
+		fmt.Fprintf(os.Stderr, "Span sent (trace-id: %s, span-id: %s)\n", traceIDHex, spanIDHex)
+	}
+
+	if result.exitCode != 0 {
+		return &internal.ExitCodeError{Code: result.exitCode}
+	}
+	return nil
+}
+
+// childEnv returns env with TRACEPARENT pointing at the span of the command
+// being run. A TRACESTATE inherited from the parent is passed on unchanged.
+func childEnv(env []string, self otlp.Traceparent) []string {
+	prefix := otlp.EnvTraceparent + "="
+	result := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, prefix) {
+			result = append(result, kv)
+		}
+	}
+	return append(result, prefix+self.String())
+}
+
+type execResult struct {
+	start    time.Time
+	end      time.Time
+	pid      int
+	exitCode int
+	// state describes how the process ended, e.g. "exit status 2" or
+	// "signal: killed".
+	state    string
+	startErr error
+	stdout   *tailBuffer
+	stderr   *tailBuffer
+}
+
+// runChild runs args with stdin, stdout and stderr connected to dash0's own,
+// keeping the last tailLines lines of each output stream.
+//
+// The child is not tied to the command context: on Ctrl+C the terminal
+// delivers SIGINT to the child directly, and dash0 waits for it to exit so
+// that the span records the real outcome.
+func runChild(args []string, env []string, tailLines int) execResult {
+	c := exec.Command(args[0], args[1:]...)
+	c.Env = env
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	result := execResult{}
+	if tailLines > 0 {
+		// Teeing gives the child a pipe rather than the terminal, which some
+		// tools use to turn off colours; --tail-lines 0 avoids that.
+		result.stdout = newTailBuffer(tailLines)
+		result.stderr = newTailBuffer(tailLines)
+		c.Stdout = io.MultiWriter(os.Stdout, result.stdout)
+		c.Stderr = io.MultiWriter(os.Stderr, result.stderr)
+	}
+
+	result.start = time.Now()
+	if err := c.Start(); err != nil {
+		result.startErr = err
+		return result
+	}
+	result.pid = c.Process.Pid
+	err := c.Wait()
+	result.end = time.Now()
+	result.state = c.ProcessState.String()
+	result.exitCode = c.ProcessState.ExitCode()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		// The process ran but copying its output failed.
+		result.state = err.Error()
+	}
+	if ws, ok := c.ProcessState.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		// Report a signal the way shells do, e.g. 130 for SIGINT.
+		result.exitCode = 128 + int(ws.Signal())
+	} else if result.exitCode == -1 {
+		result.exitCode = 1
+	}
+	return result
+}
+
+type execSpan struct {
+	name          string
+	kind          ptrace.SpanKind
+	self          otlp.Traceparent
+	parentSpanID  pcommon.SpanID
+	args          []string
+	result        execResult
+	resourceAttrs map[string]string
+	spanAttrs     map[string]string
+	scopeName     string
+	scopeVersion  string
+	scopeAttrs    map[string]string
+}
+
+func buildExecSpan(e execSpan) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	for k, v := range e.resourceAttrs {
+		rs.Resource().Attributes().PutStr(k, v)
+	}
+
+	ss := rs.ScopeSpans().AppendEmpty()
+	scope := ss.Scope()
+	scope.SetName(e.scopeName)
+	scope.SetVersion(e.scopeVersion)
+	for k, v := range e.scopeAttrs {
+		scope.Attributes().PutStr(k, v)
+	}
+
+	s := ss.Spans().AppendEmpty()
+	name := e.name
+	if name == "" {
+		name = strings.Join(e.args, " ")
+	}
+	s.SetName(name)
+	s.SetKind(e.kind)
+	s.SetTraceID(e.self.TraceID)
+	s.SetSpanID(e.self.SpanID)
+	if !e.parentSpanID.IsEmpty() {
+		s.SetParentSpanID(e.parentSpanID)
+	}
+	if tracestate := otlp.TracestateFromEnv(); tracestate != "" && !e.parentSpanID.IsEmpty() {
+		s.TraceState().FromRaw(tracestate)
+	}
+	s.SetStartTimestamp(pcommon.NewTimestampFromTime(e.result.start))
+	s.SetEndTimestamp(pcommon.NewTimestampFromTime(e.result.end))
+
+	if e.result.exitCode == 0 {
+		s.Status().SetCode(ptrace.StatusCodeOk)
+	} else {
+		s.Status().SetCode(ptrace.StatusCodeError)
+		s.Status().SetMessage(e.result.state)
+	}
+
+	attrs := s.Attributes()
+	attrs.PutStr("process.executable.name", filepath.Base(e.args[0]))
+	commandArgs := attrs.PutEmptySlice("process.command_args")
+	for _, a := range e.args {
+		commandArgs.AppendEmpty().SetStr(a)
+	}
+	attrs.PutInt("process.pid", int64(e.result.pid))
+	attrs.PutInt("process.exit.code", int64(e.result.exitCode))
+	for k, v := range e.spanAttrs {
+		attrs.PutStr(k, v)
+	}
+
+	addOutputEvent(s, "stdout", e.result.stdout, e.result.end)
+	addOutputEvent(s, "stderr", e.result.stderr, e.result.end)
+
+	return traces
+}
+
+func addOutputEvent(s ptrace.Span, stream string, tail *tailBuffer, at time.Time) {
+	if tail == nil {
+		return
+	}
+	text, truncated := tail.Tail()
+	if text == "" {
+		return
+	}
+	ev := s.Events().AppendEmpty()
+	ev.SetName("process.output")
+	ev.SetTimestamp(pcommon.NewTimestampFromTime(at))
+	ev.Attributes().PutStr("process.output.stream", stream)
+	ev.Attributes().PutStr("process.output.text", text)
+	ev.Attributes().PutBool("process.output.truncated", truncated)
+}
+
+// tailBuffer is an io.Writer that keeps the last max lines written to it.
+type tailBuffer struct {
+	mu        sync.Mutex
+	max       int
+	lines     []string
+	partial   []byte
+	truncated bool
+}
+
+func newTailBuffer(max int) *tailBuffer {
+	return &tailBuffer{max: max}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			b.appendPartial(p)
+			break
+		}
+		b.appendPartial(p[:i])
+		b.pushLine()
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+func (b *tailBuffer) appendPartial(p []byte) {
+	if room := maxTailLineBytes - len(b.partial); len(p) > room {
+		p = p[:max(room, 0)]
+		b.truncated = true
+	}
+	b.partial = append(b.partial, p...)
+}
+
+func (b *tailBuffer) pushLine() {
+	b.lines = append(b.lines, strings.TrimSuffix(string(b.partial), "\r"))
+	b.partial = b.partial[:0]
+	if len(b.lines) > b.max {
+		b.lines = b.lines[len(b.lines)-b.max:]
+		b.truncated = true
+	}
+}
+
+// Tail returns the retained lines, including a final line without a
+// trailing newline, and whether any output was dropped.
+func (b *tailBuffer) Tail() (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := b.lines
+	truncated := b.truncated
+	if len(b.partial) > 0 {
+		lines = append(lines[:len(lines):len(lines)], string(b.partial))
+		if len(lines) > b.max {
+			lines = lines[len(lines)-b.max:]
+			truncated = true
+		}
+	}
+	return strings.Join(lines, "\n"), truncated
+}
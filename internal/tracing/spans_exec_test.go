@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestExecRequiresCommand(t *testing.T) {
+	root, _ := newSpansSendCmd()
+	root.SetArgs([]string{"spans", "exec", "--name", "test"})
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires at least 1 arg")
+}
+
+func TestExecDoesNotParseChildFlags(t *testing.T) {
+	root, _ := newSpansSendCmd()
+	execCmd, args, err := root.Find([]string{"spans", "exec"})
+	require.NoError(t, err)
+	require.Empty(t, args)
+	require.NoError(t, execCmd.ParseFlags([]string{"--name", "list", "ls", "-la", "--name", "child"}))
+	name, _ := execCmd.Flags().GetString("name")
+	assert.Equal(t, "list", name)
+	assert.Equal(t, []string{"ls", "-la", "--name", "child"}, execCmd.Flags().Args())
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(2)
+	_, _ = b.Write([]byte("one\ntwo\r\nth"))
+	_, _ = b.Write([]byte("ree\nfour"))
+	text, truncated := b.Tail()
+	assert.Equal(t, "three\nfour", text)
+	assert.True(t, truncated)
+
+	b = newTailBuffer(5)
+	_, _ = b.Write([]byte("a\nb\n"))
+	text, truncated = b.Tail()
+	assert.Equal(t, "a\nb", text)
+	assert.False(t, truncated)
+}
+
+func TestTailBuffer_LongLine(t *testing.T) {
+	b := newTailBuffer(3)
+	_, _ = b.Write([]byte(strings.Repeat("x", 3*maxTailLineBytes) + "\nend\n"))
+	text, truncated := b.Tail()
+	assert.Equal(t, strings.Repeat("x", maxTailLineBytes)+"\nend", text)
+	assert.True(t, truncated)
+}
+
+func TestChildEnv(t *testing.T) {
+	self := otlp.Traceparent{TraceID: generateTraceID(), SpanID: generateSpanID(), Sampled: true}
+	env := childEnv([]string{"PATH=/bin", "TRACEPARENT=00-stale", "TRACESTATE=vendor=1"}, self)
+	assert.Equal(t, []string{"PATH=/bin", "TRACESTATE=vendor=1", "TRACEPARENT=" + self.String()}, env)
+}
+
+func TestBuildExecSpan(t *testing.T) {
+	start := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	stderr := newTailBuffer(5)
+	_, _ = stderr.Write([]byte("FAIL: TestCheckout\n"))
+	parent := otlp.Traceparent{TraceID: generateTraceID(), SpanID: generateSpanID()}
+	self := otlp.Traceparent{TraceID: parent.TraceID, SpanID: generateSpanID()}
+
+	traces := buildExecSpan(execSpan{
+		kind:         ptrace.SpanKindInternal,
+		self:         self,
+		parentSpanID: parent.SpanID,
+		args:         []string{"/usr/bin/make", "test"},
+		result: execResult{
+			start:    start,
+			end:      start.Add(90 * time.Second),
+			pid:      4242,
+			exitCode: 2,
+			state:    "exit status 2",
+			stdout:   newTailBuffer(5),
+			stderr:   stderr,
+		},
+		spanAttrs: map[string]string{"ci.job": "unit"},
+		scopeName: "dash0-cli",
+	})
+
+	s := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "/usr/bin/make test", s.Name())
+	assert.Equal(t, parent.TraceID, s.TraceID())
+	assert.Equal(t, parent.SpanID, s.ParentSpanID())
+	assert.Equal(t, 90*time.Second, s.EndTimestamp().AsTime().Sub(s.StartTimestamp().AsTime()))
+	assert.Equal(t, ptrace.StatusCodeError, s.Status().Code())
+	assert.Equal(t, "exit status 2", s.Status().Message())
+
+	exe, _ := s.Attributes().Get("process.executable.name")
+	assert.Equal(t, "make", exe.Str())
+	args, _ := s.Attributes().Get("process.command_args")
+	assert.Equal(t, []any{"/usr/bin/make", "test"}, args.Slice().AsRaw())
+	code, _ := s.Attributes().Get("process.exit.code")
+	assert.Equal(t, int64(2), code.Int())
+	job, _ := s.Attributes().Get("ci.job")
+	assert.Equal(t, "unit", job.Str())
+
+	// Empty stdout produces no event.
+	require.Equal(t, 1, s.Events().Len())
+	ev := s.Events().At(0)
+	assert.Equal(t, "process.output", ev.Name())
+	stream, _ := ev.Attributes().Get("process.output.stream")
+	assert.Equal(t, "stderr", stream.Str())
+	text, _ := ev.Attributes().Get("process.output.text")
+	assert.Equal(t, "FAIL: TestCheckout", text.Str())
+}
+
+func TestBuildExecSpan_SuccessWithoutParent(t *testing.T) {
+	traces := buildExecSpan(execSpan{
+		name:   "build",
+		self:   otlp.Traceparent{TraceID: generateTraceID(), SpanID: generateSpanID()},
+		args:   []string{"make"},
+		result: execResult{start: time.Now(), end: time.Now()},
+	})
+	s := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "build", s.Name())
+	assert.Equal(t, ptrace.StatusCodeOk, s.Status().Code())
+	assert.Equal(t, pcommon.SpanID{}, s.ParentSpanID())
+	assert.Equal(t, 0, s.Events().Len())
+}
+
+func TestRunChild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	self := otlp.Traceparent{TraceID: generateTraceID(), SpanID: generateSpanID(), Sampled: true}
+	result := runChild(
+		[]string{"sh", "-c", `echo "$TRACEPARENT"; echo oops >&2; exit 3`},
+		childEnv(nil, self),
+		5,
+	)
+	require.NoError(t, result.startErr)
+	assert.Equal(t, 3, result.exitCode)
+	assert.Equal(t, "exit status 3", result.state)
+	stdout, _ := result.stdout.Tail()
+	assert.Equal(t, self.String(), stdout)
+	stderr, _ := result.stderr.Tail()
+	assert.Equal(t, "oops", stderr)
+
+	result = runChild([]string{"dash0-no-such-command"}, nil, 5)
+	assert.Error(t, result.startErr)
+}
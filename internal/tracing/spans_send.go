@@ -36,6 +36,9 @@ type sendFlags struct {
 	ScopeName          string
 	ScopeVersion       string
 	ScopeAttributes    []string
+	File               string
+	JUnit              []string
+	BatchSize          int
 }
 
 func newSendCmd() *cobra.Command {
@@ -44,7 +47,14 @@ func newSendCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "send",
 		Short: "Send a span to Dash0",
-		Long:  `Send a span to Dash0 via OTLP.` + internal.CONFIG_HINT,
+		Long: `Send a span to Dash0 via OTLP.
+
+With --file, send the spans in a file (or stdin with '-') in batches instead.
+The input holds OTLP/JSON trace payloads, or one compact span per line with the fields name, kind, status-code, status-message, start-time, end-time, duration, trace-id, span-id, parent-span-id, links, attributes, and resource-attributes.
+
+With --junit, send JUnit XML test reports as one trace: a root span for the run (named by --name), a span per test suite, and a span per test case.
+Failed tests have an error status and an exception event per failure; skipped tests keep an unset status.
+In GitHub Actions, GitLab CI, and Jenkins, the pipeline run, job, repository, branch, and commit are added as cicd.* and vcs.* resource attributes.` + internal.CONFIG_HINT,
 		Example: `  # Send a simple span
   dash0 spans send --name "my-operation"
 
@@ -68,7 +78,14 @@ func newSendCmd() *cobra.Command {
   dash0 spans send --name "db-query" \
       --kind CLIENT \
       --trace-id 0af7651916cd43dd8448eb211c80319c \
-      --parent-span-id b7ad6b7169203331`,
+      --parent-span-id b7ad6b7169203331
+
+  # Send the spans in a file, one JSON object per line
+  dash0 spans send -f spans.ndjson --resource-attribute service.name=test-harness
+
+  # Send the test results of a CI job
+  dash0 spans send --junit build/test-results/junit.xml --name "unit tests" \
+      --resource-attribute service.name=checkout`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runSend(cmd, flags)
@@ -78,8 +95,7 @@ func newSendCmd() *cobra.Command {
 	cmd.Flags().StringVar(&flags.OtlpUrl, "otlp-url", "", "OTLP endpoint URL (overrides active profile)")
 	cmd.Flags().StringVar(&flags.AuthToken, "auth-token", "", "Auth token (overrides active profile)")
 	cmd.Flags().StringVar(&flags.Dataset, "dataset", "", "Dataset name")
-	cmd.Flags().StringVar(&flags.Name, "name", "", "Span name (required)")
-	_ = cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Span name (required unless --file is used); with --junit, the name of the root span")
 	cmd.Flags().StringVar(&flags.Kind, "kind", "INTERNAL", "Span kind: INTERNAL, SERVER, CLIENT, PRODUCER, CONSUMER")
 	cmd.Flags().StringVar(&flags.StatusCode, "status-code", "UNSET", "Status code: UNSET, OK, ERROR")
 	cmd.Flags().StringVar(&flags.StatusMessage, "status-message", "", "Status message (typically for ERROR status)")
@@ -95,6 +111,9 @@ func newSendCmd() *cobra.Command {
 	cmd.Flags().StringVar(&flags.ScopeName, "scope-name", otlp.DefaultScopeName, "Instrumentation scope name; defaults to 'dash0-cli'")
 	cmd.Flags().StringVar(&flags.ScopeVersion, "scope-version", version.Version, "Instrumentation scope version; defaults to the dash0 CLI version")
 	cmd.Flags().StringArrayVar(&flags.ScopeAttributes, "scope-attribute", nil, "Instrumentation scope attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringVarP(&flags.File, "file", "f", "", "Read spans from an OTLP/JSON or NDJSON file ('-' for stdin) instead of sending a single span")
+	cmd.Flags().StringArrayVar(&flags.JUnit, "junit", nil, "Send the test suites and cases of a JUnit XML report ('-' for stdin) as a trace (repeatable)")
+	cmd.Flags().IntVar(&flags.BatchSize, "batch-size", 1000, "Maximum number of spans per OTLP request with --file or --junit")
 
 	return cmd
 }
@@ -104,17 +123,17 @@ func runSend(cmd *cobra.Command, flags *sendFlags) error {
 
 	otlp.ResolveScopeDefaults(cmd, &flags.ScopeName, &flags.ScopeVersion)
 
-	if flags.EndTime != "" && flags.Duration != "" {
-		return fmt.Errorf("--end-time and --duration are mutually exclusive")
+	if len(flags.JUnit) > 0 {
+		return runSendJUnit(cmd, flags)
 	}
-
-	kind, err := ParseSpanKind(flags.Kind)
-	if err != nil {
-		return err
+	if flags.File != "" {
+		return runSendFile(cmd, flags)
+	}
+	if flags.Name == "" {
+		return fmt.Errorf(`required flag(s) "name" not set`)
 	}
 
-	statusCode, err := ParseSpanStatusCode(flags.StatusCode)
-	if err != nil {
+	if _, err := ParseSpanKind(flags.Kind); err != nil {
 		return err
 	}
 
@@ -133,101 +152,194 @@ func runSend(cmd *cobra.Command, flags *sendFlags) error {
 		return fmt.Errorf("invalid scope attribute: %w", err)
 	}
 
-	now := time.Now()
+	spec := spanSpec{
+		Name:          flags.Name,
+		Kind:          flags.Kind,
+		StatusCode:    flags.StatusCode,
+		StatusMessage: flags.StatusMessage,
+		StartTime:     flags.StartTime,
+		EndTime:       flags.EndTime,
+		Duration:      flags.Duration,
+		TraceID:       flags.TraceID,
+		SpanID:        flags.SpanID,
+		ParentSpanID:  flags.ParentSpanID,
+		Links:         flags.SpanLinks,
+	}
+
+	// Build ptrace.Traces
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+
+	resource := rs.Resource()
+	for k, v := range resourceAttrs {
+		resource.Attributes().PutStr(k, v)
+	}
+
+	ss := rs.ScopeSpans().AppendEmpty()
+	setScope(ss.Scope(), flags, scopeAttrs)
+
+	s := ss.Spans().AppendEmpty()
+	if err := spec.build(s, time.Now()); err != nil {
+		return err
+	}
+	for k, v := range spanAttrs {
+		s.Attributes().PutStr(k, v)
+	}
+
+	// Create OTLP client and send
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	defer apiClient.Close(ctx)
+
+	if err := apiClient.SendTraces(ctx, traces, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
+		return fmt.Errorf("failed to send span: %w", err)
+	}
+
+	fmt.Printf("Span sent (trace-id: %s, span-id: %s)\n", s.TraceID(), s.SpanID())
+	return nil
+}
+
+func setScope(scope pcommon.InstrumentationScope, flags *sendFlags, scopeAttrs map[string]string) {
+	scope.SetName(flags.ScopeName)
+	scope.SetVersion(flags.ScopeVersion)
+	for k, v := range scopeAttrs {
+		scope.Attributes().PutStr(k, v)
+	}
+}
+
+// spanSpec describes a span with the same fields as the spans send flags.
+// It is filled from the flags, or from one entry of a --file input.
+type spanSpec struct {
+	Name               string         `json:"name"`
+	Kind               string         `json:"kind"`
+	StatusCode         string         `json:"status-code"`
+	StatusMessage      string         `json:"status-message"`
+	StartTime          string         `json:"start-time"`
+	EndTime            string         `json:"end-time"`
+	Duration           string         `json:"duration"`
+	TraceID            string         `json:"trace-id"`
+	SpanID             string         `json:"span-id"`
+	ParentSpanID       string         `json:"parent-span-id"`
+	Links              []string       `json:"links"`
+	Attributes         map[string]any `json:"attributes"`
+	ResourceAttributes map[string]any `json:"resource-attributes"`
+}
+
+// build validates spec and fills s from it. Without a trace ID, the span
+// joins the trace in TRACEPARENT (set, for example, by `dash0 spans exec`
+// or `dash0 spans start`) or starts a new trace; a missing span ID is
+// generated.
+func (spec spanSpec) build(s ptrace.Span, now time.Time) error {
+	if spec.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if spec.EndTime != "" && spec.Duration != "" {
+		return fmt.Errorf("--end-time and --duration are mutually exclusive")
+	}
+
+	if spec.Kind == "" {
+		spec.Kind = "INTERNAL"
+	}
+	kind, err := ParseSpanKind(spec.Kind)
+	if err != nil {
+		return err
+	}
+
+	if spec.StatusCode == "" {
+		spec.StatusCode = "UNSET"
+	}
+	statusCode, err := ParseSpanStatusCode(spec.StatusCode)
+	if err != nil {
+		return err
+	}
 
 	startTime := now
-	if flags.StartTime != "" {
-		startTime, err = time.Parse(time.RFC3339Nano, flags.StartTime)
+	if spec.StartTime != "" {
+		startTime, err = time.Parse(time.RFC3339Nano, spec.StartTime)
 		if err != nil {
 			return fmt.Errorf("invalid start-time format (expected RFC3339): %w", err)
 		}
 	}
 
 	endTime := startTime
-	if flags.EndTime != "" {
-		endTime, err = time.Parse(time.RFC3339Nano, flags.EndTime)
+	if spec.EndTime != "" {
+		endTime, err = time.Parse(time.RFC3339Nano, spec.EndTime)
 		if err != nil {
 			return fmt.Errorf("invalid end-time format (expected RFC3339): %w", err)
 		}
-	} else if flags.Duration != "" {
-		d, err := ParseDuration(flags.Duration)
+	} else if spec.Duration != "" {
+		d, err := ParseDuration(spec.Duration)
 		if err != nil {
 			return err
 		}
 		endTime = startTime.Add(d)
 	}
 
+	// Without an explicit trace or parent, join the trace of an enclosing
+	// `dash0 spans exec` or `dash0 spans start` (or any other tool that sets
+	// TRACEPARENT), along with its TRACESTATE.
+	var tracestate string
+	if spec.TraceID == "" && spec.ParentSpanID == "" {
+		if parent, ok := otlp.TraceparentFromEnv(); ok {
+			spec.TraceID = hex.EncodeToString(parent.TraceID[:])
+			spec.ParentSpanID = hex.EncodeToString(parent.SpanID[:])
+			tracestate = otlp.TracestateFromEnv()
+		}
+	}
+
 	// Generate or parse trace ID
-	var traceID pcommon.TraceID
-	traceIDHex := flags.TraceID
-	if traceIDHex == "" {
-		traceID = generateTraceID()
-		traceIDHex = hex.EncodeToString(traceID[:])
-	} else {
-		traceID, err = otlp.ParseTraceID(traceIDHex)
+	traceID := generateTraceID()
+	if spec.TraceID != "" {
+		traceID, err = otlp.ParseTraceID(spec.TraceID)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Generate or parse span ID
-	var spanID pcommon.SpanID
-	spanIDHex := flags.SpanID
-	if spanIDHex == "" {
-		spanID = generateSpanID()
-		spanIDHex = hex.EncodeToString(spanID[:])
-	} else {
-		spanID, err = otlp.ParseSpanID(spanIDHex)
+	spanID := generateSpanID()
+	if spec.SpanID != "" {
+		spanID, err = otlp.ParseSpanID(spec.SpanID)
 		if err != nil {
 			return err
 		}
 	}
 
 	// Parse span links
-	links, err := parseSpanLinks(flags.SpanLinks)
+	links, err := parseSpanLinks(spec.Links)
 	if err != nil {
 		return err
 	}
 
-	// Build ptrace.Traces
-	traces := ptrace.NewTraces()
-	rs := traces.ResourceSpans().AppendEmpty()
-
-	resource := rs.Resource()
-	for k, v := range resourceAttrs {
-		resource.Attributes().PutStr(k, v)
-	}
-
-	ss := rs.ScopeSpans().AppendEmpty()
-	scope := ss.Scope()
-	scope.SetName(flags.ScopeName)
-	scope.SetVersion(flags.ScopeVersion)
-	for k, v := range scopeAttrs {
-		scope.Attributes().PutStr(k, v)
-	}
-
-	s := ss.Spans().AppendEmpty()
-	s.SetName(flags.Name)
+	s.SetName(spec.Name)
 	s.SetKind(ptrace.SpanKind(kind))
 	s.Status().SetCode(ptrace.StatusCode(statusCode))
-	if flags.StatusMessage != "" {
-		s.Status().SetMessage(flags.StatusMessage)
+	if spec.StatusMessage != "" {
+		s.Status().SetMessage(spec.StatusMessage)
 	}
 	s.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
 	s.SetEndTimestamp(pcommon.NewTimestampFromTime(endTime))
 	s.SetTraceID(traceID)
 	s.SetSpanID(spanID)
 
-	if flags.ParentSpanID != "" {
-		parentSpanID, err := otlp.ParseSpanID(flags.ParentSpanID)
+	if spec.ParentSpanID != "" {
+		parentSpanID, err := otlp.ParseSpanID(spec.ParentSpanID)
 		if err != nil {
 			return fmt.Errorf("invalid parent-span-id: %w", err)
 		}
 		s.SetParentSpanID(parentSpanID)
 	}
+	if tracestate != "" {
+		s.TraceState().FromRaw(tracestate)
+	}
 
-	for k, v := range spanAttrs {
-		s.Attributes().PutStr(k, v)
+	for k, v := range spec.Attributes {
+		if err := s.Attributes().PutEmpty(k).FromRaw(v); err != nil {
+			return fmt.Errorf("invalid attribute %q: %w", k, err)
+		}
 	}
 
 	for _, link := range links {
@@ -239,18 +351,6 @@ func runSend(cmd *cobra.Command, flags *sendFlags) error {
 		}
 	}
 
-	// Create OTLP client and send
-	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
-	if err != nil {
-		return err
-	}
-	defer apiClient.Close(ctx)
-
-	if err := apiClient.SendTraces(ctx, traces, client.ResolveDataset(ctx, flags.Dataset)); err != nil {
-		return fmt.Errorf("failed to send span: %w", err)
-	}
-
-	fmt.Printf("Span sent (trace-id: %s, span-id: %s)\n", traceIDHex, spanIDHex)
 	return nil
 }
 
@@ -0,0 +1,260 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// singleSpanFlags describe one span and have no meaning with --file, where
+// every entry carries its own values.
+var singleSpanFlags = []string{
+	"name", "status-code", "status-message", "start-time", "end-time", "duration",
+	"trace-id", "span-id", "parent-span-id", "span-link",
+}
+
+func runSendFile(cmd *cobra.Command, flags *sendFlags) error {
+	ctx := cmd.Context()
+
+	for _, name := range singleSpanFlags {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s cannot be used with --file; set %s per span in the file instead", name, name)
+		}
+	}
+	if flags.BatchSize <= 0 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
+	if _, err := ParseSpanKind(flags.Kind); err != nil {
+		return err
+	}
+
+	resourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid resource attribute: %w", err)
+	}
+
+	spanAttrs, err := otlp.ParseKeyValuePairs(flags.SpanAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid span attribute: %w", err)
+	}
+
+	scopeAttrs, err := otlp.ParseKeyValuePairs(flags.ScopeAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid scope attribute: %w", err)
+	}
+
+	var r io.Reader = os.Stdin
+	if flags.File != "-" {
+		f, err := os.Open(flags.File)
+		if err != nil {
+			return fmt.Errorf("failed to open span file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	// Spans already read are still sent after Ctrl+C.
+	sendCtx := context.WithoutCancel(ctx)
+	defer apiClient.Close(sendCtx)
+	dataset := client.ResolveDataset(ctx, flags.Dataset)
+
+	s := &spanShipper{
+		flags:         flags,
+		resourceAttrs: resourceAttrs,
+		spanAttrs:     spanAttrs,
+		scopeAttrs:    scopeAttrs,
+		send: func(ctx context.Context, traces ptrace.Traces) error {
+			return apiClient.SendTraces(ctx, traces, dataset)
+		},
+	}
+	sent, err := s.run(ctx, r)
+	if err != nil {
+		return fmt.Errorf("failed to send spans (%d sent before the failure): %w", sent, err)
+	}
+	if sent == 1 {
+		fmt.Println("1 span sent")
+	} else {
+		fmt.Printf("%d spans sent\n", sent)
+	}
+	return nil
+}
+
+// spanShipper reads a stream of JSON values, each either an OTLP/JSON
+// ExportTraceServiceRequest or a compact spanSpec, and sends the spans in
+// batches of about flags.BatchSize spans. An OTLP payload is never split, so
+// a single large payload is sent as one request.
+type spanShipper struct {
+	flags         *sendFlags
+	resourceAttrs map[string]string
+	spanAttrs     map[string]string
+	scopeAttrs    map[string]string
+	send          func(context.Context, ptrace.Traces) error
+
+	batch     ptrace.Traces
+	resources map[string]ptrace.SpanSlice
+	pending   int
+}
+
+// run sends the spans in r and returns the number of spans sent. The input
+// is validated entry by entry, so an invalid entry stops the run after the
+// batches before it have been sent.
+func (s *spanShipper) run(ctx context.Context, r io.Reader) (int, error) {
+	sendCtx := context.WithoutCancel(ctx)
+	sent := 0
+	s.reset()
+	flush := func() error {
+		if s.pending == 0 {
+			return nil
+		}
+		if err := s.send(sendCtx, s.batch); err != nil {
+			return err
+		}
+		sent += s.pending
+		s.reset()
+		return nil
+	}
+
+	dec := json.NewDecoder(r)
+	for entry := 1; ctx.Err() == nil; entry++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return sent, fmt.Errorf("entry %d: invalid JSON: %w", entry, err)
+		}
+
+		if isOTLPTracesPayload(raw) {
+			traces, err := parseOTLPTraces(raw)
+			if err != nil {
+				return sent, fmt.Errorf("entry %d: %w", entry, err)
+			}
+			if s.pending > 0 && s.pending+traces.SpanCount() > s.flags.BatchSize {
+				if err := flush(); err != nil {
+					return sent, err
+				}
+			}
+			s.pending += traces.SpanCount()
+			traces.ResourceSpans().MoveAndAppendTo(s.batch.ResourceSpans())
+		} else if err := s.appendCompact(raw); err != nil {
+			return sent, fmt.Errorf("entry %d: %w", entry, err)
+		}
+
+		if s.pending >= s.flags.BatchSize {
+			if err := flush(); err != nil {
+				return sent, err
+			}
+		}
+	}
+
+	err := flush()
+	return sent, err
+}
+
+func (s *spanShipper) reset() {
+	s.batch = ptrace.NewTraces()
+	s.resources = map[string]ptrace.SpanSlice{}
+	s.pending = 0
+}
+
+// appendCompact adds a compact span to the batch, grouping spans with equal
+// resource attributes under one resource.
+func (s *spanShipper) appendCompact(raw json.RawMessage) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	dec.UseNumber()
+	var spec spanSpec
+	if err := dec.Decode(&spec); err != nil {
+		return fmt.Errorf("invalid span: %w", err)
+	}
+	if spec.Kind == "" {
+		spec.Kind = s.flags.Kind
+	}
+	spec.Attributes, _ = otlp.NormalizeJSONNumbers(spec.Attributes).(map[string]any)
+
+	resourceAttrs := make(map[string]any, len(s.resourceAttrs)+len(spec.ResourceAttributes))
+	for k, v := range s.resourceAttrs {
+		resourceAttrs[k] = v
+	}
+	for k, v := range spec.ResourceAttributes {
+		resourceAttrs[k] = otlp.NormalizeJSONNumbers(v)
+	}
+
+	span := ptrace.NewSpan()
+	if err := spec.build(span, time.Now()); err != nil {
+		return err
+	}
+	for k, v := range s.spanAttrs {
+		if _, exists := span.Attributes().Get(k); !exists {
+			span.Attributes().PutStr(k, v)
+		}
+	}
+
+	// encoding/json sorts map keys, so equal attribute sets share a key.
+	key, err := json.Marshal(resourceAttrs)
+	if err != nil {
+		return fmt.Errorf("invalid resource-attributes: %w", err)
+	}
+	spans, ok := s.resources[string(key)]
+	if !ok {
+		rs := s.batch.ResourceSpans().AppendEmpty()
+		if err := rs.Resource().Attributes().FromRaw(resourceAttrs); err != nil {
+			return fmt.Errorf("invalid resource-attributes: %w", err)
+		}
+		ss := rs.ScopeSpans().AppendEmpty()
+		setScope(ss.Scope(), s.flags, s.scopeAttrs)
+		spans = ss.Spans()
+		s.resources[string(key)] = spans
+	}
+	span.MoveTo(spans.AppendEmpty())
+	s.pending++
+	return nil
+}
+
+// isOTLPTracesPayload reports whether raw is a JSON object with a
+// resourceSpans field, as opposed to a compact span.
+func isOTLPTracesPayload(raw json.RawMessage) bool {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+	_, ok := fields["resourceSpans"]
+	return ok
+}
+
+// parseOTLPTraces decodes an OTLP/JSON payload and checks that every span
+// can be sent: the OTLP decoder accepts spans without IDs, which the
+// backend would drop.
+func parseOTLPTraces(raw json.RawMessage) (ptrace.Traces, error) {
+	traces, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(raw)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("invalid OTLP/JSON payload: %w", err)
+	}
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		sss := rss.At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if span := spans.At(k); span.TraceID().IsEmpty() || span.SpanID().IsEmpty() {
+					return ptrace.Traces{}, fmt.Errorf("span %q has no trace ID or span ID", span.Name())
+				}
+			}
+		}
+	}
+	return traces, nil
+}
@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+type recordingTraceSender struct {
+	batches []ptrace.Traces
+}
+
+func (s *recordingTraceSender) send(_ context.Context, traces ptrace.Traces) error {
+	s.batches = append(s.batches, traces)
+	return nil
+}
+
+func newTestSpanShipper(sender *recordingTraceSender, batchSize int) *spanShipper {
+	return &spanShipper{
+		flags:         &sendFlags{Kind: "INTERNAL", ScopeName: "dash0-cli", BatchSize: batchSize},
+		resourceAttrs: map[string]string{"service.name": "test-harness"},
+		spanAttrs:     map[string]string{"test.suite": "checkout"},
+		send:          sender.send,
+	}
+}
+
+func TestSendFileRejectsSingleSpanFlags(t *testing.T) {
+	root, _ := newSpansSendCmd()
+	root.SetArgs([]string{"spans", "send", "--file", "spans.ndjson", "--trace-id", "0af7651916cd43dd8448eb211c80319c"})
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--trace-id cannot be used with --file")
+}
+
+func TestSpanShipper_CompactSpans(t *testing.T) {
+	t.Setenv(otlp.EnvTraceparent, "")
+	sender := &recordingTraceSender{}
+	s := newTestSpanShipper(sender, 100)
+	input := `{"name":"test login","kind":"CLIENT","status-code":"OK","trace-id":"0af7651916cd43dd8448eb211c80319c","span-id":"b7ad6b7169203331","start-time":"2026-03-15T10:30:00Z","duration":"250ms","attributes":{"test.retries":2,"test.flaky":true}}
+{"name":"test logout","status-code":"ERROR","trace-id":"0af7651916cd43dd8448eb211c80319c","parent-span-id":"b7ad6b7169203331","links":["4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7"],"attributes":{"test.suite":"auth"}}
+{"name":"other service","resource-attributes":{"service.name":"billing"}}
+`
+	sent, err := s.run(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	require.Len(t, sender.batches, 1)
+
+	rss := sender.batches[0].ResourceSpans()
+	require.Equal(t, 2, rss.Len(), "spans are grouped by resource")
+	serviceName, _ := rss.At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "test-harness", serviceName.Str())
+	serviceName, _ = rss.At(1).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "billing", serviceName.Str(), "the entry wins over --resource-attribute")
+
+	spans := rss.At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 2, spans.Len())
+	login := spans.At(0)
+	assert.Equal(t, ptrace.SpanKindClient, login.Kind())
+	assert.Equal(t, ptrace.StatusCodeOk, login.Status().Code())
+	assert.Equal(t, "b7ad6b7169203331", login.SpanID().String())
+	assert.Equal(t, int64(250e6), int64(login.EndTimestamp()-login.StartTimestamp()))
+	assert.Equal(t, map[string]any{"test.retries": int64(2), "test.flaky": true, "test.suite": "checkout"}, login.Attributes().AsRaw())
+
+	logout := spans.At(1)
+	assert.Equal(t, ptrace.SpanKindInternal, logout.Kind(), "--kind is the default")
+	assert.Equal(t, login.SpanID(), logout.ParentSpanID())
+	assert.Equal(t, 1, logout.Links().Len())
+	suite, _ := logout.Attributes().Get("test.suite")
+	assert.Equal(t, "auth", suite.Str())
+}
+
+func TestSpanShipper_OTLPPayloads(t *testing.T) {
+	sender := &recordingTraceSender{}
+	s := newTestSpanShipper(sender, 2)
+	payload := `{
+  "resourceSpans": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "frontend"}}]},
+    "scopeSpans": [{"spans": [
+      {"traceId": "0af7651916cd43dd8448eb211c80319c", "spanId": "b7ad6b7169203331", "name": "a"},
+      {"traceId": "0af7651916cd43dd8448eb211c80319c", "spanId": "00f067aa0ba902b7", "name": "b"}
+    ]}]
+  }]
+}
+{"name":"compact"}
+`
+	sent, err := s.run(context.Background(), strings.NewReader(payload))
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	require.Len(t, sender.batches, 2)
+	assert.Equal(t, 2, sender.batches[0].SpanCount())
+	serviceName, _ := sender.batches[0].ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "frontend", serviceName.Str(), "OTLP payloads are sent unchanged")
+	assert.Equal(t, 1, sender.batches[1].SpanCount())
+}
+
+func TestSpanShipper_Batches(t *testing.T) {
+	sender := &recordingTraceSender{}
+	s := newTestSpanShipper(sender, 2)
+	sent, err := s.run(context.Background(), strings.NewReader(`{"name":"1"}{"name":"2"}{"name":"3"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	require.Len(t, sender.batches, 2)
+	assert.Equal(t, 2, sender.batches[0].SpanCount())
+	assert.Equal(t, 1, sender.batches[1].SpanCount())
+}
+
+func TestSpanShipper_InvalidEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"unknown field", `{"name":"a","colour":"red"}`, `entry 1: invalid span: json: unknown field "colour"`},
+		{"missing name", `{"kind":"SERVER"}`, "entry 1: name is required"},
+		{"bad trace id", `{"name":"a"}` + "\n" + `{"name":"b","trace-id":"xyz"}`, "entry 2: trace-id must be 32 hex characters"},
+		{"bad parent", `{"name":"a","parent-span-id":"zzzzzzzzzzzzzzzz"}`, "invalid parent-span-id"},
+		{"bad link", `{"name":"a","links":["nope"]}`, "invalid span-link"},
+		{"bad kind", `{"name":"a","kind":"SIDEWAYS"}`, "unknown span kind"},
+		{"OTLP span without ID", `{"resourceSpans":[{"scopeSpans":[{"spans":[{"name":"a"}]}]}]}`, "has no trace ID or span ID"},
+		{"broken JSON", `{"name":`, "entry 1: invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingTraceSender{}
+			_, err := newTestSpanShipper(sender, 1).run(context.Background(), strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
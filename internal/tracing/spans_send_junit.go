@@ -0,0 +1,501 @@
+package tracing
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/client"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// defaultJUnitRootName names the root span of a --junit trace when neither
+// --name nor the report's <testsuites> element provides a name.
+const defaultJUnitRootName = "tests"
+
+func runSendJUnit(cmd *cobra.Command, flags *sendFlags) error {
+	ctx := cmd.Context()
+
+	if flags.File != "" {
+		return fmt.Errorf("--junit cannot be combined with --file")
+	}
+	// --name names the root span; the other span flags describe a single span.
+	for _, name := range append([]string{"kind"}, singleSpanFlags...) {
+		if name != "name" && cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s cannot be used with --junit; the spans are built from the report", name)
+		}
+	}
+	if flags.BatchSize <= 0 {
+		return fmt.Errorf("--batch-size must be positive")
+	}
+
+	flagResourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid resource attribute: %w", err)
+	}
+
+	spanAttrs, err := otlp.ParseKeyValuePairs(flags.SpanAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid span attribute: %w", err)
+	}
+
+	scopeAttrs, err := otlp.ParseKeyValuePairs(flags.ScopeAttributes)
+	if err != nil {
+		return fmt.Errorf("invalid scope attribute: %w", err)
+	}
+
+	reports := make([]junitSuite, 0, len(flags.JUnit))
+	for _, path := range flags.JUnit {
+		report, err := readJUnitReport(path)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, report)
+	}
+
+	// The pipeline run is detected from the CI environment; explicit
+	// --resource-attribute values win.
+	resourceAttrs := otlp.CIResourceAttributes()
+	for k, v := range flagResourceAttrs {
+		resourceAttrs[k] = v
+	}
+
+	c := &junitConverter{
+		flags:         flags,
+		resourceAttrs: resourceAttrs,
+		spanAttrs:     spanAttrs,
+		scopeAttrs:    scopeAttrs,
+	}
+	if err := c.convert(reports, time.Now()); err != nil {
+		return err
+	}
+
+	apiClient, err := client.NewOtlpClientFromContext(ctx, flags.OtlpUrl, flags.AuthToken)
+	if err != nil {
+		return err
+	}
+	// A trace cut short by Ctrl+C would lack its root span, so every batch is sent.
+	sendCtx := context.WithoutCancel(ctx)
+	defer apiClient.Close(sendCtx)
+	dataset := client.ResolveDataset(ctx, flags.Dataset)
+
+	sent := 0
+	for _, batch := range c.batches {
+		if err := apiClient.SendTraces(sendCtx, batch, dataset); err != nil {
+			return fmt.Errorf("failed to send spans (%d sent before the failure): %w", sent, err)
+		}
+		sent += batch.SpanCount()
+	}
+
+	r := c.results
+	fmt.Printf("%d spans sent (trace-id: %s): %d tests, %d passed, %d failed, %d skipped\n",
+		sent, c.traceID, r.passed+r.failed+r.skipped, r.passed, r.failed, r.skipped)
+	return nil
+}
+
+// junitSuite is a <testsuite> element, or the <testsuites> root element
+// that holds them; both share the same shape.
+type junitSuite struct {
+	XMLName   xml.Name
+	Name      string       `xml:"name,attr"`
+	Timestamp string       `xml:"timestamp,attr"`
+	Time      string       `xml:"time,attr"`
+	Suites    []junitSuite `xml:"testsuite"`
+	Cases     []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	File      string         `xml:"file,attr"`
+	Line      string         `xml:"line,attr"`
+	Failures  []junitProblem `xml:"failure"`
+	Errors    []junitProblem `xml:"error"`
+	Skipped   *junitProblem  `xml:"skipped"`
+}
+
+// junitProblem is a <failure>, <error>, or <skipped> element.
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// readJUnitReport reads a JUnit XML report from path ('-' for stdin).
+func readJUnitReport(path string) (junitSuite, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return junitSuite{}, fmt.Errorf("failed to open JUnit report: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	report, err := parseJUnitReport(r)
+	if err != nil {
+		return junitSuite{}, fmt.Errorf("invalid JUnit report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// parseJUnitReport decodes a report and returns its <testsuites> element. A
+// report with a single <testsuite> root is wrapped in an unnamed one.
+func parseJUnitReport(r io.Reader) (junitSuite, error) {
+	var root junitSuite
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return junitSuite{}, fmt.Errorf("invalid XML: %w", err)
+	}
+	switch root.XMLName.Local {
+	case "testsuites":
+		return root, nil
+	case "testsuite":
+		return junitSuite{Suites: []junitSuite{root}}, nil
+	default:
+		return junitSuite{}, fmt.Errorf("the root element is <%s>, not <testsuites> or <testsuite>", root.XMLName.Local)
+	}
+}
+
+// junitResults counts the test outcomes of a conversion.
+type junitResults struct {
+	passed  int
+	failed  int
+	skipped int
+}
+
+// junitConverter turns JUnit reports into a single trace: a root span for
+// the test run, a span per suite, and a span per test case. Spans are
+// collected in batches of at most flags.BatchSize spans.
+type junitConverter struct {
+	flags         *sendFlags
+	resourceAttrs map[string]string
+	spanAttrs     map[string]string
+	scopeAttrs    map[string]string
+
+	traceID    pcommon.TraceID
+	tracestate string
+	batches    []ptrace.Traces
+	spans      ptrace.SpanSlice
+	results    junitResults
+}
+
+// convert builds the trace of reports. JUnit reports record only the
+// duration of a test case, so the cases of a suite are laid out one after
+// another from the start of the suite. Suites without a timestamp are laid
+// out the same way, ending at now.
+func (c *junitConverter) convert(reports []junitSuite, now time.Time) error {
+	var suites []junitSuite
+	for _, report := range reports {
+		suites = append(suites, report.Suites...)
+	}
+
+	var untimed time.Duration
+	for _, s := range suites {
+		if s.Timestamp == "" {
+			d, err := s.duration()
+			if err != nil {
+				return err
+			}
+			untimed += d
+		}
+	}
+
+	// The trace joins the one in TRACEPARENT, such as that of an enclosing
+	// `dash0 spans exec`, or starts a new one.
+	c.traceID = generateTraceID()
+	var parentSpanID pcommon.SpanID
+	if parent, ok := otlp.TraceparentFromEnv(); ok {
+		c.traceID, parentSpanID = parent.TraceID, parent.SpanID
+		c.tracestate = otlp.TracestateFromEnv()
+	}
+
+	root := c.newSpan(c.rootName(reports), parentSpanID)
+	start, end := now, now
+	cursor := now.Add(-untimed)
+	failed := false
+	for i, s := range suites {
+		suiteStart := cursor
+		if s.Timestamp != "" {
+			t, err := parseJUnitTimestamp(s.Timestamp)
+			if err != nil {
+				return fmt.Errorf("test suite %q: %w", s.Name, err)
+			}
+			suiteStart = t
+		}
+		suiteEnd, suiteFailed, err := c.suite(s, root.SpanID(), suiteStart)
+		if err != nil {
+			return err
+		}
+		if s.Timestamp == "" {
+			cursor = suiteEnd
+		}
+		if i == 0 || suiteStart.Before(start) {
+			start = suiteStart
+		}
+		if i == 0 || suiteEnd.After(end) {
+			end = suiteEnd
+		}
+		failed = failed || suiteFailed
+	}
+
+	root.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	root.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+	setRunStatus(root, failed, c.results.failed)
+	return nil
+}
+
+func (c *junitConverter) rootName(reports []junitSuite) string {
+	if c.flags.Name != "" {
+		return c.flags.Name
+	}
+	if len(reports) == 1 && reports[0].Name != "" {
+		return reports[0].Name
+	}
+	return defaultJUnitRootName
+}
+
+// suite adds the spans of s and its nested suites and cases, and returns
+// when it ended and whether any of its tests failed.
+func (c *junitConverter) suite(s junitSuite, parent pcommon.SpanID, start time.Time) (time.Time, bool, error) {
+	name := s.Name
+	if name == "" {
+		name = "testsuite"
+	}
+	span := c.newSpan(name, parent)
+	if s.Name != "" {
+		span.Attributes().PutStr("test.suite.name", s.Name)
+	}
+
+	failedBefore := c.results.failed
+	cursor := start
+	for _, nested := range s.Suites {
+		end, _, err := c.suite(nested, span.SpanID(), cursor)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		cursor = end
+	}
+	for _, tc := range s.Cases {
+		end, err := c.testCase(tc, span.SpanID(), cursor)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("test suite %q: %w", s.Name, err)
+		}
+		cursor = end
+	}
+
+	end := cursor
+	if s.Time != "" {
+		d, err := parseJUnitSeconds(s.Time)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("test suite %q: %w", s.Name, err)
+		}
+		end = start.Add(d)
+	}
+	failed := c.results.failed > failedBefore
+
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+	setRunStatus(span, failed, c.results.failed-failedBefore)
+	switch {
+	case failed:
+		span.Attributes().PutStr("test.suite.run.status", "failure")
+	case len(s.Cases) > 0 && allSkipped(s.Cases):
+		span.Attributes().PutStr("test.suite.run.status", "skipped")
+	default:
+		span.Attributes().PutStr("test.suite.run.status", "success")
+	}
+	return end, failed, nil
+}
+
+// testCase adds the span of tc and returns when it ended. Failures and
+// errors become exception events on the span.
+func (c *junitConverter) testCase(tc junitCase, parent pcommon.SpanID, start time.Time) (time.Time, error) {
+	d, err := parseJUnitSeconds(tc.Time)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("test case %q: %w", tc.Name, err)
+	}
+	end := start.Add(d)
+
+	span := c.newSpan(tc.Name, parent)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(end))
+
+	fullName := tc.Name
+	if tc.ClassName != "" {
+		fullName = tc.ClassName + "." + tc.Name
+	}
+	span.Attributes().PutStr("test.case.name", fullName)
+	if tc.File != "" {
+		span.Attributes().PutStr("code.file.path", tc.File)
+	}
+	if line, err := strconv.ParseInt(tc.Line, 10, 64); err == nil {
+		span.Attributes().PutInt("code.line.number", line)
+	}
+
+	problems := append(append([]junitProblem{}, tc.Failures...), tc.Errors...)
+	switch {
+	case len(problems) > 0:
+		c.results.failed++
+		span.Attributes().PutStr("test.case.result.status", "fail")
+		span.Status().SetCode(ptrace.StatusCodeError)
+		span.Status().SetMessage(problems[0].summary())
+		for _, p := range problems {
+			event := span.Events().AppendEmpty()
+			event.SetName("exception")
+			event.SetTimestamp(pcommon.NewTimestampFromTime(end))
+			if p.Type != "" {
+				event.Attributes().PutStr("exception.type", p.Type)
+			}
+			if msg := p.summary(); msg != "" {
+				event.Attributes().PutStr("exception.message", msg)
+			}
+			if text := strings.TrimSpace(p.Text); text != "" {
+				event.Attributes().PutStr("exception.stacktrace", text)
+			}
+		}
+	case tc.Skipped != nil:
+		c.results.skipped++
+		span.Attributes().PutStr("test.case.result.status", "skipped")
+		if tc.Skipped.Message != "" {
+			span.Status().SetMessage(tc.Skipped.Message)
+		}
+	default:
+		c.results.passed++
+		span.Attributes().PutStr("test.case.result.status", "pass")
+		span.Status().SetCode(ptrace.StatusCodeOk)
+	}
+	return end, nil
+}
+
+// newSpan appends a span of the trace to the current batch, starting a new
+// batch when it is full.
+func (c *junitConverter) newSpan(name string, parent pcommon.SpanID) ptrace.Span {
+	if len(c.batches) == 0 || c.spans.Len() >= c.flags.BatchSize {
+		traces := ptrace.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		for k, v := range c.resourceAttrs {
+			rs.Resource().Attributes().PutStr(k, v)
+		}
+		ss := rs.ScopeSpans().AppendEmpty()
+		setScope(ss.Scope(), c.flags, c.scopeAttrs)
+		c.spans = ss.Spans()
+		c.batches = append(c.batches, traces)
+	}
+
+	span := c.spans.AppendEmpty()
+	span.SetName(name)
+	span.SetKind(ptrace.SpanKindInternal)
+	span.SetTraceID(c.traceID)
+	span.SetSpanID(generateSpanID())
+	if !parent.IsEmpty() {
+		span.SetParentSpanID(parent)
+	}
+	if c.tracestate != "" {
+		span.TraceState().FromRaw(c.tracestate)
+	}
+	for k, v := range c.spanAttrs {
+		span.Attributes().PutStr(k, v)
+	}
+	return span
+}
+
+// setRunStatus sets the status of a run or suite span from its failed tests.
+func setRunStatus(span ptrace.Span, failed bool, failures int) {
+	if !failed {
+		span.Status().SetCode(ptrace.StatusCodeOk)
+		return
+	}
+	span.Status().SetCode(ptrace.StatusCodeError)
+	if failures == 1 {
+		span.Status().SetMessage("1 test failed")
+	} else {
+		span.Status().SetMessage(fmt.Sprintf("%d tests failed", failures))
+	}
+}
+
+// summary returns the message of a problem, or the first line of its text
+// when the report has no message attribute.
+func (p junitProblem) summary() string {
+	if p.Message != "" {
+		return p.Message
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(p.Text), "\n")
+	return strings.TrimSpace(first)
+}
+
+// duration returns the time attribute of a suite or, without one, the sum
+// of the durations of its nested suites and cases.
+func (s junitSuite) duration() (time.Duration, error) {
+	if s.Time != "" {
+		d, err := parseJUnitSeconds(s.Time)
+		if err != nil {
+			return 0, fmt.Errorf("test suite %q: %w", s.Name, err)
+		}
+		return d, nil
+	}
+	var total time.Duration
+	for _, nested := range s.Suites {
+		d, err := nested.duration()
+		if err != nil {
+			return 0, err
+		}
+		total += d
+	}
+	for _, tc := range s.Cases {
+		d, err := parseJUnitSeconds(tc.Time)
+		if err != nil {
+			return 0, fmt.Errorf("test case %q: %w", tc.Name, err)
+		}
+		total += d
+	}
+	return total, nil
+}
+
+func allSkipped(cases []junitCase) bool {
+	for _, tc := range cases {
+		if tc.Skipped == nil || len(tc.Failures)+len(tc.Errors) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// parseJUnitSeconds parses a time attribute in seconds. Some tools format
+// it with thousands separators, such as "1,234.5"; a missing value is zero.
+func parseJUnitSeconds(s string) (time.Duration, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid time %q; expected a number of seconds", s)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// junitTimestampLayouts are the timestamp formats found in JUnit reports.
+// Most tools write ISO 8601 without a zone, meaning local time.
+var junitTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func parseJUnitTimestamp(s string) (time.Time, error) {
+	for _, layout := range junitTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q; expected ISO 8601", s)
+}
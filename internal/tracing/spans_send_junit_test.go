@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const checkoutJUnitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="checkout tests" time="3.5">
+  <testsuite name="CartTest" timestamp="2026-03-15T10:30:00Z" time="1.5">
+    <testcase name="addsItem" classname="com.acme.CartTest" time="0.5" file="src/test/CartTest.java" line="42"/>
+    <testcase name="removesItem" classname="com.acme.CartTest" time="1">
+      <failure message="expected 0 but was 1" type="AssertionError">AssertionError: expected 0 but was 1
+	at com.acme.CartTest.removesItem(CartTest.java:57)</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="PaymentTest" timestamp="2026-03-15T10:30:01.5Z" time="2">
+    <testcase name="refunds" classname="com.acme.PaymentTest" time="1.5"/>
+    <testcase name="declines" classname="com.acme.PaymentTest" time="0">
+      <skipped message="flaky on CI"/>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+func newTestJUnitConverter(batchSize int) *junitConverter {
+	return &junitConverter{
+		flags:         &sendFlags{ScopeName: "dash0-cli", BatchSize: batchSize},
+		resourceAttrs: map[string]string{"service.name": "checkout"},
+		spanAttrs:     map[string]string{"test.framework": "junit5"},
+	}
+}
+
+// junitSpansByName returns the spans of all batches by name.
+func junitSpansByName(batches []ptrace.Traces) map[string]ptrace.Span {
+	spans := map[string]ptrace.Span{}
+	for _, batch := range batches {
+		ss := batch.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < ss.Len(); i++ {
+			spans[ss.At(i).Name()] = ss.At(i)
+		}
+	}
+	return spans
+}
+
+func TestJUnitConverter_SpanTree(t *testing.T) {
+	t.Setenv(otlp.EnvTraceparent, "")
+	report, err := parseJUnitReport(strings.NewReader(checkoutJUnitReport))
+	require.NoError(t, err)
+
+	c := newTestJUnitConverter(1000)
+	require.NoError(t, c.convert([]junitSuite{report}, time.Now()))
+	require.Len(t, c.batches, 1)
+	assert.Equal(t, 7, c.batches[0].SpanCount())
+	assert.Equal(t, junitResults{passed: 2, failed: 1, skipped: 1}, c.results)
+
+	spans := junitSpansByName(c.batches)
+	root, cart, failed := spans["checkout tests"], spans["CartTest"], spans["removesItem"]
+	assert.True(t, root.ParentSpanID().IsEmpty())
+	assert.Equal(t, root.SpanID(), cart.ParentSpanID())
+	assert.Equal(t, cart.SpanID(), failed.ParentSpanID())
+	assert.Equal(t, root.TraceID(), failed.TraceID())
+
+	start := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, start, root.StartTimestamp().AsTime())
+	assert.Equal(t, start.Add(3500*time.Millisecond), root.EndTimestamp().AsTime())
+	assert.Equal(t, start.Add(500*time.Millisecond), failed.StartTimestamp().AsTime(), "cases run one after another")
+	assert.Equal(t, 1500*time.Millisecond, spans["refunds"].EndTimestamp().AsTime().Sub(spans["refunds"].StartTimestamp().AsTime()))
+
+	assert.Equal(t, ptrace.StatusCodeError, root.Status().Code())
+	assert.Equal(t, "1 test failed", root.Status().Message())
+	assert.Equal(t, ptrace.StatusCodeOk, spans["PaymentTest"].Status().Code())
+	assert.Equal(t, ptrace.StatusCodeUnset, spans["declines"].Status().Code())
+
+	status, _ := cart.Attributes().Get("test.suite.run.status")
+	assert.Equal(t, "failure", status.Str())
+	name, _ := failed.Attributes().Get("test.case.name")
+	assert.Equal(t, "com.acme.CartTest.removesItem", name.Str())
+	result, _ := spans["declines"].Attributes().Get("test.case.result.status")
+	assert.Equal(t, "skipped", result.Str())
+	line, _ := spans["addsItem"].Attributes().Get("code.line.number")
+	assert.Equal(t, int64(42), line.Int())
+	framework, _ := failed.Attributes().Get("test.framework")
+	assert.Equal(t, "junit5", framework.Str())
+
+	assert.Equal(t, "expected 0 but was 1", failed.Status().Message())
+	require.Equal(t, 1, failed.Events().Len())
+	event := failed.Events().At(0)
+	assert.Equal(t, "exception", event.Name())
+	excType, _ := event.Attributes().Get("exception.type")
+	assert.Equal(t, "AssertionError", excType.Str())
+	stack, _ := event.Attributes().Get("exception.stacktrace")
+	assert.Contains(t, stack.Str(), "CartTest.java:57")
+}
+
+func TestJUnitConverter_UntimedSuitesEndNow(t *testing.T) {
+	t.Setenv(otlp.EnvTraceparent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	t.Setenv(otlp.EnvTracestate, "vendor=abc")
+	report, err := parseJUnitReport(strings.NewReader(`<testsuite name="smoke">
+  <testcase name="ping" time="1,002"/>
+  <testcase name="login" time="3"><error message="connection refused"/></testcase>
+</testsuite>`))
+	require.NoError(t, err)
+
+	c := newTestJUnitConverter(2)
+	c.flags.Name = "smoke tests"
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, c.convert([]junitSuite{report}, now))
+	assert.Len(t, c.batches, 2, "4 spans in batches of 2")
+
+	spans := junitSpansByName(c.batches)
+	root := spans["smoke tests"]
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", root.TraceID().String(), "the trace joins TRACEPARENT")
+	assert.Equal(t, "b7ad6b7169203331", root.ParentSpanID().String())
+	assert.Equal(t, "vendor=abc", spans["login"].TraceState().AsRaw())
+	assert.Equal(t, now.Add(-1005*time.Second), root.StartTimestamp().AsTime(), "1,002 is 1002 seconds")
+	assert.Equal(t, now, root.EndTimestamp().AsTime())
+	assert.Equal(t, "connection refused", spans["login"].Status().Message())
+}
+
+func TestParseJUnitReport_Invalid(t *testing.T) {
+	_, err := parseJUnitReport(strings.NewReader(`<testrun><test name="a"/></testrun>`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the root element is <testrun>")
+
+	report, err := parseJUnitReport(strings.NewReader(`<testsuite name="a"><testcase name="b" time="soon"/></testsuite>`))
+	require.NoError(t, err)
+	err = newTestJUnitConverter(10).convert([]junitSuite{report}, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `test case "b": invalid time "soon"`)
+}
+
+func TestSendJUnitRejectsSingleSpanFlags(t *testing.T) {
+	root, _ := newSpansSendCmd()
+	root.SetArgs([]string{"spans", "send", "--junit", "report.xml", "--status-code", "ERROR"})
+	err := root.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--status-code cannot be used with --junit")
+}
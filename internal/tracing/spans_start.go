@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/spf13/cobra"
+)
+
+type startFlags struct {
+	Name               string
+	Kind               string
+	ResourceAttributes []string
+	SpanAttributes     []string
+	Format             string
+}
+
+func newStartCmd() *cobra.Command {
+	flags := &startFlags{}
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start a span that a later 'dash0 spans end' sends",
+		Long: `Start a span and print a TRACEPARENT that points at it.
+
+The span is kept in the configuration directory until 'dash0 spans end' sends it, so it can cover several commands of a shell script or CI job.
+With TRACEPARENT exported, every send command and 'dash0 spans exec' in between becomes a child of the span.
+If TRACEPARENT is already set, the span itself joins that trace, so spans can be nested.
+
+The output is a shell command to evaluate, or with --format env a 'TRACEPARENT=...' line for files such as $GITHUB_ENV.`,
+		Example: `  # Trace a deployment script
+  eval "$(dash0 spans start --name deploy --resource-attribute service.name=checkout)"
+  dash0 spans exec -- ./migrate.sh
+  dash0 spans exec -- ./rollout.sh
+  eval "$(dash0 spans end --status-code OK)"
+
+  # Keep the span open across the steps of a GitHub Actions job
+  dash0 spans start --name "release" --format env >> "$GITHUB_ENV"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runStart(flags, time.Now())
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.Name, "name", "", "Span name")
+	cmd.Flags().StringVar(&flags.Kind, "kind", "INTERNAL", "Span kind: INTERNAL, SERVER, CLIENT, PRODUCER, CONSUMER")
+	cmd.Flags().StringArrayVar(&flags.ResourceAttributes, "resource-attribute", nil, "Resource attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.SpanAttributes, "span-attribute", nil, "Span attribute as 'key=value' (repeatable)")
+	cmd.Flags().StringVar(&flags.Format, "format", "shell", "Output format: shell (an export command) or env (a KEY=value line)")
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func runStart(flags *startFlags, now time.Time) error {
+	if err := validateEnvFormat(flags.Format); err != nil {
+		return err
+	}
+	o, err := newOpenSpan(flags, now)
+	if err != nil {
+		return err
+	}
+	self, err := o.traceparent()
+	if err != nil {
+		return err
+	}
+	if err := saveOpenSpan(o); err != nil {
+		return fmt.Errorf("failed to record the span: %w", err)
+	}
+
+	// stdout is meant to be evaluated; dash0's own messages go to stderr.
+	fmt.Println(envAssignment(flags.Format, otlp.EnvTraceparent, self.String()))
+	fmt.Fprintf(os.Stderr, "Span started (trace-id: %s, span-id: %s)\n", o.TraceID, o.SpanID)
+	return nil
+}
+
+// newOpenSpan returns the span of flags, started at now. It joins the trace
+// in TRACEPARENT, if any, and starts a new sampled trace otherwise.
+func newOpenSpan(flags *startFlags, now time.Time) (openSpan, error) {
+	if _, err := ParseSpanKind(flags.Kind); err != nil {
+		return openSpan{}, err
+	}
+	resourceAttrs, err := otlp.ParseKeyValuePairs(flags.ResourceAttributes)
+	if err != nil {
+		return openSpan{}, fmt.Errorf("invalid resource attribute: %w", err)
+	}
+	spanAttrs, err := otlp.ParseKeyValuePairs(flags.SpanAttributes)
+	if err != nil {
+		return openSpan{}, fmt.Errorf("invalid span attribute: %w", err)
+	}
+
+	o := openSpan{
+		Name:               flags.Name,
+		Kind:               flags.Kind,
+		TraceID:            generateTraceID().String(),
+		SpanID:             generateSpanID().String(),
+		Sampled:            true,
+		StartedAt:          now,
+		Attributes:         spanAttrs,
+		ResourceAttributes: resourceAttrs,
+	}
+	if parent, ok := otlp.TraceparentFromEnv(); ok {
+		o.TraceID = parent.TraceID.String()
+		o.ParentSpanID = parent.SpanID.String()
+		o.Sampled = parent.Sampled
+		o.TraceState = otlp.TracestateFromEnv()
+	}
+	return o, nil
+}
+
+func validateEnvFormat(format string) error {
+	switch format {
+	case "shell", "env":
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (valid: shell, env)", format)
+	}
+}
+
+// envAssignment returns the line that sets the environment variable name to
+// value: an export command for shells, or a KEY=value line for env files.
+// An empty value unsets the variable in a shell.
+func envAssignment(format, name, value string) string {
+	switch {
+	case format == "env":
+		return name + "=" + value
+	case value == "":
+		return "unset " + name
+	default:
+		return "export " + name + "=" + value
+	}
+}
@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dash0hq/dash0-cli/internal"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// openSpan is a span started with `dash0 spans start` and not yet ended. It
+// is kept in the configuration directory until `dash0 spans end` sends it.
+type openSpan struct {
+	Name               string            `json:"name"`
+	Kind               string            `json:"kind"`
+	TraceID            string            `json:"trace-id"`
+	SpanID             string            `json:"span-id"`
+	ParentSpanID       string            `json:"parent-span-id,omitempty"`
+	TraceState         string            `json:"trace-state,omitempty"`
+	Sampled            bool              `json:"sampled"`
+	StartedAt          time.Time         `json:"started-at"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
+	ResourceAttributes map[string]string `json:"resource-attributes,omitempty"`
+}
+
+// traceparent returns the context that makes other spans children of o.
+func (o openSpan) traceparent() (otlp.Traceparent, error) {
+	traceID, err := otlp.ParseTraceID(o.TraceID)
+	if err != nil {
+		return otlp.Traceparent{}, err
+	}
+	spanID, err := otlp.ParseSpanID(o.SpanID)
+	if err != nil {
+		return otlp.Traceparent{}, err
+	}
+	return otlp.Traceparent{TraceID: traceID, SpanID: spanID, Sampled: o.Sampled}, nil
+}
+
+// parentTraceparent returns the context that was active when o started, if
+// any, so that it can be restored when o ends.
+func (o openSpan) parentTraceparent() (otlp.Traceparent, bool) {
+	if o.ParentSpanID == "" {
+		return otlp.Traceparent{}, false
+	}
+	traceID, err := otlp.ParseTraceID(o.TraceID)
+	if err != nil {
+		return otlp.Traceparent{}, false
+	}
+	spanID, err := otlp.ParseSpanID(o.ParentSpanID)
+	if err != nil {
+		return otlp.Traceparent{}, false
+	}
+	return otlp.Traceparent{TraceID: traceID, SpanID: spanID, Sampled: o.Sampled}, true
+}
+
+// openSpanPath returns the file of an open span, in the spans directory
+// under the configuration directory (default: ~/.dash0).
+func openSpanPath(spanID pcommon.SpanID) (string, error) {
+	dir, err := internal.StateDir("spans")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, spanID.String()+".json"), nil
+}
+
+func saveOpenSpan(o openSpan) error {
+	spanID, err := otlp.ParseSpanID(o.SpanID)
+	if err != nil {
+		return err
+	}
+	path, err := openSpanPath(spanID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func loadOpenSpan(spanID pcommon.SpanID) (openSpan, error) {
+	path, err := openSpanPath(spanID)
+	if err != nil {
+		return openSpan{}, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return openSpan{}, fmt.Errorf("no open span with span ID %s; spans are opened with 'dash0 spans start'", spanID)
+	}
+	if err != nil {
+		return openSpan{}, err
+	}
+	var o openSpan
+	if err := json.Unmarshal(data, &o); err != nil {
+		return openSpan{}, fmt.Errorf("failed to read open span %s: %w", spanID, err)
+	}
+	return o, nil
+}
+
+func removeOpenSpan(spanID pcommon.SpanID) {
+	if path, err := openSpanPath(spanID); err == nil {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dash0hq/dash0-api-client-go/profiles"
+	"github.com/dash0hq/dash0-cli/internal/otlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestOpenSpan_StartAndEnd(t *testing.T) {
+	t.Setenv(profiles.EnvConfigDir, t.TempDir())
+	t.Setenv(otlp.EnvTraceparent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	t.Setenv(otlp.EnvTracestate, "vendor=abc")
+	started := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	o, err := newOpenSpan(&startFlags{
+		Name:               "deploy",
+		Kind:               "INTERNAL",
+		SpanAttributes:     []string{"deployment.environment.name=staging"},
+		ResourceAttributes: []string{"service.name=checkout"},
+	}, started)
+	require.NoError(t, err)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", o.TraceID, "the span joins TRACEPARENT")
+	assert.Equal(t, "b7ad6b7169203331", o.ParentSpanID)
+	require.NoError(t, saveOpenSpan(o))
+
+	self, err := o.traceparent()
+	require.NoError(t, err)
+	loaded, err := loadOpenSpan(self.SpanID)
+	require.NoError(t, err)
+	assert.True(t, started.Equal(loaded.StartedAt))
+
+	traces, err := loaded.end(&endFlags{
+		StatusCode:     "ERROR",
+		StatusMessage:  "rollout timed out",
+		SpanAttributes: []string{"deployment.environment.name=production"},
+		ScopeName:      otlp.DefaultScopeName,
+	}, started.Add(90*time.Second))
+	require.NoError(t, err)
+	rs := traces.ResourceSpans().At(0)
+	serviceName, _ := rs.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "checkout", serviceName.Str())
+	s := rs.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "deploy", s.Name())
+	assert.Equal(t, o.SpanID, s.SpanID().String())
+	assert.Equal(t, "b7ad6b7169203331", s.ParentSpanID().String())
+	assert.Equal(t, "vendor=abc", s.TraceState().AsRaw())
+	assert.Equal(t, 90*time.Second, s.EndTimestamp().AsTime().Sub(s.StartTimestamp().AsTime()))
+	assert.Equal(t, ptrace.StatusCodeError, s.Status().Code())
+	env, _ := s.Attributes().Get("deployment.environment.name")
+	assert.Equal(t, "production", env.Str(), "attributes given at the end win")
+
+	parent, ok := o.parentTraceparent()
+	require.True(t, ok)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", parent.String())
+
+	removeOpenSpan(self.SpanID)
+	_, err = loadOpenSpan(self.SpanID)
+	assert.ErrorContains(t, err, "no open span with span ID "+o.SpanID)
+}
+
+func TestOpenSpan_NewTrace(t *testing.T) {
+	t.Setenv(otlp.EnvTraceparent, "")
+	o, err := newOpenSpan(&startFlags{Name: "build", Kind: "INTERNAL"}, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, o.ParentSpanID)
+	assert.True(t, o.Sampled)
+	_, ok := o.parentTraceparent()
+	assert.False(t, ok)
+
+	_, err = newOpenSpan(&startFlags{Name: "build", Kind: "SIDEWAYS"}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestEndWithoutOpenSpan(t *testing.T) {
+	t.Setenv(profiles.EnvConfigDir, t.TempDir())
+	t.Setenv(otlp.EnvTraceparent, "")
+
+	root, _ := newSpansSendCmd()
+	root.SetArgs([]string{"spans", "end"})
+	assert.ErrorContains(t, root.Execute(), "no span to end")
+
+	root, _ = newSpansSendCmd()
+	root.SetArgs([]string{"spans", "end", "--span-id", "b7ad6b7169203331"})
+	assert.ErrorContains(t, root.Execute(), "no open span with span ID b7ad6b7169203331")
+}
+
+func TestEnvAssignment(t *testing.T) {
+	assert.Equal(t, "export TRACEPARENT=00-x", envAssignment("shell", "TRACEPARENT", "00-x"))
+	assert.Equal(t, "unset TRACEPARENT", envAssignment("shell", "TRACEPARENT", ""))
+	assert.Equal(t, "TRACEPARENT=00-x", envAssignment("env", "TRACEPARENT", "00-x"))
+	assert.Equal(t, "TRACEPARENT=", envAssignment("env", "TRACEPARENT", ""))
+	assert.Error(t, validateEnvFormat("json"))
+}